@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/services"
+)
+
+// AccountHandler handles brokerage account endpoints
+type AccountHandler struct {
+	accountService *services.AccountService
+}
+
+// NewAccountHandler creates a new account handler
+func NewAccountHandler(accountService *services.AccountService) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+	}
+}
+
+// AccountRequest represents the request body for creating or updating an account
+type AccountRequest struct {
+	Name         string              `json:"name" binding:"required"`
+	Broker       string              `json:"broker"`
+	PortfolioID  string              `json:"portfolio_id"`
+	AccountType  models.AccountType  `json:"account_type" binding:"required"`
+	BaseCurrency string              `json:"base_currency"`
+	TaxTreatment models.TaxTreatment `json:"tax_treatment" binding:"required"`
+}
+
+// toModel converts the request into an account model, failing on a malformed portfolio ID
+func (r AccountRequest) toModel() (models.Account, error) {
+	portfolioID, err := parseOptionalUUID(r.PortfolioID)
+	if err != nil {
+		return models.Account{}, err
+	}
+	return models.Account{
+		Name:         r.Name,
+		Broker:       r.Broker,
+		PortfolioID:  portfolioID,
+		AccountType:  r.AccountType,
+		BaseCurrency: r.BaseCurrency,
+		TaxTreatment: r.TaxTreatment,
+	}, nil
+}
+
+// ListAccounts handles GET /api/v1/accounts
+func (h *AccountHandler) ListAccounts(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	accounts, err := h.accountService.ListAccounts(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to get accounts",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"accounts": accounts},
+	})
+}
+
+// GetAccount handles GET /api/v1/accounts/{id}
+func (h *AccountHandler) GetAccount(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid account ID format",
+		})
+		return
+	}
+
+	account, err := h.accountService.GetAccount(userID, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Account does not exist",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"account": account},
+	})
+}
+
+// CreateAccount handles POST /api/v1/accounts
+func (h *AccountHandler) CreateAccount(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req AccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format",
+		})
+		return
+	}
+
+	account, err := req.toModel()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid portfolio_id format",
+		})
+		return
+	}
+
+	created, err := h.accountService.CreateAccount(userID, account)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid account") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to create account",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Account created successfully",
+		"data":    gin.H{"account": created},
+	})
+}
+
+// UpdateAccount handles PUT /api/v1/accounts/{id}
+func (h *AccountHandler) UpdateAccount(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid account ID format",
+		})
+		return
+	}
+
+	var req AccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format",
+		})
+		return
+	}
+
+	account, err := req.toModel()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid portfolio_id format",
+		})
+		return
+	}
+
+	updated, err := h.accountService.UpdateAccount(userID, id, account)
+	if err != nil {
+		if err.Error() == "not_found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"message": "Account does not exist",
+			})
+			return
+		}
+		if strings.Contains(err.Error(), "invalid account") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to update account",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Account updated successfully",
+		"data":    gin.H{"account": updated},
+	})
+}
+
+// DeleteAccount handles DELETE /api/v1/accounts/{id}
+func (h *AccountHandler) DeleteAccount(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid account ID format",
+		})
+		return
+	}
+
+	if err := h.accountService.DeleteAccount(userID, id); err != nil {
+		if err.Error() == "not_found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"message": "Account does not exist",
+			})
+			return
+		}
+		if strings.Contains(err.Error(), "account in use") {
+			c.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"message": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to delete account",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Account deleted successfully",
+	})
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/services"
+)
+
+// CorporateActionHandler handles corporate action endpoints
+type CorporateActionHandler struct {
+	corporateActionService *services.CorporateActionService
+}
+
+// NewCorporateActionHandler creates a new corporate action handler
+func NewCorporateActionHandler(corporateActionService *services.CorporateActionService) *CorporateActionHandler {
+	return &CorporateActionHandler{
+		corporateActionService: corporateActionService,
+	}
+}
+
+// CorporateActionRequest represents the request body for recording a corporate action
+type CorporateActionRequest struct {
+	Symbol        string                     `json:"symbol" binding:"required"`
+	ActionType    models.CorporateActionType `json:"action_type" binding:"required"`
+	EffectiveDate string                     `json:"effective_date" binding:"required"`
+	RatioFrom     float64                    `json:"ratio_from"`
+	RatioTo       float64                    `json:"ratio_to"`
+	NewSymbol     string                     `json:"new_symbol"`
+	Notes         string                     `json:"notes"`
+}
+
+// toModel converts the request into a corporate action model
+func (r CorporateActionRequest) toModel() (models.CorporateAction, error) {
+	effectiveDate, err := time.Parse("2006-01-02", r.EffectiveDate)
+	if err != nil {
+		return models.CorporateAction{}, err
+	}
+
+	return models.CorporateAction{
+		Symbol:        r.Symbol,
+		ActionType:    r.ActionType,
+		EffectiveDate: effectiveDate,
+		RatioFrom:     r.RatioFrom,
+		RatioTo:       r.RatioTo,
+		NewSymbol:     r.NewSymbol,
+		Notes:         r.Notes,
+	}, nil
+}
+
+// ListCorporateActions handles GET /api/v1/corporate-actions
+func (h *CorporateActionHandler) ListCorporateActions(c *gin.Context) {
+	actions, err := h.corporateActionService.ListCorporateActions(strings.TrimSpace(c.Query("symbol")))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to get corporate actions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"corporate_actions": actions},
+	})
+}
+
+// CreateCorporateAction handles POST /api/v1/corporate-actions
+func (h *CorporateActionHandler) CreateCorporateAction(c *gin.Context) {
+	var req CorporateActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format",
+		})
+		return
+	}
+
+	action, err := req.toModel()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "effective_date must be in YYYY-MM-DD format",
+		})
+		return
+	}
+
+	created, err := h.corporateActionService.CreateCorporateAction(action)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid corporate action") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to create corporate action",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Corporate action created successfully",
+		"data":    gin.H{"corporate_action": created},
+	})
+}
+
+// UpdateCorporateAction handles PUT /api/v1/corporate-actions/{id}
+func (h *CorporateActionHandler) UpdateCorporateAction(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid corporate action ID format",
+		})
+		return
+	}
+
+	var req CorporateActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format",
+		})
+		return
+	}
+
+	action, err := req.toModel()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "effective_date must be in YYYY-MM-DD format",
+		})
+		return
+	}
+
+	updated, err := h.corporateActionService.UpdateCorporateAction(id, action)
+	if err != nil {
+		if err.Error() == "not_found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"message": "Corporate action does not exist",
+			})
+			return
+		}
+		if strings.Contains(err.Error(), "invalid corporate action") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to update corporate action",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Corporate action updated successfully",
+		"data":    gin.H{"corporate_action": updated},
+	})
+}
+
+// DeleteCorporateAction handles DELETE /api/v1/corporate-actions/{id}
+func (h *CorporateActionHandler) DeleteCorporateAction(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid corporate action ID format",
+		})
+		return
+	}
+
+	if err := h.corporateActionService.DeleteCorporateAction(id); err != nil {
+		if err.Error() == "not_found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"message": "Corporate action does not exist",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to delete corporate action",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Corporate action deleted successfully",
+	})
+}
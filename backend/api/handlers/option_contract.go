@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/services"
+)
+
+// OptionContractHandler handles option contract endpoints
+type OptionContractHandler struct {
+	optionContractService *services.OptionContractService
+}
+
+// NewOptionContractHandler creates a new option contract handler
+func NewOptionContractHandler(optionContractService *services.OptionContractService) *OptionContractHandler {
+	return &OptionContractHandler{
+		optionContractService: optionContractService,
+	}
+}
+
+// OptionContractRequest represents the request body for recording an option contract.
+// Symbol may be left out for a standard contract, which is recorded under its OCC symbol.
+type OptionContractRequest struct {
+	Symbol     string            `json:"symbol"`
+	Underlying string            `json:"underlying" binding:"required"`
+	Expiry     string            `json:"expiry" binding:"required"`
+	Strike     float64           `json:"strike" binding:"required"`
+	OptionType models.OptionType `json:"option_type" binding:"required"`
+	Multiplier float64           `json:"multiplier"`
+}
+
+// ListOptionContracts handles GET /api/v1/option-contracts
+func (h *OptionContractHandler) ListOptionContracts(c *gin.Context) {
+	contracts, err := h.optionContractService.ListOptionContracts()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to get option contracts",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"option_contracts": contracts},
+	})
+}
+
+// UpsertOptionContract handles POST /api/v1/option-contracts
+func (h *OptionContractHandler) UpsertOptionContract(c *gin.Context) {
+	var req OptionContractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format",
+		})
+		return
+	}
+
+	expiry, err := time.Parse("2006-01-02", req.Expiry)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "expiry must be in YYYY-MM-DD format",
+		})
+		return
+	}
+
+	contract, err := h.optionContractService.UpsertOptionContract(models.OptionContract{
+		Symbol:     req.Symbol,
+		Underlying: req.Underlying,
+		Expiry:     expiry,
+		Strike:     req.Strike,
+		OptionType: req.OptionType,
+		Multiplier: req.Multiplier,
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid option contract") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to save option contract",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Option contract saved successfully",
+		"data":    gin.H{"option_contract": contract},
+	})
+}
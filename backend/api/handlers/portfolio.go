@@ -1,14 +1,20 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/constants"
 	"github.com/transaction-tracker/backend/internal/models"
 	"github.com/transaction-tracker/backend/internal/services"
+	"github.com/transaction-tracker/backend/internal/utils"
 )
 
 // PortfolioHandler handles portfolio-related HTTP requests
@@ -30,6 +36,11 @@ func (h *PortfolioHandler) GetSingleHoldingBasicInfo(c *gin.Context) {
 		return
 	}
 
+	scope, ok := portfolioScopeFromRequest(c)
+	if !ok {
+		return
+	}
+
 	// Get symbol from URL parameter
 	symbol := strings.TrimSpace(strings.ToUpper(c.Param("symbol")))
 	if symbol == "" {
@@ -40,11 +51,11 @@ func (h *PortfolioHandler) GetSingleHoldingBasicInfo(c *gin.Context) {
 		return
 	}
 
-	// Validate symbol format (basic validation)
-	if len(symbol) < 1 || len(symbol) > 10 {
+	// Validate symbol format; holdings may be exchange-listed, crypto pairs or options
+	if !utils.SymbolRegex.MatchString(symbol) && !utils.OptionSymbolRegex.MatchString(symbol) {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
-			"message": "Symbol must be 1-10 characters",
+			"message": "Symbol must be alphanumeric uppercase, 1-10 characters, a crypto pair or an OCC option symbol",
 		})
 		return
 	}
@@ -60,8 +71,11 @@ func (h *PortfolioHandler) GetSingleHoldingBasicInfo(c *gin.Context) {
 	}
 
 	// Get stock basic info from service
-	holdingInfo, err := h.portfolioService.GetSingleHoldingBasicInfo(c.Request.Context(), userID, symbol)
+	holdingInfo, err := h.portfolioService.GetSingleHoldingBasicInfo(c.Request.Context(), userID, scope, symbol)
 	if err != nil {
+		if respondScopeNotFound(c, err) || respondFXRateUnavailable(c, err) {
+			return
+		}
 		if strings.Contains(err.Error(), "no transactions found") || strings.Contains(err.Error(), "no current holdings") {
 			c.JSON(http.StatusNotFound, gin.H{
 				"success": false,
@@ -96,16 +110,100 @@ func (h *PortfolioHandler) GetSingleHoldingBasicInfo(c *gin.Context) {
 	})
 }
 
-// GetAllHoldings handles GET /api/v1/portfolio/holdings
+// GetHoldingChart handles GET /api/v1/portfolio/holdings/{symbol}/chart
+func (h *PortfolioHandler) GetHoldingChart(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	scope, ok := portfolioScopeFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// Get symbol from URL parameter
+	symbol := strings.TrimSpace(strings.ToUpper(c.Param("symbol")))
+	if symbol == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Symbol parameter is required",
+		})
+		return
+	}
+
+	// Validate symbol format; holdings may be exchange-listed, crypto pairs or options
+	if !utils.SymbolRegex.MatchString(symbol) && !utils.OptionSymbolRegex.MatchString(symbol) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Symbol must be alphanumeric uppercase, 1-10 characters, a crypto pair or an OCC option symbol",
+		})
+		return
+	}
+
+	// Get timeframe parameter (required)
+	timeframeStr := c.Query("timeframe")
+	if timeframeStr == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "timeframe parameter is required",
+		})
+		return
+	}
+
+	timeframe := models.TimeFrame(timeframeStr)
+	if !timeframe.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid timeframe. Supported values: 1D, 1W, 1M, 3M, 6M, YTD, 1Y, 5Y, ALL",
+		})
+		return
+	}
+
+	chart, err := h.portfolioService.GetHoldingChart(c.Request.Context(), userID, scope, symbol, timeframe)
+	if err != nil {
+		if respondScopeNotFound(c, err) || respondFXRateUnavailable(c, err) {
+			return
+		}
+		if strings.Contains(err.Error(), "no transactions found") {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"message": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to get holding chart",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Holding chart retrieved successfully",
+		"data":    chart,
+	})
+}
+
+// GetAllHoldings handles GET /api/v1/portfolio/holdings and /api/v1/portfolios/{id}/holdings
 func (h *PortfolioHandler) GetAllHoldings(c *gin.Context) {
 	userID, ok := getUserIDFromContext(c)
 	if !ok {
 		return
 	}
 
+	scope, ok := portfolioScopeFromRequest(c)
+	if !ok {
+		return
+	}
+
 	// Get all holdings from service
-	holdings, err := h.portfolioService.GetAllHoldings(c.Request.Context(), userID)
+	holdings, err := h.portfolioService.GetAllHoldings(c.Request.Context(), userID, scope)
 	if err != nil {
+		if respondScopeNotFound(c, err) || respondFXRateUnavailable(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"message": "Failed to get holdings information",
@@ -122,16 +220,24 @@ func (h *PortfolioHandler) GetAllHoldings(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
-// GetPortfolioSummary handles GET /api/v1/portfolio/summary
+// GetPortfolioSummary handles GET /api/v1/portfolio/summary and /api/v1/portfolios/{id}/summary
 func (h *PortfolioHandler) GetPortfolioSummary(c *gin.Context) {
 	userID, ok := getUserIDFromContext(c)
 	if !ok {
 		return
 	}
 
+	scope, ok := portfolioScopeFromRequest(c)
+	if !ok {
+		return
+	}
+
 	// Get portfolio summary from service
-	summary, err := h.portfolioService.GetPortfolioSummary(c.Request.Context(), userID)
+	summary, err := h.portfolioService.GetPortfolioSummary(c.Request.Context(), userID, scope)
 	if err != nil {
+		if respondScopeNotFound(c, err) || respondFXRateUnavailable(c, err) {
+			return
+		}
 		if strings.Contains(err.Error(), "failed to get current price") {
 			c.JSON(http.StatusServiceUnavailable, gin.H{
 				"success": false,
@@ -150,12 +256,19 @@ func (h *PortfolioHandler) GetPortfolioSummary(c *gin.Context) {
 }
 
 // GetHistoricalPortfolioTotalValue handles GET /api/v1/portfolio/chart/historical-market-value
+// and /api/v1/portfolios/{id}/chart/historical-market-value
+// Optional ?benchmark=SPY,0050.TW adds a comparison series per benchmark
 func (h *PortfolioHandler) GetHistoricalPortfolioTotalValue(c *gin.Context) {
 	userID, ok := getUserIDFromContext(c)
 	if !ok {
 		return
 	}
 
+	scope, ok := portfolioScopeFromRequest(c)
+	if !ok {
+		return
+	}
+
 	// Get timeframe parameter (required)
 	timeframeStr := c.Query("timeframe")
 	if timeframeStr == "" {
@@ -168,21 +281,203 @@ func (h *PortfolioHandler) GetHistoricalPortfolioTotalValue(c *gin.Context) {
 
 	// Validate timeframe
 	timeframe := models.TimeFrame(timeframeStr)
-	validTimeframes := []models.TimeFrame{
-		models.TimeFrame1D, models.TimeFrame1W, models.TimeFrame1M,
-		models.TimeFrame3M, models.TimeFrame6M, models.TimeFrameYTD,
-		models.TimeFrame1Y, models.TimeFrame5Y, models.TimeFrameALL,
+	if !timeframe.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid timeframe. Supported values: 1D, 1W, 1M, 3M, 6M, YTD, 1Y, 5Y, ALL",
+		})
+		return
+	}
+
+	// Parse benchmark symbols (optional, comma-separated)
+	var benchmarks []string
+	if benchmarkParam := c.Query("benchmark"); benchmarkParam != "" {
+		for _, symbol := range strings.Split(benchmarkParam, ",") {
+			symbol = strings.ToUpper(strings.TrimSpace(symbol))
+			if !utils.SymbolRegex.MatchString(symbol) {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"success": false,
+					"message": fmt.Sprintf("Invalid benchmark symbol %q", symbol),
+				})
+				return
+			}
+			benchmarks = append(benchmarks, symbol)
+		}
+		if len(benchmarks) > constants.MaxBenchmarksPerChart {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": fmt.Sprintf("At most %d benchmarks can be compared at once", constants.MaxBenchmarksPerChart),
+			})
+			return
+		}
+	}
+
+	// Get historical total value data
+	historicalData, err := h.portfolioService.GetHistoricalPortfolioTotalValue(c.Request.Context(), userID, scope, timeframe, benchmarks)
+	if err != nil {
+		if respondScopeNotFound(c, err) || respondFXRateUnavailable(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to get historical total value data",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    historicalData,
+	})
+}
+
+// GetRealizedGains handles GET /api/v1/portfolio/realized-gains
+func (h *PortfolioHandler) GetRealizedGains(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	scope, ok := portfolioScopeFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// Get year parameter (defaults to the current year)
+	year := time.Now().Year()
+	if yearStr := c.Query("year"); yearStr != "" {
+		parsed, err := strconv.Atoi(yearStr)
+		if err != nil || parsed < 1900 || parsed > 9999 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Invalid year parameter",
+			})
+			return
+		}
+		year = parsed
+	}
+
+	realizedGains, err := h.portfolioService.GetRealizedGains(userID, scope, year)
+	if err != nil {
+		if respondScopeNotFound(c, err) || respondFXRateUnavailable(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to get realized gains",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Realized gains retrieved successfully",
+		"data":    realizedGains,
+	})
+}
+
+// GetDividendIncome handles GET /api/v1/portfolio/dividends
+func (h *PortfolioHandler) GetDividendIncome(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	scope, ok := portfolioScopeFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// Get aggregation parameter (defaults to monthly)
+	aggregation := models.DividendAggregation(c.DefaultQuery("aggregation", string(models.DividendAggregationMonthly)))
+	if aggregation != models.DividendAggregationMonthly && aggregation != models.DividendAggregationYearly {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid aggregation. Supported values: monthly, yearly",
+		})
+		return
+	}
+
+	// Optional date range in YYYY-MM-DD format
+	var startDate, endDate *time.Time
+	if startStr := c.Query("start_date"); startStr != "" {
+		parsed, err := time.Parse("2006-01-02", startStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "start_date must be in YYYY-MM-DD format",
+			})
+			return
+		}
+		startDate = &parsed
+	}
+	if endStr := c.Query("end_date"); endStr != "" {
+		parsed, err := time.Parse("2006-01-02", endStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "end_date must be in YYYY-MM-DD format",
+			})
+			return
+		}
+		// Include the whole end day
+		parsed = parsed.Add(24*time.Hour - time.Nanosecond)
+		endDate = &parsed
+	}
+	if startDate != nil && endDate != nil && startDate.After(*endDate) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "start_date must be before end_date",
+		})
+		return
 	}
 
-	isValidTimeframe := false
-	for _, vt := range validTimeframes {
-		if timeframe == vt {
-			isValidTimeframe = true
-			break
+	dividends, err := h.portfolioService.GetDividendIncome(c.Request.Context(), userID, scope, aggregation, startDate, endDate)
+	if err != nil {
+		if respondScopeNotFound(c, err) || respondFXRateUnavailable(c, err) {
+			return
 		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to get dividend income",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Dividend income retrieved successfully",
+		"data":    dividends,
+	})
+}
+
+// GetPortfolioRisk handles GET /api/v1/portfolio/risk
+// Optional ?benchmark=SPY sets the symbol beta is measured against and
+// ?risk_free_rate=4.5 the annual risk-free rate in %
+func (h *PortfolioHandler) GetPortfolioRisk(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	scope, ok := portfolioScopeFromRequest(c)
+	if !ok {
+		return
+	}
+
+	// Get timeframe parameter (required)
+	timeframeStr := c.Query("timeframe")
+	if timeframeStr == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "timeframe parameter is required",
+		})
+		return
 	}
 
-	if !isValidTimeframe {
+	timeframe := models.TimeFrame(timeframeStr)
+	if !timeframe.IsValid() {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
 			"message": "Invalid timeframe. Supported values: 1D, 1W, 1M, 3M, 6M, YTD, 1Y, 5Y, ALL",
@@ -190,21 +485,525 @@ func (h *PortfolioHandler) GetHistoricalPortfolioTotalValue(c *gin.Context) {
 		return
 	}
 
-	// Get historical total value data
-	historicalData, err := h.portfolioService.GetHistoricalPortfolioTotalValue(c.Request.Context(), userID, timeframe)
+	benchmark := strings.ToUpper(strings.TrimSpace(c.DefaultQuery("benchmark", constants.DefaultRiskBenchmark)))
+	if !utils.SymbolRegex.MatchString(benchmark) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": fmt.Sprintf("Invalid benchmark symbol %q", benchmark),
+		})
+		return
+	}
+
+	riskFreeRate := constants.DefaultRiskFreeRate
+	if rateStr := c.Query("risk_free_rate"); rateStr != "" {
+		parsed, err := strconv.ParseFloat(rateStr, 64)
+		if err != nil || math.IsNaN(parsed) || parsed < -100 || parsed > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "risk_free_rate must be an annual percentage between -100 and 100",
+			})
+			return
+		}
+		riskFreeRate = parsed
+	}
+
+	risk, err := h.portfolioService.GetPortfolioRisk(c.Request.Context(), userID, scope, timeframe, benchmark, riskFreeRate)
 	if err != nil {
+		if respondScopeNotFound(c, err) || respondFXRateUnavailable(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
-			"message": "Failed to get historical total value data",
-			"error":   err.Error(),
+			"message": "Failed to get portfolio risk metrics",
 		})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"data":    historicalData,
+		"message": "Portfolio risk metrics retrieved successfully",
+		"data":    risk,
+	})
+}
+
+// GetAllocation handles GET /api/v1/portfolio/allocation
+// ?group_by= one of sector, exchange, currency, broker, country or account
+func (h *PortfolioHandler) GetAllocation(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	scope, ok := portfolioScopeFromRequest(c)
+	if !ok {
+		return
+	}
+
+	groupBy := models.AllocationGroupBy(strings.ToLower(strings.TrimSpace(c.Query("group_by"))))
+	if !groupBy.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid group_by. Supported values: sector, exchange, currency, broker, country, account",
+		})
+		return
+	}
+
+	allocation, err := h.portfolioService.GetAllocation(c.Request.Context(), userID, scope, groupBy)
+	if err != nil {
+		if respondScopeNotFound(c, err) || respondFXRateUnavailable(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to get portfolio allocation",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Portfolio allocation retrieved successfully",
+		"data":    allocation,
+	})
+}
+
+// TargetAllocationRequest represents the target weight (%) of one symbol or asset class
+type TargetAllocationRequest struct {
+	Key    string  `json:"key" binding:"required"`
+	Weight float64 `json:"weight"`
+}
+
+// UpdateTargetAllocationsRequest represents the request body for replacing the target allocation.
+// The weights left unassigned are targeted as cash.
+type UpdateTargetAllocationsRequest struct {
+	TargetType models.TargetType         `json:"target_type" binding:"required"`
+	Targets    []TargetAllocationRequest `json:"targets"`
+}
+
+// GetTargetAllocations handles GET /api/v1/portfolio/targets
+func (h *PortfolioHandler) GetTargetAllocations(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	targets, err := h.portfolioService.GetTargetAllocations(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to get target allocations",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"targets": targets},
+	})
+}
+
+// UpdateTargetAllocations handles PUT /api/v1/portfolio/targets
+func (h *PortfolioHandler) UpdateTargetAllocations(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateTargetAllocationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format",
+		})
+		return
+	}
+
+	targets := make([]models.TargetAllocation, 0, len(req.Targets))
+	for _, target := range req.Targets {
+		targets = append(targets, models.TargetAllocation{
+			TargetKey: target.Key,
+			Weight:    target.Weight,
+		})
+	}
+
+	updated, err := h.portfolioService.UpdateTargetAllocations(userID, req.TargetType, targets)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid target allocation") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to update target allocations",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Target allocations updated successfully",
+		"data":    gin.H{"targets": updated},
+	})
+}
+
+// GetRebalancePlan handles GET /api/v1/portfolio/rebalance
+// ?no_sells=true only buys, ?min_trade= is the smallest order in the base currency and
+// ?cash= overrides the cash available to invest
+func (h *PortfolioHandler) GetRebalancePlan(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	scope, ok := portfolioScopeFromRequest(c)
+	if !ok {
+		return
+	}
+
+	var options services.RebalanceOptions
+	if noSellsStr := c.Query("no_sells"); noSellsStr != "" {
+		noSells, err := strconv.ParseBool(noSellsStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "no_sells must be true or false",
+			})
+			return
+		}
+		options.NoSells = noSells
+	}
+
+	if minTradeStr := c.Query("min_trade"); minTradeStr != "" {
+		minTrade, err := strconv.ParseFloat(minTradeStr, 64)
+		if err != nil || math.IsNaN(minTrade) || math.IsInf(minTrade, 0) || minTrade < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "min_trade must be a non-negative amount",
+			})
+			return
+		}
+		options.MinTradeValue = minTrade
+	}
+
+	if cashStr := c.Query("cash"); cashStr != "" {
+		cash, err := strconv.ParseFloat(cashStr, 64)
+		if err != nil || math.IsNaN(cash) || math.IsInf(cash, 0) || cash < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "cash must be a non-negative amount",
+			})
+			return
+		}
+		options.Cash = &cash
+	}
+
+	plan, err := h.portfolioService.GetRebalancePlan(c.Request.Context(), userID, scope, options)
+	if err != nil {
+		if respondScopeNotFound(c, err) || respondFXRateUnavailable(c, err) {
+			return
+		}
+		if strings.Contains(err.Error(), "invalid rebalance options") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to plan portfolio rebalance",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Rebalance plan calculated successfully",
+		"data":    plan,
+	})
+}
+
+// UpdatePortfolioSettingsRequest represents the request body for updating portfolio settings
+// Omitted fields are left unchanged
+type UpdatePortfolioSettingsRequest struct {
+	CostBasisMethod models.CostBasisMethod `json:"cost_basis_method"`
+	BaseCurrency    string                 `json:"base_currency"`
+}
+
+// LotSelectionRequest represents a single lot chosen for a sale
+type LotSelectionRequest struct {
+	BuyTransactionID string  `json:"buy_transaction_id" binding:"required"`
+	Quantity         float64 `json:"quantity" binding:"required,gt=0"`
+}
+
+// UpdateLotSelectionsRequest represents the request body for choosing the lots of a sale
+type UpdateLotSelectionsRequest struct {
+	Lots []LotSelectionRequest `json:"lots"`
+}
+
+// GetSettings handles GET /api/v1/portfolio/settings
+func (h *PortfolioHandler) GetSettings(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	settings, err := h.portfolioService.GetSettings(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to get portfolio settings",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    settings,
+	})
+}
+
+// UpdateSettings handles PUT /api/v1/portfolio/settings
+func (h *PortfolioHandler) UpdateSettings(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req UpdatePortfolioSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format",
+		})
+		return
+	}
+
+	if req.CostBasisMethod == "" && req.BaseCurrency == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "At least one of cost_basis_method or base_currency is required",
+		})
+		return
+	}
+
+	if req.CostBasisMethod != "" && !req.CostBasisMethod.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid cost_basis_method. Supported values: average, fifo, lifo, hifo, specific_lot",
+		})
+		return
+	}
+
+	req.BaseCurrency = strings.ToUpper(strings.TrimSpace(req.BaseCurrency))
+	if req.BaseCurrency != "" && !utils.CurrencyRegex.MatchString(req.BaseCurrency) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid base_currency. Must be a 3-letter ISO 4217 currency code",
+		})
+		return
+	}
+
+	settings, err := h.portfolioService.UpdateSettings(userID, models.PortfolioSettings{
+		CostBasisMethod: req.CostBasisMethod,
+		BaseCurrency:    req.BaseCurrency,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to update portfolio settings",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Portfolio settings updated successfully",
+		"data":    settings,
+	})
+}
+
+// GetLotSelections handles GET /api/v1/portfolio/lot-selections/{transaction_id}
+func (h *PortfolioHandler) GetLotSelections(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	transactionID, err := uuid.Parse(c.Param("transaction_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid transaction ID format",
+		})
+		return
+	}
+
+	selections, err := h.portfolioService.GetLotSelections(userID, transactionID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"message": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to get lot selections",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"lots": selections},
+	})
+}
+
+// UpdateLotSelections handles PUT /api/v1/portfolio/lot-selections/{transaction_id}
+func (h *PortfolioHandler) UpdateLotSelections(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	transactionID, err := uuid.Parse(c.Param("transaction_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid transaction ID format",
+		})
+		return
+	}
+
+	var req UpdateLotSelectionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format",
+		})
+		return
+	}
+
+	selections := make([]models.LotSelection, 0, len(req.Lots))
+	for _, lot := range req.Lots {
+		buyTransactionID, err := uuid.Parse(lot.BuyTransactionID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Invalid buy_transaction_id format: " + lot.BuyTransactionID,
+			})
+			return
+		}
+		selections = append(selections, models.LotSelection{
+			BuyTransactionID: buyTransactionID,
+			Quantity:         lot.Quantity,
+		})
+	}
+
+	updated, err := h.portfolioService.UpdateLotSelections(userID, transactionID, selections)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid lot selection") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": err.Error(),
+			})
+			return
+		}
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"message": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to update lot selections",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Lot selections updated successfully",
+		"data":    gin.H{"lots": updated},
+	})
+}
+
+// allPortfoliosID stands for the aggregate of all portfolios in the /portfolios/{id} endpoints
+const allPortfoliosID = "all"
+
+// portfolioScopeFromRequest reads the portfolio the request is about, from the {id} of the
+// /portfolios/{id} endpoints or the optional ?portfolio_id= filter, along with the optional
+// ?account_id= filter. It responds with 400 when either is not a valid ID.
+func portfolioScopeFromRequest(c *gin.Context) (services.PortfolioScope, bool) {
+	var scope services.PortfolioScope
+
+	portfolioIDStr := c.Param("id")
+	if portfolioIDStr == "" {
+		portfolioIDStr = c.Query("portfolio_id")
+	}
+	if portfolioIDStr != "" && portfolioIDStr != allPortfoliosID {
+		portfolioID, err := uuid.Parse(portfolioIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Invalid portfolio ID format",
+			})
+			return scope, false
+		}
+		scope.PortfolioID = &portfolioID
+	}
+
+	if accountIDStr := c.Query("account_id"); accountIDStr != "" {
+		accountID, err := uuid.Parse(accountIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Invalid account_id format",
+			})
+			return scope, false
+		}
+		scope.AccountID = &accountID
+	}
+	return scope, true
+}
+
+// respondScopeNotFound responds with 404 when err is about an account or portfolio the user does not have
+func respondScopeNotFound(c *gin.Context, err error) bool {
+	switch {
+	case strings.Contains(err.Error(), "account not found"):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Account does not exist",
+		})
+	case strings.Contains(err.Error(), "portfolio not found"):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Portfolio does not exist",
+		})
+	default:
+		return false
+	}
+	return true
+}
+
+// respondFXRateUnavailable responds with 422 when err is about a transaction in a currency that has
+// no rate into the base currency on its trade date, naming both
+func respondFXRateUnavailable(c *gin.Context, err error) bool {
+	if !errors.Is(err, services.ErrFXRateUnavailable) {
+		return false
+	}
+	c.JSON(http.StatusUnprocessableEntity, gin.H{
+		"success": false,
+		"message": err.Error(),
 	})
+	return true
 }
 
 // getUserIDFromContext extracts and validates user_id from gin.Context
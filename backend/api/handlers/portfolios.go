@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/models"
+)
+
+// PortfolioRequest represents the request body for creating or updating a named portfolio
+type PortfolioRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+// toModel converts the request into a portfolio model
+func (r PortfolioRequest) toModel() models.Portfolio {
+	return models.Portfolio{
+		Name:        r.Name,
+		Description: r.Description,
+	}
+}
+
+// ListPortfolios handles GET /api/v1/portfolios
+func (h *PortfolioHandler) ListPortfolios(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	portfolios, err := h.portfolioService.ListPortfolios(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to get portfolios",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"portfolios": portfolios},
+	})
+}
+
+// GetPortfolio handles GET /api/v1/portfolios/{id}
+func (h *PortfolioHandler) GetPortfolio(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid portfolio ID format",
+		})
+		return
+	}
+
+	portfolio, err := h.portfolioService.GetPortfolio(userID, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Portfolio does not exist",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"portfolio": portfolio},
+	})
+}
+
+// CreatePortfolio handles POST /api/v1/portfolios
+func (h *PortfolioHandler) CreatePortfolio(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req PortfolioRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format",
+		})
+		return
+	}
+
+	created, err := h.portfolioService.CreatePortfolio(userID, req.toModel())
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid portfolio") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to create portfolio",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Portfolio created successfully",
+		"data":    gin.H{"portfolio": created},
+	})
+}
+
+// UpdatePortfolio handles PUT /api/v1/portfolios/{id}
+func (h *PortfolioHandler) UpdatePortfolio(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid portfolio ID format",
+		})
+		return
+	}
+
+	var req PortfolioRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format",
+		})
+		return
+	}
+
+	updated, err := h.portfolioService.UpdatePortfolio(userID, id, req.toModel())
+	if err != nil {
+		if err.Error() == "not_found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"message": "Portfolio does not exist",
+			})
+			return
+		}
+		if strings.Contains(err.Error(), "invalid portfolio") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to update portfolio",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Portfolio updated successfully",
+		"data":    gin.H{"portfolio": updated},
+	})
+}
+
+// DeletePortfolio handles DELETE /api/v1/portfolios/{id}
+func (h *PortfolioHandler) DeletePortfolio(c *gin.Context) {
+	userID, ok := getUserIDFromContext(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid portfolio ID format",
+		})
+		return
+	}
+
+	if err := h.portfolioService.DeletePortfolio(userID, id); err != nil {
+		if err.Error() == "not_found" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"message": "Portfolio does not exist",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to delete portfolio",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Portfolio deleted successfully",
+	})
+}
@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"context"
+	"sync"
+
 	"github.com/transaction-tracker/backend/config"
 	"github.com/transaction-tracker/backend/internal/ai"
+	"github.com/transaction-tracker/backend/internal/constants"
 	"github.com/transaction-tracker/backend/internal/provider"
 	"github.com/transaction-tracker/backend/internal/repositories"
 	"github.com/transaction-tracker/backend/internal/services"
@@ -14,18 +18,54 @@ type Handlers struct {
 	ExtractTransactionsHandler *ExtractTransactionHandler
 	Auth                       *AuthHandler
 	Portfolio                  *PortfolioHandler
+	CorporateActions           *CorporateActionHandler
+	SymbolMetadata             *SymbolMetadataHandler
+	OptionContracts            *OptionContractHandler
+	Accounts                   *AccountHandler
+
+	// backgroundJobs run until the context passed to RunBackgroundJobs is done
+	backgroundJobs []func(ctx context.Context)
 }
 
 // InitHandlers wires up all dependencies and returns a Handlers struct
 func InitHandlers(db *gorm.DB, cfg *config.Config) *Handlers {
 	transactionRepo := repositories.NewTransactionRepository(db)
-	transactionService := services.NewTransactionService(transactionRepo)
+	accountRepo := repositories.NewAccountRepository(db)
+	portfolioRepo := repositories.NewPortfolioRepository(db)
+	transactionService := services.NewTransactionService(transactionRepo, accountRepo, portfolioRepo)
+	accountService := services.NewAccountService(accountRepo, transactionRepo, portfolioRepo)
 
 	// Initialize Price Service Manager
 	priceServiceManager := provider.NewPriceServiceManager(cfg)
 
 	// Initialize Portfolio Service
-	portfolioService := services.NewPortfolioService(transactionRepo, priceServiceManager)
+	userRepo := repositories.NewUserRepository(db)
+	lotSelectionRepo := repositories.NewLotSelectionRepository(db)
+	taxLotRepo := repositories.NewTaxLotRepository(db)
+	corporateActionRepo := repositories.NewCorporateActionRepository(db)
+	optionContractRepo := repositories.NewOptionContractRepository(db)
+	snapshotRepo := repositories.NewPortfolioSnapshotRepository(db)
+	symbolMetadataRepo := repositories.NewSymbolMetadataRepository(db)
+	targetRepo := repositories.NewTargetAllocationRepository(db)
+	symbolReferenceService := services.NewSymbolReferenceService(symbolMetadataRepo, priceServiceManager)
+	portfolioService := services.NewPortfolioService(transactionRepo, userRepo, lotSelectionRepo, taxLotRepo, corporateActionRepo, optionContractRepo, snapshotRepo, targetRepo, accountRepo, portfolioRepo, symbolReferenceService, priceServiceManager)
+	corporateActionService := services.NewCorporateActionService(corporateActionRepo, transactionRepo)
+	optionContractService := services.NewOptionContractService(optionContractRepo, transactionRepo)
+
+	// Reject transactions in symbols the Price Service symbol master does not know
+	transactionService.AddValidator(symbolReferenceService)
+
+	// Reject trades that oversell a position instead of being recorded as short sales,
+	// and option trades that do not fit the contract they trade
+	transactionService.AddValidator(portfolioService)
+
+	// Keep persisted portfolio ledgers in sync with transaction and corporate action changes.
+	// Corporate action and option contract changes may affect many users, so their ledgers are
+	// rebuilt in the background.
+	changeQueue := services.NewChangeQueue(portfolioService)
+	transactionService.AddChangeListener(portfolioService)
+	corporateActionService.AddChangeListener(changeQueue)
+	optionContractService.AddChangeListener(changeQueue)
 
 	// Initialize AI client once for reuse
 	aiClient, err := ai.NewClient(cfg)
@@ -38,5 +78,30 @@ func InitHandlers(db *gorm.DB, cfg *config.Config) *Handlers {
 		ExtractTransactionsHandler: NewExtractTransactionsHandler(cfg, aiClient),
 		Auth:                       NewAuthHandler(db, cfg),
 		Portfolio:                  NewPortfolioHandler(portfolioService),
+		CorporateActions:           NewCorporateActionHandler(corporateActionService),
+		SymbolMetadata:             NewSymbolMetadataHandler(services.NewSymbolMetadataService(symbolMetadataRepo)),
+		OptionContracts:            NewOptionContractHandler(optionContractService),
+		Accounts:                   NewAccountHandler(accountService),
+		backgroundJobs: []func(ctx context.Context){
+			changeQueue.Run,
+			portfolioService.RunSnapshotRecomputes,
+			// Keep daily portfolio snapshots backfilled for every user
+			func(ctx context.Context) {
+				portfolioService.RunSnapshotBackfill(ctx, constants.SnapshotBackfillInterval)
+			},
+		},
+	}
+}
+
+// RunBackgroundJobs runs the jobs the handlers rely on until ctx is done
+func (h *Handlers) RunBackgroundJobs(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, job := range h.backgroundJobs {
+		wg.Add(1)
+		go func(job func(ctx context.Context)) {
+			defer wg.Done()
+			job(ctx)
+		}(job)
 	}
+	wg.Wait()
 }
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/services"
+)
+
+// SymbolMetadataHandler handles symbol metadata endpoints
+type SymbolMetadataHandler struct {
+	symbolMetadataService *services.SymbolMetadataService
+}
+
+// NewSymbolMetadataHandler creates a new symbol metadata handler
+func NewSymbolMetadataHandler(symbolMetadataService *services.SymbolMetadataService) *SymbolMetadataHandler {
+	return &SymbolMetadataHandler{
+		symbolMetadataService: symbolMetadataService,
+	}
+}
+
+// SymbolMetadataRequest represents the request body for recording a symbol's metadata
+type SymbolMetadataRequest struct {
+	Name       string `json:"name"`
+	Sector     string `json:"sector"`
+	Industry   string `json:"industry"`
+	Country    string `json:"country"`
+	AssetClass string `json:"asset_class"`
+}
+
+// ListSymbolMetadata handles GET /api/v1/symbol-metadata
+func (h *SymbolMetadataHandler) ListSymbolMetadata(c *gin.Context) {
+	metadata, err := h.symbolMetadataService.ListSymbolMetadata()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to get symbol metadata",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"symbol_metadata": metadata},
+	})
+}
+
+// UpsertSymbolMetadata handles PUT /api/v1/symbol-metadata/{symbol}
+func (h *SymbolMetadataHandler) UpsertSymbolMetadata(c *gin.Context) {
+	var req SymbolMetadataRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request format",
+		})
+		return
+	}
+
+	metadata, err := h.symbolMetadataService.UpsertSymbolMetadata(models.SymbolMetadata{
+		Symbol:     c.Param("symbol"),
+		Name:       req.Name,
+		Sector:     req.Sector,
+		Industry:   req.Industry,
+		Country:    req.Country,
+		AssetClass: req.AssetClass,
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid symbol metadata") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to save symbol metadata",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Symbol metadata saved successfully",
+		"data":    gin.H{"symbol_metadata": metadata},
+	})
+}
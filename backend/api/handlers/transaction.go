@@ -8,6 +8,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/constants"
 	"github.com/transaction-tracker/backend/internal/models"
 	"github.com/transaction-tracker/backend/internal/services"
 	"github.com/transaction-tracker/backend/internal/types"
@@ -26,16 +27,64 @@ type TransactionsHandler struct {
 
 // TransactionRequest represents the request structure for creating transactions
 type TransactionRequest struct {
-	Symbol    string          `json:"symbol" binding:"required"`
-	Exchange  string          `json:"exchange"`
-	Broker    string          `json:"broker"`
-	Currency  string          `json:"currency" binding:"required"`
-	TradeDate string          `json:"transaction_date" binding:"required"`
-	TradeType types.TradeType `json:"trade_type" binding:"required"`
-	Quantity  float64         `json:"quantity" binding:"required,gt=0"`
-	Price     float64         `json:"price" binding:"required,gt=0"`
-	Amount    float64         `json:"amount" binding:"required,gt=0"`
-	UserNotes string          `json:"user_notes"`
+	Symbol      string          `json:"symbol"`
+	Exchange    string          `json:"exchange"`
+	Broker      string          `json:"broker"`
+	AccountID   string          `json:"account_id"`
+	PortfolioID string          `json:"portfolio_id"`
+	Currency    string          `json:"currency" binding:"required"`
+	TradeDate   string          `json:"transaction_date" binding:"required"`
+	TradeType   types.TradeType `json:"trade_type" binding:"required"`
+	Quantity    float64         `json:"quantity" binding:"gte=0"`
+	Price       float64         `json:"price" binding:"gte=0"`
+	Amount      float64         `json:"amount" binding:"gte=0"`
+	UserNotes   string          `json:"user_notes"`
+
+	// Fees and taxes are checked in validateTransaction, which reports them like the other fields
+	Commission     float64 `json:"commission"`
+	Fee            float64 `json:"fee"`
+	Tax            float64 `json:"tax"`
+	WithholdingTax float64 `json:"withholding_tax"`
+}
+
+// costs returns the fees and taxes of the request
+func (r TransactionRequest) costs() models.TransactionCosts {
+	return models.TransactionCosts{
+		Commission:     r.Commission,
+		Fee:            r.Fee,
+		Tax:            r.Tax,
+		WithholdingTax: r.WithholdingTax,
+	}
+}
+
+// accountID returns the account the request records the transaction in, or nil when it names none
+func (r TransactionRequest) accountID() (*uuid.UUID, error) {
+	return parseOptionalUUID(r.AccountID)
+}
+
+// portfolioID returns the portfolio the request assigns the transaction to, or nil when it names none
+func (r TransactionRequest) portfolioID() (*uuid.UUID, error) {
+	return parseOptionalUUID(r.PortfolioID)
+}
+
+// parseOptionalUUID parses an optional ID, returning nil when it is empty
+func parseOptionalUUID(value string) (*uuid.UUID, error) {
+	if value == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// symbol returns the request's symbol; cash transactions without one are recorded under their currency
+func (r TransactionRequest) symbol() string {
+	if r.TradeType.IsCash() && r.Symbol == "" {
+		return strings.ToUpper(r.Currency)
+	}
+	return r.Symbol
 }
 
 // CreateTransactionsRequest represents the batch request for creating transactions
@@ -79,6 +128,7 @@ type FiltersApplied struct {
 	Exchanges  []string `json:"exchanges,omitempty"`  // Support multiple exchanges
 	Brokers    []string `json:"brokers,omitempty"`    // Support multiple brokers
 	Currencies []string `json:"currencies,omitempty"` // Support multiple currencies
+	AccountIDs []string `json:"account_ids,omitempty"`
 	Timeframe  *string  `json:"timeframe,omitempty"`
 }
 
@@ -91,6 +141,7 @@ type TransactionQueryParams struct {
 	Exchanges  []string // Support multiple exchanges
 	Brokers    []string // Support multiple brokers
 	Currencies []string // Support multiple currencies
+	AccountIDs []uuid.UUID
 	StartDate  *time.Time
 	EndDate    *time.Time
 	SortBy     string
@@ -106,14 +157,28 @@ func modelToTransactionData(transaction models.Transaction) types.TransactionDat
 		Quantity:        transaction.Quantity,
 		Price:           transaction.Price,
 		Amount:          transaction.Amount,
+		Commission:      transaction.Commission,
+		Fee:             transaction.Fee,
+		Tax:             transaction.Tax,
+		WithholdingTax:  transaction.WithholdingTax,
 		Currency:        transaction.Currency,
 		Broker:          transaction.Broker,
+		AccountID:       optionalUUIDString(transaction.AccountID),
+		PortfolioID:     optionalUUIDString(transaction.PortfolioID),
 		Exchange:        transaction.Exchange,
 		TransactionDate: transaction.TransactionDate.Format("2006-01-02"),
 		UserNotes:       transaction.UserNotes,
 	}
 }
 
+// optionalUUIDString formats an optional ID, empty when it is nil
+func optionalUUIDString(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
 // modelsToTransactionData converts a slice of models.Transaction to []types.TransactionData
 func modelsToTransactionData(transactions []models.Transaction) []types.TransactionData {
 	if len(transactions) == 0 {
@@ -158,18 +223,33 @@ func (h *TransactionsHandler) CreateTransactions(c *gin.Context) {
 			continue
 		}
 
+		accountID, err := reqTransaction.accountID()
+		if err != nil {
+			validationErrors[fmt.Sprintf("transaction[%d].account_id", i)] = []string{"Invalid account ID format"}
+			continue
+		}
+
+		portfolioID, err := reqTransaction.portfolioID()
+		if err != nil {
+			validationErrors[fmt.Sprintf("transaction[%d].portfolio_id", i)] = []string{"Invalid portfolio ID format"}
+			continue
+		}
+
 		// Convert request transaction to model transaction
 		transaction := models.Transaction{
-			TradeType:       reqTransaction.TradeType,
-			Symbol:          reqTransaction.Symbol,
-			Quantity:        reqTransaction.Quantity,
-			Price:           reqTransaction.Price,
-			Amount:          reqTransaction.Amount,
-			Currency:        reqTransaction.Currency,
-			Broker:          reqTransaction.Broker,
-			Exchange:        reqTransaction.Exchange,
-			TransactionDate: transactionDate,
-			UserNotes:       reqTransaction.UserNotes,
+			TradeType:        reqTransaction.TradeType,
+			Symbol:           reqTransaction.symbol(),
+			Quantity:         reqTransaction.Quantity,
+			Price:            reqTransaction.Price,
+			Amount:           reqTransaction.Amount,
+			TransactionCosts: reqTransaction.costs(),
+			Currency:         reqTransaction.Currency,
+			Broker:           reqTransaction.Broker,
+			AccountID:        accountID,
+			PortfolioID:      portfolioID,
+			Exchange:         reqTransaction.Exchange,
+			TransactionDate:  transactionDate,
+			UserNotes:        reqTransaction.UserNotes,
 		}
 
 		validatedTransactions = append(validatedTransactions, transaction)
@@ -209,6 +289,38 @@ func (h *TransactionsHandler) CreateTransactions(c *gin.Context) {
 	// Create transactions using injected service
 	createdTransactions, err := h.transactionService.CreateTransactions(userUUID, validatedTransactions)
 	if err != nil {
+		if strings.Contains(err.Error(), "invalid account") {
+			c.JSON(http.StatusBadRequest, CreateTransactionsResponse{
+				Success: false,
+				Message: "Validation failed",
+				Errors:  map[string][]string{"account_id": {err.Error()}},
+			})
+			return
+		}
+		if strings.Contains(err.Error(), "invalid portfolio") {
+			c.JSON(http.StatusBadRequest, CreateTransactionsResponse{
+				Success: false,
+				Message: "Validation failed",
+				Errors:  map[string][]string{"portfolio_id": {err.Error()}},
+			})
+			return
+		}
+		if strings.Contains(err.Error(), "invalid position") {
+			c.JSON(http.StatusBadRequest, CreateTransactionsResponse{
+				Success: false,
+				Message: "Validation failed",
+				Errors:  map[string][]string{"quantity": {err.Error()}},
+			})
+			return
+		}
+		if strings.Contains(err.Error(), "invalid symbol") || strings.Contains(err.Error(), "invalid option") {
+			c.JSON(http.StatusBadRequest, CreateTransactionsResponse{
+				Success: false,
+				Message: "Validation failed",
+				Errors:  map[string][]string{"symbol": {err.Error()}},
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, CreateTransactionsResponse{
 			Success: false,
 			Message: "Failed to create transactions",
@@ -272,6 +384,7 @@ func (h *TransactionsHandler) GetTransactionHistory(c *gin.Context) {
 		Exchanges:      params.Exchanges,
 		Brokers:        params.Brokers,
 		Currencies:     params.Currencies,
+		AccountIDs:     params.AccountIDs,
 		StartDate:      params.StartDate,
 		EndDate:        params.EndDate,
 		OrderBy:        params.SortBy,
@@ -317,6 +430,9 @@ func (h *TransactionsHandler) GetTransactionHistory(c *gin.Context) {
 		Brokers:    params.Brokers,
 		Currencies: params.Currencies,
 	}
+	for _, accountID := range params.AccountIDs {
+		filtersApplied.AccountIDs = append(filtersApplied.AccountIDs, accountID.String())
+	}
 
 	// Add timeframe if dates were provided
 	if params.StartDate != nil || params.EndDate != nil {
@@ -376,6 +492,18 @@ func (h *TransactionsHandler) UpdateTransaction(c *gin.Context) {
 		return
 	}
 
+	accountID, err := req.accountID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Validation failed", "errors": map[string][]string{"account_id": {"Invalid account ID format"}}})
+		return
+	}
+
+	portfolioID, err := req.portfolioID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Validation failed", "errors": map[string][]string{"portfolio_id": {"Invalid portfolio ID format"}}})
+		return
+	}
+
 	userID, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "Unauthorized", "errors": map[string][]string{"auth": {"User not authenticated"}}})
@@ -390,7 +518,9 @@ func (h *TransactionsHandler) UpdateTransaction(c *gin.Context) {
 	updated, err := h.transactionService.UpdateTransaction(
 		userUUID,
 		transactionID,
-		req.Symbol,
+		accountID,
+		portfolioID,
+		req.symbol(),
 		req.Exchange,
 		req.Broker,
 		req.Currency,
@@ -399,6 +529,7 @@ func (h *TransactionsHandler) UpdateTransaction(c *gin.Context) {
 		req.Quantity,
 		req.Price,
 		req.Amount,
+		req.costs(),
 		req.UserNotes,
 	)
 	if err != nil {
@@ -410,6 +541,22 @@ func (h *TransactionsHandler) UpdateTransaction(c *gin.Context) {
 			c.JSON(http.StatusForbidden, gin.H{"success": false, "message": "Not the owner"})
 			return
 		default:
+			if strings.Contains(err.Error(), "invalid account") {
+				c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Validation failed", "errors": map[string][]string{"account_id": {err.Error()}}})
+				return
+			}
+			if strings.Contains(err.Error(), "invalid portfolio") {
+				c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Validation failed", "errors": map[string][]string{"portfolio_id": {err.Error()}}})
+				return
+			}
+			if strings.Contains(err.Error(), "invalid position") {
+				c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Validation failed", "errors": map[string][]string{"quantity": {err.Error()}}})
+				return
+			}
+			if strings.Contains(err.Error(), "invalid symbol") || strings.Contains(err.Error(), "invalid option") {
+				c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Validation failed", "errors": map[string][]string{"symbol": {err.Error()}}})
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Failed to update transaction"})
 			return
 		}
@@ -424,14 +571,26 @@ func (h *TransactionsHandler) UpdateTransaction(c *gin.Context) {
 
 // validateTransaction validates a single transaction request
 func validateTransaction(transaction TransactionRequest) error {
-	// Validate symbol length
-	if len(transaction.Symbol) == 0 || len(transaction.Symbol) > 10 {
-		return fmt.Errorf("symbol must be between 1 and 10 characters")
-	}
+	symbol := transaction.symbol()
+	if transaction.TradeType.IsOption() {
+		// Options are traded under their OCC symbol
+		if !utils.OptionSymbolRegex.MatchString(symbol) {
+			return fmt.Errorf("symbol of an option trade must be an OCC option symbol (e.g. AAPL240119C00150000)")
+		}
+	} else {
+		// Validate symbol length; crypto pairs such as BTC-USD may run longer
+		maxLength := 10
+		if utils.CryptoSymbolRegex.MatchString(symbol) {
+			maxLength = 15
+		}
+		if len(symbol) == 0 || len(symbol) > maxLength {
+			return fmt.Errorf("symbol must be between 1 and %d characters", maxLength)
+		}
 
-	// Validate symbol format (alphanumeric uppercase, allow dot for e.g. BRK.B)
-	if !utils.SymbolRegex.MatchString(transaction.Symbol) {
-		return fmt.Errorf("symbol must contain only uppercase letters, numbers, and optionally a single dot (e.g. BRK.B)")
+		// Validate symbol format (alphanumeric uppercase, allow dot for e.g. BRK.B, or a crypto pair)
+		if !utils.SymbolRegex.MatchString(symbol) {
+			return fmt.Errorf("symbol must contain only uppercase letters, numbers, and optionally a single dot (e.g. BRK.B), or be a crypto pair (e.g. BTC-USD)")
+		}
 	}
 
 	// Validate currency
@@ -440,35 +599,55 @@ func validateTransaction(transaction TransactionRequest) error {
 	}
 
 	// Validate trade type
-	validTradeTypes := map[types.TradeType]bool{
-		types.TradeTypeBuy:      true,
-		types.TradeTypeSell:     true,
-		types.TradeTypeDividend: true,
-	}
-	if !validTradeTypes[transaction.TradeType] {
-		return fmt.Errorf("trade_type must be one of: Buy, Sell, Dividends")
+	if !constants.ValidTradeTypesMap()[string(transaction.TradeType)] {
+		return fmt.Errorf("trade_type must be one of: %s", strings.Join(constants.ValidTradeTypes(), ", "))
 	}
 
-	// Validate quantities and amounts
-	if transaction.TradeType != types.TradeTypeDividend {
+	// Cash transactions only carry an amount; dividends also need the shares and rate they were paid on.
+	// Option expirations and assignments only carry the contracts they close.
+	if transaction.TradeType.IsOptionEvent() {
+		if transaction.Quantity <= 0 {
+			return fmt.Errorf("quantity must be positive")
+		}
+		if transaction.Price != 0 || transaction.Amount != 0 {
+			return fmt.Errorf("price and amount must be 0 for %s and %s; shares delivered on assignment are priced at the strike",
+				types.TradeTypeExpiration, types.TradeTypeAssignment)
+		}
+	} else if !transaction.TradeType.IsCash() {
 		if transaction.Quantity <= 0 {
 			return fmt.Errorf("quantity must be positive")
 		}
 		if transaction.Price <= 0 {
 			return fmt.Errorf("price must be positive")
 		}
-		if transaction.Amount <= 0 {
-			return fmt.Errorf("amount must be positive")
-		}
+	}
 
-		// Validate trade amount calculation (with tolerance for rounding)
+	// Validate quantities and amounts; option expirations and assignments were checked to carry none
+	if !transaction.TradeType.IsOptionEvent() && transaction.Amount <= 0 {
+		return fmt.Errorf("amount must be positive")
+	}
+	if transaction.TradeType.IsTrade() {
+		// Validate trade amount calculation (with tolerance for rounding); option amounts
+		// also depend on the contract multiplier, which the contract is checked against
 		expectedAmount := transaction.Quantity * transaction.Price
 		tolerance := 0.1
-		if utils.Abs(transaction.Amount-expectedAmount) > tolerance {
+		if !transaction.TradeType.IsOption() && utils.Abs(transaction.Amount-expectedAmount) > tolerance {
 			return fmt.Errorf("amount does not match quantity × price calculation")
 		}
 	}
 
+	// Validate fees and taxes
+	costs := transaction.costs()
+	if costs.Commission < 0 || costs.Fee < 0 || costs.Tax < 0 || costs.WithholdingTax < 0 {
+		return fmt.Errorf("commission, fee, tax and withholding_tax cannot be negative")
+	}
+	if transaction.TradeType != types.TradeTypeDividend && costs.WithholdingTax > 0 {
+		return fmt.Errorf("withholding_tax only applies to dividends")
+	}
+	if !transaction.TradeType.IsOutflow() && costs.Total() > transaction.Amount {
+		return fmt.Errorf("fees and taxes cannot exceed the amount received")
+	}
+
 	// Validate date format and range
 	tradeDate, err := time.Parse("2006-01-02", transaction.TradeDate)
 	if err != nil {
@@ -521,8 +700,8 @@ func parseTransactionQueryParams(c *gin.Context) (params TransactionQueryParams,
 
 		for _, symbol := range symbols {
 			symbol = strings.TrimSpace(symbol)
-			if !utils.SymbolRegex.MatchString(symbol) {
-				validationErrors["symbol"] = []string{"Each symbol must be alphanumeric uppercase, 1-10 characters"}
+			if !utils.SymbolRegex.MatchString(symbol) && !utils.OptionSymbolRegex.MatchString(symbol) {
+				validationErrors["symbol"] = []string{"Each symbol must be alphanumeric uppercase, 1-10 characters, a crypto pair or an OCC option symbol"}
 				break
 			} else {
 				validSymbols = append(validSymbols, symbol)
@@ -542,7 +721,7 @@ func parseTransactionQueryParams(c *gin.Context) (params TransactionQueryParams,
 		for _, tradeType := range typeList {
 			tradeType = strings.TrimSpace(tradeType)
 			if _, ok := utils.TradeTypeFromString(tradeType); !ok {
-				validationErrors["trade_type"] = []string{"Must be one of: Buy, Sell, Dividends (comma-separated for multiple)"}
+				validationErrors["trade_type"] = []string{"Must be one of: " + strings.Join(constants.ValidTradeTypes(), ", ") + " (comma-separated for multiple)"}
 				break
 			} else {
 				validTypes = append(validTypes, tradeType)
@@ -588,6 +767,24 @@ func parseTransactionQueryParams(c *gin.Context) (params TransactionQueryParams,
 		}
 	}
 
+	// Parse accounts (comma-separated)
+	if accountsParam := c.Query("account_id"); accountsParam != "" {
+		var validAccountIDs []uuid.UUID
+
+		for _, accountID := range strings.Split(accountsParam, ",") {
+			id, err := uuid.Parse(strings.TrimSpace(accountID))
+			if err != nil {
+				validationErrors["account_id"] = []string{"Must be valid account IDs (comma-separated for multiple)"}
+				break
+			}
+			validAccountIDs = append(validAccountIDs, id)
+		}
+
+		if len(validAccountIDs) > 0 && len(validationErrors) == 0 {
+			params.AccountIDs = validAccountIDs
+		}
+	}
+
 	// Parse currencies (comma-separated)
 	if currenciesParam := c.Query("currency"); currenciesParam != "" {
 		currencies := strings.Split(currenciesParam, ",")
@@ -745,6 +942,14 @@ func (h *TransactionsHandler) DeleteTransaction(c *gin.Context) {
 
 	err = h.transactionService.DeleteTransaction(userUUID, transactionID)
 	if err != nil {
+		if strings.Contains(err.Error(), "invalid position") {
+			c.JSON(http.StatusBadRequest, DeleteTransactionResponse{
+				Success: false,
+				Message: "Validation failed",
+				Errors:  map[string][]string{"id": {err.Error()}},
+			})
+			return
+		}
 		switch err.Error() {
 		case "not_found":
 			c.JSON(http.StatusNotFound, DeleteTransactionResponse{
@@ -876,6 +1081,14 @@ func (h *TransactionsHandler) handleBatchDeletionByBody(c *gin.Context, idString
 
 	deletedIDs, err := h.transactionService.DeleteTransactions(userUUID, transactionIDs)
 	if err != nil {
+		if strings.Contains(err.Error(), "invalid position") {
+			c.JSON(http.StatusBadRequest, DeleteTransactionResponse{
+				Success: false,
+				Message: "Validation failed",
+				Errors:  map[string][]string{"ids": {err.Error()}},
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, DeleteTransactionResponse{
 			Success: false,
 			Message: "Failed to delete transactions",
@@ -0,0 +1,35 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/constants"
+	"github.com/transaction-tracker/backend/internal/repositories"
+)
+
+// AdminMiddleware returns a middleware that only lets admin users through. It runs after
+// AuthMiddleware, which puts the authenticated user's ID in the context.
+func AdminMiddleware(userRepo repositories.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, exists := c.Get("user_id")
+		userID, ok := value.(uuid.UUID)
+		if !exists || !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": constants.ErrMsgUnauthorized,
+			})
+			return
+		}
+
+		user, err := userRepo.FindByUserID(userID)
+		if err != nil || !user.IsAdmin {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": constants.ErrMsgForbidden,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -10,10 +10,12 @@ import (
 	"github.com/transaction-tracker/backend/config"
 	"github.com/transaction-tracker/backend/internal/constants"
 	"github.com/transaction-tracker/backend/internal/database"
+	"github.com/transaction-tracker/backend/internal/repositories"
 )
 
-// SetupRouter configures the API routes
-func SetupRouter(cfg *config.Config) *gin.Engine {
+// SetupRouter configures the API routes. The returned handlers' background jobs are left for the
+// caller to run.
+func SetupRouter(cfg *config.Config) (*gin.Engine, *handlers.Handlers) {
 	r := gin.Default()
 
 	// Configure CORS
@@ -34,6 +36,7 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 	}
 
 	handlersProvider := handlers.InitHandlers(dm.GetDB(), cfg)
+	requireAdmin := middlewares.AdminMiddleware(repositories.NewUserRepository(dm.GetDB()))
 
 	// Public routes (no authentication required)
 	publicApi := r.Group(constants.APIVersion)
@@ -67,8 +70,51 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 		api.GET(constants.PortfolioSummaryEndpoint, handlersProvider.Portfolio.GetPortfolioSummary)
 		api.GET(constants.PortfolioHoldingsEndpoint, handlersProvider.Portfolio.GetAllHoldings)
 		api.GET(constants.PortfolioSingleHoldingEndpoint, handlersProvider.Portfolio.GetSingleHoldingBasicInfo)
+		api.GET(constants.PortfolioHoldingChartEndpoint, handlersProvider.Portfolio.GetHoldingChart)
 		api.GET(constants.PortfolioHistoricalMarketValueEndpoint, handlersProvider.Portfolio.GetHistoricalPortfolioTotalValue)
+		api.GET(constants.PortfolioSettingsEndpoint, handlersProvider.Portfolio.GetSettings)
+		api.PUT(constants.PortfolioSettingsEndpoint, handlersProvider.Portfolio.UpdateSettings)
+		api.GET(constants.PortfolioLotSelectionsEndpoint, handlersProvider.Portfolio.GetLotSelections)
+		api.PUT(constants.PortfolioLotSelectionsEndpoint, handlersProvider.Portfolio.UpdateLotSelections)
+		api.GET(constants.PortfolioRealizedGainsEndpoint, handlersProvider.Portfolio.GetRealizedGains)
+		api.GET(constants.PortfolioDividendsEndpoint, handlersProvider.Portfolio.GetDividendIncome)
+		api.GET(constants.PortfolioRiskEndpoint, handlersProvider.Portfolio.GetPortfolioRisk)
+		api.GET(constants.PortfolioAllocationEndpoint, handlersProvider.Portfolio.GetAllocation)
+		api.GET(constants.PortfolioTargetsEndpoint, handlersProvider.Portfolio.GetTargetAllocations)
+		api.PUT(constants.PortfolioTargetsEndpoint, handlersProvider.Portfolio.UpdateTargetAllocations)
+		api.GET(constants.PortfolioRebalanceEndpoint, handlersProvider.Portfolio.GetRebalancePlan)
+
+		// Named portfolio routes; the "all" ID stands for the aggregate of all portfolios
+		api.GET(constants.PortfoliosEndpoint, handlersProvider.Portfolio.ListPortfolios)
+		api.POST(constants.PortfoliosEndpoint, handlersProvider.Portfolio.CreatePortfolio)
+		api.GET(constants.PortfolioByIDEndpoint, handlersProvider.Portfolio.GetPortfolio)
+		api.PUT(constants.PortfolioByIDEndpoint, handlersProvider.Portfolio.UpdatePortfolio)
+		api.DELETE(constants.PortfolioByIDEndpoint, handlersProvider.Portfolio.DeletePortfolio)
+		api.GET(constants.PortfolioByIDSummaryEndpoint, handlersProvider.Portfolio.GetPortfolioSummary)
+		api.GET(constants.PortfolioByIDHoldingsEndpoint, handlersProvider.Portfolio.GetAllHoldings)
+		api.GET(constants.PortfolioByIDHistoricalMarketValueEndpoint, handlersProvider.Portfolio.GetHistoricalPortfolioTotalValue)
+
+		// Corporate action routes; actions apply to every user's holdings, so only admins modify them
+		api.GET(constants.CorporateActionsEndpoint, handlersProvider.CorporateActions.ListCorporateActions)
+		api.POST(constants.CorporateActionsEndpoint, requireAdmin, handlersProvider.CorporateActions.CreateCorporateAction)
+		api.PUT(constants.CorporateActionsEndpoint+"/:id", requireAdmin, handlersProvider.CorporateActions.UpdateCorporateAction)
+		api.DELETE(constants.CorporateActionsEndpoint+"/:id", requireAdmin, handlersProvider.CorporateActions.DeleteCorporateAction)
+
+		// Symbol metadata routes; metadata is shared by every user, so only admins modify it
+		api.GET(constants.SymbolMetadataEndpoint, handlersProvider.SymbolMetadata.ListSymbolMetadata)
+		api.PUT(constants.SymbolMetadataEndpoint+"/:symbol", requireAdmin, handlersProvider.SymbolMetadata.UpsertSymbolMetadata)
+
+		// Option contract routes; contracts value every user's positions, so only admins modify them
+		api.GET(constants.OptionContractsEndpoint, handlersProvider.OptionContracts.ListOptionContracts)
+		api.POST(constants.OptionContractsEndpoint, requireAdmin, handlersProvider.OptionContracts.UpsertOptionContract)
+
+		// Account routes
+		api.GET(constants.AccountsEndpoint, handlersProvider.Accounts.ListAccounts)
+		api.POST(constants.AccountsEndpoint, handlersProvider.Accounts.CreateAccount)
+		api.GET(constants.AccountsEndpoint+"/:id", handlersProvider.Accounts.GetAccount)
+		api.PUT(constants.AccountsEndpoint+"/:id", handlersProvider.Accounts.UpdateAccount)
+		api.DELETE(constants.AccountsEndpoint+"/:id", handlersProvider.Accounts.DeleteAccount)
 	}
 
-	return r
+	return r, handlersProvider
 }
@@ -300,6 +300,7 @@ func (c *AIModelClient) getMockResponse(filename string) *types.ExtractResponse
 			Quantity:        quantity,
 			Price:           price,
 			Amount:          quantity * price,
+			Commission:      float64(rand.Intn(1000)) / 100.0, // $0-10
 			Currency:        "USD",
 			Broker:          "Mock Broker",
 			Exchange:        "NASDAQ",
@@ -1,6 +1,10 @@
 package constants
 
-import "github.com/transaction-tracker/backend/internal/types"
+import (
+	"time"
+
+	"github.com/transaction-tracker/backend/internal/types"
+)
 
 // Default Configuration
 const (
@@ -31,7 +35,45 @@ const (
 	PortfolioSummaryEndpoint               = "/portfolio/summary"
 	PortfolioHoldingsEndpoint              = "/portfolio/holdings"
 	PortfolioSingleHoldingEndpoint         = "/portfolio/holdings/:symbol"
+	PortfolioHoldingChartEndpoint          = "/portfolio/holdings/:symbol/chart"
 	PortfolioHistoricalMarketValueEndpoint = "/portfolio/chart/historical-market-value"
+	PortfolioSettingsEndpoint              = "/portfolio/settings"
+	PortfolioLotSelectionsEndpoint         = "/portfolio/lot-selections/:transaction_id"
+	PortfolioRealizedGainsEndpoint         = "/portfolio/realized-gains"
+	PortfolioDividendsEndpoint             = "/portfolio/dividends"
+	PortfolioRiskEndpoint                  = "/portfolio/risk"
+	PortfolioAllocationEndpoint            = "/portfolio/allocation"
+	PortfolioTargetsEndpoint               = "/portfolio/targets"
+	PortfolioRebalanceEndpoint             = "/portfolio/rebalance"
+)
+
+// Named Portfolio Endpoints
+const (
+	PortfoliosEndpoint                         = "/portfolios"
+	PortfolioByIDEndpoint                      = "/portfolios/:id"
+	PortfolioByIDSummaryEndpoint               = "/portfolios/:id/summary"
+	PortfolioByIDHoldingsEndpoint              = "/portfolios/:id/holdings"
+	PortfolioByIDHistoricalMarketValueEndpoint = "/portfolios/:id/chart/historical-market-value"
+)
+
+// Corporate Action Endpoints
+const (
+	CorporateActionsEndpoint = "/corporate-actions"
+)
+
+// Symbol Metadata Endpoints
+const (
+	SymbolMetadataEndpoint = "/symbol-metadata"
+)
+
+// Option Contract Endpoints
+const (
+	OptionContractsEndpoint = "/option-contracts"
+)
+
+// Account Endpoints
+const (
+	AccountsEndpoint = "/accounts"
 )
 
 // HTTP Headers
@@ -98,21 +140,77 @@ const (
 	MaxFilesPerBatch = 10
 )
 
+// Portfolio Chart Limits
+const (
+	MaxBenchmarksPerChart   = 5
+	MaxConcurrentValuations = 8
+	// Days of prices fetched before a chart starts, so a close can be carried into its first days
+	PriceHistoryLookbackDays = 10
+)
+
+// Portfolio Risk
+const (
+	TradingDaysPerYear = 252
+	// Annual risk-free rate (%) used when a request does not set one
+	DefaultRiskFreeRate  = 0.0
+	DefaultRiskBenchmark = "SPY"
+)
+
+// Portfolio Snapshots
+const (
+	// Snapshots of the most recent days are provisional: closes may still be missing or change
+	SnapshotProvisionalDays = 3
+	// Provisional snapshots older than this are recomputed when read
+	SnapshotRefreshInterval = 15 * time.Minute
+	// How often every user's snapshots are brought up to date in the background
+	SnapshotBackfillInterval = time.Hour
+)
+
+// Symbol Reference
+const (
+	// Most symbol master entries remembered between lookups
+	MaxKnownSymbols = 5000
+)
+
 // ValidTradeTypes returns a slice of valid trade types
 func ValidTradeTypes() []string {
 	return []string{
 		string(types.TradeTypeBuy),
 		string(types.TradeTypeSell),
 		string(types.TradeTypeDividend),
+		string(types.TradeTypeSellShort),
+		string(types.TradeTypeBuyToCover),
+		string(types.TradeTypeBuyToOpen),
+		string(types.TradeTypeSellToOpen),
+		string(types.TradeTypeBuyToClose),
+		string(types.TradeTypeSellToClose),
+		string(types.TradeTypeExpiration),
+		string(types.TradeTypeAssignment),
+		string(types.TradeTypeDeposit),
+		string(types.TradeTypeWithdrawal),
+		string(types.TradeTypeInterest),
+		string(types.TradeTypeFee),
 	}
 }
 
 // ValidTradeTypesMap returns a map of valid trade types for quick lookup
 func ValidTradeTypesMap() map[string]bool {
 	return map[string]bool{
-		string(types.TradeTypeBuy):      true,
-		string(types.TradeTypeSell):     true,
-		string(types.TradeTypeDividend): true,
+		string(types.TradeTypeBuy):         true,
+		string(types.TradeTypeSell):        true,
+		string(types.TradeTypeDividend):    true,
+		string(types.TradeTypeSellShort):   true,
+		string(types.TradeTypeBuyToCover):  true,
+		string(types.TradeTypeBuyToOpen):   true,
+		string(types.TradeTypeSellToOpen):  true,
+		string(types.TradeTypeBuyToClose):  true,
+		string(types.TradeTypeSellToClose): true,
+		string(types.TradeTypeExpiration):  true,
+		string(types.TradeTypeAssignment):  true,
+		string(types.TradeTypeDeposit):     true,
+		string(types.TradeTypeWithdrawal):  true,
+		string(types.TradeTypeInterest):    true,
+		string(types.TradeTypeFee):         true,
 	}
 }
 
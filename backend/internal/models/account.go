@@ -0,0 +1,91 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AccountType represents the kind of account transactions are kept in
+type AccountType string
+
+const (
+	AccountTypeBrokerage  AccountType = "brokerage"
+	AccountTypeRetirement AccountType = "retirement"
+	AccountTypeEducation  AccountType = "education"
+	AccountTypeOther      AccountType = "other"
+)
+
+// AccountTypes returns every supported account type
+func AccountTypes() []AccountType {
+	return []AccountType{AccountTypeBrokerage, AccountTypeRetirement, AccountTypeEducation, AccountTypeOther}
+}
+
+// IsValid reports whether the type is one of the supported account types
+func (t AccountType) IsValid() bool {
+	for _, accountType := range AccountTypes() {
+		if t == accountType {
+			return true
+		}
+	}
+	return false
+}
+
+// TaxTreatment represents how gains and income in an account are taxed
+type TaxTreatment string
+
+const (
+	TaxTreatmentTaxable     TaxTreatment = "taxable"
+	TaxTreatmentTaxDeferred TaxTreatment = "tax_deferred"
+	TaxTreatmentTaxExempt   TaxTreatment = "tax_exempt"
+)
+
+// TaxTreatments returns every supported tax treatment
+func TaxTreatments() []TaxTreatment {
+	return []TaxTreatment{TaxTreatmentTaxable, TaxTreatmentTaxDeferred, TaxTreatmentTaxExempt}
+}
+
+// IsValid reports whether the treatment is one of the supported tax treatments
+func (t TaxTreatment) IsValid() bool {
+	for _, treatment := range TaxTreatments() {
+		if t == treatment {
+			return true
+		}
+	}
+	return false
+}
+
+// Account represents a brokerage account a user keeps transactions in, such as a taxable
+// account and an IRA held with the same broker. Its transactions belong to the account's
+// portfolio unless they are assigned to one themselves.
+type Account struct {
+	ID           uuid.UUID    `gorm:"type:varchar(36);primaryKey" json:"id"`
+	UserID       uuid.UUID    `gorm:"type:varchar(36);not null;index" json:"user_id"`
+	PortfolioID  *uuid.UUID   `gorm:"type:varchar(36);index" json:"portfolio_id"`
+	Name         string       `gorm:"size:100;not null" json:"name"`
+	Broker       string       `gorm:"size:100" json:"broker"`
+	AccountType  AccountType  `gorm:"size:20;not null" json:"account_type"`
+	BaseCurrency string       `gorm:"size:3;not null;default:'USD'" json:"base_currency"`
+	TaxTreatment TaxTreatment `gorm:"size:20;not null" json:"tax_treatment"`
+	BaseModel
+}
+
+// TableName specifies the table name for Account model
+func (Account) TableName() string {
+	return "accounts"
+}
+
+// BeforeCreate hook for Account model
+func (a *Account) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now()
+	}
+	if a.UpdatedAt.IsZero() {
+		a.UpdatedAt = time.Now()
+	}
+	return nil
+}
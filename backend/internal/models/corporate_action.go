@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CorporateActionType represents the kind of corporate action
+type CorporateActionType string
+
+const (
+	CorporateActionSplit        CorporateActionType = "split"
+	CorporateActionReverseSplit CorporateActionType = "reverse_split"
+	CorporateActionRename       CorporateActionType = "rename"
+	CorporateActionMerger       CorporateActionType = "merger"
+)
+
+// IsValid reports whether the type is one of the supported corporate action types
+func (t CorporateActionType) IsValid() bool {
+	switch t {
+	case CorporateActionSplit, CorporateActionReverseSplit, CorporateActionRename, CorporateActionMerger:
+		return true
+	}
+	return false
+}
+
+// CorporateAction represents a market event that changes the shares or symbol of a holding.
+// RatioFrom old shares become RatioTo new shares, held under NewSymbol when it is set.
+type CorporateAction struct {
+	ID            uuid.UUID           `gorm:"type:varchar(36);primaryKey" json:"id"`
+	Symbol        string              `gorm:"size:20;not null;index" json:"symbol"`
+	ActionType    CorporateActionType `gorm:"column:action_type;size:20;not null" json:"action_type"`
+	EffectiveDate time.Time           `gorm:"not null;index" json:"effective_date"`
+	RatioFrom     float64             `gorm:"type:decimal(15,6);not null;default:1" json:"ratio_from"`
+	RatioTo       float64             `gorm:"type:decimal(15,6);not null;default:1" json:"ratio_to"`
+	NewSymbol     string              `gorm:"size:20" json:"new_symbol"`
+	Notes         string              `gorm:"type:text" json:"notes"`
+	BaseModel
+}
+
+// TableName specifies the table name for CorporateAction model
+func (CorporateAction) TableName() string {
+	return "corporate_actions"
+}
+
+// ShareFactor returns the number of new shares received for each old share
+func (a CorporateAction) ShareFactor() float64 {
+	if a.RatioFrom <= 0 || a.RatioTo <= 0 {
+		return 1
+	}
+	return a.RatioTo / a.RatioFrom
+}
+
+// TargetSymbol returns the symbol the shares are held under after the action
+func (a CorporateAction) TargetSymbol() string {
+	if a.NewSymbol != "" {
+		return a.NewSymbol
+	}
+	return a.Symbol
+}
+
+// BeforeCreate hook for CorporateAction model
+func (a *CorporateAction) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now()
+	}
+	if a.UpdatedAt.IsZero() {
+		a.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+// BeforeUpdate hook for CorporateAction model
+func (a *CorporateAction) BeforeUpdate(tx *gorm.DB) error {
+	a.UpdatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CostBasisMethod represents how sold shares are matched against acquired lots
+type CostBasisMethod string
+
+const (
+	CostBasisAverage     CostBasisMethod = "average"
+	CostBasisFIFO        CostBasisMethod = "fifo"
+	CostBasisLIFO        CostBasisMethod = "lifo"
+	CostBasisHIFO        CostBasisMethod = "hifo"
+	CostBasisSpecificLot CostBasisMethod = "specific_lot"
+)
+
+// DefaultCostBasisMethod is used for users who have not chosen a method
+const DefaultCostBasisMethod = CostBasisAverage
+
+// IsValid reports whether the method is one of the supported cost basis methods
+func (m CostBasisMethod) IsValid() bool {
+	switch m {
+	case CostBasisAverage, CostBasisFIFO, CostBasisLIFO, CostBasisHIFO, CostBasisSpecificLot:
+		return true
+	}
+	return false
+}
+
+// OpenLot represents the unsold remainder of an acquisition. A short sale opens a lot of
+// negative quantity whose unit cost is the proceeds per share, so its cost basis is the
+// negative of the proceeds still owed back.
+type OpenLot struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+	Symbol        string    `json:"symbol"`
+	AcquiredAt    time.Time `json:"acquired_at"`
+	Quantity      float64   `json:"quantity"`
+	UnitCost      float64   `json:"unit_cost"`
+}
+
+// CostBasis returns the total cost of the shares remaining in the lot
+func (l OpenLot) CostBasis() float64 {
+	return l.Quantity * l.UnitCost
+}
+
+// IsShort reports whether the lot was opened by a short sale
+func (l OpenLot) IsShort() bool {
+	return l.Quantity < 0
+}
+
+// ClosedLot represents a portion of a lot that was matched against a sale. For a short lot the
+// buy is the cover and the sell is the short sale, which was made first on AcquiredAt.
+type ClosedLot struct {
+	BuyTransactionID  uuid.UUID `json:"buy_transaction_id"`
+	SellTransactionID uuid.UUID `json:"sell_transaction_id"`
+	Symbol            string    `json:"symbol"`
+	AcquiredAt        time.Time `json:"acquired_at"`
+	DisposedAt        time.Time `json:"disposed_at"`
+	Quantity          float64   `json:"quantity"`
+	CostBasis         float64   `json:"cost_basis"`
+	Proceeds          float64   `json:"proceeds"`
+	Short             bool      `json:"short"`
+}
+
+// GainLoss returns the realized gain or loss of the match
+func (l ClosedLot) GainLoss() float64 {
+	return l.Proceeds - l.CostBasis
+}
+
+// LotSelection records which acquisition lot a sale should consume under the specific lot method
+type LotSelection struct {
+	ID                uuid.UUID `gorm:"type:varchar(36);primaryKey" json:"id"`
+	UserID            uuid.UUID `gorm:"type:varchar(36);not null;index" json:"user_id"`
+	SellTransactionID uuid.UUID `gorm:"type:varchar(36);not null;index" json:"sell_transaction_id"`
+	BuyTransactionID  uuid.UUID `gorm:"type:varchar(36);not null" json:"buy_transaction_id"`
+	Quantity          float64   `gorm:"type:decimal(24,10);not null" json:"quantity"`
+	BaseModel
+}
+
+// TableName specifies the table name for LotSelection model
+func (LotSelection) TableName() string {
+	return "lot_selections"
+}
+
+// BeforeCreate hook for LotSelection model
+func (l *LotSelection) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	if l.CreatedAt.IsZero() {
+		l.CreatedAt = time.Now()
+	}
+	if l.UpdatedAt.IsZero() {
+		l.UpdatedAt = time.Now()
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Portfolio represents a named group of a user's accounts and transactions, such as a long-term
+// core and a trading sandbox, valued on its own
+type Portfolio struct {
+	ID          uuid.UUID `gorm:"type:varchar(36);primaryKey" json:"id"`
+	UserID      uuid.UUID `gorm:"type:varchar(36);not null;index" json:"user_id"`
+	Name        string    `gorm:"size:100;not null" json:"name"`
+	Description string    `gorm:"type:text" json:"description"`
+	BaseModel
+}
+
+// TableName specifies the table name for Portfolio model
+func (Portfolio) TableName() string {
+	return "portfolios"
+}
+
+// BeforeCreate hook for Portfolio model
+func (p *Portfolio) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = time.Now()
+	}
+	if p.UpdatedAt.IsZero() {
+		p.UpdatedAt = time.Now()
+	}
+	return nil
+}
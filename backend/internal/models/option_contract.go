@@ -0,0 +1,106 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OptionType represents whether an option is a call or a put
+type OptionType string
+
+const (
+	OptionTypeCall OptionType = "call"
+	OptionTypePut  OptionType = "put"
+)
+
+// IsValid reports whether the type is a call or a put
+func (t OptionType) IsValid() bool {
+	return t == OptionTypeCall || t == OptionTypePut
+}
+
+// DefaultOptionMultiplier is the number of underlying shares a standard equity option contract covers
+const DefaultOptionMultiplier = 100
+
+// OptionContract describes a listed option. Trades in the contract are recorded under Symbol, the
+// OCC symbol without padding, in contracts; each contract covers Multiplier shares of Underlying.
+// Contracts adjusted after a corporate action keep their symbol but cover a different multiplier.
+type OptionContract struct {
+	ID         uuid.UUID  `gorm:"type:varchar(36);primaryKey" json:"id"`
+	Symbol     string     `gorm:"size:32;not null;uniqueIndex:uk_option_contracts_symbol" json:"symbol"`
+	Underlying string     `gorm:"size:20;not null;index" json:"underlying"`
+	Expiry     time.Time  `gorm:"type:date;not null" json:"expiry"`
+	Strike     float64    `gorm:"type:decimal(15,4);not null" json:"strike"`
+	OptionType OptionType `gorm:"column:option_type;size:4;not null" json:"option_type"`
+	Multiplier float64    `gorm:"type:decimal(15,4);not null;default:100" json:"multiplier"`
+	BaseModel
+}
+
+// TableName specifies the table name for OptionContract model
+func (OptionContract) TableName() string {
+	return "option_contracts"
+}
+
+// OCCSymbol returns the OCC symbol of a standard contract on the underlying
+func (c OptionContract) OCCSymbol() string {
+	right := "C"
+	if c.OptionType == OptionTypePut {
+		right = "P"
+	}
+	return fmt.Sprintf("%s%s%s%08d", c.Underlying, c.Expiry.Format("060102"), right, int64(math.Round(c.Strike*1000)))
+}
+
+// ParseOCCSymbol reads a standard contract from an unpadded OCC symbol such as AAPL240119C00150000,
+// taking the root as the underlying. It reports false when the symbol is not an option symbol.
+func ParseOCCSymbol(symbol string) (OptionContract, bool) {
+	// The root is followed by 15 characters: expiry, right and strike
+	if len(symbol) < 16 {
+		return OptionContract{}, false
+	}
+	root, rest := symbol[:len(symbol)-15], symbol[len(symbol)-15:]
+
+	expiry, err := time.Parse("060102", rest[:6])
+	if err != nil {
+		return OptionContract{}, false
+	}
+	var optionType OptionType
+	switch rest[6] {
+	case 'C':
+		optionType = OptionTypeCall
+	case 'P':
+		optionType = OptionTypePut
+	default:
+		return OptionContract{}, false
+	}
+	strike, err := strconv.ParseUint(rest[7:], 10, 64)
+	if err != nil {
+		return OptionContract{}, false
+	}
+
+	return OptionContract{
+		Symbol:     symbol,
+		Underlying: root,
+		Expiry:     expiry,
+		Strike:     float64(strike) / 1000,
+		OptionType: optionType,
+		Multiplier: DefaultOptionMultiplier,
+	}, true
+}
+
+// BeforeCreate hook for OptionContract model
+func (c *OptionContract) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = time.Now()
+	}
+	if c.UpdatedAt.IsZero() {
+		c.UpdatedAt = time.Now()
+	}
+	return nil
+}
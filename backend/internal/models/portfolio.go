@@ -1,11 +1,30 @@
 package models
 
-import "time"
+import (
+	"time"
 
-// SingleHolding represents basic information about a stock holding
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/types"
+)
+
+// SingleHolding represents basic information about a stock holding.
+// Costs and returns are net of fees and taxes; GrossTotalReturn adds them back.
+// UnitCost and CurrentPrice are quoted in the holding's currency; every other amount is
+// converted into the user's base currency, with FXRate being today's holding-to-base rate.
+// A short position has a negative quantity, cost and market value; its cost is the proceeds
+// of the short sales still open and it gains as the price falls.
+// An option holding is counted in contracts, with UnitCost per contract and CurrentPrice per share;
+// Multiplier is the number of shares a contract covers, and 1 for holdings of shares.
 type SingleHolding struct {
 	Symbol               string  `json:"symbol"`
+	Name                 string  `json:"name"`
+	AssetClass           string  `json:"asset_class"`
+	Sector               string  `json:"sector"`
+	Currency             string  `json:"currency"`
+	FXRate               float64 `json:"fx_rate"`
 	TotalQuantity        float64 `json:"total_quantity"`
+	Multiplier           float64 `json:"multiplier"`
+	Short                bool    `json:"short"`
 	TotalCost            float64 `json:"total_cost"`
 	UnitCost             float64 `json:"unit_cost"`
 	CurrentPrice         float64 `json:"current_price"`
@@ -14,6 +33,15 @@ type SingleHolding struct {
 	AnnualizedReturnRate float64 `json:"annualized_return_rate"`
 	RealizedGainLoss     float64 `json:"realized_gain_loss"`
 	UnrealizedGainLoss   float64 `json:"unrealized_gain_loss"`
+	PriceGainLoss        float64 `json:"price_gain_loss"`
+	FXGainLoss           float64 `json:"fx_gain_loss"`
+	DividendIncome       float64 `json:"dividend_income"`
+	DividendYield        float64 `json:"dividend_yield"`
+	YieldOnCost          float64 `json:"yield_on_cost"`
+	TotalReturn          float64 `json:"total_return"`
+	TotalFees            float64 `json:"total_fees"`
+	TotalTaxes           float64 `json:"total_taxes"`
+	GrossTotalReturn     float64 `json:"gross_total_return"`
 }
 
 // SingleHoldingResponse represents the response structure for stock basic info
@@ -30,18 +58,52 @@ type AllHoldingsResponse struct {
 
 // PortfolioSummary represents the overall portfolio summary
 type PortfolioSummary struct {
-	Timestamp             time.Time `json:"timestamp"`
-	Currency              string    `json:"currency"`
-	MarketValue           float64   `json:"market_value"`
-	TotalCost             float64   `json:"total_cost"`
-	TotalReturn           float64   `json:"total_return"`
-	TotalReturnPercentage float64   `json:"total_return_percentage"`
-	HoldingsCount         int       `json:"holdings_count"`
-	HasTransactions       bool      `json:"has_transactions"`
-	AnnualizedReturnRate  float64   `json:"annualized_return_rate"`
-	LastUpdated           time.Time `json:"last_updated"`
+	Timestamp             time.Time       `json:"timestamp"`
+	Currency              string          `json:"currency"`
+	MarketValue           float64         `json:"market_value"`
+	TotalCost             float64         `json:"total_cost"`
+	TotalReturn           float64         `json:"total_return"`
+	TotalReturnPercentage float64         `json:"total_return_percentage"`
+	GrossTotalReturn      float64         `json:"gross_total_return"`
+	TotalFees             float64         `json:"total_fees"`
+	TotalTaxes            float64         `json:"total_taxes"`
+	HoldingsCount         int             `json:"holdings_count"`
+	HasTransactions       bool            `json:"has_transactions"`
+	AnnualizedReturnRate  float64         `json:"annualized_return_rate"`
+	PriceGainLoss         float64         `json:"price_gain_loss"`
+	FXGainLoss            float64         `json:"fx_gain_loss"`
+	DividendIncome        float64         `json:"dividend_income"`
+	DividendYield         float64         `json:"dividend_yield"`
+	YieldOnCost           float64         `json:"yield_on_cost"`
+	CashBalance           float64         `json:"cash_balance"`
+	CashBalances          []CashBalance   `json:"cash_balances"`
+	InterestIncome        float64         `json:"interest_income"`
+	CostBasisMethod       CostBasisMethod `json:"cost_basis_method"`
+	LastUpdated           time.Time       `json:"last_updated"`
+
+	// TimeWeightedReturns holds the time-weighted return (%) over each timeframe up to now
+	TimeWeightedReturns map[TimeFrame]float64 `json:"time_weighted_returns"`
+}
+
+// CashBalance represents the uninvested cash held with one broker in one currency.
+// Balance is in Currency; Value is the balance converted into the portfolio's base currency.
+type CashBalance struct {
+	Broker    string     `json:"broker"`
+	AccountID *uuid.UUID `json:"account_id,omitempty"`
+	Currency  string     `json:"currency"`
+	Balance   float64    `json:"balance"`
+	Value     float64    `json:"value"`
+}
+
+// PortfolioSettings represents user-level preferences for portfolio calculations
+type PortfolioSettings struct {
+	CostBasisMethod CostBasisMethod `json:"cost_basis_method"`
+	BaseCurrency    string          `json:"base_currency"`
 }
 
+// DefaultBaseCurrency is used for users who have not chosen a base currency
+const DefaultBaseCurrency = "USD"
+
 // PortfolioAnalysisType represents the type of analysis requested
 type PortfolioAnalysisType string
 
@@ -65,6 +127,25 @@ const (
 	TimeFrameALL TimeFrame = "ALL"
 )
 
+// TimeFrames returns every supported timeframe, shortest first
+func TimeFrames() []TimeFrame {
+	return []TimeFrame{
+		TimeFrame1D, TimeFrame1W, TimeFrame1M,
+		TimeFrame3M, TimeFrame6M, TimeFrameYTD,
+		TimeFrame1Y, TimeFrame5Y, TimeFrameALL,
+	}
+}
+
+// IsValid reports whether the timeframe is one of the supported timeframes
+func (t TimeFrame) IsValid() bool {
+	for _, timeframe := range TimeFrames() {
+		if t == timeframe {
+			return true
+		}
+	}
+	return false
+}
+
 // Granularity represents data point frequency
 type Granularity string
 
@@ -79,8 +160,14 @@ const (
 type TotalValueDataPoint struct {
 	Timestamp        time.Time `json:"timestamp"`
 	TotalValue       float64   `json:"market_value"`
+	CostBasis        float64   `json:"cost_basis"`
+	FXGainLoss       float64   `json:"fx_gain_loss"`
+	DividendIncome   float64   `json:"dividend_income"`
+	CashBalance      float64   `json:"cash_balance"`
 	DayChange        float64   `json:"day_change"`
 	DayChangePercent float64   `json:"day_change_percent"`
+	// TimeWeightedReturn is the cumulative time-weighted return (%) since the start of the period
+	TimeWeightedReturn float64 `json:"time_weighted_return"`
 }
 
 // TotalValueTrendSummary represents summary statistics for the time period
@@ -90,16 +177,245 @@ type TotalValueTrendSummary struct {
 	Volatility    float64 `json:"volatility"`
 	MaxValue      float64 `json:"max_value"`
 	MinValue      float64 `json:"min_value"`
+	// TimeWeightedReturn (%) removes the effect of cash flows, unlike ChangePercent
+	TimeWeightedReturn float64 `json:"time_weighted_return"`
 }
 
 // HistoricalTotalValueResponse represents the complete response for historical data
 type HistoricalTotalValueResponse struct {
-	TimeFrame   TimeFrame   `json:"timeframe"`
-	Granularity Granularity `json:"granularity"`
-	Period      struct {
+	TimeFrame       TimeFrame       `json:"timeframe"`
+	Granularity     Granularity     `json:"granularity"`
+	Currency        string          `json:"currency"`
+	CostBasisMethod CostBasisMethod `json:"cost_basis_method"`
+	Period          struct {
 		StartDate time.Time `json:"start_date"`
 		EndDate   time.Time `json:"end_date"`
 	} `json:"period"`
 	DataPoints []TotalValueDataPoint  `json:"data_points"`
 	Summary    TotalValueTrendSummary `json:"summary"`
+	Benchmarks []BenchmarkSeries      `json:"benchmarks,omitempty"`
+}
+
+// BenchmarkDataPoint represents a benchmark's value at one of the chart's timestamps
+type BenchmarkDataPoint struct {
+	Timestamp          time.Time `json:"timestamp"`
+	Value              float64   `json:"market_value"`
+	TimeWeightedReturn float64   `json:"time_weighted_return"`
+}
+
+// BenchmarkSeries shows what the portfolio would be worth had its value at the start of the
+// period and every later cash flow gone into the benchmark instead
+type BenchmarkSeries struct {
+	Symbol     string                 `json:"symbol"`
+	DataPoints []BenchmarkDataPoint   `json:"data_points"`
+	Summary    TotalValueTrendSummary `json:"summary"`
+	// ExcessReturn is the portfolio's time-weighted return minus the benchmark's, in percentage points
+	ExcessReturn float64 `json:"excess_return"`
+}
+
+// HoldingChartDataPoint represents a single holding at one of the chart's timestamps.
+// Price is the close in the holding's currency; every other amount is in the base currency.
+// RealizedGainLoss and DividendIncome are cumulative since the first transaction.
+type HoldingChartDataPoint struct {
+	Timestamp          time.Time `json:"timestamp"`
+	Quantity           float64   `json:"quantity"`
+	Price              float64   `json:"price"`
+	CostBasis          float64   `json:"cost_basis"`
+	MarketValue        float64   `json:"market_value"`
+	UnrealizedGainLoss float64   `json:"unrealized_gain_loss"`
+	RealizedGainLoss   float64   `json:"realized_gain_loss"`
+	DividendIncome     float64   `json:"dividend_income"`
+}
+
+// HoldingChartMarker marks a buy, sell or dividend of the holding on its chart.
+// Quantity, price and amount are as recorded, in the transaction's currency.
+type HoldingChartMarker struct {
+	TransactionID uuid.UUID       `json:"transaction_id"`
+	Date          time.Time       `json:"date"`
+	TradeType     types.TradeType `json:"trade_type"`
+	Symbol        string          `json:"symbol"`
+	Quantity      float64         `json:"quantity"`
+	Price         float64         `json:"price"`
+	Amount        float64         `json:"amount"`
+	Currency      string          `json:"currency"`
+}
+
+// HoldingChartResponse represents a single holding's history over a timeframe
+type HoldingChartResponse struct {
+	Symbol          string          `json:"symbol"`
+	TimeFrame       TimeFrame       `json:"timeframe"`
+	Granularity     Granularity     `json:"granularity"`
+	Currency        string          `json:"currency"`
+	CostBasisMethod CostBasisMethod `json:"cost_basis_method"`
+	Period          struct {
+		StartDate time.Time `json:"start_date"`
+		EndDate   time.Time `json:"end_date"`
+	} `json:"period"`
+	DataPoints []HoldingChartDataPoint `json:"data_points"`
+	Markers    []HoldingChartMarker    `json:"markers"`
+}
+
+// BenchmarkRisk measures how the portfolio's daily returns move with a benchmark's
+type BenchmarkRisk struct {
+	Symbol      string  `json:"symbol"`
+	Beta        float64 `json:"beta"`
+	Correlation float64 `json:"correlation"`
+}
+
+// PortfolioRiskResponse represents the risk metrics of the portfolio's daily time-weighted
+// returns over a timeframe. Rates and ratios are annualized; percentages are in %.
+type PortfolioRiskResponse struct {
+	TimeFrame TimeFrame `json:"timeframe"`
+	Currency  string    `json:"currency"`
+	Period    struct {
+		StartDate time.Time `json:"start_date"`
+		EndDate   time.Time `json:"end_date"`
+	} `json:"period"`
+	// Observations is the number of daily returns the metrics are computed from
+	Observations         int     `json:"observations"`
+	RiskFreeRate         float64 `json:"risk_free_rate"`
+	AnnualizedReturn     float64 `json:"annualized_return"`
+	AnnualizedVolatility float64 `json:"annualized_volatility"`
+	SharpeRatio          float64 `json:"sharpe_ratio"`
+	SortinoRatio         float64 `json:"sortino_ratio"`
+	// MaxDrawdown is the largest fall (negative, %) from a peak to a later trough
+	MaxDrawdown        float64        `json:"max_drawdown"`
+	DrawdownPeakDate   *time.Time     `json:"drawdown_peak_date,omitempty"`
+	DrawdownTroughDate *time.Time     `json:"drawdown_trough_date,omitempty"`
+	Benchmark          *BenchmarkRisk `json:"benchmark,omitempty"`
+}
+
+// AllocationGroupBy represents the dimension holdings are grouped by in an allocation breakdown
+type AllocationGroupBy string
+
+const (
+	AllocationGroupBySector   AllocationGroupBy = "sector"
+	AllocationGroupByExchange AllocationGroupBy = "exchange"
+	AllocationGroupByCurrency AllocationGroupBy = "currency"
+	AllocationGroupByBroker   AllocationGroupBy = "broker"
+	AllocationGroupByCountry  AllocationGroupBy = "country"
+	AllocationGroupByAccount  AllocationGroupBy = "account"
+)
+
+// AllocationGroupBys returns every supported allocation dimension
+func AllocationGroupBys() []AllocationGroupBy {
+	return []AllocationGroupBy{
+		AllocationGroupBySector, AllocationGroupByExchange, AllocationGroupByCurrency,
+		AllocationGroupByBroker, AllocationGroupByCountry, AllocationGroupByAccount,
+	}
+}
+
+// IsValid reports whether the dimension is one of the supported allocation dimensions
+func (g AllocationGroupBy) IsValid() bool {
+	for _, groupBy := range AllocationGroupBys() {
+		if g == groupBy {
+			return true
+		}
+	}
+	return false
+}
+
+// AllocationGroup represents the part of the portfolio in one group, valued in the base currency.
+// Weight is the group's share (%) of the portfolio's total value.
+type AllocationGroup struct {
+	Key         string   `json:"key"`
+	MarketValue float64  `json:"market_value"`
+	Weight      float64  `json:"weight"`
+	Symbols     []string `json:"symbols"`
+}
+
+// AllocationResponse represents the portfolio's holdings and cash broken down by one dimension,
+// largest group first
+type AllocationResponse struct {
+	GroupBy    AllocationGroupBy `json:"group_by"`
+	Currency   string            `json:"currency"`
+	TotalValue float64           `json:"total_value"`
+	Groups     []AllocationGroup `json:"groups"`
+	Timestamp  time.Time         `json:"timestamp"`
+}
+
+// RebalanceDrift represents how far one target (a symbol, an asset class or cash) is from its
+// target weight. Drift is the current weight minus the target weight, in percentage points;
+// TradeValue is the amount (in the base currency) that would bring it back to target.
+type RebalanceDrift struct {
+	Key           string  `json:"key"`
+	CurrentValue  float64 `json:"current_value"`
+	CurrentWeight float64 `json:"current_weight"`
+	TargetWeight  float64 `json:"target_weight"`
+	Drift         float64 `json:"drift"`
+	TradeValue    float64 `json:"trade_value"`
+}
+
+// RebalanceOrder represents a proposed trade in whole shares at the current price.
+// Price is in the symbol's currency; EstimatedAmount is in the base currency.
+type RebalanceOrder struct {
+	Symbol          string          `json:"symbol"`
+	Action          types.TradeType `json:"action"`
+	Quantity        float64         `json:"quantity"`
+	Price           float64         `json:"price"`
+	Currency        string          `json:"currency"`
+	EstimatedAmount float64         `json:"estimated_amount"`
+}
+
+// RebalancePlanResponse represents the drift of the portfolio from its target allocation and the
+// orders proposed to return to it. Sells are listed before the buys they fund.
+type RebalancePlanResponse struct {
+	TargetType    TargetType       `json:"target_type"`
+	Currency      string           `json:"currency"`
+	TotalValue    float64          `json:"total_value"`
+	Cash          float64          `json:"cash"`
+	CashAfter     float64          `json:"cash_after"`
+	NoSells       bool             `json:"no_sells"`
+	MinTradeValue float64          `json:"min_trade_value"`
+	Drifts        []RebalanceDrift `json:"drifts"`
+	Orders        []RebalanceOrder `json:"orders"`
+	Timestamp     time.Time        `json:"timestamp"`
+}
+
+// RealizedGainsTotals aggregates the disposals of one holding period
+type RealizedGainsTotals struct {
+	Proceeds  float64 `json:"proceeds"`
+	CostBasis float64 `json:"cost_basis"`
+	GainLoss  float64 `json:"gain_loss"`
+	Count     int     `json:"count"`
+}
+
+// RealizedGainsResponse represents the realized gains report for a tax year
+type RealizedGainsResponse struct {
+	Year            int                 `json:"year"`
+	Currency        string              `json:"currency"`
+	CostBasisMethod CostBasisMethod     `json:"cost_basis_method"`
+	Disposals       []LotDisposal       `json:"disposals"`
+	ShortTerm       RealizedGainsTotals `json:"short_term"`
+	LongTerm        RealizedGainsTotals `json:"long_term"`
+	Total           RealizedGainsTotals `json:"total"`
+}
+
+// DividendAggregation represents the period used to group dividend income
+type DividendAggregation string
+
+const (
+	DividendAggregationMonthly DividendAggregation = "monthly"
+	DividendAggregationYearly  DividendAggregation = "yearly"
+)
+
+// DividendIncomePeriod represents the dividend income received in one period.
+// Income and BySymbol are net of withholding tax and fees.
+type DividendIncomePeriod struct {
+	Period         string             `json:"period"`
+	Income         float64            `json:"income"`
+	GrossIncome    float64            `json:"gross_income"`
+	WithholdingTax float64            `json:"withholding_tax"`
+	Payments       int                `json:"payments"`
+	BySymbol       map[string]float64 `json:"by_symbol"`
+}
+
+// DividendIncomeResponse represents aggregated dividend income
+type DividendIncomeResponse struct {
+	Aggregation    DividendAggregation    `json:"aggregation"`
+	Currency       string                 `json:"currency"`
+	TotalIncome    float64                `json:"total_income"`
+	GrossIncome    float64                `json:"gross_income"`
+	WithholdingTax float64                `json:"withholding_tax"`
+	Periods        []DividendIncomePeriod `json:"periods"`
 }
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SnapshotHolding is a position held at the end of a snapshot day, valued in the base currency
+type SnapshotHolding struct {
+	Symbol      string  `json:"symbol"`
+	Quantity    float64 `json:"quantity"`
+	Price       float64 `json:"price"`
+	MarketValue float64 `json:"market_value"`
+	CostBasis   float64 `json:"cost_basis"`
+}
+
+// SnapshotHoldings is stored as a JSON column
+type SnapshotHoldings []SnapshotHolding
+
+// Value implements driver.Valuer
+func (h SnapshotHoldings) Value() (driver.Value, error) {
+	if h == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(h)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (h *SnapshotHoldings) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*h = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type for snapshot holdings: %T", value)
+	}
+	return json.Unmarshal(data, h)
+}
+
+// PortfolioSnapshot is a user's portfolio at the end of a day, valued in their base currency.
+// Snapshots are derived data: they are rebuilt from a date whenever transactions on or after it change.
+type PortfolioSnapshot struct {
+	ID           uuid.UUID `gorm:"type:varchar(36);primaryKey" json:"id"`
+	UserID       uuid.UUID `gorm:"type:varchar(36);not null;uniqueIndex:uk_portfolio_snapshots_user_date" json:"user_id"`
+	SnapshotDate time.Time `gorm:"type:date;not null;uniqueIndex:uk_portfolio_snapshots_user_date" json:"snapshot_date"`
+	Currency     string    `gorm:"size:3;not null;default:'USD'" json:"currency"`
+	MarketValue  float64   `gorm:"type:decimal(15,4);not null" json:"market_value"`
+	CostBasis    float64   `gorm:"type:decimal(15,4);not null" json:"cost_basis"`
+	CashBalance  float64   `gorm:"type:decimal(15,4);not null" json:"cash_balance"`
+	// FXGainLoss is the part of the unrealized gain caused by exchange rates moving since purchase
+	FXGainLoss float64 `gorm:"type:decimal(15,4);not null" json:"fx_gain_loss"`
+	// DividendIncome is the cumulative dividend cash received up to the end of the day
+	DividendIncome float64          `gorm:"type:decimal(15,4);not null" json:"dividend_income"`
+	Holdings       SnapshotHoldings `gorm:"type:json" json:"holdings"`
+	BaseModel
+}
+
+// TableName specifies the table name for PortfolioSnapshot model
+func (PortfolioSnapshot) TableName() string {
+	return "portfolio_snapshots"
+}
+
+// BeforeCreate hook for PortfolioSnapshot model
+func (p *PortfolioSnapshot) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = time.Now()
+	}
+	if p.UpdatedAt.IsZero() {
+		p.UpdatedAt = time.Now()
+	}
+	return nil
+}
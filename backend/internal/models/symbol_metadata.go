@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AssetClassCrypto is the asset class of crypto pairs such as BTC-USD, which they fall in without metadata
+const AssetClassCrypto = "crypto"
+
+// SymbolMetadata describes the company or fund behind a symbol. Country is an ISO 3166-1 alpha-2 code;
+// AssetClass is a lowercase class such as equity, bond or commodity.
+type SymbolMetadata struct {
+	ID         uuid.UUID `gorm:"type:varchar(36);primaryKey" json:"id"`
+	Symbol     string    `gorm:"size:20;not null;uniqueIndex:uk_symbol_metadata_symbol" json:"symbol"`
+	Name       string    `gorm:"size:255" json:"name"`
+	Sector     string    `gorm:"size:100" json:"sector"`
+	Industry   string    `gorm:"size:100" json:"industry"`
+	Country    string    `gorm:"size:2" json:"country"`
+	AssetClass string    `gorm:"size:50" json:"asset_class"`
+	BaseModel
+}
+
+// TableName specifies the table name for SymbolMetadata model
+func (SymbolMetadata) TableName() string {
+	return "symbol_metadata"
+}
+
+// BeforeCreate hook for SymbolMetadata model
+func (m *SymbolMetadata) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = time.Now()
+	}
+	if m.UpdatedAt.IsZero() {
+		m.UpdatedAt = time.Now()
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TargetType represents what a target allocation weight applies to
+type TargetType string
+
+const (
+	TargetTypeSymbol     TargetType = "symbol"
+	TargetTypeAssetClass TargetType = "asset_class"
+)
+
+// IsValid reports whether the type is one of the supported target types
+func (t TargetType) IsValid() bool {
+	return t == TargetTypeSymbol || t == TargetTypeAssetClass
+}
+
+// TargetAllocation is the weight (%) of the portfolio a user aims to hold in a symbol or an
+// asset class. A user's targets are all of one type; weights left unassigned are held as cash.
+type TargetAllocation struct {
+	ID         uuid.UUID  `gorm:"type:varchar(36);primaryKey" json:"id"`
+	UserID     uuid.UUID  `gorm:"type:varchar(36);not null;uniqueIndex:uk_target_allocations_user_key" json:"user_id"`
+	TargetType TargetType `gorm:"size:20;not null;uniqueIndex:uk_target_allocations_user_key" json:"target_type"`
+	TargetKey  string     `gorm:"size:100;not null;uniqueIndex:uk_target_allocations_user_key" json:"target_key"`
+	Weight     float64    `gorm:"type:decimal(7,4);not null" json:"weight"`
+	BaseModel
+}
+
+// TableName specifies the table name for TargetAllocation model
+func (TargetAllocation) TableName() string {
+	return "target_allocations"
+}
+
+// BeforeCreate hook for TargetAllocation model
+func (t *TargetAllocation) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	if t.CreatedAt.IsZero() {
+		t.CreatedAt = time.Now()
+	}
+	if t.UpdatedAt.IsZero() {
+		t.UpdatedAt = time.Now()
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// HoldingPeriod classifies a disposal for tax purposes
+type HoldingPeriod string
+
+const (
+	HoldingPeriodShortTerm HoldingPeriod = "short_term"
+	HoldingPeriodLongTerm  HoldingPeriod = "long_term"
+)
+
+// HoldingPeriodFor returns long term when the shares were held for more than one year
+func HoldingPeriodFor(acquiredAt, disposedAt time.Time) HoldingPeriod {
+	if disposedAt.After(acquiredAt.AddDate(1, 0, 0)) {
+		return HoldingPeriodLongTerm
+	}
+	return HoldingPeriodShortTerm
+}
+
+// TaxLot represents the shares acquired by a single buy transaction, or sold by a single short
+// sale. A short lot is keyed by its short sale and its cost basis is the proceeds of the sale.
+type TaxLot struct {
+	ID                 uuid.UUID `gorm:"type:varchar(36);primaryKey" json:"id"`
+	UserID             uuid.UUID `gorm:"type:varchar(36);not null;index" json:"user_id"`
+	BuyTransactionID   uuid.UUID `gorm:"type:varchar(36);not null;index" json:"buy_transaction_id"`
+	Symbol             string    `gorm:"size:32;not null;index" json:"symbol"`
+	Short              bool      `gorm:"not null;default:false" json:"short"`
+	AcquiredAt         time.Time `gorm:"not null" json:"acquired_at"`
+	Quantity           float64   `gorm:"type:decimal(24,10);not null" json:"quantity"`
+	CostBasis          float64   `gorm:"type:decimal(15,4);not null" json:"cost_basis"`
+	RemainingQuantity  float64   `gorm:"type:decimal(24,10);not null" json:"remaining_quantity"`
+	RemainingCostBasis float64   `gorm:"type:decimal(15,4);not null" json:"remaining_cost_basis"`
+	Currency           string    `gorm:"size:3;not null;default:'USD'" json:"currency"`
+	BaseModel
+}
+
+// TableName specifies the table name for TaxLot model
+func (TaxLot) TableName() string {
+	return "tax_lots"
+}
+
+// BeforeCreate hook for TaxLot model
+func (l *TaxLot) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	if l.CreatedAt.IsZero() {
+		l.CreatedAt = time.Now()
+	}
+	if l.UpdatedAt.IsZero() {
+		l.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+// LotDisposal records the part of a tax lot consumed by a sell transaction. A short lot is
+// consumed by a buy to cover, recorded as the buy, and its short sale is the sell.
+type LotDisposal struct {
+	ID                uuid.UUID     `gorm:"type:varchar(36);primaryKey" json:"id"`
+	UserID            uuid.UUID     `gorm:"type:varchar(36);not null;index" json:"user_id"`
+	TaxLotID          uuid.UUID     `gorm:"type:varchar(36);not null;index" json:"tax_lot_id"`
+	BuyTransactionID  uuid.UUID     `gorm:"type:varchar(36);not null" json:"buy_transaction_id"`
+	SellTransactionID uuid.UUID     `gorm:"type:varchar(36);not null;index" json:"sell_transaction_id"`
+	Symbol            string        `gorm:"size:32;not null" json:"symbol"`
+	Short             bool          `gorm:"not null;default:false" json:"short"`
+	AcquiredAt        time.Time     `gorm:"not null" json:"acquired_at"`
+	DisposedAt        time.Time     `gorm:"not null;index" json:"disposed_at"`
+	Quantity          float64       `gorm:"type:decimal(24,10);not null" json:"quantity"`
+	Proceeds          float64       `gorm:"type:decimal(15,4);not null" json:"proceeds"`
+	CostBasis         float64       `gorm:"type:decimal(15,4);not null" json:"cost_basis"`
+	GainLoss          float64       `gorm:"type:decimal(15,4);not null" json:"gain_loss"`
+	HoldingPeriod     HoldingPeriod `gorm:"size:20;not null" json:"holding_period"`
+	HoldingDays       int           `gorm:"not null" json:"holding_days"`
+	Currency          string        `gorm:"size:3;not null;default:'USD'" json:"currency"`
+	BaseModel
+}
+
+// TableName specifies the table name for LotDisposal model
+func (LotDisposal) TableName() string {
+	return "lot_disposals"
+}
+
+// BeforeCreate hook for LotDisposal model
+func (d *LotDisposal) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	if d.CreatedAt.IsZero() {
+		d.CreatedAt = time.Now()
+	}
+	if d.UpdatedAt.IsZero() {
+		d.UpdatedAt = time.Now()
+	}
+	return nil
+}
@@ -13,21 +13,64 @@ type Transaction struct {
 	TransactionID   uuid.UUID       `gorm:"type:varchar(36);primaryKey" json:"transaction_id"`
 	UserID          uuid.UUID       `gorm:"type:varchar(36);not null;index" json:"user_id"`
 	TradeType       types.TradeType `gorm:"column:trade_type;size:50;not null;index" json:"trade_type"`
-	Symbol          string          `gorm:"size:20;not null;index" json:"symbol"`
-	Quantity        float64         `gorm:"type:decimal(15,4);not null" json:"quantity"`
-	Price           float64         `gorm:"type:decimal(15,4);not null" json:"price"`
+	Symbol          string          `gorm:"size:32;not null;index" json:"symbol"`
+	Quantity        float64         `gorm:"type:decimal(24,10);not null" json:"quantity"`
+	Price           float64         `gorm:"type:decimal(24,10);not null" json:"price"`
 	Amount          float64         `gorm:"type:decimal(15,2);not null" json:"amount"`
 	Currency        string          `gorm:"size:3;not null;default:'USD'" json:"currency"`
 	Exchange        string          `gorm:"size:50" json:"exchange"`
 	Broker          string          `gorm:"size:100" json:"broker"`
+	AccountID       *uuid.UUID      `gorm:"type:varchar(36);index" json:"account_id"`
+	PortfolioID     *uuid.UUID      `gorm:"type:varchar(36);index" json:"portfolio_id"`
 	TransactionDate time.Time       `gorm:"not null;index" json:"transaction_date"`
 	UserNotes       string          `gorm:"type:text" json:"user_notes"`
+	TransactionCosts
 	BaseModel
 
 	// User relationship - foreign key is UserID pointing to users.user_id
 	User User `gorm:"foreignKey:UserID;references:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"user,omitempty"`
 }
 
+// TransactionCosts holds the charges deducted from or added to a transaction's gross amount
+type TransactionCosts struct {
+	Commission     float64 `gorm:"type:decimal(15,2);not null;default:0" json:"commission"`      // Broker commission
+	Fee            float64 `gorm:"type:decimal(15,2);not null;default:0" json:"fee"`             // Exchange and regulatory fees
+	Tax            float64 `gorm:"type:decimal(15,2);not null;default:0" json:"tax"`             // Transaction tax, e.g. Taiwan securities transaction tax
+	WithholdingTax float64 `gorm:"type:decimal(15,2);not null;default:0" json:"withholding_tax"` // Tax withheld from dividends
+}
+
+// Fees returns the commission and fees charged
+func (c TransactionCosts) Fees() float64 {
+	return c.Commission + c.Fee
+}
+
+// Taxes returns the transaction and withholding taxes charged
+func (c TransactionCosts) Taxes() float64 {
+	return c.Tax + c.WithholdingTax
+}
+
+// Total returns every fee and tax charged
+func (c TransactionCosts) Total() float64 {
+	return c.Fees() + c.Taxes()
+}
+
+// NetAmount returns the cash that actually changed hands: the gross amount plus costs
+// for buys, withdrawals and fees, and minus costs for everything received
+func (t Transaction) NetAmount() float64 {
+	if t.TradeType.IsOutflow() {
+		return t.Amount + t.TransactionCosts.Total()
+	}
+	return t.Amount - t.TransactionCosts.Total()
+}
+
+// CashFlow returns the signed change the transaction makes to its account's cash balance
+func (t Transaction) CashFlow() float64 {
+	if t.TradeType.IsOutflow() {
+		return -t.NetAmount()
+	}
+	return t.NetAmount()
+}
+
 // TableName specifies the table name for Transaction model
 func (Transaction) TableName() string {
 	return "transactions"
@@ -17,6 +17,10 @@ type User struct {
 	FirstName    string    `gorm:"size:100" json:"first_name"`
 	LastName     string    `gorm:"size:100" json:"last_name"`
 	IsActive     bool      `gorm:"default:true" json:"is_active"`
+	IsAdmin      bool      `gorm:"not null;default:false" json:"is_admin"` // may modify data shared by all users
+
+	CostBasisMethod CostBasisMethod `gorm:"size:20;not null;default:'average'" json:"cost_basis_method"`
+	BaseCurrency    string          `gorm:"size:3;not null;default:'USD'" json:"base_currency"`
 	BaseModel
 
 	Transactions []Transaction `gorm:"foreignKey:UserID;references:UserID" json:"transactions,omitempty"`
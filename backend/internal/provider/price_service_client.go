@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -21,6 +22,9 @@ type PriceServiceClient interface {
 	GetCurrentPrices(ctx context.Context, symbols []string) ([]SymbolCurrentPrice, error)
 	GetHistoricalPrices(ctx context.Context, symbols []string, resolution Resolution, fromDate, toDate string) ([]SymbolHistoricalPrice, error)
 	GetHistoricalPriceAtDate(ctx context.Context, symbol string, date string) (*SymbolHistoricalPrice, error)
+	GetIntradayPrices(ctx context.Context, symbol string, interval Interval, fromDate, toDate string) (*SymbolIntradayPrice, error)
+	GetFXRates(ctx context.Context, base, quote, fromDate, toDate string) (*CurrencyPairRates, error)
+	GetSymbolInfo(ctx context.Context, symbol string) (*SymbolInfo, error)
 	HealthCheck(ctx context.Context) (*HealthResponse, error)
 	IsHealthy() bool
 }
@@ -117,12 +121,21 @@ func (c *priceServiceClient) makeRequest(ctx context.Context, method, endpoint s
 
 	for attempt := 0; attempt <= c.config.PriceService.MaxRetries; attempt++ {
 		var respBody []byte
+		var notFoundErr error
 		err := c.circuitBreaker.Execute(func() error {
 			var execErr error
 			respBody, execErr = c.doRequest(ctx, method, endpoint, body)
+			// An unknown symbol is an answer, not a failure of the service
+			if errors.Is(execErr, ErrNotFound) {
+				notFoundErr = execErr
+				return nil
+			}
 			return execErr
 		})
 
+		if notFoundErr != nil {
+			return nil, notFoundErr
+		}
 		if err == nil {
 			return respBody, nil
 		}
@@ -193,6 +206,9 @@ func (c *priceServiceClient) doRequest(ctx context.Context, method, endpoint str
 	if resp.StatusCode >= 400 {
 		var errorResp ErrorResponse
 		if err := json.Unmarshal(respBody, &errorResp); err == nil {
+			if resp.StatusCode == http.StatusNotFound && errorResp.Error.Code == ErrSymbolNotFound {
+				return nil, fmt.Errorf("%w: %s", ErrNotFound, errorResp.Error.Message)
+			}
 			return nil, fmt.Errorf("price service error: %s - %s", errorResp.Error.Code, errorResp.Error.Message)
 		}
 		return nil, fmt.Errorf("price service returned status %d: %s", resp.StatusCode, string(respBody))
@@ -233,31 +249,75 @@ func (c *priceServiceClient) GetCurrentPrices(ctx context.Context, symbols []str
 	return response.Data, nil
 }
 
-// GetHistoricalPrices retrieves historical prices for the specified symbols
+// GetHistoricalPrices retrieves historical prices for the specified symbols, one request per symbol.
+// When fromDate and toDate are both set the daily closes in that range are returned and resolution is ignored.
 func (c *priceServiceClient) GetHistoricalPrices(ctx context.Context, symbols []string, resolution Resolution, fromDate, toDate string) ([]SymbolHistoricalPrice, error) {
 	if len(symbols) == 0 {
 		return nil, fmt.Errorf("symbols list cannot be empty")
 	}
+	if (fromDate == "") != (toDate == "") {
+		return nil, fmt.Errorf("both fromDate and toDate are required for a date range")
+	}
 
-	// Build query parameters
-	params := url.Values{}
-	params.Set("symbols", strings.Join(symbols, ","))
-	params.Set("resolution", string(resolution))
-	if fromDate != "" {
-		params.Set("from_date", fromDate)
+	result := make([]SymbolHistoricalPrice, 0, len(symbols))
+	for _, symbol := range symbols {
+		// Build query parameters
+		params := url.Values{}
+		params.Set("symbol", symbol)
+		if fromDate != "" {
+			params.Set("from", fromDate)
+			params.Set("to", toDate)
+		} else {
+			params.Set("resolution", string(resolution))
+		}
+
+		endpoint := fmt.Sprintf("/api/v1/price/historical?%s", params.Encode())
+
+		respBody, err := c.makeRequest(ctx, "GET", endpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get historical prices for %s: %w", symbol, err)
+		}
+
+		var response HistoricalPricesResponse
+		if err := json.Unmarshal(respBody, &response); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+
+		if !response.Success {
+			return nil, fmt.Errorf("price service returned unsuccessful response")
+		}
+
+		result = append(result, response.Data)
 	}
-	if toDate != "" {
-		params.Set("to_date", toDate)
+
+	return result, nil
+}
+
+// GetHistoricalPriceAtDate retrieves historical price for a single symbol at a specific date
+func (c *priceServiceClient) GetHistoricalPriceAtDate(ctx context.Context, symbol string, date string) (*SymbolHistoricalPrice, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol cannot be empty")
+	}
+	if date == "" {
+		return nil, fmt.Errorf("date cannot be empty")
 	}
 
+	// Build query parameters for single symbol single date query
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("date", date)
+
 	endpoint := fmt.Sprintf("/api/v1/price/historical?%s", params.Encode())
 
 	respBody, err := c.makeRequest(ctx, "GET", endpoint, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get historical prices: %w", err)
+		return nil, fmt.Errorf("failed to get historical price at date: %w", err)
 	}
 
-	var response HistoricalPricesResponse
+	var response struct {
+		Success bool                  `json:"success"`
+		Data    SymbolHistoricalPrice `json:"data"`
+	}
 	if err := json.Unmarshal(respBody, &response); err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
@@ -266,34 +326,98 @@ func (c *priceServiceClient) GetHistoricalPrices(ctx context.Context, symbols []
 		return nil, fmt.Errorf("price service returned unsuccessful response")
 	}
 
-	return response.Data, nil
+	return &response.Data, nil
 }
 
-// GetHistoricalPriceAtDate retrieves historical price for a single symbol at a specific date
-func (c *priceServiceClient) GetHistoricalPriceAtDate(ctx context.Context, symbol string, date string) (*SymbolHistoricalPrice, error) {
+// GetIntradayPrices retrieves the intraday bars of a symbol, newest to oldest. When fromDate and
+// toDate are both set only the bars of the days in that range are returned.
+func (c *priceServiceClient) GetIntradayPrices(ctx context.Context, symbol string, interval Interval, fromDate, toDate string) (*SymbolIntradayPrice, error) {
 	if symbol == "" {
 		return nil, fmt.Errorf("symbol cannot be empty")
 	}
-	if date == "" {
-		return nil, fmt.Errorf("date cannot be empty")
+	if interval.Duration() == 0 {
+		return nil, fmt.Errorf("unsupported interval: %s", interval)
+	}
+	if (fromDate == "") != (toDate == "") {
+		return nil, fmt.Errorf("both fromDate and toDate are required for a date range")
 	}
 
-	// Build query parameters for single symbol single date query
 	params := url.Values{}
 	params.Set("symbol", symbol)
-	params.Set("date", date)
+	params.Set("resolution", string(ResolutionIntraday))
+	params.Set("interval", string(interval))
+	if fromDate != "" {
+		params.Set("from", fromDate)
+		params.Set("to", toDate)
+	}
 
 	endpoint := fmt.Sprintf("/api/v1/price/historical?%s", params.Encode())
 
 	respBody, err := c.makeRequest(ctx, "GET", endpoint, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get historical price at date: %w", err)
+		return nil, fmt.Errorf("failed to get intraday prices for %s: %w", symbol, err)
 	}
 
-	var response struct {
-		Success bool                  `json:"success"`
-		Data    SymbolHistoricalPrice `json:"data"`
+	var response IntradayPricesResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if !response.Success {
+		return nil, fmt.Errorf("price service returned unsuccessful response")
+	}
+
+	return &response.Data, nil
+}
+
+// GetFXRates retrieves daily exchange rates converting base into quote currency, sorted newest to oldest
+func (c *priceServiceClient) GetFXRates(ctx context.Context, base, quote, fromDate, toDate string) (*CurrencyPairRates, error) {
+	if base == "" || quote == "" {
+		return nil, fmt.Errorf("base and quote currencies cannot be empty")
+	}
+
+	params := url.Values{}
+	params.Set("base", base)
+	params.Set("quote", quote)
+	if fromDate != "" && toDate != "" {
+		params.Set("from", fromDate)
+		params.Set("to", toDate)
+	}
+
+	endpoint := fmt.Sprintf("/api/v1/fx/rates?%s", params.Encode())
+
+	respBody, err := c.makeRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get FX rates: %w", err)
+	}
+
+	var response FXRatesResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
+
+	if !response.Success {
+		return nil, fmt.Errorf("price service returned unsuccessful response")
+	}
+
+	return &response.Data, nil
+}
+
+// GetSymbolInfo retrieves the reference data of a symbol, such as its name, asset class and sector.
+// ErrNotFound is returned when the symbol is unknown.
+func (c *priceServiceClient) GetSymbolInfo(ctx context.Context, symbol string) (*SymbolInfo, error) {
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol cannot be empty")
+	}
+
+	endpoint := fmt.Sprintf("/api/v1/symbols/%s", url.PathEscape(symbol))
+
+	respBody, err := c.makeRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get symbol info for %s: %w", symbol, err)
+	}
+
+	var response SymbolInfoResponse
 	if err := json.Unmarshal(respBody, &response); err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
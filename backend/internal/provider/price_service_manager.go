@@ -63,6 +63,21 @@ func (psm *PriceServiceManager) GetHistoricalPriceAtDate(ctx context.Context, sy
 	return psm.client.GetHistoricalPriceAtDate(ctx, symbol, date)
 }
 
+// GetIntradayPrices retrieves the intraday bars of a symbol over a date range
+func (psm *PriceServiceManager) GetIntradayPrices(ctx context.Context, symbol string, interval Interval, fromDate, toDate string) (*SymbolIntradayPrice, error) {
+	return psm.client.GetIntradayPrices(ctx, symbol, interval, fromDate, toDate)
+}
+
+// GetFXRates retrieves daily exchange rates converting base into quote currency
+func (psm *PriceServiceManager) GetFXRates(ctx context.Context, base, quote, fromDate, toDate string) (*CurrencyPairRates, error) {
+	return psm.client.GetFXRates(ctx, base, quote, fromDate, toDate)
+}
+
+// GetSymbolInfo retrieves the reference data of a symbol from the symbol master
+func (psm *PriceServiceManager) GetSymbolInfo(ctx context.Context, symbol string) (*SymbolInfo, error) {
+	return psm.client.GetSymbolInfo(ctx, symbol)
+}
+
 // HealthCheck performs a health check on the Price Service
 func (psm *PriceServiceManager) HealthCheck(ctx context.Context) (*HealthResponse, error) {
 	return psm.client.HealthCheck(ctx)
@@ -147,6 +147,153 @@ func TestPriceServiceClient_HealthCheck(t *testing.T) {
 	assert.Equal(t, "price-service", health.Service)
 }
 
+func TestPriceServiceClient_GetFXRates(t *testing.T) {
+	// Mock server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/fx/rates", r.URL.Path)
+		assert.Equal(t, "TWD", r.URL.Query().Get("base"))
+		assert.Equal(t, "USD", r.URL.Query().Get("quote"))
+		assert.Equal(t, "2025-01-01", r.URL.Query().Get("from"))
+		assert.Equal(t, "2025-01-31", r.URL.Query().Get("to"))
+
+		response := FXRatesResponse{
+			Success: true,
+			Data: CurrencyPairRates{
+				Base:  "TWD",
+				Quote: "USD",
+				Rates: []FXRate{
+					{Date: "2025-01-31", Rate: 0.0304},
+					{Date: "2025-01-02", Rate: 0.0301},
+				},
+			},
+			Timestamp: time.Now(),
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		PriceService: config.PriceServiceConfig{
+			BaseURL:    server.URL,
+			APIKey:     "test-key",
+			Timeout:    30 * time.Second,
+			MaxRetries: 3,
+		},
+	}
+
+	client := NewPriceServiceClient(cfg)
+	ctx := context.Background()
+
+	rates, err := client.GetFXRates(ctx, "TWD", "USD", "2025-01-01", "2025-01-31")
+	require.NoError(t, err)
+	assert.Equal(t, "TWD", rates.Base)
+	assert.Equal(t, "USD", rates.Quote)
+	require.Len(t, rates.Rates, 2)
+	assert.Equal(t, 0.0304, rates.Rates[0].Rate)
+}
+
+func TestPriceServiceClient_GetHistoricalPrices(t *testing.T) {
+	// Mock server
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/price/historical", r.URL.Path)
+		assert.Equal(t, "2024-01-01", r.URL.Query().Get("from"))
+		assert.Equal(t, "2024-01-31", r.URL.Query().Get("to"))
+		symbol := r.URL.Query().Get("symbol")
+		requested = append(requested, symbol)
+
+		response := HistoricalPricesResponse{
+			Success: true,
+			Data: SymbolHistoricalPrice{
+				Symbol:     symbol,
+				Resolution: ResolutionDaily,
+				HistoricalPrices: []ClosePrice{
+					{Date: "2024-01-31", Price: 110},
+					{Date: "2024-01-02", Price: 100},
+				},
+			},
+			Timestamp: time.Now(),
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		PriceService: config.PriceServiceConfig{
+			BaseURL:    server.URL,
+			APIKey:     "test-key",
+			Timeout:    30 * time.Second,
+			MaxRetries: 3,
+		},
+	}
+
+	client := NewPriceServiceClient(cfg)
+	ctx := context.Background()
+
+	prices, err := client.GetHistoricalPrices(ctx, []string{"AAPL", "0050.TW"}, ResolutionDaily, "2024-01-01", "2024-01-31")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"AAPL", "0050.TW"}, requested)
+	require.Len(t, prices, 2)
+	assert.Equal(t, "0050.TW", prices[1].Symbol)
+	require.Len(t, prices[0].HistoricalPrices, 2)
+	assert.Equal(t, 110.0, prices[0].HistoricalPrices[0].Price)
+}
+
+func TestPriceServiceClient_GetIntradayPrices(t *testing.T) {
+	barTime := time.Date(2024, 1, 31, 20, 45, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/price/historical", r.URL.Path)
+		assert.Equal(t, "intraday", r.URL.Query().Get("resolution"))
+		assert.Equal(t, "15min", r.URL.Query().Get("interval"))
+		assert.Equal(t, "2024-01-30", r.URL.Query().Get("from"))
+		assert.Equal(t, "2024-01-31", r.URL.Query().Get("to"))
+
+		response := IntradayPricesResponse{
+			Success: true,
+			Data: SymbolIntradayPrice{
+				Symbol:         r.URL.Query().Get("symbol"),
+				Resolution:     ResolutionIntraday,
+				Interval:       Interval15Min,
+				IntradayPrices: []IntradayPrice{{Time: barTime, Price: 184.4}},
+			},
+			Timestamp: time.Now(),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		PriceService: config.PriceServiceConfig{
+			BaseURL:    server.URL,
+			APIKey:     "test-key",
+			Timeout:    30 * time.Second,
+			MaxRetries: 3,
+		},
+	}
+
+	client := NewPriceServiceClient(cfg)
+	prices, err := client.GetIntradayPrices(context.Background(), "AAPL", Interval15Min, "2024-01-30", "2024-01-31")
+	require.NoError(t, err)
+	assert.Equal(t, "AAPL", prices.Symbol)
+	require.Len(t, prices.IntradayPrices, 1)
+	assert.True(t, barTime.Equal(prices.IntradayPrices[0].Time))
+	assert.Equal(t, 184.4, prices.IntradayPrices[0].Price)
+
+	_, err = client.GetIntradayPrices(context.Background(), "AAPL", Interval("30min"), "", "")
+	assert.Error(t, err)
+}
+
 func TestPriceServiceManager_GetCurrentPrice(t *testing.T) {
 	// Mock server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -226,3 +373,84 @@ func TestCircuitBreaker(t *testing.T) {
 	})
 	assert.NoError(t, err5)
 }
+
+func TestPriceServiceClient_GetSymbolInfo(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/symbols/BRK.B" {
+			json.NewEncoder(w).Encode(SymbolInfoResponse{
+				Success: true,
+				Data: SymbolInfo{
+					Symbol:     "BRK.B",
+					Name:       "Berkshire Hathaway Inc Class B",
+					AssetClass: "equity",
+					Exchange:   "NYSE",
+					Currency:   "USD",
+					Country:    "US",
+					Sector:     "Financials",
+				},
+				Timestamp: time.Now(),
+			})
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(ErrorResponse{
+			Success: false,
+			Error:   ErrorDetail{Code: ErrSymbolNotFound, Message: "symbol NOPE not found"},
+		})
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		PriceService: config.PriceServiceConfig{
+			BaseURL:    server.URL,
+			Timeout:    30 * time.Second,
+			MaxRetries: 3,
+		},
+	}
+
+	client := NewPriceServiceClient(cfg)
+	ctx := context.Background()
+
+	info, err := client.GetSymbolInfo(ctx, "BRK.B")
+	require.NoError(t, err)
+	assert.Equal(t, "Berkshire Hathaway Inc Class B", info.Name)
+	assert.Equal(t, "Financials", info.Sector)
+
+	// Unknown symbols are an answer: they are neither retried nor count against the circuit breaker
+	requests = 0
+	for i := 0; i < 6; i++ {
+		_, err = client.GetSymbolInfo(ctx, "NOPE")
+		assert.ErrorIs(t, err, ErrNotFound)
+	}
+	assert.Equal(t, 6, requests)
+
+	_, err = client.GetSymbolInfo(ctx, "BRK.B")
+	assert.NoError(t, err)
+}
+
+func TestQuoteSymbol(t *testing.T) {
+	tests := []struct {
+		symbol   string
+		exchange string
+		expected string
+	}{
+		{"2330", "TPE", "2330.TW"},
+		{"2330", "twse", "2330.TW"},
+		{"6488", "TPEx", "6488.TWO"},
+		{"SHOP", "TSX", "SHOP.TO"},
+		{"0700", "HKG", "0700.HK"},
+		{"AAPL", "NASDAQ", "AAPL"},
+		{"BRK.B", "NYSE", "BRK.B"},
+		{"AAPL", "", "AAPL"},
+		// Symbols already quoted with a suffix are left alone
+		{"2330.TW", "TPE", "2330.TW"},
+		{"6488.TWO", "TPE", "6488.TWO"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, QuoteSymbol(tt.symbol, tt.exchange), "%s on %s", tt.symbol, tt.exchange)
+	}
+}
@@ -0,0 +1,49 @@
+package provider
+
+import "strings"
+
+// exchangeSymbolSuffixes maps the exchanges transactions record, by their codes and common
+// aliases, to the suffix the Price Service routes their symbols by. US exchanges have none.
+var exchangeSymbolSuffixes = map[string]string{
+	"TWSE": ".TW",
+	"TPE":  ".TW",
+	"TAI":  ".TW",
+	"TPEX": ".TWO",
+	"TWO":  ".TWO",
+	"OTC":  ".TWO",
+	"GTSM": ".TWO",
+	"TSX":  ".TO",
+	"TOR":  ".TO",
+	"TSXV": ".V",
+	"CVE":  ".V",
+	"LSE":  ".L",
+	"LON":  ".L",
+	"HKEX": ".HK",
+	"HKG":  ".HK",
+	"SEHK": ".HK",
+	"TSE":  ".T",
+	"TYO":  ".T",
+	"JPX":  ".T",
+	"SSE":  ".SS",
+	"SHA":  ".SS",
+	"SZSE": ".SZ",
+	"SHE":  ".SZ",
+}
+
+// QuoteSymbol returns the symbol the Price Service quotes a security traded on exchange under,
+// e.g. 2330 on TPE is 2330.TW and 6488 on TPEx is 6488.TWO. Symbols already carrying an exchange
+// suffix, and those of exchanges without one, are returned unchanged.
+func QuoteSymbol(symbol, exchange string) string {
+	suffix, ok := exchangeSymbolSuffixes[strings.ToUpper(strings.TrimSpace(exchange))]
+	if !ok || symbol == "" {
+		return symbol
+	}
+	if dot := strings.LastIndex(symbol, "."); dot > 0 {
+		for _, known := range exchangeSymbolSuffixes {
+			if strings.EqualFold(symbol[dot:], known) {
+				return symbol
+			}
+		}
+	}
+	return symbol + suffix
+}
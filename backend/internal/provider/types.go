@@ -1,6 +1,9 @@
 package provider
 
-import "time"
+import (
+	"errors"
+	"time"
+)
 
 // Resolution types for historical data
 type Resolution string
@@ -21,6 +24,7 @@ type SymbolCurrentPrice struct {
 	ChangePercent float64   `json:"change_percent"` // relative difference (change/previous_close)
 	PreviousClose float64   `json:"previous_close"`
 	Timestamp     time.Time `json:"timestamp"`
+	Provider      string    `json:"provider"` // third-party provider that served the price
 }
 
 // ClosePrice represents a date-price pair
@@ -34,6 +38,73 @@ type SymbolHistoricalPrice struct {
 	Symbol           string       `json:"symbol"`
 	Resolution       Resolution   `json:"resolution"`
 	HistoricalPrices []ClosePrice `json:"historical_prices"`
+	Provider         string       `json:"provider"` // third-party provider that served the prices
+}
+
+// Interval is the width of the bars of intraday prices
+type Interval string
+
+const (
+	Interval1Min  Interval = "1min"
+	Interval5Min  Interval = "5min"
+	Interval15Min Interval = "15min"
+	Interval60Min Interval = "60min"
+)
+
+// Duration returns the width of a bar, or zero for an unsupported interval
+func (i Interval) Duration() time.Duration {
+	switch i {
+	case Interval1Min:
+		return time.Minute
+	case Interval5Min:
+		return 5 * time.Minute
+	case Interval15Min:
+		return 15 * time.Minute
+	case Interval60Min:
+		return time.Hour
+	}
+	return 0
+}
+
+// IntradayPrice represents the closing price of an intraday bar, keyed by the moment the bar opens
+type IntradayPrice struct {
+	Time  time.Time `json:"time"`
+	Price float64   `json:"price"`
+}
+
+// SymbolIntradayPrice represents intraday price bars for a symbol, newest to oldest
+type SymbolIntradayPrice struct {
+	Symbol         string          `json:"symbol"`
+	Resolution     Resolution      `json:"resolution"`
+	Interval       Interval        `json:"interval"`
+	IntradayPrices []IntradayPrice `json:"intraday_prices"`
+	Provider       string          `json:"provider"` // third-party provider that served the prices
+}
+
+// FXRate represents the closing exchange rate of a currency pair on a date
+type FXRate struct {
+	Date string  `json:"date"` // YYYY-MM-DD format
+	Rate float64 `json:"rate"` // units of quote currency per unit of base currency
+}
+
+// CurrencyPairRates represents historical exchange rates for a currency pair
+type CurrencyPairRates struct {
+	Base     string   `json:"base"`
+	Quote    string   `json:"quote"`
+	Rates    []FXRate `json:"rates"`
+	Provider string   `json:"provider"` // third-party provider that served the rates
+}
+
+// SymbolInfo represents the reference data of a symbol from the Price Service symbol master
+type SymbolInfo struct {
+	Symbol     string `json:"symbol"`
+	Name       string `json:"name"`
+	AssetClass string `json:"asset_class"` // equity, etf, bond, commodity or crypto
+	Exchange   string `json:"exchange"`
+	Currency   string `json:"currency"`
+	Country    string `json:"country"`
+	Sector     string `json:"sector"`
+	Industry   string `json:"industry"`
 }
 
 // ErrorCode represents error codes from Price Service
@@ -48,6 +119,9 @@ const (
 	ErrUnauthorized       ErrorCode = "UNAUTHORIZED"
 )
 
+// ErrNotFound is returned when the Price Service does not know the requested symbol
+var ErrNotFound = errors.New("not found")
+
 // ErrorResponse represents the standard error response format from Price Service
 type ErrorResponse struct {
 	Success bool        `json:"success"`
@@ -73,11 +147,32 @@ type CurrentPricesResponse struct {
 	Timestamp time.Time            `json:"timestamp"`
 }
 
-// HistoricalPricesResponse represents the response from /api/v1/price/historical
+// HistoricalPricesResponse represents the response from /api/v1/price/historical, which serves one symbol per request
 type HistoricalPricesResponse struct {
-	Success   bool                    `json:"success"`
-	Data      []SymbolHistoricalPrice `json:"data"`
-	Timestamp time.Time               `json:"timestamp"`
+	Success   bool                  `json:"success"`
+	Data      SymbolHistoricalPrice `json:"data"`
+	Timestamp time.Time             `json:"timestamp"`
+}
+
+// IntradayPricesResponse represents the response from /api/v1/price/historical with the intraday resolution
+type IntradayPricesResponse struct {
+	Success   bool                `json:"success"`
+	Data      SymbolIntradayPrice `json:"data"`
+	Timestamp time.Time           `json:"timestamp"`
+}
+
+// FXRatesResponse represents the response from /api/v1/fx/rates
+type FXRatesResponse struct {
+	Success   bool              `json:"success"`
+	Data      CurrencyPairRates `json:"data"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+// SymbolInfoResponse represents the response from /api/v1/symbols/:symbol
+type SymbolInfoResponse struct {
+	Success   bool       `json:"success"`
+	Data      SymbolInfo `json:"data"`
+	Timestamp time.Time  `json:"timestamp"`
 }
 
 // HealthResponse represents the response from /health endpoint
@@ -93,10 +188,10 @@ type GetCurrentPricesRequest struct {
 	Symbols []string `json:"symbols"`
 }
 
-// GetHistoricalPricesRequest represents the request parameters for getting historical prices
+// GetHistoricalPricesRequest represents the query parameters for getting historical prices of a symbol
 type GetHistoricalPricesRequest struct {
-	Symbols    []string   `json:"symbols"`
+	Symbol     string     `json:"symbol"`
 	Resolution Resolution `json:"resolution"`
-	FromDate   string     `json:"from_date"` // YYYY-MM-DD format
-	ToDate     string     `json:"to_date"`   // YYYY-MM-DD format
+	From       string     `json:"from"` // YYYY-MM-DD format
+	To         string     `json:"to"`   // YYYY-MM-DD format
 }
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// AccountRepository handles account database operations
+type AccountRepository struct {
+	db *gorm.DB
+}
+
+// NewAccountRepository creates a new account repository
+func NewAccountRepository(db *gorm.DB) *AccountRepository {
+	return &AccountRepository{db: db}
+}
+
+// Create creates a single account
+func (r *AccountRepository) Create(account *models.Account) error {
+	if err := r.db.Create(account).Error; err != nil {
+		return fmt.Errorf("failed to create account: %w", err)
+	}
+	return nil
+}
+
+// GetByIDAndUserID retrieves an account by id, provided it belongs to the user
+func (r *AccountRepository) GetByIDAndUserID(id, userID uuid.UUID) (*models.Account, error) {
+	var account models.Account
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&account).Error; err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// GetByUserID retrieves a user's accounts ordered by name
+func (r *AccountRepository) GetByUserID(userID uuid.UUID) ([]models.Account, error) {
+	var accounts []models.Account
+	if err := r.db.Where("user_id = ?", userID).Order("name ASC").Find(&accounts).Error; err != nil {
+		return nil, fmt.Errorf("failed to get accounts for user %s: %w", userID, err)
+	}
+	return accounts, nil
+}
+
+// UpdateByIDAndUserID updates an account by id, provided it belongs to the user
+func (r *AccountRepository) UpdateByIDAndUserID(id, userID uuid.UUID, updates map[string]interface{}) error {
+	if err := r.db.Model(&models.Account{}).Where("id = ? AND user_id = ?", id, userID).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to update account: %w", err)
+	}
+	return nil
+}
+
+// DeleteByIDAndUserID soft deletes an account by id, provided it belongs to the user
+func (r *AccountRepository) DeleteByIDAndUserID(id, userID uuid.UUID) error {
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Account{}).Error; err != nil {
+		return fmt.Errorf("failed to delete account: %w", err)
+	}
+	return nil
+}
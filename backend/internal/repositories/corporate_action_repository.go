@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// CorporateActionRepository handles corporate action database operations
+type CorporateActionRepository struct {
+	db *gorm.DB
+}
+
+// NewCorporateActionRepository creates a new corporate action repository
+func NewCorporateActionRepository(db *gorm.DB) *CorporateActionRepository {
+	return &CorporateActionRepository{db: db}
+}
+
+// Create creates a single corporate action
+func (r *CorporateActionRepository) Create(action *models.CorporateAction) error {
+	if err := r.db.Create(action).Error; err != nil {
+		return fmt.Errorf("failed to create corporate action: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a corporate action by id
+func (r *CorporateActionRepository) GetByID(id uuid.UUID) (*models.CorporateAction, error) {
+	var action models.CorporateAction
+	if err := r.db.Where("id = ?", id).First(&action).Error; err != nil {
+		return nil, err
+	}
+	return &action, nil
+}
+
+// GetAll retrieves all corporate actions ordered by effective date
+func (r *CorporateActionRepository) GetAll() ([]models.CorporateAction, error) {
+	var actions []models.CorporateAction
+	if err := r.db.Order("effective_date ASC").Find(&actions).Error; err != nil {
+		return nil, fmt.Errorf("failed to get corporate actions: %w", err)
+	}
+	return actions, nil
+}
+
+// GetBySymbols retrieves the corporate actions affecting or producing any of the symbols
+func (r *CorporateActionRepository) GetBySymbols(symbols []string) ([]models.CorporateAction, error) {
+	var actions []models.CorporateAction
+	err := r.db.Where("symbol IN ? OR new_symbol IN ?", symbols, symbols).
+		Order("effective_date ASC").
+		Find(&actions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get corporate actions for symbols: %w", err)
+	}
+	return actions, nil
+}
+
+// UpdateByID updates a corporate action by id
+func (r *CorporateActionRepository) UpdateByID(id uuid.UUID, updates map[string]interface{}) error {
+	if err := r.db.Model(&models.CorporateAction{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to update corporate action: %w", err)
+	}
+	return nil
+}
+
+// DeleteByID soft deletes a corporate action by id
+func (r *CorporateActionRepository) DeleteByID(id uuid.UUID) error {
+	if err := r.db.Where("id = ?", id).Delete(&models.CorporateAction{}).Error; err != nil {
+		return fmt.Errorf("failed to delete corporate action: %w", err)
+	}
+	return nil
+}
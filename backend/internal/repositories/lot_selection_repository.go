@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// LotSelectionRepository handles specific lot selection database operations
+type LotSelectionRepository struct {
+	db *gorm.DB
+}
+
+// NewLotSelectionRepository creates a new lot selection repository
+func NewLotSelectionRepository(db *gorm.DB) *LotSelectionRepository {
+	return &LotSelectionRepository{db: db}
+}
+
+// GetByUserID retrieves all lot selections for a user
+func (r *LotSelectionRepository) GetByUserID(userID uuid.UUID) ([]models.LotSelection, error) {
+	var selections []models.LotSelection
+	if err := r.db.Where("user_id = ?", userID).Find(&selections).Error; err != nil {
+		return nil, fmt.Errorf("failed to get lot selections for user %s: %w", userID, err)
+	}
+	return selections, nil
+}
+
+// GetBySellTransactionID retrieves the lot selections of a single sale
+func (r *LotSelectionRepository) GetBySellTransactionID(sellTransactionID uuid.UUID) ([]models.LotSelection, error) {
+	var selections []models.LotSelection
+	if err := r.db.Where("sell_transaction_id = ?", sellTransactionID).Find(&selections).Error; err != nil {
+		return nil, fmt.Errorf("failed to get lot selections for transaction %s: %w", sellTransactionID, err)
+	}
+	return selections, nil
+}
+
+// ReplaceForSell replaces the lot selections of a sale in a single database transaction
+func (r *LotSelectionRepository) ReplaceForSell(userID, sellTransactionID uuid.UUID, selections []models.LotSelection) ([]models.LotSelection, error) {
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", tx.Error)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Unscoped().Where("user_id = ? AND sell_transaction_id = ?", userID, sellTransactionID).Delete(&models.LotSelection{}).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to clear lot selections: %w", err)
+	}
+
+	created := make([]models.LotSelection, 0, len(selections))
+	for _, selection := range selections {
+		selection.UserID = userID
+		selection.SellTransactionID = sellTransactionID
+		if err := tx.Create(&selection).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to create lot selection: %w", err)
+		}
+		created = append(created, selection)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return created, nil
+}
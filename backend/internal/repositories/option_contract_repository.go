@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/transaction-tracker/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// OptionContractRepository handles option contract database operations
+type OptionContractRepository struct {
+	db *gorm.DB
+}
+
+// NewOptionContractRepository creates a new option contract repository
+func NewOptionContractRepository(db *gorm.DB) *OptionContractRepository {
+	return &OptionContractRepository{db: db}
+}
+
+// GetAll retrieves every option contract ordered by underlying, expiry and symbol
+func (r *OptionContractRepository) GetAll() ([]models.OptionContract, error) {
+	var contracts []models.OptionContract
+	if err := r.db.Order("underlying ASC, expiry ASC, symbol ASC").Find(&contracts).Error; err != nil {
+		return nil, fmt.Errorf("failed to get option contracts: %w", err)
+	}
+	return contracts, nil
+}
+
+// Upsert creates an option contract or replaces the one recorded under its symbol
+func (r *OptionContractRepository) Upsert(contract *models.OptionContract) error {
+	var existing models.OptionContract
+	err := r.db.Unscoped().Where("symbol = ?", contract.Symbol).First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if err := r.db.Create(contract).Error; err != nil {
+			return fmt.Errorf("failed to create option contract: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get option contract: %w", err)
+	}
+
+	contract.ID = existing.ID
+	contract.CreatedAt = existing.CreatedAt
+	if err := r.db.Unscoped().Save(contract).Error; err != nil {
+		return fmt.Errorf("failed to update option contract: %w", err)
+	}
+	return nil
+}
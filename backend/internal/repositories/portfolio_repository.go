@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// PortfolioRepository handles named portfolio database operations
+type PortfolioRepository struct {
+	db *gorm.DB
+}
+
+// NewPortfolioRepository creates a new portfolio repository
+func NewPortfolioRepository(db *gorm.DB) *PortfolioRepository {
+	return &PortfolioRepository{db: db}
+}
+
+// Create creates a single portfolio
+func (r *PortfolioRepository) Create(portfolio *models.Portfolio) error {
+	if err := r.db.Create(portfolio).Error; err != nil {
+		return fmt.Errorf("failed to create portfolio: %w", err)
+	}
+	return nil
+}
+
+// GetByIDAndUserID retrieves a portfolio by id, provided it belongs to the user
+func (r *PortfolioRepository) GetByIDAndUserID(id, userID uuid.UUID) (*models.Portfolio, error) {
+	var portfolio models.Portfolio
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&portfolio).Error; err != nil {
+		return nil, err
+	}
+	return &portfolio, nil
+}
+
+// GetByUserID retrieves a user's portfolios ordered by name
+func (r *PortfolioRepository) GetByUserID(userID uuid.UUID) ([]models.Portfolio, error) {
+	var portfolios []models.Portfolio
+	if err := r.db.Where("user_id = ?", userID).Order("name ASC").Find(&portfolios).Error; err != nil {
+		return nil, fmt.Errorf("failed to get portfolios for user %s: %w", userID, err)
+	}
+	return portfolios, nil
+}
+
+// UpdateByIDAndUserID updates a portfolio by id, provided it belongs to the user
+func (r *PortfolioRepository) UpdateByIDAndUserID(id, userID uuid.UUID, updates map[string]interface{}) error {
+	if err := r.db.Model(&models.Portfolio{}).Where("id = ? AND user_id = ?", id, userID).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to update portfolio: %w", err)
+	}
+	return nil
+}
+
+// DeleteByIDAndUserID soft deletes a portfolio by id, provided it belongs to the user. The
+// accounts and transactions assigned to it are left unassigned in the same transaction.
+func (r *PortfolioRepository) DeleteByIDAndUserID(id, userID uuid.UUID) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Account{}).Where("portfolio_id = ? AND user_id = ?", id, userID).Update("portfolio_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Transaction{}).Where("portfolio_id = ? AND user_id = ?", id, userID).Update("portfolio_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Portfolio{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete portfolio: %w", err)
+	}
+	return nil
+}
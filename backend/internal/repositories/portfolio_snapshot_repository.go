@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// PortfolioSnapshotRepository handles portfolio snapshot database operations
+type PortfolioSnapshotRepository struct {
+	db *gorm.DB
+}
+
+// NewPortfolioSnapshotRepository creates a new portfolio snapshot repository
+func NewPortfolioSnapshotRepository(db *gorm.DB) *PortfolioSnapshotRepository {
+	return &PortfolioSnapshotRepository{db: db}
+}
+
+// GetLatestByUserID retrieves a user's most recent snapshot, or nil when they have none
+func (r *PortfolioSnapshotRepository) GetLatestByUserID(userID uuid.UUID) (*models.PortfolioSnapshot, error) {
+	var snapshot models.PortfolioSnapshot
+	err := r.db.Where("user_id = ?", userID).Order("snapshot_date DESC").First(&snapshot).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest portfolio snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// GetByUserIDAndDateRange retrieves a user's snapshots dated within [startDate, endDate], oldest first
+func (r *PortfolioSnapshotRepository) GetByUserIDAndDateRange(userID uuid.UUID, startDate, endDate time.Time) ([]models.PortfolioSnapshot, error) {
+	var snapshots []models.PortfolioSnapshot
+	err := r.db.Where("user_id = ? AND snapshot_date >= ? AND snapshot_date <= ?", userID, startDate.Format("2006-01-02"), endDate.Format("2006-01-02")).
+		Order("snapshot_date ASC").
+		Find(&snapshots).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get portfolio snapshots: %w", err)
+	}
+	return snapshots, nil
+}
+
+// DeleteFromDate removes a user's snapshots dated on or after fromDate
+func (r *PortfolioSnapshotRepository) DeleteFromDate(userID uuid.UUID, fromDate time.Time) error {
+	err := r.db.Unscoped().
+		Where("user_id = ? AND snapshot_date >= ?", userID, fromDate.Format("2006-01-02")).
+		Delete(&models.PortfolioSnapshot{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to clear portfolio snapshots: %w", err)
+	}
+	return nil
+}
+
+// ReplaceFromDate replaces a user's snapshots dated on or after fromDate in a single database transaction
+func (r *PortfolioSnapshotRepository) ReplaceFromDate(userID uuid.UUID, fromDate time.Time, snapshots []models.PortfolioSnapshot) error {
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return fmt.Errorf("failed to begin transaction: %w", tx.Error)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	err := tx.Unscoped().
+		Where("user_id = ? AND snapshot_date >= ?", userID, fromDate.Format("2006-01-02")).
+		Delete(&models.PortfolioSnapshot{}).Error
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to clear portfolio snapshots: %w", err)
+	}
+
+	if len(snapshots) > 0 {
+		if err := tx.CreateInBatches(snapshots, 100).Error; err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to create portfolio snapshots: %w", err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
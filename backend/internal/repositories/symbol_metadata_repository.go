@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/transaction-tracker/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// SymbolMetadataRepository handles symbol metadata database operations
+type SymbolMetadataRepository struct {
+	db *gorm.DB
+}
+
+// NewSymbolMetadataRepository creates a new symbol metadata repository
+func NewSymbolMetadataRepository(db *gorm.DB) *SymbolMetadataRepository {
+	return &SymbolMetadataRepository{db: db}
+}
+
+// GetAll retrieves the metadata of every symbol ordered by symbol
+func (r *SymbolMetadataRepository) GetAll() ([]models.SymbolMetadata, error) {
+	var metadata []models.SymbolMetadata
+	if err := r.db.Order("symbol ASC").Find(&metadata).Error; err != nil {
+		return nil, fmt.Errorf("failed to get symbol metadata: %w", err)
+	}
+	return metadata, nil
+}
+
+// GetBySymbols retrieves the metadata recorded for any of the symbols
+func (r *SymbolMetadataRepository) GetBySymbols(symbols []string) ([]models.SymbolMetadata, error) {
+	var metadata []models.SymbolMetadata
+	if len(symbols) == 0 {
+		return metadata, nil
+	}
+	if err := r.db.Where("symbol IN ?", symbols).Find(&metadata).Error; err != nil {
+		return nil, fmt.Errorf("failed to get symbol metadata for symbols: %w", err)
+	}
+	return metadata, nil
+}
+
+// Upsert creates the metadata of a symbol or replaces the recorded one
+func (r *SymbolMetadataRepository) Upsert(metadata *models.SymbolMetadata) error {
+	var existing models.SymbolMetadata
+	err := r.db.Unscoped().Where("symbol = ?", metadata.Symbol).First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if err := r.db.Create(metadata).Error; err != nil {
+			return fmt.Errorf("failed to create symbol metadata: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get symbol metadata: %w", err)
+	}
+
+	metadata.ID = existing.ID
+	metadata.CreatedAt = existing.CreatedAt
+	if err := r.db.Unscoped().Save(metadata).Error; err != nil {
+		return fmt.Errorf("failed to update symbol metadata: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// TargetAllocationRepository handles target allocation database operations
+type TargetAllocationRepository struct {
+	db *gorm.DB
+}
+
+// NewTargetAllocationRepository creates a new target allocation repository
+func NewTargetAllocationRepository(db *gorm.DB) *TargetAllocationRepository {
+	return &TargetAllocationRepository{db: db}
+}
+
+// GetByUserID retrieves a user's target allocations, heaviest first
+func (r *TargetAllocationRepository) GetByUserID(userID uuid.UUID) ([]models.TargetAllocation, error) {
+	var targets []models.TargetAllocation
+	if err := r.db.Where("user_id = ?", userID).Order("weight DESC, target_key ASC").Find(&targets).Error; err != nil {
+		return nil, fmt.Errorf("failed to get target allocations for user %s: %w", userID, err)
+	}
+	return targets, nil
+}
+
+// ReplaceForUser replaces a user's target allocations in a single database transaction
+func (r *TargetAllocationRepository) ReplaceForUser(userID uuid.UUID, targets []models.TargetAllocation) ([]models.TargetAllocation, error) {
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", tx.Error)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.TargetAllocation{}).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to clear target allocations: %w", err)
+	}
+
+	created := make([]models.TargetAllocation, 0, len(targets))
+	for _, target := range targets {
+		target.UserID = userID
+		if err := tx.Create(&target).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to create target allocation: %w", err)
+		}
+		created = append(created, target)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return created, nil
+}
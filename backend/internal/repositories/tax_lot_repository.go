@@ -0,0 +1,158 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// TaxLotRepository handles tax lot and lot disposal database operations
+type TaxLotRepository struct {
+	db *gorm.DB
+}
+
+// NewTaxLotRepository creates a new tax lot repository
+func NewTaxLotRepository(db *gorm.DB) *TaxLotRepository {
+	return &TaxLotRepository{db: db}
+}
+
+// CountByUserID returns the number of tax lots stored for a user
+func (r *TaxLotRepository) CountByUserID(userID uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.TaxLot{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count tax lots: %w", err)
+	}
+	return count, nil
+}
+
+// GetByUserID retrieves all tax lots of a user ordered by acquisition date
+func (r *TaxLotRepository) GetByUserID(userID uuid.UUID) ([]models.TaxLot, error) {
+	var lots []models.TaxLot
+	if err := r.db.Where("user_id = ?", userID).Order("acquired_at ASC").Find(&lots).Error; err != nil {
+		return nil, fmt.Errorf("failed to get tax lots: %w", err)
+	}
+	return lots, nil
+}
+
+// GetDisposalsByUserIDAndDateRange retrieves the disposals of a user within [startDate, endDate)
+func (r *TaxLotRepository) GetDisposalsByUserIDAndDateRange(userID uuid.UUID, startDate, endDate time.Time) ([]models.LotDisposal, error) {
+	var disposals []models.LotDisposal
+	err := r.db.Where("user_id = ? AND disposed_at >= ? AND disposed_at < ?", userID, startDate, endDate).
+		Order("disposed_at ASC, symbol ASC, acquired_at ASC").
+		Find(&disposals).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lot disposals: %w", err)
+	}
+	return disposals, nil
+}
+
+// ReplaceForUser replaces a user's tax lots and disposals in a single database transaction
+func (r *TaxLotRepository) ReplaceForUser(userID uuid.UUID, lots []models.TaxLot, disposals []models.LotDisposal) error {
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return fmt.Errorf("failed to begin transaction: %w", tx.Error)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.LotDisposal{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to clear lot disposals: %w", err)
+	}
+	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.TaxLot{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to clear tax lots: %w", err)
+	}
+
+	if len(lots) > 0 {
+		if err := tx.CreateInBatches(lots, 100).Error; err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to create tax lots: %w", err)
+		}
+	}
+	if len(disposals) > 0 {
+		if err := tx.CreateInBatches(disposals, 100).Error; err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to create lot disposals: %w", err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ReplaceForUserSince replaces a user's tax lots acquired and disposals made from since on in a
+// single database transaction. Lots acquired earlier keep their rows, with their remaining
+// quantity and cost basis updated, so lots must carry the IDs of the persisted ones.
+func (r *TaxLotRepository) ReplaceForUserSince(userID uuid.UUID, since time.Time, lots []models.TaxLot, disposals []models.LotDisposal) error {
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return fmt.Errorf("failed to begin transaction: %w", tx.Error)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Unscoped().Where("user_id = ? AND disposed_at >= ?", userID, since).Delete(&models.LotDisposal{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to clear lot disposals: %w", err)
+	}
+	if err := tx.Unscoped().Where("user_id = ? AND acquired_at >= ?", userID, since).Delete(&models.TaxLot{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to clear tax lots: %w", err)
+	}
+
+	var newLots []models.TaxLot
+	for _, lot := range lots {
+		if !lot.AcquiredAt.Before(since) {
+			newLots = append(newLots, lot)
+			continue
+		}
+		if err := tx.Model(&models.TaxLot{}).Where("id = ? AND user_id = ?", lot.ID, userID).Updates(map[string]interface{}{
+			"remaining_quantity":   lot.RemainingQuantity,
+			"remaining_cost_basis": lot.RemainingCostBasis,
+		}).Error; err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to update tax lot: %w", err)
+		}
+	}
+
+	var newDisposals []models.LotDisposal
+	for _, disposal := range disposals {
+		if !disposal.DisposedAt.Before(since) {
+			newDisposals = append(newDisposals, disposal)
+		}
+	}
+
+	if len(newLots) > 0 {
+		if err := tx.CreateInBatches(newLots, 100).Error; err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to create tax lots: %w", err)
+		}
+	}
+	if len(newDisposals) > 0 {
+		if err := tx.CreateInBatches(newDisposals, 100).Error; err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to create lot disposals: %w", err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/types"
 	"gorm.io/gorm"
 )
 
@@ -85,6 +86,15 @@ func (r *TransactionRepository) GetByUserID(userID uuid.UUID) ([]models.Transact
 	return transactions, err
 }
 
+// CountByAccountID returns the number of transactions recorded in an account
+func (r *TransactionRepository) CountByAccountID(accountID uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.Transaction{}).Where("account_id = ?", accountID).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count transactions for account %s: %w", accountID, err)
+	}
+	return count, nil
+}
+
 // UpdateByID updates a transaction by transaction_id (UUID)
 func (r *TransactionRepository) UpdateByID(id uuid.UUID, updates map[string]interface{}) error {
 	return r.db.Model(&models.Transaction{}).Where("transaction_id = ?", id).Updates(updates).Error
@@ -138,7 +148,7 @@ func (r *TransactionRepository) DeleteByIDsAndUserID(ids []uuid.UUID, userID uui
 }
 
 // GetWithFilters retrieves transactions with advanced filtering
-func (r *TransactionRepository) GetWithFilters(userID *uuid.UUID, symbols []string, types []string, exchanges []string, brokers []string, currencies []string,
+func (r *TransactionRepository) GetWithFilters(userID *uuid.UUID, symbols []string, types []string, exchanges []string, brokers []string, currencies []string, accountIDs []uuid.UUID,
 	startDate *time.Time, endDate *time.Time, minAmount *float64, maxAmount *float64,
 	orderBy string, orderDirection string, limit int, offset int) ([]models.Transaction, error) {
 
@@ -164,6 +174,9 @@ func (r *TransactionRepository) GetWithFilters(userID *uuid.UUID, symbols []stri
 	if len(currencies) > 0 {
 		query = query.Where("currency IN ?", currencies)
 	}
+	if len(accountIDs) > 0 {
+		query = query.Where("account_id IN ?", accountIDs)
+	}
 	if startDate != nil {
 		query = query.Where("transaction_date >= ?", *startDate)
 	}
@@ -205,7 +218,7 @@ func (r *TransactionRepository) GetWithFilters(userID *uuid.UUID, symbols []stri
 }
 
 // CountWithFilters returns the count of transactions based on filters
-func (r *TransactionRepository) CountWithFilters(userID *uuid.UUID, symbols []string, types []string, exchanges []string, brokers []string, currencies []string,
+func (r *TransactionRepository) CountWithFilters(userID *uuid.UUID, symbols []string, types []string, exchanges []string, brokers []string, currencies []string, accountIDs []uuid.UUID,
 	startDate *time.Time, endDate *time.Time, minAmount *float64, maxAmount *float64) (int64, error) {
 
 	var count int64
@@ -230,6 +243,9 @@ func (r *TransactionRepository) CountWithFilters(userID *uuid.UUID, symbols []st
 	if len(currencies) > 0 {
 		query = query.Where("currency IN ?", currencies)
 	}
+	if len(accountIDs) > 0 {
+		query = query.Where("account_id IN ?", accountIDs)
+	}
 	if startDate != nil {
 		query = query.Where("transaction_date >= ?", *startDate)
 	}
@@ -274,8 +290,8 @@ func (r *TransactionRepository) GetPortfolioSummaryByUserID(userID uuid.UUID) (m
 		return nil, fmt.Errorf("failed to sum sell amounts: %w", err)
 	}
 
-	// Count unique symbols
-	if err := r.db.Model(&models.Transaction{}).Where("user_id = ?", userID).Distinct("symbol").Count(&result.UniqueSymbols).Error; err != nil {
+	// Count unique symbols, leaving out the currencies cash transactions are recorded under
+	if err := r.db.Model(&models.Transaction{}).Where("user_id = ? AND trade_type NOT IN ?", userID, types.CashTradeTypes()).Distinct("symbol").Count(&result.UniqueSymbols).Error; err != nil {
 		return nil, fmt.Errorf("failed to count unique symbols: %w", err)
 	}
 
@@ -344,3 +360,45 @@ func (r *TransactionRepository) GetByUserIDAndSymbol(userID uuid.UUID, symbol st
 	}
 	return transactions, nil
 }
+
+// GetByUserIDAndTradeType retrieves a user's transactions of one trade type, optionally within [startDate, endDate]
+func (r *TransactionRepository) GetByUserIDAndTradeType(userID uuid.UUID, tradeType types.TradeType, startDate, endDate *time.Time) ([]models.Transaction, error) {
+	query := r.db.Where("user_id = ? AND trade_type = ?", userID, tradeType)
+	if startDate != nil {
+		query = query.Where("transaction_date >= ?", *startDate)
+	}
+	if endDate != nil {
+		query = query.Where("transaction_date <= ?", *endDate)
+	}
+
+	var transactions []models.Transaction
+	if err := query.Order("transaction_date ASC").Find(&transactions).Error; err != nil {
+		return nil, fmt.Errorf("failed to get %s transactions for user %s: %w", tradeType, userID, err)
+	}
+	return transactions, nil
+}
+
+// GetUserIDsBySymbols returns the users that have transactions in any of the symbols
+func (r *TransactionRepository) GetUserIDsBySymbols(symbols []string) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	err := r.db.Model(&models.Transaction{}).
+		Where("symbol IN ?", symbols).
+		Distinct("user_id").
+		Pluck("user_id", &userIDs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users for symbols: %w", err)
+	}
+	return userIDs, nil
+}
+
+// GetUserIDs returns every user that has transactions
+func (r *TransactionRepository) GetUserIDs() ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	err := r.db.Model(&models.Transaction{}).
+		Distinct("user_id").
+		Pluck("user_id", &userIDs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users with transactions: %w", err)
+	}
+	return userIDs, nil
+}
@@ -14,6 +14,7 @@ type UserRepository interface {
 	FindByUserID(userID uuid.UUID) (*models.User, error)
 	FindByEmail(email string) (*models.User, error)
 	Update(user *models.User) error
+	UpdateFields(userID uuid.UUID, updates map[string]interface{}) error
 }
 
 // userRepository implements UserRepository
@@ -67,3 +68,11 @@ func (r *userRepository) Update(user *models.User) error {
 	}
 	return nil
 }
+
+// UpdateFields updates selected columns of a user
+func (r *userRepository) UpdateFields(userID uuid.UUID, updates map[string]interface{}) error {
+	if err := r.db.Model(&models.User{}).Where("user_id = ?", userID).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/repositories"
+	"github.com/transaction-tracker/backend/internal/utils"
+)
+
+// maxAccountNameLength is the longest name or broker an account can be given
+const maxAccountNameLength = 100
+
+// AccountService handles the brokerage accounts users keep transactions in
+type AccountService struct {
+	accountRepo     *repositories.AccountRepository
+	transactionRepo *repositories.TransactionRepository
+	portfolioRepo   *repositories.PortfolioRepository
+}
+
+// NewAccountService creates a new account service
+func NewAccountService(accountRepo *repositories.AccountRepository, transactionRepo *repositories.TransactionRepository, portfolioRepo *repositories.PortfolioRepository) *AccountService {
+	return &AccountService{
+		accountRepo:     accountRepo,
+		transactionRepo: transactionRepo,
+		portfolioRepo:   portfolioRepo,
+	}
+}
+
+// ListAccounts retrieves a user's accounts
+func (s *AccountService) ListAccounts(userID uuid.UUID) ([]models.Account, error) {
+	return s.accountRepo.GetByUserID(userID)
+}
+
+// GetAccount retrieves one of a user's accounts
+func (s *AccountService) GetAccount(userID, accountID uuid.UUID) (*models.Account, error) {
+	account, err := s.accountRepo.GetByIDAndUserID(accountID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("not_found")
+	}
+	return account, nil
+}
+
+// CreateAccount validates and records a new account for a user
+func (s *AccountService) CreateAccount(userID uuid.UUID, account models.Account) (*models.Account, error) {
+	if err := normalizeAccount(&account); err != nil {
+		return nil, err
+	}
+	if err := s.checkPortfolio(userID, account.PortfolioID); err != nil {
+		return nil, err
+	}
+
+	account.UserID = userID
+	if err := s.accountRepo.Create(&account); err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// UpdateAccount validates and replaces the details of one of a user's accounts
+func (s *AccountService) UpdateAccount(userID, accountID uuid.UUID, account models.Account) (*models.Account, error) {
+	if _, err := s.accountRepo.GetByIDAndUserID(accountID, userID); err != nil {
+		return nil, fmt.Errorf("not_found")
+	}
+
+	if err := normalizeAccount(&account); err != nil {
+		return nil, err
+	}
+	if err := s.checkPortfolio(userID, account.PortfolioID); err != nil {
+		return nil, err
+	}
+
+	if err := s.accountRepo.UpdateByIDAndUserID(accountID, userID, map[string]interface{}{
+		"portfolio_id":  account.PortfolioID,
+		"name":          account.Name,
+		"broker":        account.Broker,
+		"account_type":  account.AccountType,
+		"base_currency": account.BaseCurrency,
+		"tax_treatment": account.TaxTreatment,
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.accountRepo.GetByIDAndUserID(accountID, userID)
+}
+
+// DeleteAccount deletes one of a user's accounts. Accounts still holding transactions are kept,
+// so that no transaction is left pointing at a deleted account.
+func (s *AccountService) DeleteAccount(userID, accountID uuid.UUID) error {
+	if _, err := s.accountRepo.GetByIDAndUserID(accountID, userID); err != nil {
+		return fmt.Errorf("not_found")
+	}
+
+	count, err := s.transactionRepo.CountByAccountID(accountID)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("account in use: %d transactions are recorded in it", count)
+	}
+
+	return s.accountRepo.DeleteByIDAndUserID(accountID, userID)
+}
+
+// checkPortfolio checks that the portfolio an account is assigned to belongs to the user
+func (s *AccountService) checkPortfolio(userID uuid.UUID, portfolioID *uuid.UUID) error {
+	if portfolioID == nil {
+		return nil
+	}
+	if _, err := s.portfolioRepo.GetByIDAndUserID(*portfolioID, userID); err != nil {
+		return fmt.Errorf("invalid account: portfolio %s not found", *portfolioID)
+	}
+	return nil
+}
+
+// normalizeAccount trims the account's names, fills the default currency and validates the account
+func normalizeAccount(account *models.Account) error {
+	account.Name = strings.TrimSpace(account.Name)
+	account.Broker = strings.TrimSpace(account.Broker)
+	account.BaseCurrency = strings.ToUpper(strings.TrimSpace(account.BaseCurrency))
+	if account.BaseCurrency == "" {
+		account.BaseCurrency = "USD"
+	}
+
+	if account.Name == "" || len(account.Name) > maxAccountNameLength {
+		return fmt.Errorf("invalid account: name must be 1 to %d characters", maxAccountNameLength)
+	}
+	if len(account.Broker) > maxAccountNameLength {
+		return fmt.Errorf("invalid account: broker must be at most %d characters", maxAccountNameLength)
+	}
+	if !account.AccountType.IsValid() {
+		return fmt.Errorf("invalid account: account_type must be one of brokerage, retirement, education or other")
+	}
+	if !account.TaxTreatment.IsValid() {
+		return fmt.Errorf("invalid account: tax_treatment must be one of taxable, tax_deferred or tax_exempt")
+	}
+	if !utils.CurrencyRegex.MatchString(account.BaseCurrency) {
+		return fmt.Errorf("invalid account: base_currency must be a 3-letter ISO 4217 currency code")
+	}
+	return nil
+}
@@ -0,0 +1,353 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/utils"
+)
+
+// Keys of the allocation groups that are not named after a holding attribute
+const (
+	AllocationGroupCash    = "Cash"
+	AllocationGroupUnknown = "Unknown"
+)
+
+// SymbolMetadataSource looks up the sector, industry and country of symbols.
+// Symbols it knows nothing about are left out of the result.
+type SymbolMetadataSource interface {
+	GetBySymbols(symbols []string) ([]models.SymbolMetadata, error)
+}
+
+// symbolCountrySuffixes maps exchange suffixes of symbols to the country the exchange is in
+var symbolCountrySuffixes = map[string]string{
+	".TW":  "TW",
+	".TWO": "TW",
+	".HK":  "HK",
+	".T":   "JP",
+	".L":   "GB",
+	".SS":  "CN",
+	".SZ":  "CN",
+	".KS":  "KR",
+	".TO":  "CA",
+	".AX":  "AU",
+}
+
+// symbolCountry infers the country of a symbol without metadata from its exchange suffix;
+// symbols without a suffix quoted in US dollars are taken to be listed in the US. Crypto pairs
+// are not listed in any country.
+func symbolCountry(symbol, currency string) string {
+	if utils.CryptoSymbolRegex.MatchString(symbol) {
+		return ""
+	}
+	if index := strings.LastIndex(symbol, "."); index > 0 {
+		if country, ok := symbolCountrySuffixes[symbol[index:]]; ok {
+			return country
+		}
+		return ""
+	}
+	if currency == "USD" {
+		return "US"
+	}
+	return ""
+}
+
+// symbolAssetClass returns the asset class of a symbol from its metadata, falling back to crypto for crypto pairs
+func symbolAssetClass(symbol string, metadata map[string]models.SymbolMetadata) string {
+	if assetClass := metadata[symbol].AssetClass; assetClass != "" {
+		return assetClass
+	}
+	if utils.CryptoSymbolRegex.MatchString(symbol) {
+		return models.AssetClassCrypto
+	}
+	return ""
+}
+
+// AllocationPosition is the part of a holding kept in one account with one broker, valued in the
+// base currency. AccountID is nil for shares recorded in no account.
+type AllocationPosition struct {
+	Symbol      string
+	Broker      string
+	AccountID   *uuid.UUID
+	Exchange    string
+	Currency    string
+	MarketValue float64
+}
+
+// accountKey returns the key an account is grouped under, empty for no account
+func accountKey(accountID *uuid.UUID) string {
+	if accountID == nil {
+		return ""
+	}
+	return accountID.String()
+}
+
+// positionHolder identifies where part of a holding is kept
+type positionHolder struct {
+	broker    string
+	accountID string
+}
+
+// AllocationPositions splits each valued holding across the brokers and accounts its shares are
+// kept in, in proportion to the quantity each of them holds as of asOf. Short shares count
+// negative, so each part is valued at its own quantity whatever the side of the others.
+func AllocationPositions(engine *CostBasisEngine, holdings []models.SingleHolding, transactions []models.Transaction, asOf time.Time) []AllocationPosition {
+	transactionsBySymbol := engine.GroupBySymbol(transactions, asOf)
+
+	var positions []AllocationPosition
+	for _, holding := range holdings {
+		symbolTransactions := sortTransactionsByDate(transactionsBySymbol[holding.Symbol])
+
+		// The exchange of the latest trade, and the transactions recorded in each account
+		exchange := ""
+		byHolder := make(map[positionHolder][]models.Transaction)
+		accountIDs := make(map[string]*uuid.UUID)
+		for _, tx := range symbolTransactions {
+			if tx.Exchange != "" {
+				exchange = tx.Exchange
+			}
+			holder := positionHolder{broker: tx.Broker, accountID: accountKey(tx.AccountID)}
+			byHolder[holder] = append(byHolder[holder], tx)
+			accountIDs[holder.accountID] = tx.AccountID
+		}
+
+		quantities := make(map[positionHolder]float64)
+		totalQuantity := 0.0
+		for holder, holderTransactions := range byHolder {
+			if quantity := engine.CalculateAt(holderTransactions, asOf).TotalQuantity(); math.Abs(quantity) > quantityEpsilon {
+				quantities[holder] = quantity
+				totalQuantity += quantity
+			}
+		}
+		if math.Abs(totalQuantity) <= quantityEpsilon {
+			// Sales recorded in another account than the purchases; keep the holding whole
+			quantities = map[positionHolder]float64{{}: 1}
+			totalQuantity = 1
+		}
+
+		for holder, quantity := range quantities {
+			positions = append(positions, AllocationPosition{
+				Symbol:      holding.Symbol,
+				Broker:      holder.broker,
+				AccountID:   accountIDs[holder.accountID],
+				Exchange:    exchange,
+				Currency:    holding.Currency,
+				MarketValue: holding.MarketValue * quantity / totalQuantity,
+			})
+		}
+	}
+
+	sort.Slice(positions, func(i, j int) bool {
+		if positions[i].Symbol != positions[j].Symbol {
+			return positions[i].Symbol < positions[j].Symbol
+		}
+		if positions[i].Broker != positions[j].Broker {
+			return positions[i].Broker < positions[j].Broker
+		}
+		return accountKey(positions[i].AccountID) < accountKey(positions[j].AccountID)
+	})
+	return positions
+}
+
+// BuildAllocation groups positions and cash balances by one dimension and weighs each group against
+// the total. Sector and country come from metadata, keyed by symbol; country falls back to the
+// symbol's exchange suffix. Accounts are keyed by their ID. Cash is kept in its own group unless
+// grouping by currency, broker or account.
+func BuildAllocation(groupBy models.AllocationGroupBy, positions []AllocationPosition, cash []models.CashBalance, metadata map[string]models.SymbolMetadata) []models.AllocationGroup {
+	groups := make(map[string]*models.AllocationGroup)
+	add := func(key, symbol string, value float64) {
+		if key == "" {
+			key = AllocationGroupUnknown
+		}
+		group, ok := groups[key]
+		if !ok {
+			group = &models.AllocationGroup{Key: key, Symbols: []string{}}
+			groups[key] = group
+		}
+		group.MarketValue += value
+		if symbol != "" && (len(group.Symbols) == 0 || group.Symbols[len(group.Symbols)-1] != symbol) {
+			group.Symbols = append(group.Symbols, symbol)
+		}
+	}
+
+	total := 0.0
+	for _, position := range positions {
+		var key string
+		switch groupBy {
+		case models.AllocationGroupBySector:
+			key = metadata[position.Symbol].Sector
+		case models.AllocationGroupByExchange:
+			key = position.Exchange
+		case models.AllocationGroupByCurrency:
+			key = position.Currency
+		case models.AllocationGroupByBroker:
+			key = position.Broker
+		case models.AllocationGroupByCountry:
+			key = metadata[position.Symbol].Country
+			if key == "" {
+				key = symbolCountry(position.Symbol, position.Currency)
+			}
+		case models.AllocationGroupByAccount:
+			key = accountKey(position.AccountID)
+		}
+		add(key, position.Symbol, position.MarketValue)
+		total += position.MarketValue
+	}
+
+	for _, balance := range cash {
+		switch groupBy {
+		case models.AllocationGroupByCurrency:
+			add(balance.Currency, "", balance.Value)
+		case models.AllocationGroupByBroker:
+			add(balance.Broker, "", balance.Value)
+		case models.AllocationGroupByAccount:
+			add(accountKey(balance.AccountID), "", balance.Value)
+		default:
+			add(AllocationGroupCash, "", balance.Value)
+		}
+		total += balance.Value
+	}
+
+	result := make([]models.AllocationGroup, 0, len(groups))
+	for _, group := range groups {
+		if total != 0 {
+			group.Weight = utils.RoundTo4(group.MarketValue / total * 100)
+		}
+		group.MarketValue = utils.RoundTo4(group.MarketValue)
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].MarketValue != result[j].MarketValue {
+			return result[i].MarketValue > result[j].MarketValue
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+// symbolMetadata looks up the metadata of symbols keyed by symbol. Symbols without metadata of
+// their own are described by the symbol the price service quotes them under on the exchange of
+// their transactions, e.g. 2330 on TPE by 2330.TW. Failures are logged and leave the holdings in
+// the unknown group rather than failing the breakdown.
+func (s *PortfolioService) symbolMetadata(symbols []string, transactions []models.Transaction) map[string]models.SymbolMetadata {
+	metadata := make(map[string]models.SymbolMetadata)
+	if s.metadataSource == nil || len(symbols) == 0 {
+		return metadata
+	}
+
+	quotes := quoteSymbols(transactions)
+	lookup := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		lookup = append(lookup, symbol)
+		if quote, ok := quotes[symbol]; ok {
+			lookup = append(lookup, quote)
+		}
+	}
+
+	found, err := s.metadataSource.GetBySymbols(lookup)
+	if err != nil {
+		fmt.Printf("Warning: failed to get symbol metadata: %v\n", err)
+		return metadata
+	}
+	bySymbol := make(map[string]models.SymbolMetadata, len(found))
+	for _, m := range found {
+		bySymbol[m.Symbol] = m
+	}
+	for _, symbol := range symbols {
+		if m, ok := bySymbol[symbol]; ok {
+			metadata[symbol] = m
+		} else if m, ok := bySymbol[quotes[symbol]]; ok {
+			metadata[symbol] = m
+		}
+	}
+	return metadata
+}
+
+// describeHoldings fills in the name, asset class and sector of holdings from their symbol
+// metadata, quoting them on the exchange of their transactions
+func (s *PortfolioService) describeHoldings(holdings []*models.SingleHolding, transactions []models.Transaction) {
+	symbols := make([]string, len(holdings))
+	for i, holding := range holdings {
+		symbols[i] = holding.Symbol
+	}
+
+	metadata := s.symbolMetadata(symbols, transactions)
+	for _, holding := range holdings {
+		holding.Name = metadata[holding.Symbol].Name
+		holding.AssetClass = symbolAssetClass(holding.Symbol, metadata)
+		holding.Sector = metadata[holding.Symbol].Sector
+	}
+}
+
+// GetAllocation breaks the portfolio's current holdings and cash in scope down by one dimension.
+// Account groups are named after their accounts.
+func (s *PortfolioService) GetAllocation(ctx context.Context, userID uuid.UUID, scope PortfolioScope, groupBy models.AllocationGroupBy) (*models.AllocationResponse, error) {
+	if !groupBy.IsValid() {
+		return nil, fmt.Errorf("invalid group by: %s", groupBy)
+	}
+
+	settings, err := s.GetSettings(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load portfolio settings: %w", err)
+	}
+
+	engine, err := s.costBasisEngine(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cost basis settings: %w", err)
+	}
+
+	transactions, err := s.scopedTransactions(userID, scope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions for allocation: %w", err)
+	}
+
+	now := time.Now()
+	fx := s.fxConverter(ctx, settings.BaseCurrency)
+	holdings := s.getAllHoldings(ctx, engine, fx, transactions)
+	positions := AllocationPositions(engine, holdings, transactions, now)
+
+	cashBalances, _, err := valueCashBalances(fx, transactions, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to value cash balances in %s: %w", fx.BaseCurrency(), err)
+	}
+
+	symbols := make([]string, 0, len(positions))
+	for _, position := range positions {
+		symbols = append(symbols, position.Symbol)
+	}
+
+	groups := BuildAllocation(groupBy, positions, cashBalances, s.symbolMetadata(symbols, transactions))
+	if groupBy == models.AllocationGroupByAccount {
+		accounts, err := s.accountRepo.GetByUserID(userID)
+		if err != nil {
+			return nil, err
+		}
+		names := make(map[string]string, len(accounts))
+		for _, account := range accounts {
+			names[account.ID.String()] = account.Name
+		}
+		for i := range groups {
+			if name, ok := names[groups[i].Key]; ok {
+				groups[i].Key = name
+			}
+		}
+	}
+	totalValue := 0.0
+	for _, group := range groups {
+		totalValue += group.MarketValue
+	}
+
+	return &models.AllocationResponse{
+		GroupBy:    groupBy,
+		Currency:   fx.BaseCurrency(),
+		TotalValue: utils.RoundTo4(totalValue),
+		Groups:     groups,
+		Timestamp:  now,
+	}, nil
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/types"
+)
+
+// cashAccount identifies a cash balance: cash is held per account, or per broker for transactions
+// recorded in no account, with one balance per currency
+type cashAccount struct {
+	broker    string
+	accountID uuid.UUID
+	currency  string
+}
+
+// CashBalances replays the cash flows of transactions dated on or before asOf into running
+// balances per broker and currency. Deposits, sales, dividends and interest add cash;
+// withdrawals, purchases and fees take it out, all net of their fees and taxes.
+// A balance goes negative when purchases were funded by deposits that were never recorded.
+func CashBalances(transactions []models.Transaction, asOf time.Time) []models.CashBalance {
+	balances := make(map[cashAccount]float64)
+	for _, tx := range transactionsUpTo(transactions, asOf) {
+		account := cashAccount{broker: tx.Broker, currency: normalizeCurrency(tx.Currency)}
+		if tx.AccountID != nil {
+			account.accountID = *tx.AccountID
+		}
+		balances[account] += tx.CashFlow()
+	}
+
+	result := make([]models.CashBalance, 0, len(balances))
+	for account, balance := range balances {
+		cashBalance := models.CashBalance{
+			Broker:   account.broker,
+			Currency: account.currency,
+			Balance:  balance,
+		}
+		if account.accountID != uuid.Nil {
+			accountID := account.accountID
+			cashBalance.AccountID = &accountID
+		}
+		result = append(result, cashBalance)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Broker != result[j].Broker {
+			return result[i].Broker < result[j].Broker
+		}
+		if accountKey(result[i].AccountID) != accountKey(result[j].AccountID) {
+			return accountKey(result[i].AccountID) < accountKey(result[j].AccountID)
+		}
+		return result[i].Currency < result[j].Currency
+	})
+	return result
+}
+
+// valueCashBalances returns the cash balances at asOf with each valued in the base currency
+// at the rate on asOf, together with their total
+func valueCashBalances(fx *FXConverter, transactions []models.Transaction, asOf time.Time) ([]models.CashBalance, float64, error) {
+	balances := CashBalances(transactions, asOf)
+
+	var total float64
+	for i := range balances {
+		value, err := fx.Convert(balances[i].Balance, balances[i].Currency, asOf)
+		if err != nil {
+			return nil, 0, err
+		}
+		balances[i].Value = value
+		total += value
+	}
+	return balances, total, nil
+}
+
+// interestIncome sums the interest received among transactions, net of any fees and taxes
+func interestIncome(transactions []models.Transaction) float64 {
+	var income float64
+	for _, tx := range transactions {
+		if tx.TradeType == types.TradeTypeInterest {
+			income += tx.NetAmount()
+		}
+	}
+	return income
+}
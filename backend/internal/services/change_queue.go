@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChangeQueue is a TransactionChangeListener that hands changes to its own listeners in the
+// background, so a request affecting many users does not wait for every user's ledgers to be
+// rebuilt. Changes of a user queued before the earlier ones are handled coalesce into one, since
+// the earliest of their dates.
+type ChangeQueue struct {
+	listeners []TransactionChangeListener
+
+	mu      sync.Mutex
+	pending map[uuid.UUID]time.Time
+	order   []uuid.UUID
+	wake    chan struct{}
+}
+
+// NewChangeQueue creates a queue handing changes to listeners once Run is started
+func NewChangeQueue(listeners ...TransactionChangeListener) *ChangeQueue {
+	return &ChangeQueue{
+		listeners: listeners,
+		pending:   make(map[uuid.UUID]time.Time),
+		wake:      make(chan struct{}, 1),
+	}
+}
+
+// OnTransactionsChanged queues the change of a user's ledger since a date
+func (q *ChangeQueue) OnTransactionsChanged(userID uuid.UUID, since time.Time) error {
+	q.mu.Lock()
+	queued, exists := q.pending[userID]
+	if !exists {
+		q.order = append(q.order, userID)
+	}
+	if !exists || since.Before(queued) {
+		q.pending[userID] = since
+	}
+	q.mu.Unlock()
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Pending returns the number of users with changes waiting to be handled
+func (q *ChangeQueue) Pending() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.order)
+}
+
+// Queued reports whether a change of a user is waiting to be handled
+func (q *ChangeQueue) Queued(userID uuid.UUID) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	_, queued := q.pending[userID]
+	return queued
+}
+
+// Run hands queued changes to the listeners, in the order users were queued, until ctx is done
+func (q *ChangeQueue) Run(ctx context.Context) {
+	for {
+		for q.handleNext(ctx) {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.wake:
+		}
+	}
+}
+
+// handleNext hands the change of the first queued user to the listeners, returning false when
+// nothing is queued or ctx is done
+func (q *ChangeQueue) handleNext(ctx context.Context) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	q.mu.Lock()
+	if len(q.order) == 0 {
+		q.mu.Unlock()
+		return false
+	}
+	userID := q.order[0]
+	q.order = q.order[1:]
+	since := q.pending[userID]
+	delete(q.pending, userID)
+	q.mu.Unlock()
+
+	for _, listener := range q.listeners {
+		if err := listener.OnTransactionsChanged(userID, since); err != nil {
+			fmt.Printf("Warning: failed to process queued changes for user %s: %v\n", userID, err)
+		}
+	}
+	return true
+}
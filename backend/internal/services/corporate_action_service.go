@@ -0,0 +1,203 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/repositories"
+	"github.com/transaction-tracker/backend/internal/utils"
+)
+
+// CorporateActionService handles corporate action business logic
+type CorporateActionService struct {
+	corporateActionRepo *repositories.CorporateActionRepository
+	transactionRepo     *repositories.TransactionRepository
+	listeners           []TransactionChangeListener
+}
+
+// NewCorporateActionService creates a new corporate action service
+func NewCorporateActionService(corporateActionRepo *repositories.CorporateActionRepository, transactionRepo *repositories.TransactionRepository) *CorporateActionService {
+	return &CorporateActionService{
+		corporateActionRepo: corporateActionRepo,
+		transactionRepo:     transactionRepo,
+	}
+}
+
+// AddChangeListener registers a listener notified for every user holding a symbol whose corporate actions change
+func (s *CorporateActionService) AddChangeListener(listener TransactionChangeListener) {
+	s.listeners = append(s.listeners, listener)
+}
+
+// ListCorporateActions retrieves all corporate actions, or only those involving symbol when it is set
+func (s *CorporateActionService) ListCorporateActions(symbol string) ([]models.CorporateAction, error) {
+	if symbol == "" {
+		return s.corporateActionRepo.GetAll()
+	}
+	return s.corporateActionRepo.GetBySymbols([]string{strings.ToUpper(symbol)})
+}
+
+// CreateCorporateAction validates and records a corporate action
+func (s *CorporateActionService) CreateCorporateAction(action models.CorporateAction) (*models.CorporateAction, error) {
+	if err := normalizeCorporateAction(&action); err != nil {
+		return nil, err
+	}
+
+	if err := s.corporateActionRepo.Create(&action); err != nil {
+		return nil, err
+	}
+
+	s.notifyAffectedUsers(action.EffectiveDate, action)
+	return &action, nil
+}
+
+// UpdateCorporateAction validates and replaces the details of a corporate action
+func (s *CorporateActionService) UpdateCorporateAction(id uuid.UUID, action models.CorporateAction) (*models.CorporateAction, error) {
+	existing, err := s.corporateActionRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("not_found")
+	}
+
+	if err := normalizeCorporateAction(&action); err != nil {
+		return nil, err
+	}
+
+	if err := s.corporateActionRepo.UpdateByID(id, map[string]interface{}{
+		"symbol":         action.Symbol,
+		"action_type":    action.ActionType,
+		"effective_date": action.EffectiveDate,
+		"ratio_from":     action.RatioFrom,
+		"ratio_to":       action.RatioTo,
+		"new_symbol":     action.NewSymbol,
+		"notes":          action.Notes,
+	}); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.corporateActionRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	since := existing.EffectiveDate
+	if updated.EffectiveDate.Before(since) {
+		since = updated.EffectiveDate
+	}
+	s.notifyAffectedUsers(since, *existing, *updated)
+	return updated, nil
+}
+
+// DeleteCorporateAction deletes a corporate action
+func (s *CorporateActionService) DeleteCorporateAction(id uuid.UUID) error {
+	existing, err := s.corporateActionRepo.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("not_found")
+	}
+
+	if err := s.corporateActionRepo.DeleteByID(id); err != nil {
+		return err
+	}
+
+	s.notifyAffectedUsers(existing.EffectiveDate, *existing)
+	return nil
+}
+
+// normalizeCorporateAction upper-cases symbols, fills implied ratios and validates the action
+func normalizeCorporateAction(action *models.CorporateAction) error {
+	action.Symbol = strings.ToUpper(strings.TrimSpace(action.Symbol))
+	action.NewSymbol = strings.ToUpper(strings.TrimSpace(action.NewSymbol))
+
+	if !utils.SymbolRegex.MatchString(action.Symbol) {
+		return fmt.Errorf("invalid corporate action: symbol %q is not valid", action.Symbol)
+	}
+	if !action.ActionType.IsValid() {
+		return fmt.Errorf("invalid corporate action: action_type must be one of: split, reverse_split, rename, merger")
+	}
+	if action.EffectiveDate.IsZero() {
+		return fmt.Errorf("invalid corporate action: effective_date is required")
+	}
+
+	switch action.ActionType {
+	case models.CorporateActionSplit, models.CorporateActionReverseSplit:
+		if action.NewSymbol != "" {
+			return fmt.Errorf("invalid corporate action: new_symbol is not allowed for %s", action.ActionType)
+		}
+		if action.RatioFrom <= 0 || action.RatioTo <= 0 {
+			return fmt.Errorf("invalid corporate action: ratio_from and ratio_to must be positive")
+		}
+		if action.ActionType == models.CorporateActionSplit && action.RatioTo <= action.RatioFrom {
+			return fmt.Errorf("invalid corporate action: a split must increase the share count")
+		}
+		if action.ActionType == models.CorporateActionReverseSplit && action.RatioTo >= action.RatioFrom {
+			return fmt.Errorf("invalid corporate action: a reverse split must decrease the share count")
+		}
+	case models.CorporateActionRename:
+		// A rename keeps the share count
+		action.RatioFrom, action.RatioTo = 1, 1
+	case models.CorporateActionMerger:
+		if action.RatioFrom <= 0 || action.RatioTo <= 0 {
+			return fmt.Errorf("invalid corporate action: ratio_from and ratio_to must be positive")
+		}
+	}
+
+	if action.ActionType == models.CorporateActionRename || action.ActionType == models.CorporateActionMerger {
+		if !utils.SymbolRegex.MatchString(action.NewSymbol) {
+			return fmt.Errorf("invalid corporate action: new_symbol is required for %s", action.ActionType)
+		}
+		if action.NewSymbol == action.Symbol {
+			return fmt.Errorf("invalid corporate action: new_symbol must differ from symbol")
+		}
+	}
+
+	return nil
+}
+
+// notifyAffectedUsers informs listeners about every user holding the actions' symbols under any earlier name
+func (s *CorporateActionService) notifyAffectedUsers(since time.Time, actions ...models.CorporateAction) {
+	if len(s.listeners) == 0 {
+		return
+	}
+
+	symbols := make(map[string]bool)
+	for _, action := range actions {
+		symbols[action.Symbol] = true
+		symbols[action.TargetSymbol()] = true
+	}
+
+	// Users may still record the holding under a name it had before an earlier rename or merger
+	allActions, err := s.corporateActionRepo.GetAll()
+	if err != nil {
+		fmt.Printf("Warning: failed to load corporate actions: %v\n", err)
+		return
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, action := range allActions {
+			if symbols[action.TargetSymbol()] && !symbols[action.Symbol] {
+				symbols[action.Symbol] = true
+				changed = true
+			}
+		}
+	}
+
+	symbolList := make([]string, 0, len(symbols))
+	for symbol := range symbols {
+		symbolList = append(symbolList, symbol)
+	}
+
+	userIDs, err := s.transactionRepo.GetUserIDsBySymbols(symbolList)
+	if err != nil {
+		fmt.Printf("Warning: failed to find users affected by corporate action: %v\n", err)
+		return
+	}
+
+	for _, userID := range userIDs {
+		for _, listener := range s.listeners {
+			if err := listener.OnTransactionsChanged(userID, since); err != nil {
+				fmt.Printf("Warning: failed to process corporate action for user %s: %v\n", userID, err)
+			}
+		}
+	}
+}
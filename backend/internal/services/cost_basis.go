@@ -0,0 +1,440 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/types"
+	"github.com/transaction-tracker/backend/internal/utils"
+)
+
+// quantityEpsilon absorbs float noise when comparing share quantities
+const quantityEpsilon = 1e-9
+
+// CostBasisResult holds the lots produced by replaying a symbol's transactions
+type CostBasisResult struct {
+	OpenLots   []models.OpenLot
+	ClosedLots []models.ClosedLot
+	// UnmatchedQuantity is the number of shares sold or covered without any open lot to consume
+	UnmatchedQuantity float64
+	// UnmatchedTrades holds the unmatched shares of each sale or cover that had any
+	UnmatchedTrades map[uuid.UUID]float64
+}
+
+// TotalQuantity returns the number of shares still held, negative for a short position
+func (r *CostBasisResult) TotalQuantity() float64 {
+	var quantity float64
+	for _, lot := range r.OpenLots {
+		quantity += lot.Quantity
+	}
+	return quantity
+}
+
+// TotalCost returns the cost basis of the shares still held, negative for a short position
+func (r *CostBasisResult) TotalCost() float64 {
+	var cost float64
+	for _, lot := range r.OpenLots {
+		cost += lot.CostBasis()
+	}
+	return cost
+}
+
+// UnitCost returns the average cost per share still held, or the average price sold short at
+func (r *CostBasisResult) UnitCost() float64 {
+	quantity := r.TotalQuantity()
+	if math.Abs(quantity) <= quantityEpsilon {
+		return 0
+	}
+	return r.TotalCost() / quantity
+}
+
+// RealizedGainLoss returns the sum of gains and losses over all closed lots
+func (r *CostBasisResult) RealizedGainLoss() float64 {
+	var gainLoss float64
+	for _, lot := range r.ClosedLots {
+		gainLoss += lot.GainLoss()
+	}
+	return gainLoss
+}
+
+// CostBasisEngine replays a symbol's transactions into open and closed lots
+type CostBasisEngine struct {
+	method     models.CostBasisMethod
+	selections map[uuid.UUID][]models.LotSelection
+	// actions are the corporate actions applied to open lots, ordered by effective date
+	actions []models.CorporateAction
+	// contracts are the recorded option contracts by symbol
+	contracts map[string]models.OptionContract
+}
+
+// NewCostBasisEngine creates a cost basis engine for the given method.
+// Selections are only consulted by the specific lot method; sales without a
+// selection fall back to FIFO, which is what brokers apply by default.
+func NewCostBasisEngine(method models.CostBasisMethod, selections []models.LotSelection) *CostBasisEngine {
+	if !method.IsValid() {
+		method = models.DefaultCostBasisMethod
+	}
+
+	bySell := make(map[uuid.UUID][]models.LotSelection)
+	for _, selection := range selections {
+		bySell[selection.SellTransactionID] = append(bySell[selection.SellTransactionID], selection)
+	}
+
+	return &CostBasisEngine{
+		method:     method,
+		selections: bySell,
+	}
+}
+
+// WithCorporateActions makes the engine adjust open lots through splits, renames and mergers
+func (e *CostBasisEngine) WithCorporateActions(actions []models.CorporateAction) *CostBasisEngine {
+	sorted := make([]models.CorporateAction, len(actions))
+	copy(sorted, actions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].EffectiveDate.Before(sorted[j].EffectiveDate)
+	})
+	e.actions = sorted
+	return e
+}
+
+// WithOptionContracts makes the engine use the recorded terms of option contracts, such as an adjusted
+// multiplier, rather than those read from their symbols
+func (e *CostBasisEngine) WithOptionContracts(contracts []models.OptionContract) *CostBasisEngine {
+	e.contracts = make(map[string]models.OptionContract, len(contracts))
+	for _, contract := range contracts {
+		e.contracts[contract.Symbol] = contract
+	}
+	return e
+}
+
+// OptionContract returns the option contract traded under symbol: the recorded one, or else the
+// standard contract its OCC symbol describes. It reports false for symbols that are not options.
+func (e *CostBasisEngine) OptionContract(symbol string) (models.OptionContract, bool) {
+	if contract, ok := e.contracts[symbol]; ok {
+		return contract, true
+	}
+	if !utils.OptionSymbolRegex.MatchString(symbol) {
+		return models.OptionContract{}, false
+	}
+	return models.ParseOCCSymbol(symbol)
+}
+
+// Multiplier returns the number of shares one unit of symbol covers: the contract multiplier of an
+// option, and 1 for everything else. Option quantities are counted in contracts and prices quoted
+// per share, so market values are quantity times multiplier times price.
+func (e *CostBasisEngine) Multiplier(symbol string) float64 {
+	if contract, ok := e.OptionContract(symbol); ok && contract.Multiplier > 0 {
+		return contract.Multiplier
+	}
+	return 1
+}
+
+// Method returns the cost basis method used by the engine
+func (e *CostBasisEngine) Method() models.CostBasisMethod {
+	return e.method
+}
+
+// SymbolAt returns the symbol that shares traded as symbol on date are held under at asOf,
+// following the renames and mergers that took effect in between
+func (e *CostBasisEngine) SymbolAt(symbol string, date, asOf time.Time) string {
+	for _, action := range e.actions {
+		if !action.EffectiveDate.After(date) || action.EffectiveDate.After(asOf) {
+			continue
+		}
+		if action.Symbol == symbol {
+			symbol = action.TargetSymbol()
+		}
+	}
+	return symbol
+}
+
+// GroupBySymbol groups transactions dated on or before asOf by the symbol they are held under at asOf.
+// Cash transactions are left out since they do not belong to any holding.
+func (e *CostBasisEngine) GroupBySymbol(transactions []models.Transaction, asOf time.Time) map[string][]models.Transaction {
+	grouped := make(map[string][]models.Transaction)
+	for _, tx := range transactionsUpTo(transactions, asOf) {
+		if tx.TradeType.IsCash() {
+			continue
+		}
+		symbol := e.SymbolAt(tx.Symbol, tx.TransactionDate, asOf)
+		grouped[symbol] = append(grouped[symbol], tx)
+	}
+	return grouped
+}
+
+// Calculate replays the transactions of a single holding in chronological order as of now
+func (e *CostBasisEngine) Calculate(transactions []models.Transaction) *CostBasisResult {
+	return e.CalculateAt(transactions, time.Now())
+}
+
+// CalculateAt replays the transactions of a single holding up to asOf, applying the corporate
+// actions that took effect by then. An action applies before transactions dated on its effective date.
+// Lots are costed net of fees and taxes: they add to the cost of a buy and reduce the proceeds of a sale.
+// Short sales open lots of negative quantity that only buys to cover close. Option trades open and
+// close lots of contracts in the same way, and an option's lots are costed per contract.
+func (e *CostBasisEngine) CalculateAt(transactions []models.Transaction, asOf time.Time) *CostBasisResult {
+	result := &CostBasisResult{
+		OpenLots:        []models.OpenLot{},
+		ClosedLots:      []models.ClosedLot{},
+		UnmatchedTrades: make(map[uuid.UUID]float64),
+	}
+
+	nextAction := 0
+	applyActionsThrough := func(date time.Time) {
+		for nextAction < len(e.actions) && !e.actions[nextAction].EffectiveDate.After(date) {
+			if !e.actions[nextAction].EffectiveDate.After(asOf) {
+				applyCorporateAction(result, e.actions[nextAction])
+			}
+			nextAction++
+		}
+	}
+
+	for _, tx := range sortTransactionsByDate(transactions) {
+		if tx.TransactionDate.After(asOf) {
+			break
+		}
+		applyActionsThrough(tx.TransactionDate)
+
+		if tx.Quantity <= 0 {
+			continue
+		}
+
+		switch tx.TradeType {
+		case types.TradeTypeBuy, types.TradeTypeBuyToOpen:
+			result.OpenLots = append(result.OpenLots, models.OpenLot{
+				TransactionID: tx.TransactionID,
+				Symbol:        tx.Symbol,
+				AcquiredAt:    tx.TransactionDate,
+				Quantity:      tx.Quantity,
+				UnitCost:      tx.NetAmount() / tx.Quantity,
+			})
+		case types.TradeTypeSell, types.TradeTypeSellToClose:
+			e.closeLots(result, tx, false, atPrice(tx.NetAmount()/tx.Quantity))
+		case types.TradeTypeSellShort, types.TradeTypeSellToOpen:
+			result.OpenLots = append(result.OpenLots, models.OpenLot{
+				TransactionID: tx.TransactionID,
+				Symbol:        tx.Symbol,
+				AcquiredAt:    tx.TransactionDate,
+				Quantity:      -tx.Quantity,
+				UnitCost:      tx.NetAmount() / tx.Quantity,
+			})
+		case types.TradeTypeBuyToCover, types.TradeTypeBuyToClose:
+			e.closeLots(result, tx, true, atPrice(tx.NetAmount()/tx.Quantity))
+		case types.TradeTypeExpiration:
+			// Expired contracts close at no value on whichever side was open; any costs
+			// charged reduce the proceeds of long contracts or add to the cost of short ones
+			short := hasShortLots(result, tx.Symbol)
+			costs := tx.TransactionCosts.Total() / tx.Quantity
+			if !short {
+				costs = -costs
+			}
+			e.closeLots(result, tx, short, atPrice(costs))
+		case types.TradeTypeAssignment:
+			// The premium of assigned contracts carries over into the underlying shares delivered,
+			// so closing them realizes nothing
+			e.closeLots(result, tx, true, atCost)
+		}
+	}
+	applyActionsThrough(asOf)
+
+	return result
+}
+
+// applyCorporateAction converts the open lots of the action's symbol into post-action shares.
+// Total cost is preserved, so unit cost moves inversely to the share count.
+func applyCorporateAction(result *CostBasisResult, action models.CorporateAction) {
+	factor := action.ShareFactor()
+	for i := range result.OpenLots {
+		lot := &result.OpenLots[i]
+		if lot.Symbol != action.Symbol {
+			continue
+		}
+		lot.Quantity *= factor
+		lot.UnitCost /= factor
+		lot.Symbol = action.TargetSymbol()
+	}
+}
+
+// lotPrice returns the proceeds per share of a sale, or the cost per share of a cover, closing
+// shares of the given unit cost
+type lotPrice func(unitCost float64) float64
+
+// atPrice closes every lot at the same price
+func atPrice(price float64) lotPrice {
+	return func(float64) float64 { return price }
+}
+
+// atCost closes every lot at its own cost, realizing no gain or loss
+func atCost(unitCost float64) float64 {
+	return unitCost
+}
+
+// hasShortLots reports whether any open lot held under symbol is short
+func hasShortLots(result *CostBasisResult, symbol string) bool {
+	for _, lot := range result.OpenLots {
+		if lot.Symbol == symbol && lot.IsShort() {
+			return true
+		}
+	}
+	return false
+}
+
+// closeLots consumes open lots for a sale, or for a cover when short, according to the engine's method.
+// Only lots of the same side held under the traded symbol are eligible, which keeps merged holdings
+// apart until the merger.
+func (e *CostBasisEngine) closeLots(result *CostBasisResult, trade models.Transaction, short bool, price lotPrice) {
+	remaining := trade.Quantity
+
+	eligible := make([]int, 0, len(result.OpenLots))
+	for i, lot := range result.OpenLots {
+		if lot.Symbol == trade.Symbol && lot.IsShort() == short {
+			eligible = append(eligible, i)
+		}
+	}
+
+	// Under the average method every share closed carries the pool's average cost, or average
+	// proceeds for short lots
+	var averageCost float64
+	if e.method == models.CostBasisAverage {
+		var quantity, cost float64
+		for _, index := range eligible {
+			quantity += math.Abs(result.OpenLots[index].Quantity)
+			cost += math.Abs(result.OpenLots[index].CostBasis())
+		}
+		if quantity > quantityEpsilon {
+			averageCost = cost / quantity
+		}
+	}
+
+	consume := func(index int, quantity float64) {
+		lot := &result.OpenLots[index]
+		if open := math.Abs(lot.Quantity); quantity > open {
+			quantity = open
+		}
+		if quantity <= quantityEpsilon {
+			return
+		}
+
+		unitCost := lot.UnitCost
+		if e.method == models.CostBasisAverage {
+			unitCost = averageCost
+		}
+		pricePerShare := price(unitCost)
+
+		closed := models.ClosedLot{
+			BuyTransactionID:  lot.TransactionID,
+			SellTransactionID: trade.TransactionID,
+			Symbol:            trade.Symbol,
+			AcquiredAt:        lot.AcquiredAt,
+			DisposedAt:        trade.TransactionDate,
+			Quantity:          quantity,
+			CostBasis:         quantity * unitCost,
+			Proceeds:          quantity * pricePerShare,
+		}
+		if short {
+			closed.BuyTransactionID, closed.SellTransactionID = trade.TransactionID, lot.TransactionID
+			closed.CostBasis, closed.Proceeds = quantity*pricePerShare, quantity*unitCost
+			closed.Short = true
+			lot.Quantity += quantity
+		} else {
+			lot.Quantity -= quantity
+		}
+		result.ClosedLots = append(result.ClosedLots, closed)
+		remaining -= quantity
+	}
+
+	// Honour explicit lot selections first
+	if e.method == models.CostBasisSpecificLot {
+		for _, selection := range e.selections[trade.TransactionID] {
+			if remaining <= quantityEpsilon {
+				break
+			}
+			for _, index := range eligible {
+				if result.OpenLots[index].TransactionID == selection.BuyTransactionID {
+					quantity := selection.Quantity
+					if quantity > remaining {
+						quantity = remaining
+					}
+					consume(index, quantity)
+					break
+				}
+			}
+		}
+	}
+
+	for _, index := range e.lotOrder(result.OpenLots, eligible) {
+		if remaining <= quantityEpsilon {
+			break
+		}
+		consume(index, remaining)
+	}
+
+	if remaining > quantityEpsilon {
+		result.UnmatchedQuantity += remaining
+		result.UnmatchedTrades[trade.TransactionID] += remaining
+	}
+
+	// Drop exhausted lots and, for the average method, re-price the pool
+	openLots := result.OpenLots[:0]
+	for _, lot := range result.OpenLots {
+		if math.Abs(lot.Quantity) <= quantityEpsilon {
+			continue
+		}
+		if e.method == models.CostBasisAverage && lot.Symbol == trade.Symbol && lot.IsShort() == short {
+			lot.UnitCost = averageCost
+		}
+		openLots = append(openLots, lot)
+	}
+	result.OpenLots = openLots
+}
+
+// lotOrder returns the eligible lot indexes in the order they should be consumed
+func (e *CostBasisEngine) lotOrder(lots []models.OpenLot, eligible []int) []int {
+	order := make([]int, len(eligible))
+	copy(order, eligible)
+
+	switch e.method {
+	case models.CostBasisLIFO:
+		sort.SliceStable(order, func(i, j int) bool {
+			return lots[order[i]].AcquiredAt.After(lots[order[j]].AcquiredAt)
+		})
+	case models.CostBasisHIFO:
+		sort.SliceStable(order, func(i, j int) bool {
+			return lots[order[i]].UnitCost > lots[order[j]].UnitCost
+		})
+	default:
+		// Average, FIFO and the specific lot fallback consume the oldest lots first
+		sort.SliceStable(order, func(i, j int) bool {
+			return lots[order[i]].AcquiredAt.Before(lots[order[j]].AcquiredAt)
+		})
+	}
+
+	return order
+}
+
+// sortTransactionsByDate returns a copy of transactions ordered by trade date, then creation time
+func sortTransactionsByDate(transactions []models.Transaction) []models.Transaction {
+	sorted := make([]models.Transaction, len(transactions))
+	copy(sorted, transactions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].TransactionDate.Equal(sorted[j].TransactionDate) {
+			return sorted[i].TransactionDate.Before(sorted[j].TransactionDate)
+		}
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+	return sorted
+}
+
+// transactionsUpTo returns the transactions dated on or before the target time
+func transactionsUpTo(transactions []models.Transaction, target time.Time) []models.Transaction {
+	var filtered []models.Transaction
+	for _, tx := range transactions {
+		if !tx.TransactionDate.After(target) {
+			filtered = append(filtered, tx)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/types"
+	"github.com/transaction-tracker/backend/internal/utils"
+)
+
+// dividendIncome sums the dividend cash received among transactions, net of withholding tax and fees
+func dividendIncome(transactions []models.Transaction) float64 {
+	var income float64
+	for _, tx := range transactions {
+		if tx.TradeType == types.TradeTypeDividend {
+			income += tx.NetAmount()
+		}
+	}
+	return income
+}
+
+// trailingDividendIncome sums the net dividend cash received in the twelve months up to asOf
+func trailingDividendIncome(transactions []models.Transaction, asOf time.Time) float64 {
+	since := asOf.AddDate(-1, 0, 0)
+	var income float64
+	for _, tx := range transactions {
+		if tx.TradeType == types.TradeTypeDividend && tx.TransactionDate.After(since) && !tx.TransactionDate.After(asOf) {
+			income += tx.NetAmount()
+		}
+	}
+	return income
+}
+
+// calculateDividendMetrics returns total dividend income, trailing dividend yield and yield on cost (percentages)
+func calculateDividendMetrics(transactions []models.Transaction, marketValue, totalCost float64, asOf time.Time) (income, yield, yieldOnCost float64) {
+	income = dividendIncome(transactions)
+	trailing := trailingDividendIncome(transactions, asOf)
+	if marketValue > 0 {
+		yield = (trailing / marketValue) * 100
+	}
+	if totalCost > 0 {
+		yieldOnCost = (trailing / totalCost) * 100
+	}
+	return income, yield, yieldOnCost
+}
+
+// AggregateDividendIncome groups dividend transactions into monthly or yearly income periods.
+// Transactions are summed as given, so callers convert them into a single currency first.
+func AggregateDividendIncome(transactions []models.Transaction, currency string, aggregation models.DividendAggregation) *models.DividendIncomeResponse {
+	layout := "2006-01"
+	if aggregation == models.DividendAggregationYearly {
+		layout = "2006"
+	}
+
+	periods := make(map[string]*models.DividendIncomePeriod)
+	var totalIncome, totalGrossIncome, totalWithholdingTax float64
+	for _, tx := range transactions {
+		if tx.TradeType != types.TradeTypeDividend {
+			continue
+		}
+
+		key := tx.TransactionDate.Format(layout)
+		period, ok := periods[key]
+		if !ok {
+			period = &models.DividendIncomePeriod{
+				Period:   key,
+				BySymbol: make(map[string]float64),
+			}
+			periods[key] = period
+		}
+		period.Income += tx.NetAmount()
+		period.GrossIncome += tx.Amount
+		period.WithholdingTax += tx.WithholdingTax
+		period.Payments++
+		period.BySymbol[tx.Symbol] += tx.NetAmount()
+		totalIncome += tx.NetAmount()
+		totalGrossIncome += tx.Amount
+		totalWithholdingTax += tx.WithholdingTax
+	}
+
+	response := &models.DividendIncomeResponse{
+		Aggregation:    aggregation,
+		Currency:       currency,
+		TotalIncome:    utils.RoundTo4(totalIncome),
+		GrossIncome:    utils.RoundTo4(totalGrossIncome),
+		WithholdingTax: utils.RoundTo4(totalWithholdingTax),
+		Periods:        make([]models.DividendIncomePeriod, 0, len(periods)),
+	}
+	for _, period := range periods {
+		period.Income = utils.RoundTo4(period.Income)
+		period.GrossIncome = utils.RoundTo4(period.GrossIncome)
+		period.WithholdingTax = utils.RoundTo4(period.WithholdingTax)
+		for symbol, income := range period.BySymbol {
+			period.BySymbol[symbol] = utils.RoundTo4(income)
+		}
+		response.Periods = append(response.Periods, *period)
+	}
+	sort.Slice(response.Periods, func(i, j int) bool {
+		return response.Periods[i].Period < response.Periods[j].Period
+	})
+
+	return response
+}
+
+// GetDividendIncome retrieves a user's dividend income in scope aggregated by month or year,
+// with each payment converted into the base currency at the rate of its payment date
+func (s *PortfolioService) GetDividendIncome(ctx context.Context, userID uuid.UUID, scope PortfolioScope, aggregation models.DividendAggregation, startDate, endDate *time.Time) (*models.DividendIncomeResponse, error) {
+	settings, err := s.GetSettings(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load portfolio settings: %w", err)
+	}
+
+	scope, err = s.resolveScope(userID, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	dividends, err := s.transactionRepo.GetByUserIDAndTradeType(userID, types.TradeTypeDividend, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dividend transactions: %w", err)
+	}
+	dividends = scope.Filter(dividends)
+
+	converted, err := s.fxConverter(ctx, settings.BaseCurrency).ConvertTransactions(dividends)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert dividends into %s: %w", settings.BaseCurrency, err)
+	}
+
+	return AggregateDividendIncome(converted, settings.BaseCurrency, aggregation), nil
+}
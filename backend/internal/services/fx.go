@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/provider"
+)
+
+// FXRateFetcher loads the daily rates converting one unit of currency into the base currency
+type FXRateFetcher func(currency string) ([]provider.FXRate, error)
+
+// dailyValue is a daily rate or price keyed by its YYYY-MM-DD date, which sorts chronologically
+type dailyValue struct {
+	day   string
+	value float64
+}
+
+// valueOn returns the last of the sorted values dated on or before day
+func valueOn(values []dailyValue, day string) (float64, bool) {
+	index := sort.Search(len(values), func(i int) bool {
+		return values[i].day > day
+	})
+	if index == 0 {
+		return 0, false
+	}
+	return values[index-1].value, true
+}
+
+// FXConverter converts amounts into a base currency at the rate in effect on a given date.
+// Each currency's series is fetched once and reused for every conversion.
+type FXConverter struct {
+	baseCurrency string
+	fetch        FXRateFetcher
+	mutex        sync.Mutex
+	series       map[string][]dailyValue
+}
+
+// NewFXConverter creates a converter into baseCurrency backed by fetch
+func NewFXConverter(baseCurrency string, fetch FXRateFetcher) *FXConverter {
+	return &FXConverter{
+		baseCurrency: normalizeCurrency(baseCurrency),
+		fetch:        fetch,
+		series:       make(map[string][]dailyValue),
+	}
+}
+
+// BaseCurrency returns the currency amounts are converted into
+func (c *FXConverter) BaseCurrency() string {
+	return c.baseCurrency
+}
+
+// ErrFXRateUnavailable is returned for dates before a currency's rate series starts
+var ErrFXRateUnavailable = errors.New("fx rate unavailable")
+
+// RateAt returns the rate converting currency into the base currency on date.
+// Weekends and holidays carry the last published rate forward; dates before the
+// series starts have no rate and return ErrFXRateUnavailable.
+func (c *FXConverter) RateAt(currency string, date time.Time) (float64, error) {
+	currency = normalizeCurrency(currency)
+	if currency == c.baseCurrency {
+		return 1, nil
+	}
+
+	points, err := c.load(currency)
+	if err != nil {
+		return 0, err
+	}
+
+	day := date.Format("2006-01-02")
+	if rate, ok := valueOn(points, day); ok {
+		return rate, nil
+	}
+	return 0, fmt.Errorf("%w: no %s/%s rate on %s, rates start on %s", ErrFXRateUnavailable, currency, c.baseCurrency, day, points[0].day)
+}
+
+// Convert converts an amount in currency into the base currency at the rate on date
+func (c *FXConverter) Convert(amount float64, currency string, date time.Time) (float64, error) {
+	rate, err := c.RateAt(currency, date)
+	if err != nil {
+		return 0, err
+	}
+	return amount * rate, nil
+}
+
+// ConvertTransactions returns copies of the transactions with price, amount and costs
+// converted into the base currency at each transaction's trade date
+func (c *FXConverter) ConvertTransactions(transactions []models.Transaction) ([]models.Transaction, error) {
+	converted := make([]models.Transaction, len(transactions))
+	for i, tx := range transactions {
+		rate, err := c.RateAt(tx.Currency, tx.TransactionDate)
+		if err != nil {
+			return nil, err
+		}
+		tx.Price *= rate
+		tx.Amount *= rate
+		tx.Commission *= rate
+		tx.Fee *= rate
+		tx.Tax *= rate
+		tx.WithholdingTax *= rate
+		tx.Currency = c.baseCurrency
+		converted[i] = tx
+	}
+	return converted, nil
+}
+
+// load returns the cached series of a currency, fetching it on first use
+func (c *FXConverter) load(currency string) ([]dailyValue, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if points, ok := c.series[currency]; ok {
+		return points, nil
+	}
+
+	rates, err := c.fetch(currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get FX rates for %s/%s: %w", currency, c.baseCurrency, err)
+	}
+
+	points := make([]dailyValue, 0, len(rates))
+	for _, rate := range rates {
+		if _, err := time.Parse("2006-01-02", rate.Date); err != nil || rate.Rate <= 0 {
+			continue
+		}
+		points = append(points, dailyValue{day: rate.Date, value: rate.Rate})
+	}
+	if len(points) == 0 {
+		return nil, fmt.Errorf("no FX rates available for %s/%s", currency, c.baseCurrency)
+	}
+
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].day < points[j].day
+	})
+	c.series[currency] = points
+	return points, nil
+}
+
+// normalizeCurrency upper-cases a currency code, treating an empty code as the default currency
+func normalizeCurrency(currency string) string {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return models.DefaultBaseCurrency
+	}
+	return currency
+}
+
+// holdingCurrency returns the currency a holding is quoted in, taken from its latest transaction
+func holdingCurrency(transactions []models.Transaction) string {
+	sorted := sortTransactionsByDate(transactions)
+	if len(sorted) == 0 {
+		return models.DefaultBaseCurrency
+	}
+	return normalizeCurrency(sorted[len(sorted)-1].Currency)
+}
+
+// fxConverter builds a converter into the user's base currency backed by the price service
+func (s *PortfolioService) fxConverter(ctx context.Context, baseCurrency string) *FXConverter {
+	return NewFXConverter(baseCurrency, func(currency string) ([]provider.FXRate, error) {
+		rates, err := s.priceManager.GetFXRates(ctx, currency, normalizeCurrency(baseCurrency), "", "")
+		if err != nil {
+			return nil, err
+		}
+		return rates.Rates, nil
+	})
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/types"
+	"github.com/transaction-tracker/backend/internal/utils"
+)
+
+// HoldingTimeline replays a single holding's transactions at each time point, pricing the shares
+// at the close of the symbol they were held under at that time. Amounts are converted into the
+// base currency: costs and proceeds at their trade dates, market value at each point's rate.
+// Points without a close so far have no market value or unrealized gain, and the price is left
+// out while the shares are held under several symbols before a merger.
+func HoldingTimeline(engine *CostBasisEngine, fx *FXConverter, prices *PriceHistory, transactions []models.Transaction, timePoints []time.Time) ([]models.HoldingChartDataPoint, error) {
+	converted, err := fx.ConvertTransactions(transactions)
+	if err != nil {
+		return nil, err
+	}
+	currency := holdingCurrency(transactions)
+
+	points := make([]models.HoldingChartDataPoint, 0, len(timePoints))
+	for _, t := range timePoints {
+		local := engine.CalculateAt(transactions, t)
+		base := engine.CalculateAt(converted, t)
+		point := models.HoldingChartDataPoint{
+			Timestamp:        t,
+			Quantity:         utils.RoundTo10(local.TotalQuantity()),
+			CostBasis:        utils.RoundTo4(base.TotalCost()),
+			RealizedGainLoss: utils.RoundTo4(base.RealizedGainLoss()),
+			DividendIncome:   utils.RoundTo4(dividendIncome(transactionsUpTo(converted, t))),
+		}
+
+		// Shares are priced under the symbols they were held as at t, following renames up to then;
+		// before a merger they may be spread over several symbols
+		grouped := engine.GroupBySymbol(transactions, t)
+		marketValue := 0.0
+		priced := len(grouped) > 0
+		for heldSymbol, heldTransactions := range grouped {
+			price, err := prices.CloseAt(heldSymbol, t)
+			if err != nil {
+				priced = false
+				break
+			}
+			rate, err := fx.RateAt(currency, t)
+			if err != nil {
+				return nil, err
+			}
+			marketValue += engine.CalculateAt(heldTransactions, t).TotalQuantity() * engine.Multiplier(heldSymbol) * price * rate
+			if len(grouped) == 1 {
+				point.Price = utils.RoundTo10(price)
+			}
+		}
+		if priced {
+			point.MarketValue = utils.RoundTo4(marketValue)
+			point.UnrealizedGainLoss = utils.RoundTo4(marketValue - base.TotalCost())
+		}
+
+		points = append(points, point)
+	}
+	return points, nil
+}
+
+// HoldingMarkers returns the trades and dividends dated between from and to, oldest first
+func HoldingMarkers(transactions []models.Transaction, from, to time.Time) []models.HoldingChartMarker {
+	markers := []models.HoldingChartMarker{}
+	for _, tx := range sortTransactionsByDate(transactions) {
+		if tx.TransactionDate.Before(from) || tx.TransactionDate.After(to) {
+			continue
+		}
+		if tx.TradeType.IsTrade() || tx.TradeType.IsOptionEvent() || tx.TradeType == types.TradeTypeDividend {
+			markers = append(markers, models.HoldingChartMarker{
+				TransactionID: tx.TransactionID,
+				Date:          tx.TransactionDate,
+				TradeType:     tx.TradeType,
+				Symbol:        tx.Symbol,
+				Quantity:      tx.Quantity,
+				Price:         tx.Price,
+				Amount:        tx.Amount,
+				Currency:      tx.Currency,
+			})
+		}
+	}
+	return markers
+}
+
+// GetHoldingChart calculates a single holding's quantity, cost basis, market value and gains over
+// time, with a marker for each of its trades and dividends. Closed positions can still be charted.
+func (s *PortfolioService) GetHoldingChart(ctx context.Context, userID uuid.UUID, scope PortfolioScope, symbol string, timeframe models.TimeFrame) (*models.HoldingChartResponse, error) {
+	endTime := time.Now()
+	startTime, err := s.calculateStartTime(endTime, timeframe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate start time: %w", err)
+	}
+
+	engine, fx, allTransactions, _, err := s.valuationInputs(ctx, userID, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	// Earlier symbols of a renamed or merged holding count towards it
+	transactions := sortTransactionsByDate(engine.GroupBySymbol(allTransactions, endTime)[symbol])
+	if len(transactions) == 0 {
+		return nil, fmt.Errorf("no transactions found for symbol %s", symbol)
+	}
+
+	// For ALL timeframe, start from the holding's first transaction
+	if timeframe == models.TimeFrameALL {
+		startTime = transactions[0].TransactionDate
+	}
+
+	granularity := s.determineDefaultGranularity(timeframe)
+	timePoints := s.generateTimePoints(startTime, endTime, granularity)
+
+	dataPoints, err := HoldingTimeline(engine, fx, s.priceHistory(ctx, startTime, endTime, transactions), transactions, timePoints)
+	if err != nil {
+		return nil, fmt.Errorf("failed to value %s into %s: %w", symbol, fx.BaseCurrency(), err)
+	}
+
+	response := &models.HoldingChartResponse{
+		Symbol:          symbol,
+		TimeFrame:       timeframe,
+		Granularity:     granularity,
+		Currency:        fx.BaseCurrency(),
+		CostBasisMethod: engine.Method(),
+		DataPoints:      dataPoints,
+		Markers:         HoldingMarkers(transactions, startTime, endTime),
+	}
+	response.Period.StartDate = startTime
+	response.Period.EndDate = endTime
+	return response, nil
+}
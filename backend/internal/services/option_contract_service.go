@@ -0,0 +1,107 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/repositories"
+	"github.com/transaction-tracker/backend/internal/utils"
+)
+
+// OptionContractService handles the option contracts that option trades are recorded in
+type OptionContractService struct {
+	optionContractRepo *repositories.OptionContractRepository
+	transactionRepo    *repositories.TransactionRepository
+	listeners          []TransactionChangeListener
+}
+
+// NewOptionContractService creates a new option contract service
+func NewOptionContractService(optionContractRepo *repositories.OptionContractRepository, transactionRepo *repositories.TransactionRepository) *OptionContractService {
+	return &OptionContractService{
+		optionContractRepo: optionContractRepo,
+		transactionRepo:    transactionRepo,
+	}
+}
+
+// AddChangeListener registers a listener notified for every user who traded a contract whose terms change
+func (s *OptionContractService) AddChangeListener(listener TransactionChangeListener) {
+	s.listeners = append(s.listeners, listener)
+}
+
+// ListOptionContracts retrieves every recorded option contract
+func (s *OptionContractService) ListOptionContracts() ([]models.OptionContract, error) {
+	return s.optionContractRepo.GetAll()
+}
+
+// UpsertOptionContract validates and records an option contract, replacing any recorded under its symbol.
+// Without a symbol, the contract is recorded under the OCC symbol of a standard contract.
+func (s *OptionContractService) UpsertOptionContract(contract models.OptionContract) (*models.OptionContract, error) {
+	if err := normalizeOptionContract(&contract); err != nil {
+		return nil, err
+	}
+
+	if err := s.optionContractRepo.Upsert(&contract); err != nil {
+		return nil, err
+	}
+
+	s.notifyAffectedUsers(contract.Symbol)
+	return &contract, nil
+}
+
+// normalizeOptionContract upper-cases symbols, fills the default multiplier and symbol and validates the contract
+func normalizeOptionContract(contract *models.OptionContract) error {
+	contract.Symbol = strings.ToUpper(strings.TrimSpace(contract.Symbol))
+	contract.Underlying = strings.ToUpper(strings.TrimSpace(contract.Underlying))
+	contract.OptionType = models.OptionType(strings.ToLower(strings.TrimSpace(string(contract.OptionType))))
+
+	if !utils.SymbolRegex.MatchString(contract.Underlying) {
+		return fmt.Errorf("invalid option contract: underlying %q is not valid", contract.Underlying)
+	}
+	if !contract.OptionType.IsValid() {
+		return fmt.Errorf("invalid option contract: option_type must be call or put")
+	}
+	if contract.Expiry.IsZero() {
+		return fmt.Errorf("invalid option contract: expiry is required")
+	}
+	if contract.Strike <= 0 {
+		return fmt.Errorf("invalid option contract: strike must be positive")
+	}
+	if contract.Multiplier == 0 {
+		contract.Multiplier = models.DefaultOptionMultiplier
+	}
+	if contract.Multiplier < 0 {
+		return fmt.Errorf("invalid option contract: multiplier must be positive")
+	}
+
+	if contract.Symbol == "" {
+		contract.Symbol = contract.OCCSymbol()
+	}
+	if !utils.OptionSymbolRegex.MatchString(contract.Symbol) {
+		return fmt.Errorf("invalid option contract: symbol %q is not an OCC option symbol", contract.Symbol)
+	}
+
+	return nil
+}
+
+// notifyAffectedUsers informs listeners about every user who traded the contract
+func (s *OptionContractService) notifyAffectedUsers(symbol string) {
+	if len(s.listeners) == 0 {
+		return
+	}
+
+	userIDs, err := s.transactionRepo.GetUserIDsBySymbols([]string{symbol})
+	if err != nil {
+		fmt.Printf("Warning: failed to find users affected by option contract %s: %v\n", symbol, err)
+		return
+	}
+
+	for _, userID := range userIDs {
+		for _, listener := range s.listeners {
+			if err := listener.OnTransactionsChanged(userID, time.Time{}); err != nil {
+				fmt.Printf("Warning: failed to process option contract %s for user %s: %v\n", symbol, userID, err)
+			}
+		}
+	}
+}
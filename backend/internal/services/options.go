@@ -0,0 +1,103 @@
+package services
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/types"
+	"github.com/transaction-tracker/backend/internal/utils"
+)
+
+// optionAmountTolerance absorbs rounding between an option trade's amount and its contracts times price
+const optionAmountTolerance = 0.1
+
+// ExpandAssignments adds the trade of underlying shares that each assignment of option contracts
+// delivers: a buy at the strike for an assigned put, a sale at the strike for an assigned call.
+// The premium received for the contracts assigned is carried into the shares, reducing the cost of
+// the shares bought or adding to the proceeds of those sold, and the assignment itself pays that
+// premium over. Cash balances therefore move by the strike alone. The delivered trade shares the
+// assignment's ID and bears its fees, so lot selections and tax lots can refer to it.
+func (e *CostBasisEngine) ExpandAssignments(transactions []models.Transaction) []models.Transaction {
+	var bySymbol map[string][]models.Transaction
+	expanded := make([]models.Transaction, 0, len(transactions))
+	for _, tx := range transactions {
+		contract, ok := e.OptionContract(tx.Symbol)
+		if tx.TradeType != types.TradeTypeAssignment || !ok {
+			expanded = append(expanded, tx)
+			continue
+		}
+
+		if bySymbol == nil {
+			bySymbol = make(map[string][]models.Transaction)
+			for _, other := range transactions {
+				bySymbol[other.Symbol] = append(bySymbol[other.Symbol], other)
+			}
+		}
+
+		// Replay the contract up to the assignment for the contracts it closes and their premium
+		var contracts, premium float64
+		for _, closed := range e.CalculateAt(bySymbol[tx.Symbol], tx.TransactionDate).ClosedLots {
+			if closed.BuyTransactionID == tx.TransactionID {
+				contracts += closed.Quantity
+				premium += closed.Proceeds
+			}
+		}
+		if contracts <= quantityEpsilon {
+			expanded = append(expanded, tx)
+			continue
+		}
+
+		shares := contracts * contract.Multiplier
+		delivered := tx
+		delivered.Symbol = contract.Underlying
+		delivered.Quantity = shares
+		delivered.Price = contract.Strike
+		delivered.UserNotes = fmt.Sprintf("Assignment of %g %s contracts", contracts, tx.Symbol)
+		if contract.OptionType == models.OptionTypePut {
+			delivered.TradeType = types.TradeTypeBuy
+			delivered.Amount = shares*contract.Strike - premium
+		} else {
+			delivered.TradeType = types.TradeTypeSell
+			delivered.Amount = shares*contract.Strike + premium
+		}
+
+		assignment := tx
+		assignment.Amount = premium
+		assignment.TransactionCosts = models.TransactionCosts{}
+
+		expanded = append(expanded, assignment, delivered)
+	}
+	return expanded
+}
+
+// ValidateOptionTrades checks that option trade types are only recorded in option contracts and the
+// other trade types only in shares, that an option trade's amount is its contracts times price times
+// the contract multiplier, and that contracts do not expire before their expiry date
+func ValidateOptionTrades(engine *CostBasisEngine, transactions []models.Transaction) error {
+	for _, tx := range transactions {
+		contract, isOption := engine.OptionContract(tx.Symbol)
+		if !tx.TradeType.IsOption() {
+			if isOption {
+				return fmt.Errorf("invalid option: %s is an option contract; record it as %s, %s, %s or %s",
+					tx.Symbol, types.TradeTypeBuyToOpen, types.TradeTypeSellToOpen, types.TradeTypeBuyToClose, types.TradeTypeSellToClose)
+			}
+			continue
+		}
+		if !isOption {
+			return fmt.Errorf("invalid option: %s is not an option contract symbol", tx.Symbol)
+		}
+
+		if tx.TradeType.IsTrade() {
+			expected := tx.Quantity * tx.Price * contract.Multiplier
+			if math.Abs(tx.Amount-expected) > optionAmountTolerance {
+				return fmt.Errorf("invalid option: amount of %s does not match contracts × price × multiplier (%g)",
+					tx.Symbol, utils.RoundTo4(expected))
+			}
+		}
+		if tx.TradeType == types.TradeTypeExpiration && tx.TransactionDate.Before(contract.Expiry) {
+			return fmt.Errorf("invalid option: %s cannot expire before %s", tx.Symbol, contract.Expiry.Format("2006-01-02"))
+		}
+	}
+	return nil
+}
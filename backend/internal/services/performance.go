@@ -0,0 +1,312 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/transaction-tracker/backend/internal/constants"
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/types"
+)
+
+// PerformancePoint is the portfolio's value at a point in time, after the external cash flow
+// made at that time. Flow is positive for money put into the portfolio.
+type PerformancePoint struct {
+	Time  time.Time
+	Value float64
+	Flow  float64
+}
+
+// CumulativeTimeWeightedReturns chains the returns of the sub-periods between consecutive points
+// and returns the cumulative time-weighted return (%) at each point. Each sub-period ends just
+// before its point's flow, so money moving in or out does not count as performance.
+// Sub-periods starting from an empty portfolio are skipped.
+func CumulativeTimeWeightedReturns(points []PerformancePoint) []float64 {
+	returns := make([]float64, len(points))
+	growth := 1.0
+	for i := 1; i < len(points); i++ {
+		if previous := points[i-1].Value; previous > 0 {
+			growth *= (points[i].Value - points[i].Flow) / previous
+		}
+		returns[i] = (growth - 1) * 100
+	}
+	return returns
+}
+
+// recordsCashFlows reports whether the user records deposits or withdrawals. When they do,
+// cash is part of the portfolio and only those transfers are external flows; otherwise the
+// portfolio is the securities held and buying, selling and dividends move money in and out.
+func recordsCashFlows(transactions []models.Transaction) bool {
+	for _, tx := range transactions {
+		if tx.TradeType == types.TradeTypeDeposit || tx.TradeType == types.TradeTypeWithdrawal {
+			return true
+		}
+	}
+	return false
+}
+
+// externalFlow returns the money a transaction moves into (positive) or out of the portfolio
+func externalFlow(tx models.Transaction, includeCash bool) float64 {
+	switch {
+	case tx.TradeType == types.TradeTypeDeposit || tx.TradeType == types.TradeTypeWithdrawal:
+		if includeCash {
+			return tx.CashFlow()
+		}
+	case tx.TradeType.IsTrade() || tx.TradeType.IsOptionEvent() || tx.TradeType == types.TradeTypeDividend:
+		if !includeCash {
+			return -tx.CashFlow()
+		}
+	}
+	return 0
+}
+
+// performanceTracker values a portfolio at arbitrary times for time-weighted returns,
+// memoising valuations so chart points and flow dates are only valued once. Days with a stored
+// snapshot are read from it; other prices come from a shared history that fetches each symbol
+// once for the tracker's whole date range.
+type performanceTracker struct {
+	service      *PortfolioService
+	ctx          context.Context
+	engine       *CostBasisEngine
+	fx           *FXConverter
+	prices       *PriceHistory
+	transactions []models.Transaction
+	converted    []models.Transaction
+	includeCash  bool
+	snapshots    map[string]portfolioValuation
+	mutex        sync.Mutex
+	valuations   map[time.Time]portfolioValuation
+}
+
+// newPerformanceTracker creates a tracker over transactions and their base currency conversions,
+// for valuations between from and to
+func (s *PortfolioService) newPerformanceTracker(ctx context.Context, engine *CostBasisEngine, fx *FXConverter, transactions, converted []models.Transaction, from, to time.Time) *performanceTracker {
+	return &performanceTracker{
+		service:      s,
+		ctx:          ctx,
+		engine:       engine,
+		fx:           fx,
+		prices:       s.priceHistory(ctx, from, to, transactions),
+		transactions: transactions,
+		converted:    converted,
+		includeCash:  recordsCashFlows(transactions),
+		valuations:   make(map[time.Time]portfolioValuation),
+	}
+}
+
+// valuationAt values the portfolio at t
+func (p *performanceTracker) valuationAt(t time.Time) (portfolioValuation, error) {
+	p.mutex.Lock()
+	valuation, ok := p.valuations[t]
+	p.mutex.Unlock()
+	if ok {
+		return valuation, nil
+	}
+	if valuation, ok := p.snapshots[snapshotKey(t)]; ok {
+		return valuation, nil
+	}
+
+	valuation, err := p.service.calculateTotalValueAtTime(p.prices, p.engine, p.fx, p.transactions, p.converted, t)
+	if err != nil {
+		return portfolioValuation{}, err
+	}
+
+	p.mutex.Lock()
+	p.valuations[t] = valuation
+	p.mutex.Unlock()
+	return valuation, nil
+}
+
+// valueAll values the portfolio at every time in parallel, at most
+// constants.MaxConcurrentValuations at once, and returns the first error
+func (p *performanceTracker) valueAll(times []time.Time) error {
+	semaphore := make(chan struct{}, constants.MaxConcurrentValuations)
+	errs := make(chan error, len(times))
+	var wg sync.WaitGroup
+
+	for _, t := range times {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(t time.Time) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			if _, err := p.valuationAt(t); err != nil {
+				errs <- err
+			}
+		}(t)
+	}
+
+	wg.Wait()
+	close(errs)
+	return <-errs
+}
+
+// valueAt returns the value that returns are measured on at t
+func (p *performanceTracker) valueAt(t time.Time) (float64, error) {
+	valuation, err := p.valuationAt(t)
+	if err != nil {
+		return 0, err
+	}
+	if p.includeCash {
+		return valuation.MarketValue + valuation.CashBalance, nil
+	}
+	return valuation.MarketValue, nil
+}
+
+// flowPoints merges the sorted time points with every day that has external flows in between.
+// A flow dated exactly on a time point is attributed to it; flows on the first point are
+// already part of its value. The returned set marks which points are time points.
+func (p *performanceTracker) flowPoints(timePoints []time.Time) ([]PerformancePoint, map[time.Time]bool) {
+	isTimePoint := make(map[time.Time]bool, len(timePoints))
+	if len(timePoints) == 0 {
+		return []PerformancePoint{}, isTimePoint
+	}
+	start, end := timePoints[0], timePoints[len(timePoints)-1]
+
+	// Net the flows of each day, converted into the base currency at their trade dates
+	flows := make(map[time.Time]float64)
+	for _, tx := range p.converted {
+		if !tx.TransactionDate.After(start) || tx.TransactionDate.After(end) {
+			continue
+		}
+		if flow := externalFlow(tx, p.includeCash); flow != 0 {
+			flows[tx.TransactionDate] += flow
+		}
+	}
+
+	points := make([]PerformancePoint, 0, len(timePoints)+len(flows))
+	for _, t := range timePoints {
+		isTimePoint[t] = true
+		points = append(points, PerformancePoint{Time: t, Flow: flows[t]})
+	}
+	for t, flow := range flows {
+		if !isTimePoint[t] {
+			points = append(points, PerformancePoint{Time: t, Flow: flow})
+		}
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].Time.Before(points[j].Time)
+	})
+	return points, isTimePoint
+}
+
+// valuedFlowPoints returns the flow points of timePoints with the portfolio valued at each
+func (p *performanceTracker) valuedFlowPoints(timePoints []time.Time) ([]PerformancePoint, map[time.Time]bool, error) {
+	points, isTimePoint := p.flowPoints(timePoints)
+
+	times := make([]time.Time, len(points))
+	for i, point := range points {
+		times[i] = point.Time
+	}
+	if err := p.valueAll(times); err != nil {
+		return nil, nil, err
+	}
+
+	for i := range points {
+		value, err := p.valueAt(points[i].Time)
+		if err != nil {
+			return nil, nil, err
+		}
+		points[i].Value = value
+	}
+	return points, isTimePoint, nil
+}
+
+// timeWeightedReturns returns the cumulative time-weighted return (%) at each of the sorted time
+// points, measured from the first. The portfolio is also valued on every day with external flows
+// in between so that sub-periods split exactly at the flows.
+func (p *performanceTracker) timeWeightedReturns(timePoints []time.Time) ([]float64, error) {
+	points, isTimePoint, err := p.valuedFlowPoints(timePoints)
+	if err != nil {
+		return nil, err
+	}
+
+	cumulative := CumulativeTimeWeightedReturns(points)
+	returns := make([]float64, 0, len(timePoints))
+	for i, point := range points {
+		if isTimePoint[point.Time] {
+			returns = append(returns, cumulative[i])
+		}
+	}
+	return returns, nil
+}
+
+// ReplayCashFlows invests the value of the first point into an instrument and then buys or sells
+// it with every later flow, returning the value of the instrument held at each point.
+// prices[i] is the instrument's price at points[i]; a missing (zero) price carries the last one
+// forward, and flows made before the first known price are invested at it.
+func ReplayCashFlows(points []PerformancePoint, prices []float64) []float64 {
+	values := make([]float64, len(points))
+	var units, price, pending float64
+	for i, point := range points {
+		if i == 0 {
+			pending = point.Value
+		} else {
+			pending += point.Flow
+		}
+		if prices[i] > 0 {
+			price = prices[i]
+		}
+		if price <= 0 {
+			continue
+		}
+		units += pending / price
+		pending = 0
+		values[i] = units * price
+	}
+	return values
+}
+
+// benchmarkValues replays the portfolio's external flows into symbol and returns the benchmark
+// holding's value and cumulative time-weighted return (%) at each time point. Prices are
+// converted into the base currency at each point's rate.
+func (p *performanceTracker) benchmarkValues(symbol string, timePoints []time.Time) (values, returns []float64, err error) {
+	quote, err := p.service.priceManager.GetCurrentPrice(p.ctx, symbol)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get current price for %s: %w", symbol, err)
+	}
+
+	points, isTimePoint, err := p.valuedFlowPoints(timePoints)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prices := make([]float64, len(points))
+	priced := 0
+	for i, point := range points {
+		price, err := p.prices.PriceAt(symbol, point.Time)
+		if err != nil {
+			continue
+		}
+		priced++
+		rate, err := p.fx.RateAt(quote.Currency, point.Time)
+		if err != nil {
+			return nil, nil, err
+		}
+		prices[i] = price * rate
+	}
+	if priced == 0 {
+		return nil, nil, fmt.Errorf("no prices available for %s", symbol)
+	}
+
+	// The benchmark's return over a sub-period is its price change, whatever the flows
+	replayed := ReplayCashFlows(points, prices)
+	benchmark := make([]PerformancePoint, len(points))
+	for i, point := range points {
+		benchmark[i] = PerformancePoint{Time: point.Time, Value: replayed[i], Flow: point.Flow}
+	}
+	cumulative := CumulativeTimeWeightedReturns(benchmark)
+
+	values = make([]float64, 0, len(timePoints))
+	returns = make([]float64, 0, len(timePoints))
+	for i, point := range points {
+		if isTimePoint[point.Time] {
+			values = append(values, replayed[i])
+			returns = append(returns, cumulative[i])
+		}
+	}
+	return values, returns, nil
+}
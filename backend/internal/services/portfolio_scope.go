@@ -0,0 +1,112 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/models"
+)
+
+// PortfolioScope narrows the portfolio views down to part of a user's transactions.
+// The zero value covers all of them, the aggregate of all portfolios.
+type PortfolioScope struct {
+	// PortfolioID keeps only the transactions of one named portfolio
+	PortfolioID *uuid.UUID
+	// AccountID keeps only the transactions recorded in one account
+	AccountID *uuid.UUID
+
+	// portfolioAccounts are the accounts assigned to the portfolio, whose transactions
+	// belong to it unless assigned elsewhere
+	portfolioAccounts map[uuid.UUID]bool
+}
+
+// IsAll reports whether the scope covers all of a user's transactions. Only such views are
+// backed by the persisted snapshots and tax lot ledger, which are kept per user.
+func (s PortfolioScope) IsAll() bool {
+	return s.PortfolioID == nil && s.AccountID == nil
+}
+
+// WithPortfolioAccounts returns the scope with the accounts assigned to its portfolio
+func (s PortfolioScope) WithPortfolioAccounts(accountIDs []uuid.UUID) PortfolioScope {
+	s.portfolioAccounts = make(map[uuid.UUID]bool, len(accountIDs))
+	for _, id := range accountIDs {
+		s.portfolioAccounts[id] = true
+	}
+	return s
+}
+
+// inPortfolio reports whether a transaction belongs to the scope's portfolio: either assigned to it
+// directly, or recorded in one of its accounts without a portfolio of its own
+func (s PortfolioScope) inPortfolio(tx models.Transaction) bool {
+	if tx.PortfolioID != nil {
+		return *tx.PortfolioID == *s.PortfolioID
+	}
+	return tx.AccountID != nil && s.portfolioAccounts[*tx.AccountID]
+}
+
+// Filter returns the transactions within the scope
+func (s PortfolioScope) Filter(transactions []models.Transaction) []models.Transaction {
+	if s.IsAll() {
+		return transactions
+	}
+
+	filtered := make([]models.Transaction, 0, len(transactions))
+	for _, tx := range transactions {
+		if s.AccountID != nil && (tx.AccountID == nil || *tx.AccountID != *s.AccountID) {
+			continue
+		}
+		if s.PortfolioID != nil && !s.inPortfolio(tx) {
+			continue
+		}
+		filtered = append(filtered, tx)
+	}
+	return filtered
+}
+
+// resolveScope checks that the account and portfolio the scope names belong to the user, and
+// looks up the accounts assigned to the portfolio
+func (s *PortfolioService) resolveScope(userID uuid.UUID, scope PortfolioScope) (PortfolioScope, error) {
+	if scope.AccountID != nil {
+		if _, err := s.accountRepo.GetByIDAndUserID(*scope.AccountID, userID); err != nil {
+			return scope, fmt.Errorf("account not found: %s", *scope.AccountID)
+		}
+	}
+	if scope.PortfolioID == nil {
+		return scope, nil
+	}
+
+	if _, err := s.portfolioRepo.GetByIDAndUserID(*scope.PortfolioID, userID); err != nil {
+		return scope, fmt.Errorf("portfolio not found: %s", *scope.PortfolioID)
+	}
+	accounts, err := s.accountRepo.GetByUserID(userID)
+	if err != nil {
+		return scope, err
+	}
+	var accountIDs []uuid.UUID
+	for _, account := range accounts {
+		if account.PortfolioID != nil && *account.PortfolioID == *scope.PortfolioID {
+			accountIDs = append(accountIDs, account.ID)
+		}
+	}
+	return scope.WithPortfolioAccounts(accountIDs), nil
+}
+
+// scopedTransactions loads the user's transactions within scope, together with the trades of
+// underlying shares that option assignments among them deliver
+func (s *PortfolioService) scopedTransactions(userID uuid.UUID, scope PortfolioScope) ([]models.Transaction, error) {
+	scope, err := s.resolveScope(userID, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := s.transactionRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	engine, err := s.costBasisEngine(userID)
+	if err != nil {
+		return nil, err
+	}
+	return engine.ExpandAssignments(scope.Filter(transactions)), nil
+}
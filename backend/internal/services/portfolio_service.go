@@ -4,84 +4,291 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/transaction-tracker/backend/internal/models"
 	"github.com/transaction-tracker/backend/internal/provider"
 	"github.com/transaction-tracker/backend/internal/repositories"
+	"github.com/transaction-tracker/backend/internal/types"
 	"github.com/transaction-tracker/backend/internal/utils"
 )
 
 // PortfolioService handles portfolio-related business logic
 type PortfolioService struct {
-	transactionRepo *repositories.TransactionRepository
-	priceManager    *provider.PriceServiceManager
+	transactionRepo     *repositories.TransactionRepository
+	userRepo            repositories.UserRepository
+	lotSelectionRepo    *repositories.LotSelectionRepository
+	taxLotRepo          *repositories.TaxLotRepository
+	corporateActionRepo *repositories.CorporateActionRepository
+	optionContractRepo  *repositories.OptionContractRepository
+	snapshotRepo        *repositories.PortfolioSnapshotRepository
+	targetRepo          *repositories.TargetAllocationRepository
+	accountRepo         *repositories.AccountRepository
+	portfolioRepo       *repositories.PortfolioRepository
+	metadataSource      SymbolMetadataSource
+	priceManager        *provider.PriceServiceManager
+	// snapshotLocks serialises snapshot recomputation per user
+	snapshotLocks sync.Map
+	// snapshotQueue recomputes snapshots after changes in the background
+	snapshotQueue *ChangeQueue
 }
 
 // NewPortfolioService creates a new portfolio service
 func NewPortfolioService(
 	transactionRepo *repositories.TransactionRepository,
+	userRepo repositories.UserRepository,
+	lotSelectionRepo *repositories.LotSelectionRepository,
+	taxLotRepo *repositories.TaxLotRepository,
+	corporateActionRepo *repositories.CorporateActionRepository,
+	optionContractRepo *repositories.OptionContractRepository,
+	snapshotRepo *repositories.PortfolioSnapshotRepository,
+	targetRepo *repositories.TargetAllocationRepository,
+	accountRepo *repositories.AccountRepository,
+	portfolioRepo *repositories.PortfolioRepository,
+	metadataSource SymbolMetadataSource,
 	priceManager *provider.PriceServiceManager,
 ) *PortfolioService {
-	return &PortfolioService{
-		transactionRepo: transactionRepo,
-		priceManager:    priceManager,
+	s := &PortfolioService{
+		transactionRepo:     transactionRepo,
+		userRepo:            userRepo,
+		lotSelectionRepo:    lotSelectionRepo,
+		taxLotRepo:          taxLotRepo,
+		corporateActionRepo: corporateActionRepo,
+		optionContractRepo:  optionContractRepo,
+		snapshotRepo:        snapshotRepo,
+		targetRepo:          targetRepo,
+		accountRepo:         accountRepo,
+		portfolioRepo:       portfolioRepo,
+		metadataSource:      metadataSource,
+		priceManager:        priceManager,
 	}
+	s.snapshotQueue = NewChangeQueue(snapshotRecomputer{s})
+	return s
+}
+
+// GetSettings retrieves the portfolio calculation settings of a user
+func (s *PortfolioService) GetSettings(userID uuid.UUID) (*models.PortfolioSettings, error) {
+	user, err := s.userRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user settings: %w", err)
+	}
+
+	method := user.CostBasisMethod
+	if !method.IsValid() {
+		method = models.DefaultCostBasisMethod
+	}
+
+	baseCurrency := normalizeCurrency(user.BaseCurrency)
+	if !utils.CurrencyRegex.MatchString(baseCurrency) {
+		baseCurrency = models.DefaultBaseCurrency
+	}
+
+	return &models.PortfolioSettings{
+		CostBasisMethod: method,
+		BaseCurrency:    baseCurrency,
+	}, nil
+}
+
+// UpdateSettings updates the portfolio calculation settings of a user.
+// Empty fields are left unchanged.
+func (s *PortfolioService) UpdateSettings(userID uuid.UUID, settings models.PortfolioSettings) (*models.PortfolioSettings, error) {
+	updates := make(map[string]interface{})
+
+	if settings.CostBasisMethod != "" {
+		if !settings.CostBasisMethod.IsValid() {
+			return nil, fmt.Errorf("invalid cost basis method: %s", settings.CostBasisMethod)
+		}
+		updates["cost_basis_method"] = settings.CostBasisMethod
+	}
+
+	if settings.BaseCurrency != "" {
+		baseCurrency := strings.ToUpper(settings.BaseCurrency)
+		if !utils.CurrencyRegex.MatchString(baseCurrency) {
+			return nil, fmt.Errorf("invalid base currency: %s", settings.BaseCurrency)
+		}
+		updates["base_currency"] = baseCurrency
+	}
+
+	if len(updates) == 0 {
+		return nil, fmt.Errorf("invalid settings: nothing to update")
+	}
+
+	if err := s.userRepo.UpdateFields(userID, updates); err != nil {
+		return nil, fmt.Errorf("failed to update user settings: %w", err)
+	}
+
+	// Realized gains depend on both the matching method and the currency they are reported in
+	if err := s.RebuildTaxLots(userID, time.Time{}); err != nil {
+		fmt.Printf("Warning: failed to rebuild tax lots for user %s: %v\n", userID, err)
+	}
+	s.queueSnapshotRecompute(userID, time.Time{})
+
+	return s.GetSettings(userID)
+}
+
+// GetLotSelections retrieves the lots chosen for a sale under the specific lot method
+func (s *PortfolioService) GetLotSelections(userID, sellTransactionID uuid.UUID) ([]models.LotSelection, error) {
+	if _, err := s.transactionRepo.GetByIDAndUserID(sellTransactionID, userID); err != nil {
+		return nil, fmt.Errorf("transaction %s not found", sellTransactionID)
+	}
+	return s.lotSelectionRepo.GetBySellTransactionID(sellTransactionID)
+}
+
+// UpdateLotSelections validates and stores the lots a sale should consume under the specific lot method
+func (s *PortfolioService) UpdateLotSelections(userID, sellTransactionID uuid.UUID, selections []models.LotSelection) ([]models.LotSelection, error) {
+	sell, err := s.transactionRepo.GetByIDAndUserID(sellTransactionID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("transaction %s not found", sellTransactionID)
+	}
+	if sell.TradeType != types.TradeTypeSell {
+		return nil, fmt.Errorf("invalid lot selection: transaction %s is not a sell", sellTransactionID)
+	}
+
+	engine, err := s.costBasisEngine(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cost basis settings: %w", err)
+	}
+
+	// Other sells may already have taken part of the selected lots
+	existing, err := s.lotSelectionRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load lot selections: %w", err)
+	}
+
+	var totalQuantity float64
+	selected := make(map[uuid.UUID]float64)
+	for _, selection := range selections {
+		if selection.Quantity <= 0 {
+			return nil, fmt.Errorf("invalid lot selection: quantity must be positive")
+		}
+
+		buy, err := s.transactionRepo.GetByIDAndUserID(selection.BuyTransactionID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid lot selection: buy transaction %s not found", selection.BuyTransactionID)
+		}
+		if buy.TradeType != types.TradeTypeBuy || engine.SymbolAt(buy.Symbol, buy.TransactionDate, sell.TransactionDate) != sell.Symbol {
+			return nil, fmt.Errorf("invalid lot selection: transaction %s is not a %s buy", selection.BuyTransactionID, sell.Symbol)
+		}
+		if buy.TransactionDate.After(sell.TransactionDate) {
+			return nil, fmt.Errorf("invalid lot selection: lot %s was acquired after the sale", selection.BuyTransactionID)
+		}
+		selected[buy.TransactionID] += selection.Quantity
+		if selected[buy.TransactionID] > RemainingLotQuantity(*buy, sellTransactionID, existing)+quantityEpsilon {
+			return nil, fmt.Errorf("invalid lot selection: quantity exceeds the remaining size of lot %s", selection.BuyTransactionID)
+		}
+		totalQuantity += selection.Quantity
+	}
+
+	if totalQuantity > sell.Quantity+quantityEpsilon {
+		return nil, fmt.Errorf("invalid lot selection: selected quantity exceeds sold quantity")
+	}
+
+	updated, err := s.lotSelectionRepo.ReplaceForSell(userID, sellTransactionID, selections)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.RebuildTaxLots(userID, sell.TransactionDate); err != nil {
+		fmt.Printf("Warning: failed to rebuild tax lots for user %s: %v\n", userID, err)
+	}
+	s.queueSnapshotRecompute(userID, sell.TransactionDate)
+
+	return updated, nil
+}
+
+// RemainingLotQuantity returns the quantity of a buy lot not taken by the lot selections of sells
+// other than sellTransactionID
+func RemainingLotQuantity(buy models.Transaction, sellTransactionID uuid.UUID, selections []models.LotSelection) float64 {
+	remaining := buy.Quantity
+	for _, selection := range selections {
+		if selection.BuyTransactionID == buy.TransactionID && selection.SellTransactionID != sellTransactionID {
+			remaining -= selection.Quantity
+		}
+	}
+	return remaining
+}
+
+// costBasisEngine builds the cost basis engine configured for a user
+func (s *PortfolioService) costBasisEngine(userID uuid.UUID) (*CostBasisEngine, error) {
+	settings, err := s.GetSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var selections []models.LotSelection
+	if settings.CostBasisMethod == models.CostBasisSpecificLot {
+		selections, err = s.lotSelectionRepo.GetByUserID(userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	actions, err := s.corporateActionRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	contracts, err := s.optionContractRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCostBasisEngine(settings.CostBasisMethod, selections).WithCorporateActions(actions).WithOptionContracts(contracts), nil
 }
 
 // GetSingleHoldingBasicInfo retrieves basic information for a specific stock holding
-func (s *PortfolioService) GetSingleHoldingBasicInfo(ctx context.Context, userID uuid.UUID, symbol string) (*models.SingleHolding, error) {
-	// Get all transactions for this user and symbol
-	transactions, err := s.transactionRepo.GetByUserIDAndSymbol(userID, symbol)
+func (s *PortfolioService) GetSingleHoldingBasicInfo(ctx context.Context, userID uuid.UUID, scope PortfolioScope, symbol string) (*models.SingleHolding, error) {
+	// Get all transactions in scope; earlier symbols of a renamed or merged holding count towards it
+	allTransactions, err := s.scopedTransactions(userID, scope)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get transactions for symbol %s: %w", symbol, err)
 	}
 
+	settings, err := s.GetSettings(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load portfolio settings: %w", err)
+	}
+
+	engine, err := s.costBasisEngine(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cost basis settings: %w", err)
+	}
+
+	transactions := engine.GroupBySymbol(allTransactions, time.Now())[symbol]
 	if len(transactions) == 0 {
 		return nil, fmt.Errorf("no transactions found for symbol %s", symbol)
 	}
 
-	// Calculate basic metrics from transactions
-	totalQuantity, totalCost, unitCost, realizedGainLoss := s.calculateHoldingMetrics(transactions)
-
 	// Check if user still holds this stock
-	if totalQuantity <= 0 {
+	totalQuantity, _, _, _ := s.calculateHoldingMetrics(engine, transactions)
+	if math.Abs(totalQuantity) <= quantityEpsilon {
 		return nil, fmt.Errorf("no current holdings for symbol %s", symbol)
 	}
 
-	// Get current price from PriceServiceManager
-	currentPriceData, err := s.priceManager.GetCurrentPrice(ctx, symbol)
+	// Get current price from PriceServiceManager, on the exchange the holding trades on
+	currentPriceData, err := s.priceManager.GetCurrentPrice(ctx, holdingQuoteSymbol(symbol, transactions))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get current price for %s: %w", symbol, err)
 	}
 
-	currentPrice := currentPriceData.CurrentPrice
-	marketValue := totalQuantity * currentPrice
-	unrealizedGainLoss := marketValue - totalCost
-
-	// Calculate return rates
-	simpleReturnRate := s.calculateSimpleReturnRate(totalCost, marketValue)
-	annualizedReturnRate := s.calculateAnnualizedReturnRate(transactions, totalCost, marketValue)
+	fx := s.fxConverter(ctx, settings.BaseCurrency)
+	holding, err := s.valueHolding(engine, fx, symbol, transactions, currentPriceData.CurrentPrice, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %s into %s: %w", symbol, settings.BaseCurrency, err)
+	}
 
-	return &models.SingleHolding{
-		Symbol:               symbol,
-		TotalQuantity:        utils.RoundTo4(totalQuantity),
-		TotalCost:            utils.RoundTo4(totalCost),
-		UnitCost:             utils.RoundTo4(unitCost),
-		CurrentPrice:         currentPrice,
-		MarketValue:          utils.RoundTo4(marketValue),
-		SimpleReturnRate:     utils.RoundTo4(simpleReturnRate),
-		AnnualizedReturnRate: utils.RoundTo4(annualizedReturnRate),
-		RealizedGainLoss:     utils.RoundTo4(realizedGainLoss),
-		UnrealizedGainLoss:   utils.RoundTo4(unrealizedGainLoss),
-	}, nil
+	s.describeHoldings([]*models.SingleHolding{holding}, transactions)
+	return holding, nil
 }
 
 // GetAllHoldings retrieves basic information for all current holdings of a user
-func (s *PortfolioService) GetAllHoldings(ctx context.Context, userID uuid.UUID) ([]models.SingleHolding, error) {
-	// Get all transactions for this user
-	transactions, err := s.transactionRepo.GetByUserID(userID)
+func (s *PortfolioService) GetAllHoldings(ctx context.Context, userID uuid.UUID, scope PortfolioScope) ([]models.SingleHolding, error) {
+	// Get all transactions in scope
+	transactions, err := s.scopedTransactions(userID, scope)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get transactions for user: %w", err)
 	}
@@ -90,156 +297,337 @@ func (s *PortfolioService) GetAllHoldings(ctx context.Context, userID uuid.UUID)
 		return []models.SingleHolding{}, nil
 	}
 
-	// Group transactions by symbol
-	transactionsBySymbol := make(map[string][]models.Transaction)
-	for _, tx := range transactions {
-		transactionsBySymbol[tx.Symbol] = append(transactionsBySymbol[tx.Symbol], tx)
+	settings, err := s.GetSettings(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load portfolio settings: %w", err)
 	}
 
+	engine, err := s.costBasisEngine(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cost basis settings: %w", err)
+	}
+
+	holdings := s.getAllHoldings(ctx, engine, s.fxConverter(ctx, settings.BaseCurrency), transactions)
+	described := make([]*models.SingleHolding, len(holdings))
+	for i := range holdings {
+		described[i] = &holdings[i]
+	}
+	s.describeHoldings(described, transactions)
+	return holdings, nil
+}
+
+// getAllHoldings values every symbol still held, skipping those whose price or FX rate is unavailable
+func (s *PortfolioService) getAllHoldings(ctx context.Context, engine *CostBasisEngine, fx *FXConverter, transactions []models.Transaction) []models.SingleHolding {
+	now := time.Now()
+
+	// Group transactions by the symbol they are held under today
+	transactionsBySymbol := engine.GroupBySymbol(transactions, now)
+
 	var holdings []models.SingleHolding
 	for symbol, symbolTransactions := range transactionsBySymbol {
-		// Calculate basic metrics for this symbol
-		totalQuantity, totalCost, unitCost, realizedGainLoss := s.calculateHoldingMetrics(symbolTransactions)
-
-		// Skip if user no longer holds this stock
-		if totalQuantity <= 0 {
+		// Skip if user no longer holds this stock, long or short
+		totalQuantity, _, _, _ := s.calculateHoldingMetrics(engine, symbolTransactions)
+		if math.Abs(totalQuantity) <= quantityEpsilon {
 			continue
 		}
 
-		// Get current price from PriceServiceManager
-		currentPriceData, err := s.priceManager.GetCurrentPrice(ctx, symbol)
+		// Get current price from PriceServiceManager, on the exchange the holding trades on
+		currentPriceData, err := s.priceManager.GetCurrentPrice(ctx, holdingQuoteSymbol(symbol, symbolTransactions))
 		if err != nil {
 			// Log error but continue with other holdings
 			fmt.Printf("Warning: failed to get current price for %s: %v\n", symbol, err)
 			continue
 		}
 
-		currentPrice := currentPriceData.CurrentPrice
-		marketValue := totalQuantity * currentPrice
-		unrealizedGainLoss := marketValue - totalCost
+		holding, err := s.valueHolding(engine, fx, symbol, symbolTransactions, currentPriceData.CurrentPrice, now)
+		if err != nil {
+			fmt.Printf("Warning: failed to convert %s into %s: %v\n", symbol, fx.BaseCurrency(), err)
+			continue
+		}
 
-		// Calculate return rates
-		simpleReturnRate := s.calculateSimpleReturnRate(totalCost, marketValue)
-		annualizedReturnRate := s.calculateAnnualizedReturnRate(symbolTransactions, totalCost, marketValue)
+		holdings = append(holdings, *holding)
+	}
 
-		holding := models.SingleHolding{
-			Symbol:               symbol,
-			TotalQuantity:        utils.RoundTo4(totalQuantity),
-			TotalCost:            utils.RoundTo4(totalCost),
-			UnitCost:             utils.RoundTo4(unitCost),
-			CurrentPrice:         utils.RoundTo4(currentPrice),
-			MarketValue:          utils.RoundTo4(marketValue),
-			SimpleReturnRate:     utils.RoundTo4(simpleReturnRate),
-			AnnualizedReturnRate: utils.RoundTo4(annualizedReturnRate),
-			RealizedGainLoss:     utils.RoundTo4(realizedGainLoss),
-			UnrealizedGainLoss:   utils.RoundTo4(unrealizedGainLoss),
-		}
+	return holdings
+}
 
-		holdings = append(holdings, holding)
+// valueHolding computes a holding's figures at the current price. Transactions are replayed twice:
+// in the holding's currency and converted into the base currency at each trade date's rate.
+// The unrealized gain then splits into the part earned by the share price and the part earned
+// by the exchange rate moving since the shares were bought.
+func (s *PortfolioService) valueHolding(engine *CostBasisEngine, fx *FXConverter, symbol string, transactions []models.Transaction, currentPrice float64, asOf time.Time) (*models.SingleHolding, error) {
+	currency := holdingCurrency(transactions)
+	rate, err := fx.RateAt(currency, asOf)
+	if err != nil {
+		return nil, err
 	}
 
-	return holdings, nil
+	converted, err := fx.ConvertTransactions(transactions)
+	if err != nil {
+		return nil, err
+	}
+
+	local := engine.CalculateAt(transactions, asOf)
+	base := engine.CalculateAt(converted, asOf)
+
+	totalQuantity := local.TotalQuantity()
+	totalCost := base.TotalCost()
+	realizedGainLoss := base.RealizedGainLoss()
+	multiplier := engine.Multiplier(symbol)
+	marketValue := totalQuantity * multiplier * currentPrice * rate
+	unrealizedGainLoss := marketValue - totalCost
+	fxGainLoss := local.TotalCost()*rate - totalCost
+	priceGainLoss := unrealizedGainLoss - fxGainLoss
+
+	// Calculate return rates
+	simpleReturnRate := s.calculateSimpleReturnRate(totalCost, marketValue)
+	annualizedReturnRate := s.calculateAnnualizedReturnRate(converted, totalCost, marketValue)
+	income, dividendYield, yieldOnCost := calculateDividendMetrics(converted, marketValue, totalCost, asOf)
+	fees, taxes := transactionCosts(converted)
+	totalReturn := unrealizedGainLoss + realizedGainLoss + income
+
+	return &models.SingleHolding{
+		Symbol:               symbol,
+		Currency:             currency,
+		FXRate:               rate,
+		TotalQuantity:        utils.RoundTo10(totalQuantity),
+		Multiplier:           multiplier,
+		Short:                totalQuantity < 0,
+		TotalCost:            utils.RoundTo4(totalCost),
+		UnitCost:             utils.RoundTo10(local.UnitCost()),
+		CurrentPrice:         utils.RoundTo10(currentPrice),
+		MarketValue:          utils.RoundTo4(marketValue),
+		SimpleReturnRate:     utils.RoundTo4(simpleReturnRate),
+		AnnualizedReturnRate: utils.RoundTo4(annualizedReturnRate),
+		RealizedGainLoss:     utils.RoundTo4(realizedGainLoss),
+		UnrealizedGainLoss:   utils.RoundTo4(unrealizedGainLoss),
+		PriceGainLoss:        utils.RoundTo4(priceGainLoss),
+		FXGainLoss:           utils.RoundTo4(fxGainLoss),
+		DividendIncome:       utils.RoundTo4(income),
+		DividendYield:        utils.RoundTo4(dividendYield),
+		YieldOnCost:          utils.RoundTo4(yieldOnCost),
+		TotalReturn:          utils.RoundTo4(totalReturn),
+		TotalFees:            utils.RoundTo4(fees),
+		TotalTaxes:           utils.RoundTo4(taxes),
+		GrossTotalReturn:     utils.RoundTo4(totalReturn + fees + taxes),
+	}, nil
 }
 
 // GetPortfolioSummary retrieves comprehensive portfolio summary for a user
-func (s *PortfolioService) GetPortfolioSummary(ctx context.Context, userID uuid.UUID) (*models.PortfolioSummary, error) {
-	// Get all current holdings
-	holdings, err := s.GetAllHoldings(ctx, userID)
+func (s *PortfolioService) GetPortfolioSummary(ctx context.Context, userID uuid.UUID, scope PortfolioScope) (*models.PortfolioSummary, error) {
+	settings, err := s.GetSettings(userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get holdings for portfolio summary: %w", err)
+		return nil, fmt.Errorf("failed to load portfolio settings: %w", err)
 	}
 
-	now := time.Now().UTC()
+	engine, err := s.costBasisEngine(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cost basis settings: %w", err)
+	}
 
-	// Calculate summary metrics
-	var totalMarketValue, totalCost, totalRealizedGainLoss, totalUnrealizedGainLoss float64
-	holdingsCount := len(holdings)
+	now := time.Now().UTC()
 
 	// Check if user has any transactions (not just current holdings)
-	allTransactions, err := s.transactionRepo.GetByUserID(userID)
+	allTransactions, err := s.scopedTransactions(userID, scope)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all transactions for portfolio summary: %w", err)
 	}
 	hasTransactions := len(allTransactions) > 0
 
-	for _, holding := range holdings {
-		totalMarketValue += holding.MarketValue
-		totalCost += holding.TotalCost
-		totalRealizedGainLoss += holding.RealizedGainLoss
-		totalUnrealizedGainLoss += holding.UnrealizedGainLoss
+	// Cash flows are converted at the rate of the day they happened
+	fx := s.fxConverter(ctx, settings.BaseCurrency)
+	convertedTransactions, err := fx.ConvertTransactions(allTransactions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert transactions into %s: %w", fx.BaseCurrency(), err)
 	}
 
-	// Calculate total return and percentage
-	totalReturn := totalUnrealizedGainLoss + totalRealizedGainLoss
+	// The whole portfolio is valued from today's snapshot; portfolios in part, and users whose
+	// snapshots are being recomputed, are valued at current prices
+	var snapshots map[string]portfolioValuation
+	var firstTransactionDate time.Time
+	if hasTransactions {
+		firstTransactionDate = sortTransactionsByDate(allTransactions)[0].TransactionDate
+		if scope.IsAll() {
+			snapshots = s.snapshotValuations(ctx, userID, engine, fx, allTransactions, convertedTransactions, firstTransactionDate, now)
+		}
+	}
+	totals, ok := snapshotHoldingTotals(engine, convertedTransactions, snapshots, now)
+	if !ok {
+		totals = liveHoldingTotals(s.getAllHoldings(ctx, engine, fx, allTransactions))
+	}
+	totalMarketValue, totalCost := totals.MarketValue, totals.Cost
+
+	// Dividends count as income across the whole portfolio, including positions that were since closed
+	totalDividendIncome, dividendYield, yieldOnCost := calculateDividendMetrics(convertedTransactions, totalMarketValue, totalCost, now)
+
+	// Calculate total return and percentage; the gross cost counts short positions at the
+	// proceeds they raised
+	totalReturn := totals.UnrealizedGainLoss + totals.RealizedGainLoss + totalDividendIncome
 	var totalReturnPercentage float64
-	if totalCost > 0 {
-		totalReturnPercentage = (totalReturn / totalCost) * 100
+	if totals.GrossCost > 0 {
+		totalReturnPercentage = (totalReturn / totals.GrossCost) * 100
+	}
+
+	// Gross return adds back the costs behind the figures above: trading costs of the
+	// holdings still held and the costs of every dividend counted as income
+	totalFees, totalTaxes := transactionCosts(convertedTransactions)
+	grossTotalReturn := totalReturn
+	convertedBySymbol := engine.GroupBySymbol(convertedTransactions, now)
+	for _, symbol := range totals.Symbols {
+		for _, tx := range convertedBySymbol[symbol] {
+			if tx.TradeType != types.TradeTypeDividend {
+				grossTotalReturn += tx.TransactionCosts.Total()
+			}
+		}
+	}
+	for _, tx := range convertedTransactions {
+		if tx.TradeType == types.TradeTypeDividend {
+			grossTotalReturn += tx.TransactionCosts.Total()
+		}
+	}
+
+	// Uninvested cash is valued at today's rates, one balance per broker and currency
+	cashBalances, cashBalance, err := valueCashBalances(fx, allTransactions, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to value cash balances in %s: %w", fx.BaseCurrency(), err)
+	}
+	for i := range cashBalances {
+		cashBalances[i].Balance = utils.RoundTo4(cashBalances[i].Balance)
+		cashBalances[i].Value = utils.RoundTo4(cashBalances[i].Value)
+	}
+
+	// Time-weighted returns over every timeframe, unaffected by when money was added or withdrawn
+	timeWeightedReturns := make(map[models.TimeFrame]float64)
+	if hasTransactions {
+		tracker := s.newPerformanceTracker(ctx, engine, fx, allTransactions, convertedTransactions, firstTransactionDate, now)
+		tracker.snapshots = snapshots
+		for _, timeframe := range models.TimeFrames() {
+			startTime, err := s.calculateStartTime(now, timeframe)
+			if err != nil {
+				return nil, fmt.Errorf("failed to calculate start time: %w", err)
+			}
+			if timeframe == models.TimeFrameALL {
+				startTime = firstTransactionDate
+			}
+
+			returns, err := tracker.timeWeightedReturns([]time.Time{startTime, now})
+			if err != nil {
+				return nil, fmt.Errorf("failed to calculate %s time-weighted return: %w", timeframe, err)
+			}
+			timeWeightedReturns[timeframe] = utils.RoundTo4(returns[len(returns)-1])
+		}
 	}
 
 	// Calculate annualized return rate (XIRR) for the whole portfolio
 	var annualizedReturnRate float64
 	if hasTransactions && totalCost > 0 && totalMarketValue > 0 {
-		// Gather all transactions for XIRR calculation
-		allTxs := append([]models.Transaction{}, allTransactions...)
-		annualizedReturnRate = s.calculateAnnualizedReturnRate(allTxs, totalCost, totalMarketValue)
+		annualizedReturnRate = s.calculateAnnualizedReturnRate(convertedTransactions, totalCost, totalMarketValue)
 	}
 
 	return &models.PortfolioSummary{
 		Timestamp:             now,
-		Currency:              "USD", // Default currency as per requirements
+		Currency:              fx.BaseCurrency(),
 		MarketValue:           utils.RoundTo4(totalMarketValue),
 		TotalCost:             utils.RoundTo4(totalCost),
 		TotalReturn:           utils.RoundTo4(totalReturn),
 		TotalReturnPercentage: utils.RoundTo4(totalReturnPercentage),
-		HoldingsCount:         holdingsCount,
+		GrossTotalReturn:      utils.RoundTo4(grossTotalReturn),
+		TotalFees:             utils.RoundTo4(totalFees),
+		TotalTaxes:            utils.RoundTo4(totalTaxes),
+		HoldingsCount:         len(totals.Symbols),
 		HasTransactions:       hasTransactions,
 		AnnualizedReturnRate:  utils.RoundTo4(annualizedReturnRate),
+		PriceGainLoss:         utils.RoundTo4(totals.PriceGainLoss),
+		FXGainLoss:            utils.RoundTo4(totals.FXGainLoss),
+		DividendIncome:        utils.RoundTo4(totalDividendIncome),
+		DividendYield:         utils.RoundTo4(dividendYield),
+		YieldOnCost:           utils.RoundTo4(yieldOnCost),
+		CashBalance:           utils.RoundTo4(cashBalance),
+		CashBalances:          cashBalances,
+		InterestIncome:        utils.RoundTo4(interestIncome(convertedTransactions)),
+		CostBasisMethod:       engine.Method(),
 		LastUpdated:           now,
+		TimeWeightedReturns:   timeWeightedReturns,
 	}, nil
 }
 
-// calculateHoldingMetrics calculates total quantity, cost, unit cost, and realized gains/losses
-func (s *PortfolioService) calculateHoldingMetrics(transactions []models.Transaction) (totalQuantity, totalCost, unitCost, realizedGainLoss float64) {
-	var totalBoughtQuantity, totalBoughtCost float64
-	var totalSoldQuantity, totalSoldRevenue float64
+// holdingTotals sums up the figures of a portfolio's current holdings in the base currency
+type holdingTotals struct {
+	MarketValue        float64
+	Cost               float64
+	GrossCost          float64
+	RealizedGainLoss   float64
+	UnrealizedGainLoss float64
+	PriceGainLoss      float64
+	FXGainLoss         float64
+	// Symbols are the symbols of the holdings
+	Symbols []string
+}
 
-	for _, tx := range transactions {
-		switch tx.TradeType {
-		case "Buy":
-			totalBoughtQuantity += tx.Quantity
-			totalBoughtCost += tx.Amount
-		case "Sell":
-			totalSoldQuantity += tx.Quantity
-			totalSoldRevenue += tx.Amount
-		}
+// liveHoldingTotals sums up holdings valued at current prices
+func liveHoldingTotals(holdings []models.SingleHolding) holdingTotals {
+	var totals holdingTotals
+	for _, holding := range holdings {
+		totals.MarketValue += holding.MarketValue
+		totals.Cost += holding.TotalCost
+		totals.GrossCost += math.Abs(holding.TotalCost)
+		totals.RealizedGainLoss += holding.RealizedGainLoss
+		totals.UnrealizedGainLoss += holding.UnrealizedGainLoss
+		totals.PriceGainLoss += holding.PriceGainLoss
+		totals.FXGainLoss += holding.FXGainLoss
+		totals.Symbols = append(totals.Symbols, holding.Symbol)
 	}
+	return totals
+}
 
-	// Current holdings
-	totalQuantity = totalBoughtQuantity - totalSoldQuantity
-
-	if totalQuantity > 0 && totalBoughtQuantity > 0 {
-		// Calculate weighted average cost basis for remaining shares
-		avgCostPerShare := totalBoughtCost / totalBoughtQuantity
-		totalCost = totalQuantity * avgCostPerShare
-		unitCost = avgCostPerShare
+// snapshotHoldingTotals sums up the holdings of the snapshot of now's day. Snapshots do not keep
+// realized gains, so those of the holdings are replayed from the converted transactions.
+// ok is false when there is no such snapshot.
+func snapshotHoldingTotals(engine *CostBasisEngine, converted []models.Transaction, snapshots map[string]portfolioValuation, now time.Time) (holdingTotals, bool) {
+	snapshot, ok := snapshots[snapshotKey(now)]
+	if !ok {
+		return holdingTotals{}, false
 	}
 
-	// Realized gain/loss from sold positions
-	if totalSoldQuantity > 0 && totalBoughtQuantity > 0 {
-		avgCostPerShare := totalBoughtCost / totalBoughtQuantity
-		soldCostBasis := totalSoldQuantity * avgCostPerShare
-		realizedGainLoss = totalSoldRevenue - soldCostBasis
+	totals := holdingTotals{
+		MarketValue:        snapshot.MarketValue,
+		Cost:               snapshot.CostBasis,
+		UnrealizedGainLoss: snapshot.MarketValue - snapshot.CostBasis,
+		FXGainLoss:         snapshot.FXGainLoss,
+		PriceGainLoss:      snapshot.MarketValue - snapshot.CostBasis - snapshot.FXGainLoss,
 	}
+	convertedBySymbol := engine.GroupBySymbol(converted, now)
+	for _, holding := range snapshot.Holdings {
+		totals.GrossCost += math.Abs(holding.CostBasis)
+		totals.RealizedGainLoss += engine.CalculateAt(convertedBySymbol[holding.Symbol], now).RealizedGainLoss()
+		totals.Symbols = append(totals.Symbols, holding.Symbol)
+	}
+	return totals, true
+}
 
-	return totalQuantity, totalCost, unitCost, realizedGainLoss
+// calculateHoldingMetrics calculates total quantity, cost, unit cost, and realized gains/losses
+// using the user's cost basis method
+func (s *PortfolioService) calculateHoldingMetrics(engine *CostBasisEngine, transactions []models.Transaction) (totalQuantity, totalCost, unitCost, realizedGainLoss float64) {
+	result := engine.Calculate(transactions)
+	return result.TotalQuantity(), result.TotalCost(), result.UnitCost(), result.RealizedGainLoss()
+}
+
+// transactionCosts sums the fees and taxes charged on transactions
+func transactionCosts(transactions []models.Transaction) (fees, taxes float64) {
+	for _, tx := range transactions {
+		fees += tx.TransactionCosts.Fees()
+		taxes += tx.TransactionCosts.Taxes()
+	}
+	return fees, taxes
 }
 
-// calculateSimpleReturnRate calculates simple return rate percentage
+// calculateSimpleReturnRate calculates simple return rate percentage. Short positions have a
+// negative cost and market value, and earn their return on the proceeds of the short sales.
 func (s *PortfolioService) calculateSimpleReturnRate(totalCost, marketValue float64) float64 {
-	if totalCost <= 0 {
+	if totalCost == 0 {
 		return 0
 	}
-	return ((marketValue - totalCost) / totalCost) * 100
+	return ((marketValue - totalCost) / math.Abs(totalCost)) * 100
 }
 
 // calculateAnnualizedReturnRate calculates XIRR (Internal Rate of Return) based on cash flows
@@ -255,10 +643,11 @@ func (s *PortfolioService) calculateAnnualizedReturnRate(transactions []models.T
 	}
 
 	for _, tx := range transactions {
-		amount := tx.Amount
-		if tx.TradeType == "Buy" {
-			amount = -amount
+		// Buys are outflows; sale proceeds and dividend income are inflows, all net of fees and taxes
+		if !tx.TradeType.IsTrade() && !tx.TradeType.IsOptionEvent() && tx.TradeType != types.TradeTypeDividend {
+			continue
 		}
+		amount := tx.CashFlow()
 		cashFlows = append(cashFlows, struct {
 			Amount float64
 			Date   time.Time
@@ -299,8 +688,9 @@ func (s *PortfolioService) calculateAnnualizedReturnRate(transactions []models.T
 	return rate * 100
 }
 
-// GetHistoricalPortfolioTotalValue calculates portfolio total value over time
-func (s *PortfolioService) GetHistoricalPortfolioTotalValue(ctx context.Context, userID uuid.UUID, timeframe models.TimeFrame) (*models.HistoricalTotalValueResponse, error) {
+// GetHistoricalPortfolioTotalValue calculates portfolio total value over time, along with a
+// comparison series for each benchmark symbol
+func (s *PortfolioService) GetHistoricalPortfolioTotalValue(ctx context.Context, userID uuid.UUID, scope PortfolioScope, timeframe models.TimeFrame, benchmarks []string) (*models.HistoricalTotalValueResponse, error) {
 	// Calculate time range based on timeframe
 	endTime := time.Now()
 	startTime, err := s.calculateStartTime(endTime, timeframe)
@@ -308,25 +698,42 @@ func (s *PortfolioService) GetHistoricalPortfolioTotalValue(ctx context.Context,
 		return nil, fmt.Errorf("failed to calculate start time: %w", err)
 	}
 
-	// Determine granularity
+	// Determine granularity; the shortest timeframes are charted hourly from intraday bars
 	defaultGranularity := s.determineDefaultGranularity(timeframe)
+	interval, intraday := s.intradayInterval(timeframe)
+	if intraday {
+		defaultGranularity = models.GranularityHourly
+	}
 	var granularity = &defaultGranularity
 
-	// Get all transactions for the user up to end time (needed for correct portfolio calculation)
-	allTransactions, err := s.transactionRepo.GetByUserID(userID)
+	// Get all transactions in scope up to end time (needed for correct portfolio calculation)
+	allTransactions, err := s.scopedTransactions(userID, scope)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get transactions: %w", err)
 	}
 
+	settings, err := s.GetSettings(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load portfolio settings: %w", err)
+	}
+
+	engine, err := s.costBasisEngine(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cost basis settings: %w", err)
+	}
+
 	// For ALL timeframe, use first transaction date as start time
+	allTransactions = sortTransactionsByDate(allTransactions)
 	if timeframe == models.TimeFrameALL && len(allTransactions) > 0 {
 		startTime = allTransactions[0].TransactionDate
 	}
 
 	if len(allTransactions) == 0 {
 		return &models.HistoricalTotalValueResponse{
-			TimeFrame:   timeframe,
-			Granularity: *granularity,
+			TimeFrame:       timeframe,
+			Granularity:     *granularity,
+			Currency:        settings.BaseCurrency,
+			CostBasisMethod: engine.Method(),
 			Period: struct {
 				StartDate time.Time `json:"start_date"`
 				EndDate   time.Time `json:"end_date"`
@@ -345,18 +752,41 @@ func (s *PortfolioService) GetHistoricalPortfolioTotalValue(ctx context.Context,
 		}, nil
 	}
 
+	// Convert every cash flow at its trade date; holdings are converted at each time point's rate
+	fx := s.fxConverter(ctx, settings.BaseCurrency)
+	convertedTransactions, err := fx.ConvertTransactions(allTransactions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert transactions into %s: %w", settings.BaseCurrency, err)
+	}
+
 	// Generate time points based on granularity
 	timePoints := s.generateTimePoints(startTime, endTime, *granularity)
 
+	// Value every time point, plus each day with an external cash flow in between, from the daily
+	// snapshots; anything they do not cover is valued in parallel. Time-weighted returns chain
+	// from the start of the period, splitting at those flows. Snapshots hold end-of-day values,
+	// so intraday time points are all valued from intraday bars instead.
+	tracker := s.newPerformanceTracker(ctx, engine, fx, allTransactions, convertedTransactions, startTime, endTime)
+	if intraday {
+		tracker.prices = s.intradayPriceHistory(ctx, startTime, endTime, interval, allTransactions)
+	} else if scope.IsAll() {
+		tracker.snapshots = s.snapshotValuations(ctx, userID, engine, fx, allTransactions, convertedTransactions, startTime, endTime)
+	}
+	timeWeightedReturns, err := tracker.timeWeightedReturns(timePoints)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate time-weighted returns: %w", err)
+	}
+
 	// Calculate total value for each time point
 	dataPoints := make([]models.TotalValueDataPoint, 0, len(timePoints))
 	var previousValue float64
 
 	for i, timePoint := range timePoints {
-		totalValue, err := s.calculateTotalValueAtTime(ctx, allTransactions, timePoint)
+		valuation, err := tracker.valuationAt(timePoint)
 		if err != nil {
 			return nil, fmt.Errorf("failed to calculate total value at %v: %w", timePoint, err)
 		}
+		totalValue := valuation.MarketValue
 
 		// Calculate day change
 		dayChange := 0.0
@@ -367,10 +797,15 @@ func (s *PortfolioService) GetHistoricalPortfolioTotalValue(ctx context.Context,
 		}
 
 		dataPoints = append(dataPoints, models.TotalValueDataPoint{
-			Timestamp:        timePoint,
-			TotalValue:       totalValue,
-			DayChange:        dayChange,
-			DayChangePercent: dayChangePercent,
+			Timestamp:          timePoint,
+			TotalValue:         totalValue,
+			CostBasis:          utils.RoundTo4(valuation.CostBasis),
+			FXGainLoss:         utils.RoundTo4(valuation.FXGainLoss),
+			DividendIncome:     utils.RoundTo4(valuation.DividendIncome),
+			CashBalance:        utils.RoundTo4(valuation.CashBalance),
+			DayChange:          dayChange,
+			DayChangePercent:   dayChangePercent,
+			TimeWeightedReturn: utils.RoundTo4(timeWeightedReturns[i]),
 		})
 
 		previousValue = totalValue
@@ -379,9 +814,39 @@ func (s *PortfolioService) GetHistoricalPortfolioTotalValue(ctx context.Context,
 	// Calculate summary statistics
 	summary := s.calculateSummaryStatistics(dataPoints)
 
+	// Replay the same cash flows into each benchmark, skipping those without prices
+	benchmarkSeries := make([]models.BenchmarkSeries, 0, len(benchmarks))
+	for _, symbol := range benchmarks {
+		values, returns, err := tracker.benchmarkValues(symbol, timePoints)
+		if err != nil {
+			fmt.Printf("Warning: failed to build benchmark series for %s: %v\n", symbol, err)
+			continue
+		}
+
+		benchmarkPoints := make([]models.BenchmarkDataPoint, len(timePoints))
+		for i, timePoint := range timePoints {
+			benchmarkPoints[i] = models.BenchmarkDataPoint{
+				Timestamp:          timePoint,
+				Value:              utils.RoundTo4(values[i]),
+				TimeWeightedReturn: utils.RoundTo4(returns[i]),
+			}
+		}
+
+		benchmarkSummary := summarizeValues(values)
+		benchmarkSummary.TimeWeightedReturn = benchmarkPoints[len(benchmarkPoints)-1].TimeWeightedReturn
+		benchmarkSeries = append(benchmarkSeries, models.BenchmarkSeries{
+			Symbol:       symbol,
+			DataPoints:   benchmarkPoints,
+			Summary:      benchmarkSummary,
+			ExcessReturn: utils.RoundTo4(summary.TimeWeightedReturn - benchmarkSummary.TimeWeightedReturn),
+		})
+	}
+
 	return &models.HistoricalTotalValueResponse{
-		TimeFrame:   timeframe,
-		Granularity: *granularity,
+		TimeFrame:       timeframe,
+		Granularity:     *granularity,
+		Currency:        settings.BaseCurrency,
+		CostBasisMethod: engine.Method(),
 		Period: struct {
 			StartDate time.Time `json:"start_date"`
 			EndDate   time.Time `json:"end_date"`
@@ -391,6 +856,7 @@ func (s *PortfolioService) GetHistoricalPortfolioTotalValue(ctx context.Context,
 		},
 		DataPoints: dataPoints,
 		Summary:    summary,
+		Benchmarks: benchmarkSeries,
 	}, nil
 }
 
@@ -438,6 +904,19 @@ func (s *PortfolioService) determineDefaultGranularity(timeframe models.TimeFram
 	}
 }
 
+// intradayInterval returns the width of the intraday bars a timeframe is charted from, and false
+// for timeframes charted from daily closes
+func (s *PortfolioService) intradayInterval(timeframe models.TimeFrame) (provider.Interval, bool) {
+	switch timeframe {
+	case models.TimeFrame1D:
+		return provider.Interval15Min, true
+	case models.TimeFrame1W:
+		return provider.Interval60Min, true
+	default:
+		return "", false
+	}
+}
+
 // generateTimePoints creates time points based on granularity
 func (s *PortfolioService) generateTimePoints(startTime, endTime time.Time, granularity models.Granularity) []time.Time {
 	var timePoints []time.Time
@@ -469,73 +948,91 @@ func (s *PortfolioService) generateTimePoints(startTime, endTime time.Time, gran
 	return timePoints
 }
 
-// calculateTotalValueAtTime calculates portfolio total value at a specific time
-func (s *PortfolioService) calculateTotalValueAtTime(ctx context.Context, transactions []models.Transaction, targetTime time.Time) (float64, error) {
-	// Group transactions by symbol and calculate holdings at target time
-	holdings := make(map[string]float64)
-
-	for _, transaction := range transactions {
-		if transaction.TransactionDate.After(targetTime) {
-			continue // Skip future transactions
-		}
+// portfolioValuation holds the portfolio figures at a point in time, in the base currency
+type portfolioValuation struct {
+	MarketValue float64
+	CostBasis   float64
+	// FXGainLoss is the part of the unrealized gain caused by exchange rates moving since purchase
+	FXGainLoss float64
+	// DividendIncome is the cumulative dividend cash received up to the point in time
+	DividendIncome float64
+	// CashBalance is the uninvested cash held across brokers at the point in time
+	CashBalance float64
+	// Holdings are the positions held at the point in time, sorted by symbol
+	Holdings []models.SnapshotHolding
+}
 
-		symbol := transaction.Symbol
-		quantity := transaction.Quantity
+// heldPosition is the quantity of a symbol held at a point in time and its rate into the base currency
+type heldPosition struct {
+	Quantity float64
+	FXRate   float64
+}
 
-		if transaction.TradeType == "Sell" {
-			quantity = -quantity
+// calculateTotalValueAtTime calculates portfolio market value, cost basis, dividend income and cash at a specific time.
+// Holdings are priced at the target time from prices: their last close on or before it, or their
+// last intraday bar when prices has intraday bars.
+// converted holds the same transactions as transactions, converted into the base currency at their trade dates.
+func (s *PortfolioService) calculateTotalValueAtTime(prices *PriceHistory, engine *CostBasisEngine, fx *FXConverter, transactions, converted []models.Transaction, targetTime time.Time) (portfolioValuation, error) {
+	// Group transactions by the symbol held at target time, skipping future transactions
+	pastTransactions := transactionsUpTo(converted, targetTime)
+	localBySymbol := engine.GroupBySymbol(transactions, targetTime)
+	convertedBySymbol := engine.GroupBySymbol(pastTransactions, targetTime)
+
+	// Replay each symbol through the cost basis engine to get holdings at target time
+	holdings := make(map[string]heldPosition)
+	costBases := make(map[string]float64)
+	costBasis := 0.0
+	fxGainLoss := 0.0
+	for symbol, symbolTransactions := range localBySymbol {
+		rate, err := fx.RateAt(holdingCurrency(symbolTransactions), targetTime)
+		if err != nil {
+			return portfolioValuation{}, err
 		}
 
-		holdings[symbol] += quantity
+		local := engine.CalculateAt(symbolTransactions, targetTime)
+		base := engine.CalculateAt(convertedBySymbol[symbol], targetTime)
+		holdings[symbol] = heldPosition{Quantity: local.TotalQuantity(), FXRate: rate}
+		costBases[symbol] = base.TotalCost()
+		costBasis += base.TotalCost()
+		fxGainLoss += local.TotalCost()*rate - base.TotalCost()
 	}
 
-	// Calculate total market value using historical prices at target time
+	// Calculate total market value using the closing prices at target time
 	totalValue := 0.0
-	targetDateStr := targetTime.Format("2006-01-02")
+	positions := make([]models.SnapshotHolding, 0, len(holdings))
 
-	for symbol, quantity := range holdings {
-		if quantity <= 0 {
-			continue // Skip if no holdings
+	for symbol, position := range holdings {
+		quantity := position.Quantity
+		if math.Abs(quantity) <= quantityEpsilon {
+			continue // Skip if no holdings; short positions are valued negative
 		}
 
-		// Get historical price for the symbol at target date
-		historicalPrice, err := s.priceManager.GetHistoricalPriceAtDate(ctx, symbol, targetDateStr)
-		if err != nil {
-			// If we can't get historical price, fallback to current price as last resort
-			priceData, fallbackErr := s.priceManager.GetCurrentPrice(ctx, symbol)
-			if fallbackErr != nil {
-				// If both historical and current price fail, skip this holding
-				continue
-			}
-			totalValue += quantity * priceData.CurrentPrice
-			continue
-		}
-
-		// Find the price for the exact date
-		var priceAtDate float64
-		found := false
-
-		for _, pricePoint := range historicalPrice.HistoricalPrices {
-			if pricePoint.Date == targetDateStr {
-				priceAtDate = pricePoint.Price
-				found = true
-				break
-			}
-		}
-
-		if !found {
-			// If exact date not found, fallback to current price
-			priceData, fallbackErr := s.priceManager.GetCurrentPrice(ctx, symbol)
-			if fallbackErr != nil {
-				continue
-			}
-			priceAtDate = priceData.CurrentPrice
+		held := models.SnapshotHolding{Symbol: symbol, Quantity: quantity, CostBasis: costBases[symbol]}
+		if priceAtDate, err := prices.PriceAt(symbol, targetTime); err == nil {
+			// Holdings without any price up to the target time are listed but not valued
+			held.Price = priceAtDate * position.FXRate
+			held.MarketValue = quantity * engine.Multiplier(symbol) * held.Price
+			totalValue += held.MarketValue
 		}
+		positions = append(positions, held)
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		return positions[i].Symbol < positions[j].Symbol
+	})
 
-		totalValue += quantity * priceAtDate
+	_, cashBalance, err := valueCashBalances(fx, transactions, targetTime)
+	if err != nil {
+		return portfolioValuation{}, err
 	}
 
-	return totalValue, nil
+	return portfolioValuation{
+		MarketValue:    totalValue,
+		CostBasis:      costBasis,
+		FXGainLoss:     fxGainLoss,
+		DividendIncome: dividendIncome(pastTransactions),
+		CashBalance:    cashBalance,
+		Holdings:       positions,
+	}, nil
 }
 
 // calculateSummaryStatistics calculates summary statistics for the data points
@@ -544,8 +1041,24 @@ func (s *PortfolioService) calculateSummaryStatistics(dataPoints []models.TotalV
 		return models.TotalValueTrendSummary{}
 	}
 
-	firstValue := dataPoints[0].TotalValue
-	lastValue := dataPoints[len(dataPoints)-1].TotalValue
+	values := make([]float64, len(dataPoints))
+	for i, dataPoint := range dataPoints {
+		values[i] = dataPoint.TotalValue
+	}
+
+	summary := summarizeValues(values)
+	summary.TimeWeightedReturn = dataPoints[len(dataPoints)-1].TimeWeightedReturn
+	return summary
+}
+
+// summarizeValues calculates the change, volatility and range of a value series
+func summarizeValues(values []float64) models.TotalValueTrendSummary {
+	if len(values) == 0 {
+		return models.TotalValueTrendSummary{}
+	}
+
+	firstValue := values[0]
+	lastValue := values[len(values)-1]
 
 	change := lastValue - firstValue
 	changePercent := 0.0
@@ -553,20 +1066,20 @@ func (s *PortfolioService) calculateSummaryStatistics(dataPoints []models.TotalV
 		changePercent = (change / firstValue) * 100
 	}
 
-	minValue := dataPoints[0].TotalValue
-	maxValue := dataPoints[0].TotalValue
+	minValue := values[0]
+	maxValue := values[0]
 
 	var returns []float64
-	for i := 1; i < len(dataPoints); i++ {
-		if dataPoints[i-1].TotalValue > 0 {
-			dailyReturn := (dataPoints[i].TotalValue - dataPoints[i-1].TotalValue) / dataPoints[i-1].TotalValue
+	for i := 1; i < len(values); i++ {
+		if values[i-1] > 0 {
+			dailyReturn := (values[i] - values[i-1]) / values[i-1]
 			returns = append(returns, dailyReturn)
 		}
-		if dataPoints[i].TotalValue > maxValue {
-			maxValue = dataPoints[i].TotalValue
+		if values[i] > maxValue {
+			maxValue = values[i]
 		}
-		if dataPoints[i].TotalValue < minValue {
-			minValue = dataPoints[i].TotalValue
+		if values[i] < minValue {
+			minValue = values[i]
 		}
 	}
 
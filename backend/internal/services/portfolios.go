@@ -0,0 +1,79 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/models"
+)
+
+// maxPortfolioNameLength is the longest name a portfolio can be given
+const maxPortfolioNameLength = 100
+
+// ListPortfolios retrieves a user's named portfolios
+func (s *PortfolioService) ListPortfolios(userID uuid.UUID) ([]models.Portfolio, error) {
+	return s.portfolioRepo.GetByUserID(userID)
+}
+
+// GetPortfolio retrieves one of a user's named portfolios
+func (s *PortfolioService) GetPortfolio(userID, portfolioID uuid.UUID) (*models.Portfolio, error) {
+	portfolio, err := s.portfolioRepo.GetByIDAndUserID(portfolioID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("not_found")
+	}
+	return portfolio, nil
+}
+
+// CreatePortfolio validates and records a new named portfolio for a user
+func (s *PortfolioService) CreatePortfolio(userID uuid.UUID, portfolio models.Portfolio) (*models.Portfolio, error) {
+	if err := normalizePortfolio(&portfolio); err != nil {
+		return nil, err
+	}
+
+	portfolio.UserID = userID
+	if err := s.portfolioRepo.Create(&portfolio); err != nil {
+		return nil, err
+	}
+	return &portfolio, nil
+}
+
+// UpdatePortfolio validates and replaces the name and description of one of a user's portfolios
+func (s *PortfolioService) UpdatePortfolio(userID, portfolioID uuid.UUID, portfolio models.Portfolio) (*models.Portfolio, error) {
+	if _, err := s.portfolioRepo.GetByIDAndUserID(portfolioID, userID); err != nil {
+		return nil, fmt.Errorf("not_found")
+	}
+
+	if err := normalizePortfolio(&portfolio); err != nil {
+		return nil, err
+	}
+
+	if err := s.portfolioRepo.UpdateByIDAndUserID(portfolioID, userID, map[string]interface{}{
+		"name":        portfolio.Name,
+		"description": portfolio.Description,
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.portfolioRepo.GetByIDAndUserID(portfolioID, userID)
+}
+
+// DeletePortfolio deletes one of a user's portfolios. Its accounts and transactions are kept
+// and only left unassigned.
+func (s *PortfolioService) DeletePortfolio(userID, portfolioID uuid.UUID) error {
+	if _, err := s.portfolioRepo.GetByIDAndUserID(portfolioID, userID); err != nil {
+		return fmt.Errorf("not_found")
+	}
+	return s.portfolioRepo.DeleteByIDAndUserID(portfolioID, userID)
+}
+
+// normalizePortfolio trims the portfolio's name and description and validates the portfolio
+func normalizePortfolio(portfolio *models.Portfolio) error {
+	portfolio.Name = strings.TrimSpace(portfolio.Name)
+	portfolio.Description = strings.TrimSpace(portfolio.Description)
+
+	if portfolio.Name == "" || len(portfolio.Name) > maxPortfolioNameLength {
+		return fmt.Errorf("invalid portfolio: name must be 1 to %d characters", maxPortfolioNameLength)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/types"
+	"github.com/transaction-tracker/backend/internal/utils"
+)
+
+// ValidatePositions checks that changing a user's existing transactions leaves no sale or cover
+// closing more shares or option contracts than are open at the time. Changed transactions replace
+// existing ones of the same ID. A sale beyond the shares held is taken for a mistake unless recorded
+// as a short sale, and a cover beyond the shares sold short would leave a long position behind.
+// Shares delivered by assignments count as trades, so an assigned call needs the shares it sells.
+// Trades that were already unmatched before the change are let through, so older records can still be edited.
+func ValidatePositions(engine *CostBasisEngine, existing, changed []models.Transaction) error {
+	return validatePositions(engine, existing, changed, nil)
+}
+
+// ValidateRemainingPositions checks that deleting some of a user's existing transactions leaves
+// no sale or cover closing more than is open at the time, e.g. a sale of the shares of a deleted buy
+func ValidateRemainingPositions(engine *CostBasisEngine, existing []models.Transaction, deleted []uuid.UUID) error {
+	removed := make(map[uuid.UUID]bool, len(deleted))
+	for _, id := range deleted {
+		removed[id] = true
+	}
+	if err := validatePositions(engine, existing, nil, removed); err != nil {
+		return fmt.Errorf("cannot delete: %w", err)
+	}
+	return nil
+}
+
+// validatePositions is ValidatePositions with the existing transactions in removed left out
+func validatePositions(engine *CostBasisEngine, existing, changed []models.Transaction, removed map[uuid.UUID]bool) error {
+	now := time.Now()
+	asOf := now
+
+	replaced := make(map[uuid.UUID]bool, len(changed))
+	candidates := make([]models.Transaction, 0, len(changed))
+	for _, tx := range changed {
+		if tx.TransactionID == uuid.Nil {
+			tx.TransactionID = uuid.New()
+		}
+		// New transactions follow the existing ones of the same day
+		if tx.CreatedAt.IsZero() {
+			tx.CreatedAt = now
+		}
+		if tx.TransactionDate.After(asOf) {
+			asOf = tx.TransactionDate
+		}
+		replaced[tx.TransactionID] = true
+		candidates = append(candidates, tx)
+	}
+
+	// Only the holdings the change touches, before and after, need replaying; an option trade
+	// also touches the underlying its assignments deliver
+	symbols := make(map[string]bool)
+	touch := func(tx models.Transaction) {
+		symbols[engine.SymbolAt(tx.Symbol, tx.TransactionDate, asOf)] = true
+		if contract, ok := engine.OptionContract(tx.Symbol); ok {
+			symbols[engine.SymbolAt(contract.Underlying, tx.TransactionDate, asOf)] = true
+		}
+	}
+	after := make([]models.Transaction, 0, len(existing)+len(candidates))
+	for _, tx := range existing {
+		if replaced[tx.TransactionID] || removed[tx.TransactionID] {
+			touch(tx)
+			continue
+		}
+		after = append(after, tx)
+	}
+	for _, tx := range candidates {
+		touch(tx)
+	}
+	after = append(after, candidates...)
+
+	assignments := make(map[uuid.UUID]models.Transaction)
+	for _, tx := range after {
+		if tx.TradeType == types.TradeTypeAssignment {
+			assignments[tx.TransactionID] = tx
+		}
+	}
+
+	sortedSymbols := make([]string, 0, len(symbols))
+	for symbol := range symbols {
+		sortedSymbols = append(sortedSymbols, symbol)
+	}
+	sort.Strings(sortedSymbols)
+
+	beforeBySymbol := engine.GroupBySymbol(engine.ExpandAssignments(existing), asOf)
+	afterBySymbol := engine.GroupBySymbol(engine.ExpandAssignments(after), asOf)
+	for _, symbol := range sortedSymbols {
+		before := engine.CalculateAt(beforeBySymbol[symbol], asOf).UnmatchedTrades
+		unmatchedAfter := engine.CalculateAt(afterBySymbol[symbol], asOf).UnmatchedTrades
+		for _, tx := range sortTransactionsByDate(afterBySymbol[symbol]) {
+			unmatched := unmatchedAfter[tx.TransactionID]
+			if unmatched-before[tx.TransactionID] <= quantityEpsilon {
+				continue
+			}
+
+			// Shares delivered by an assignment share its ID
+			if assignment, ok := assignments[tx.TransactionID]; ok && tx.TradeType != types.TradeTypeAssignment {
+				return fmt.Errorf("invalid position: assigning %g contracts of %s on %s delivers %g shares of %s more than are held",
+					assignment.Quantity, assignment.Symbol, tx.TransactionDate.Format("2006-01-02"), utils.RoundTo4(unmatched), tx.Symbol)
+			}
+			return unmatchedTradeError(tx, unmatched)
+		}
+	}
+	return nil
+}
+
+// unmatchedTradeError describes a trade closing more shares or contracts than were open
+func unmatchedTradeError(tx models.Transaction, unmatched float64) error {
+	date := tx.TransactionDate.Format("2006-01-02")
+	unmatched = utils.RoundTo4(unmatched)
+	switch tx.TradeType {
+	case types.TradeTypeBuyToCover:
+		return fmt.Errorf("invalid position: covering %g shares of %s on %s exceeds the shares sold short by %g",
+			tx.Quantity, tx.Symbol, date, unmatched)
+	case types.TradeTypeBuyToClose:
+		return fmt.Errorf("invalid position: buying to close %g contracts of %s on %s exceeds the contracts written by %g",
+			tx.Quantity, tx.Symbol, date, unmatched)
+	case types.TradeTypeAssignment:
+		return fmt.Errorf("invalid position: assigning %g contracts of %s on %s exceeds the contracts written by %g",
+			tx.Quantity, tx.Symbol, date, unmatched)
+	case types.TradeTypeSellToClose:
+		return fmt.Errorf("invalid position: selling to close %g contracts of %s on %s exceeds the contracts held by %g; record it as %s if it is meant to write contracts",
+			tx.Quantity, tx.Symbol, date, unmatched, types.TradeTypeSellToOpen)
+	case types.TradeTypeExpiration:
+		return fmt.Errorf("invalid position: expiring %g contracts of %s on %s exceeds the open contracts by %g",
+			tx.Quantity, tx.Symbol, date, unmatched)
+	default:
+		return fmt.Errorf("invalid position: selling %g shares of %s on %s exceeds the shares held by %g; record it as %s if it is meant as a short sale",
+			tx.Quantity, tx.Symbol, date, unmatched, types.TradeTypeSellShort)
+	}
+}
+
+// ValidateTransactions checks a user's new or edited transactions against the option contracts
+// they trade and their positions
+func (s *PortfolioService) ValidateTransactions(userID uuid.UUID, transactions []models.Transaction) error {
+	engine, err := s.costBasisEngine(userID)
+	if err != nil {
+		return fmt.Errorf("failed to load cost basis settings: %w", err)
+	}
+
+	if err := ValidateOptionTrades(engine, transactions); err != nil {
+		return err
+	}
+
+	existing, err := s.transactionRepo.GetByUserID(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get transactions for validation: %w", err)
+	}
+
+	return ValidatePositions(engine, existing, transactions)
+}
+
+// ValidateDeletion checks that deleting some of a user's transactions leaves their remaining
+// positions consistent
+func (s *PortfolioService) ValidateDeletion(userID uuid.UUID, transactionIDs []uuid.UUID) error {
+	engine, err := s.costBasisEngine(userID)
+	if err != nil {
+		return fmt.Errorf("failed to load cost basis settings: %w", err)
+	}
+
+	existing, err := s.transactionRepo.GetByUserID(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get transactions for validation: %w", err)
+	}
+
+	return ValidateRemainingPositions(engine, existing, transactionIDs)
+}
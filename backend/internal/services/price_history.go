@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/transaction-tracker/backend/internal/constants"
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/provider"
+)
+
+// PriceFetcher loads the daily closes of a symbol between two YYYY-MM-DD dates
+type PriceFetcher func(symbol, fromDate, toDate string) ([]provider.ClosePrice, error)
+
+// IntradayFetcher loads the intraday bars of a symbol on the days between two YYYY-MM-DD dates
+type IntradayFetcher func(symbol, fromDate, toDate string) ([]provider.IntradayPrice, error)
+
+// priceSeries is one symbol's closes, loaded at most once
+type priceSeries struct {
+	once   sync.Once
+	closes []dailyValue
+	err    error
+}
+
+// barSeries is one symbol's intraday bars oldest first, loaded at most once
+type barSeries struct {
+	once sync.Once
+	bars []provider.IntradayPrice
+	err  error
+}
+
+// PriceHistory serves the closing prices of any symbol over a fixed date range. Each symbol's
+// whole range is fetched once on first use, so valuing many dates costs one request per symbol.
+// It is safe for concurrent use; different symbols load in parallel.
+type PriceHistory struct {
+	fromDate string
+	toDate   string
+	fetch    PriceFetcher
+	mutex    sync.Mutex
+	series   map[string]*priceSeries
+
+	// Intraday bars, when the history prices times within the day
+	intraday IntradayFetcher
+	barWidth time.Duration
+	bars     map[string]*barSeries
+}
+
+// NewPriceHistory creates a price history covering from to to, backed by fetch. The range starts
+// a few days early so that a close is available to carry forward into its first days.
+func NewPriceHistory(from, to time.Time, fetch PriceFetcher) *PriceHistory {
+	today := time.Now()
+	if to.After(today) {
+		to = today
+	}
+	from = from.AddDate(0, 0, -constants.PriceHistoryLookbackDays)
+	if from.After(to) {
+		from = to
+	}
+
+	return &PriceHistory{
+		fromDate: from.Format("2006-01-02"),
+		toDate:   to.Format("2006-01-02"),
+		fetch:    fetch,
+		series:   make(map[string]*priceSeries),
+	}
+}
+
+// WithIntraday makes PriceAt price times from intraday bars of the given width, fetched by fetch
+// over the history's date range. It returns the history for chaining.
+func (h *PriceHistory) WithIntraday(width time.Duration, fetch IntradayFetcher) *PriceHistory {
+	h.intraday = fetch
+	h.barWidth = width
+	h.bars = make(map[string]*barSeries)
+	return h
+}
+
+// PriceAt returns the symbol's price at t. With intraday bars, that is the close of the last bar
+// that ended by t; times before the first bar, and symbols without bars, fall back to CloseAt.
+func (h *PriceHistory) PriceAt(symbol string, t time.Time) (float64, error) {
+	if h.intraday != nil {
+		if bars, err := h.loadBars(symbol); err == nil {
+			ended := sort.Search(len(bars), func(i int) bool {
+				return bars[i].Time.Add(h.barWidth).After(t)
+			})
+			if ended > 0 {
+				return bars[ended-1].Price, nil
+			}
+		}
+	}
+	return h.CloseAt(symbol, t)
+}
+
+// CloseAt returns the symbol's last close on or before date, carrying it forward over weekends,
+// holidays and dates the provider has no close for yet
+func (h *PriceHistory) CloseAt(symbol string, date time.Time) (float64, error) {
+	closes, err := h.load(symbol)
+	if err != nil {
+		return 0, err
+	}
+
+	price, ok := valueOn(closes, date.Format("2006-01-02"))
+	if !ok {
+		return 0, fmt.Errorf("no price for %s on or before %s", symbol, date.Format("2006-01-02"))
+	}
+	return price, nil
+}
+
+// load returns the symbol's closes, fetching them on first use
+func (h *PriceHistory) load(symbol string) ([]dailyValue, error) {
+	h.mutex.Lock()
+	series, ok := h.series[symbol]
+	if !ok {
+		series = &priceSeries{}
+		h.series[symbol] = series
+	}
+	h.mutex.Unlock()
+
+	series.once.Do(func() {
+		prices, err := h.fetch(symbol, h.fromDate, h.toDate)
+		if err != nil {
+			series.err = fmt.Errorf("failed to get historical prices for %s: %w", symbol, err)
+			return
+		}
+
+		closes := make([]dailyValue, 0, len(prices))
+		for _, price := range prices {
+			if _, err := time.Parse("2006-01-02", price.Date); err != nil || price.Price <= 0 {
+				continue
+			}
+			closes = append(closes, dailyValue{day: price.Date, value: price.Price})
+		}
+		sort.Slice(closes, func(i, j int) bool {
+			return closes[i].day < closes[j].day
+		})
+		series.closes = closes
+	})
+
+	return series.closes, series.err
+}
+
+// loadBars returns the symbol's intraday bars, fetching them on first use
+func (h *PriceHistory) loadBars(symbol string) ([]provider.IntradayPrice, error) {
+	h.mutex.Lock()
+	series, ok := h.bars[symbol]
+	if !ok {
+		series = &barSeries{}
+		h.bars[symbol] = series
+	}
+	h.mutex.Unlock()
+
+	series.once.Do(func() {
+		prices, err := h.intraday(symbol, h.fromDate, h.toDate)
+		if err != nil {
+			series.err = fmt.Errorf("failed to get intraday prices for %s: %w", symbol, err)
+			return
+		}
+
+		bars := make([]provider.IntradayPrice, 0, len(prices))
+		for _, price := range prices {
+			if price.Time.IsZero() || price.Price <= 0 {
+				continue
+			}
+			bars = append(bars, price)
+		}
+		sort.Slice(bars, func(i, j int) bool {
+			return bars[i].Time.Before(bars[j].Time)
+		})
+		series.bars = bars
+	})
+
+	return series.bars, series.err
+}
+
+// holdingQuoteSymbol returns the symbol the price service quotes a holding under, from the
+// exchange of its latest transaction naming one, e.g. 2330.TW for 2330 traded on TPE
+func holdingQuoteSymbol(symbol string, transactions []models.Transaction) string {
+	sorted := sortTransactionsByDate(transactions)
+	for i := len(sorted) - 1; i >= 0; i-- {
+		if sorted[i].Exchange != "" {
+			return provider.QuoteSymbol(symbol, sorted[i].Exchange)
+		}
+	}
+	return symbol
+}
+
+// quoteSymbols maps the symbols of transactions to the symbols the price service quotes them
+// under. Symbols not in the map, such as benchmarks, are quoted as they are.
+func quoteSymbols(transactions []models.Transaction) map[string]string {
+	bySymbol := make(map[string][]models.Transaction)
+	for _, tx := range transactions {
+		bySymbol[tx.Symbol] = append(bySymbol[tx.Symbol], tx)
+	}
+	quotes := make(map[string]string, len(bySymbol))
+	for symbol, symbolTransactions := range bySymbol {
+		if quote := holdingQuoteSymbol(symbol, symbolTransactions); quote != symbol {
+			quotes[symbol] = quote
+		}
+	}
+	return quotes
+}
+
+// priceHistory builds a price history over a date range backed by the price service. Symbols of
+// transactions are quoted on the exchange they were traded on.
+func (s *PortfolioService) priceHistory(ctx context.Context, from, to time.Time, transactions []models.Transaction) *PriceHistory {
+	quotes := quoteSymbols(transactions)
+	return NewPriceHistory(from, to, func(symbol, fromDate, toDate string) ([]provider.ClosePrice, error) {
+		if quote, ok := quotes[symbol]; ok {
+			symbol = quote
+		}
+		prices, err := s.priceManager.GetHistoricalPrices(ctx, []string{symbol}, provider.ResolutionDaily, fromDate, toDate)
+		if err != nil {
+			return nil, err
+		}
+		if len(prices) == 0 {
+			return nil, fmt.Errorf("no historical prices returned for %s", symbol)
+		}
+		return prices[0].HistoricalPrices, nil
+	})
+}
+
+// intradayPriceHistory builds a price history over a date range that prices times from intraday
+// bars of interval, backed by the price service
+func (s *PortfolioService) intradayPriceHistory(ctx context.Context, from, to time.Time, interval provider.Interval, transactions []models.Transaction) *PriceHistory {
+	quotes := quoteSymbols(transactions)
+	return s.priceHistory(ctx, from, to, transactions).WithIntraday(interval.Duration(), func(symbol, fromDate, toDate string) ([]provider.IntradayPrice, error) {
+		if quote, ok := quotes[symbol]; ok {
+			symbol = quote
+		}
+		prices, err := s.priceManager.GetIntradayPrices(ctx, symbol, interval, fromDate, toDate)
+		if err != nil {
+			return nil, err
+		}
+		return prices.IntradayPrices, nil
+	})
+}
@@ -0,0 +1,375 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/types"
+	"github.com/transaction-tracker/backend/internal/utils"
+)
+
+// maxAssetClassLength is the longest asset class a target allocation can be set for
+const maxAssetClassLength = 50
+
+// cryptoOrderIncrement is the smallest fraction of a coin a rebalancing order trades, one satoshi of BTC
+const cryptoOrderIncrement = 1e-8
+
+// RebalanceOptions constrains the orders a rebalancing plan proposes
+type RebalanceOptions struct {
+	// NoSells only tops up underweight targets from available cash
+	NoSells bool
+	// MinTradeValue is the smallest order worth placing, in the base currency
+	MinTradeValue float64
+	// Cash overrides the cash available to invest, in the base currency; nil uses the recorded balances
+	Cash *float64
+}
+
+// RebalanceHolding is a position a rebalancing plan can trade. Price is the current price in
+// the symbol's currency, FXRate converts it into the base currency, and MarketValue is in the
+// base currency. Targeted symbols that are not held yet have a zero quantity.
+type RebalanceHolding struct {
+	Symbol      string
+	AssetClass  string
+	Quantity    float64
+	Price       float64
+	Currency    string
+	FXRate      float64
+	MarketValue float64
+}
+
+// rebalanceGroup is the part of the portfolio counted towards one target
+type rebalanceGroup struct {
+	target  float64
+	value   float64
+	members []RebalanceHolding
+}
+
+// GetTargetAllocations retrieves the target weights a user has set
+func (s *PortfolioService) GetTargetAllocations(userID uuid.UUID) ([]models.TargetAllocation, error) {
+	return s.targetRepo.GetByUserID(userID)
+}
+
+// UpdateTargetAllocations validates and replaces a user's target weights. All targets are of one
+// type; their weights (%) must add up to at most 100, with the remainder targeted as cash.
+// An empty list clears the targets.
+func (s *PortfolioService) UpdateTargetAllocations(userID uuid.UUID, targetType models.TargetType, targets []models.TargetAllocation) ([]models.TargetAllocation, error) {
+	if !targetType.IsValid() {
+		return nil, fmt.Errorf("invalid target allocation: target type must be symbol or asset_class")
+	}
+
+	seen := make(map[string]bool, len(targets))
+	totalWeight := 0.0
+	for i := range targets {
+		key := strings.TrimSpace(targets[i].TargetKey)
+		if targetType == models.TargetTypeSymbol {
+			key = strings.ToUpper(key)
+			if !utils.SymbolRegex.MatchString(key) {
+				return nil, fmt.Errorf("invalid target allocation: symbol %q is not valid", key)
+			}
+		} else {
+			key = strings.ToLower(key)
+			if key == "" || len(key) > maxAssetClassLength {
+				return nil, fmt.Errorf("invalid target allocation: asset class must be 1 to %d characters", maxAssetClassLength)
+			}
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("invalid target allocation: %s is targeted more than once", key)
+		}
+		seen[key] = true
+
+		if targets[i].Weight <= 0 || targets[i].Weight > 100 {
+			return nil, fmt.Errorf("invalid target allocation: weight of %s must be above 0 and at most 100", key)
+		}
+		totalWeight += targets[i].Weight
+
+		targets[i].TargetType = targetType
+		targets[i].TargetKey = key
+		targets[i].Weight = utils.RoundTo4(targets[i].Weight)
+	}
+
+	if totalWeight > 100+quantityEpsilon {
+		return nil, fmt.Errorf("invalid target allocation: weights add up to %.4f, more than 100", totalWeight)
+	}
+
+	return s.targetRepo.ReplaceForUser(userID, targets)
+}
+
+// PlanRebalance measures how far each target is from its weight and proposes the whole-share
+// orders that bring the portfolio back to target at current prices. Holdings count towards the
+// target of their symbol or asset class, depending on the targets' type; cash is targeted at the
+// weight the targets leave unassigned. Overweight targets are sold down unless NoSells is set,
+// and the proceeds together with the cash above its target fund the buys, which are scaled down
+// evenly when they cost more. An asset class is traded across the symbols held in it, in
+// proportion to their value, so a class with nothing held yet only reports its drift.
+// Orders worth less than the minimum trade value are left out.
+func PlanRebalance(targets []models.TargetAllocation, holdings []RebalanceHolding, cash float64, options RebalanceOptions) ([]models.RebalanceDrift, []models.RebalanceOrder) {
+	byAssetClass := len(targets) > 0 && targets[0].TargetType == models.TargetTypeAssetClass
+
+	groups := make(map[string]*rebalanceGroup)
+	var keys []string
+	group := func(key string) *rebalanceGroup {
+		g, ok := groups[key]
+		if !ok {
+			g = &rebalanceGroup{}
+			groups[key] = g
+			keys = append(keys, key)
+		}
+		return g
+	}
+
+	// Targets first in the order given, then holdings that are not targeted (and so sold off)
+	targetWeight := 0.0
+	for _, target := range targets {
+		group(target.TargetKey).target = target.Weight
+		targetWeight += target.Weight
+	}
+	total := cash
+	for _, holding := range holdings {
+		key := holding.Symbol
+		if byAssetClass {
+			key = holding.AssetClass
+			if key == "" {
+				key = AllocationGroupUnknown
+			}
+		}
+		g := group(key)
+		g.value += holding.MarketValue
+		g.members = append(g.members, holding)
+		total += holding.MarketValue
+	}
+	cashTarget := math.Max(100-targetWeight, 0)
+
+	drifts := make([]models.RebalanceDrift, 0, len(keys)+1)
+	drift := func(key string, value, target float64) {
+		d := models.RebalanceDrift{Key: key, CurrentValue: utils.RoundTo4(value), TargetWeight: utils.RoundTo4(target)}
+		if total > 0 {
+			d.CurrentWeight = utils.RoundTo4(value / total * 100)
+			d.Drift = utils.RoundTo4(value/total*100 - target)
+			d.TradeValue = utils.RoundTo4(target/100*total - value)
+		}
+		drifts = append(drifts, d)
+	}
+	for _, key := range keys {
+		drift(key, groups[key].value, groups[key].target)
+	}
+	drift(AllocationGroupCash, cash, cashTarget)
+
+	orders := []models.RebalanceOrder{}
+	if total <= 0 {
+		return drifts, orders
+	}
+
+	// order sizes a trade of value across the group's members in whole shares, or fractions of a coin
+	// for crypto, keeping the orders worth at least the minimum trade value, and returns the amount they trade
+	order := func(g *rebalanceGroup, action types.TradeType, value float64) float64 {
+		traded := 0.0
+		for _, member := range g.members {
+			unitPrice := member.Price * member.FXRate
+			if unitPrice <= 0 {
+				continue
+			}
+
+			share := value / float64(len(g.members))
+			if g.value > 0 {
+				share = value * member.MarketValue / g.value
+			}
+
+			increment := 1.0
+			if utils.CryptoSymbolRegex.MatchString(member.Symbol) {
+				increment = cryptoOrderIncrement
+			}
+			quantity := math.Floor(share/unitPrice/increment+quantityEpsilon) * increment
+			if action == types.TradeTypeSell && (quantity > member.Quantity || share >= member.MarketValue-quantityEpsilon) {
+				// Selling out of a position sells its fractional shares too
+				quantity = member.Quantity
+			}
+			amount := quantity * unitPrice
+			if quantity <= 0 || amount < options.MinTradeValue {
+				continue
+			}
+
+			orders = append(orders, models.RebalanceOrder{
+				Symbol:          member.Symbol,
+				Action:          action,
+				Quantity:        utils.RoundTo10(quantity),
+				Price:           utils.RoundTo10(member.Price),
+				Currency:        member.Currency,
+				EstimatedAmount: utils.RoundTo4(amount),
+			})
+			traded += amount
+		}
+		return traded
+	}
+
+	proceeds := 0.0
+	var buys []*rebalanceGroup
+	needed := 0.0
+	for _, key := range keys {
+		g := groups[key]
+		trade := g.target/100*total - g.value
+		switch {
+		case trade < 0 && !options.NoSells && -trade >= options.MinTradeValue:
+			proceeds += order(g, types.TradeTypeSell, -trade)
+		case trade > 0 && trade >= options.MinTradeValue && len(g.members) > 0:
+			buys = append(buys, g)
+			needed += trade
+		}
+	}
+
+	spendable := math.Max(cash+proceeds-cashTarget/100*total, 0)
+	scale := 1.0
+	if needed > spendable {
+		scale = spendable / needed
+	}
+	for _, g := range buys {
+		order(g, types.TradeTypeBuy, (g.target/100*total-g.value)*scale)
+	}
+
+	sort.SliceStable(orders, func(i, j int) bool {
+		if orders[i].Action != orders[j].Action {
+			return orders[i].Action == types.TradeTypeSell
+		}
+		if orders[i].EstimatedAmount != orders[j].EstimatedAmount {
+			return orders[i].EstimatedAmount > orders[j].EstimatedAmount
+		}
+		return orders[i].Symbol < orders[j].Symbol
+	})
+	return drifts, orders
+}
+
+// GetRebalancePlan proposes the orders that return the portfolio's current holdings in scope,
+// valued at current prices, to the user's target allocation
+func (s *PortfolioService) GetRebalancePlan(ctx context.Context, userID uuid.UUID, scope PortfolioScope, options RebalanceOptions) (*models.RebalancePlanResponse, error) {
+	if options.MinTradeValue < 0 {
+		return nil, fmt.Errorf("invalid rebalance options: minimum trade value must not be negative")
+	}
+	if options.Cash != nil && *options.Cash < 0 {
+		return nil, fmt.Errorf("invalid rebalance options: cash must not be negative")
+	}
+
+	targets, err := s.targetRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("invalid rebalance options: no target allocation has been set")
+	}
+	targetType := targets[0].TargetType
+
+	settings, err := s.GetSettings(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load portfolio settings: %w", err)
+	}
+
+	engine, err := s.costBasisEngine(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cost basis settings: %w", err)
+	}
+
+	transactions, err := s.scopedTransactions(userID, scope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions for rebalancing: %w", err)
+	}
+
+	now := time.Now()
+	fx := s.fxConverter(ctx, settings.BaseCurrency)
+
+	var holdings []RebalanceHolding
+	held := make(map[string]bool)
+	for _, holding := range s.getAllHoldings(ctx, engine, fx, transactions) {
+		// Short positions and options are managed by hand; the plan only buys and sells long holdings of shares
+		if holding.Short || holding.Multiplier != 1 {
+			continue
+		}
+		holdings = append(holdings, RebalanceHolding{
+			Symbol:      holding.Symbol,
+			Quantity:    holding.TotalQuantity,
+			Price:       holding.CurrentPrice,
+			Currency:    holding.Currency,
+			FXRate:      holding.FXRate,
+			MarketValue: holding.MarketValue,
+		})
+		held[holding.Symbol] = true
+	}
+
+	switch targetType {
+	case models.TargetTypeAssetClass:
+		symbols := make([]string, 0, len(holdings))
+		for _, holding := range holdings {
+			symbols = append(symbols, holding.Symbol)
+		}
+		metadata := s.symbolMetadata(symbols, transactions)
+		for i := range holdings {
+			holdings[i].AssetClass = symbolAssetClass(holdings[i].Symbol, metadata)
+		}
+	case models.TargetTypeSymbol:
+		// Targeted symbols not held yet are priced so they can be bought
+		for _, target := range targets {
+			if held[target.TargetKey] {
+				continue
+			}
+			priceData, err := s.priceManager.GetCurrentPrice(ctx, target.TargetKey)
+			if err != nil {
+				fmt.Printf("Warning: failed to get current price for %s: %v\n", target.TargetKey, err)
+				continue
+			}
+			currency := normalizeCurrency(priceData.Currency)
+			rate, err := fx.RateAt(currency, now)
+			if err != nil {
+				fmt.Printf("Warning: failed to convert %s into %s: %v\n", target.TargetKey, fx.BaseCurrency(), err)
+				continue
+			}
+			holdings = append(holdings, RebalanceHolding{
+				Symbol:   target.TargetKey,
+				Price:    priceData.CurrentPrice,
+				Currency: currency,
+				FXRate:   rate,
+			})
+		}
+	}
+
+	var cash float64
+	if options.Cash != nil {
+		cash = *options.Cash
+	} else {
+		_, cash, err = valueCashBalances(fx, transactions, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to value cash balances in %s: %w", fx.BaseCurrency(), err)
+		}
+		// Purchases funded by unrecorded deposits leave no cash to invest
+		cash = math.Max(cash, 0)
+	}
+
+	drifts, orders := PlanRebalance(targets, holdings, cash, options)
+
+	totalValue, cashAfter := cash, cash
+	for _, holding := range holdings {
+		totalValue += holding.MarketValue
+	}
+	for _, o := range orders {
+		if o.Action == types.TradeTypeSell {
+			cashAfter += o.EstimatedAmount
+		} else {
+			cashAfter -= o.EstimatedAmount
+		}
+	}
+
+	return &models.RebalancePlanResponse{
+		TargetType:    targetType,
+		Currency:      fx.BaseCurrency(),
+		TotalValue:    utils.RoundTo4(totalValue),
+		Cash:          utils.RoundTo4(cash),
+		CashAfter:     utils.RoundTo4(cashAfter),
+		NoSells:       options.NoSells,
+		MinTradeValue: options.MinTradeValue,
+		Drifts:        drifts,
+		Orders:        orders,
+		Timestamp:     now,
+	}, nil
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/constants"
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/utils"
+)
+
+// RiskMetrics are the risk figures of a daily return series. Rates are in %.
+type RiskMetrics struct {
+	Observations         int
+	AnnualizedReturn     float64
+	AnnualizedVolatility float64
+	SharpeRatio          float64
+	SortinoRatio         float64
+	MaxDrawdown          float64
+	// PeakIndex and TroughIndex locate the max drawdown in the input series; both are -1 without one
+	PeakIndex   int
+	TroughIndex int
+}
+
+// periodReturns turns cumulative returns (%) into the return of each period between consecutive points
+func periodReturns(cumulative []float64) []float64 {
+	returns := make([]float64, 0, len(cumulative))
+	for i := 1; i < len(cumulative); i++ {
+		previous := 1 + cumulative[i-1]/100
+		if previous <= 0 {
+			continue
+		}
+		returns = append(returns, (1+cumulative[i]/100)/previous-1)
+	}
+	return returns
+}
+
+// CalculateRiskMetrics computes annualized return and volatility, Sharpe and Sortino ratios and the
+// max drawdown of a series of cumulative time-weighted returns (%) sampled once per trading day.
+// riskFreeRate is the annual risk-free rate in %.
+func CalculateRiskMetrics(cumulative []float64, riskFreeRate float64) RiskMetrics {
+	metrics := RiskMetrics{PeakIndex: -1, TroughIndex: -1}
+
+	// Drawdowns are measured on the growth of one unit invested at the start
+	peak := 0
+	for i := range cumulative {
+		growth := 1 + cumulative[i]/100
+		if growth > 1+cumulative[peak]/100 {
+			peak = i
+			continue
+		}
+		if peakGrowth := 1 + cumulative[peak]/100; peakGrowth > 0 {
+			if drawdown := (growth/peakGrowth - 1) * 100; drawdown < metrics.MaxDrawdown {
+				metrics.MaxDrawdown = drawdown
+				metrics.PeakIndex = peak
+				metrics.TroughIndex = i
+			}
+		}
+	}
+
+	returns := periodReturns(cumulative)
+	metrics.Observations = len(returns)
+	if len(returns) == 0 {
+		return metrics
+	}
+
+	tradingDays := float64(constants.TradingDaysPerYear)
+	totalGrowth := 1 + cumulative[len(cumulative)-1]/100
+	if totalGrowth > 0 {
+		metrics.AnnualizedReturn = (math.Pow(totalGrowth, tradingDays/float64(len(returns))) - 1) * 100
+	}
+
+	// Excess returns over the daily equivalent of the risk-free rate
+	dailyRiskFree := math.Pow(1+riskFreeRate/100, 1/tradingDays) - 1
+	excess := make([]float64, len(returns))
+	downside := 0.0
+	for i, r := range returns {
+		excess[i] = r - dailyRiskFree
+		if excess[i] < 0 {
+			downside += excess[i] * excess[i]
+		}
+	}
+	meanExcess := utils.Mean(excess)
+	volatility := utils.StandardDeviation(returns)
+	downsideDeviation := math.Sqrt(downside / float64(len(returns)))
+
+	metrics.AnnualizedVolatility = volatility * math.Sqrt(tradingDays) * 100
+	if volatility > 0 {
+		metrics.SharpeRatio = meanExcess / volatility * math.Sqrt(tradingDays)
+	}
+	if downsideDeviation > 0 {
+		metrics.SortinoRatio = meanExcess / downsideDeviation * math.Sqrt(tradingDays)
+	}
+	return metrics
+}
+
+// CalculateBeta returns the beta and correlation of a portfolio's period returns against a
+// benchmark's, both given as cumulative returns (%) sampled at the same points
+func CalculateBeta(portfolio, benchmark []float64) (beta, correlation float64) {
+	portfolioReturns := periodReturns(portfolio)
+	benchmarkReturns := periodReturns(benchmark)
+	if len(portfolioReturns) == 0 || len(portfolioReturns) != len(benchmarkReturns) {
+		return 0, 0
+	}
+
+	covariance := utils.Covariance(portfolioReturns, benchmarkReturns)
+	portfolioDeviation := utils.StandardDeviation(portfolioReturns)
+	benchmarkDeviation := utils.StandardDeviation(benchmarkReturns)
+	if benchmarkDeviation > 0 {
+		beta = covariance / (benchmarkDeviation * benchmarkDeviation)
+	}
+	if portfolioDeviation > 0 && benchmarkDeviation > 0 {
+		correlation = covariance / (portfolioDeviation * benchmarkDeviation)
+	}
+	return beta, correlation
+}
+
+// tradingDayPoints keeps the daily time points that fall on weekdays, along with the first one,
+// so that weekends with carried-forward prices do not count as flat trading days
+func tradingDayPoints(timePoints []time.Time) []time.Time {
+	points := make([]time.Time, 0, len(timePoints))
+	for i, t := range timePoints {
+		if i == 0 || (t.Weekday() != time.Saturday && t.Weekday() != time.Sunday) {
+			points = append(points, t)
+		}
+	}
+	return points
+}
+
+// GetPortfolioRisk calculates the risk metrics of the portfolio's daily time-weighted returns over
+// a timeframe, with beta and correlation against benchmark. riskFreeRate is the annual rate in %.
+func (s *PortfolioService) GetPortfolioRisk(ctx context.Context, userID uuid.UUID, scope PortfolioScope, timeframe models.TimeFrame, benchmark string, riskFreeRate float64) (*models.PortfolioRiskResponse, error) {
+	endTime := time.Now()
+	startTime, err := s.calculateStartTime(endTime, timeframe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate start time: %w", err)
+	}
+
+	engine, fx, allTransactions, convertedTransactions, err := s.valuationInputs(ctx, userID, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	// For ALL timeframe, use first transaction date as start time
+	if timeframe == models.TimeFrameALL && len(allTransactions) > 0 {
+		startTime = allTransactions[0].TransactionDate
+	}
+
+	response := &models.PortfolioRiskResponse{
+		TimeFrame:    timeframe,
+		Currency:     fx.BaseCurrency(),
+		RiskFreeRate: riskFreeRate,
+	}
+	response.Period.StartDate = startTime
+	response.Period.EndDate = endTime
+	if len(allTransactions) == 0 {
+		return response, nil
+	}
+
+	// The same daily valuations the chart is drawn from, one point per trading day
+	timePoints := tradingDayPoints(s.generateTimePoints(startTime, endTime, models.GranularityDaily))
+	tracker := s.newPerformanceTracker(ctx, engine, fx, allTransactions, convertedTransactions, startTime, endTime)
+	if scope.IsAll() {
+		tracker.snapshots = s.snapshotValuations(ctx, userID, engine, fx, allTransactions, convertedTransactions, startTime, endTime)
+	}
+
+	returns, err := tracker.timeWeightedReturns(timePoints)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate time-weighted returns: %w", err)
+	}
+
+	metrics := CalculateRiskMetrics(returns, riskFreeRate)
+	response.Observations = metrics.Observations
+	response.AnnualizedReturn = utils.RoundTo4(metrics.AnnualizedReturn)
+	response.AnnualizedVolatility = utils.RoundTo4(metrics.AnnualizedVolatility)
+	response.SharpeRatio = utils.RoundTo4(metrics.SharpeRatio)
+	response.SortinoRatio = utils.RoundTo4(metrics.SortinoRatio)
+	response.MaxDrawdown = utils.RoundTo4(metrics.MaxDrawdown)
+	if metrics.PeakIndex >= 0 {
+		peak, trough := timePoints[metrics.PeakIndex], timePoints[metrics.TroughIndex]
+		response.DrawdownPeakDate = &peak
+		response.DrawdownTroughDate = &trough
+	}
+
+	// A benchmark without prices only leaves out beta and correlation
+	if benchmark != "" {
+		_, benchmarkReturns, err := tracker.benchmarkValues(benchmark, timePoints)
+		if err != nil {
+			fmt.Printf("Warning: failed to build benchmark series for %s: %v\n", benchmark, err)
+		} else {
+			beta, correlation := CalculateBeta(returns, benchmarkReturns)
+			response.Benchmark = &models.BenchmarkRisk{
+				Symbol:      benchmark,
+				Beta:        utils.RoundTo4(beta),
+				Correlation: utils.RoundTo4(correlation),
+			}
+		}
+	}
+
+	return response, nil
+}
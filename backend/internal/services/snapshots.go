@@ -0,0 +1,287 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/constants"
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/utils"
+)
+
+// snapshotDay returns the UTC day a time falls on, which is the date its snapshot is stored under
+func snapshotDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// snapshotKey returns the YYYY-MM-DD key of the snapshot covering t
+func snapshotKey(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+// StaleSnapshotsFrom returns the first day whose snapshot has to be (re)computed, given the
+// user's latest snapshot (nil when they have none) and their first transaction date.
+// Missing days are always computed; the provisional last days are recomputed once the
+// latest snapshot is older than the refresh interval. ok is false when nothing is stale.
+func StaleSnapshotsFrom(latest *models.PortfolioSnapshot, firstTransaction time.Time, currency string, now time.Time) (from time.Time, ok bool) {
+	today := snapshotDay(now)
+	from = snapshotDay(firstTransaction)
+
+	// Snapshots in another currency predate a base currency change and are all rebuilt
+	if latest != nil && latest.Currency == currency {
+		from = snapshotDay(latest.SnapshotDate).AddDate(0, 0, 1)
+		if now.Sub(latest.UpdatedAt) > constants.SnapshotRefreshInterval {
+			provisional := today.AddDate(0, 0, -constants.SnapshotProvisionalDays)
+			if provisional.Before(from) {
+				from = provisional
+			}
+		}
+	}
+
+	if from.After(today) {
+		return time.Time{}, false
+	}
+	return from, true
+}
+
+// snapshotLock returns the mutex serialising snapshot recomputation for a user
+func (s *PortfolioService) snapshotLock(userID uuid.UUID) *sync.Mutex {
+	lock, _ := s.snapshotLocks.LoadOrStore(userID, &sync.Mutex{})
+	return lock.(*sync.Mutex)
+}
+
+// valuationInputs loads what valuing a user's portfolio needs: their cost basis engine, a converter
+// into their base currency, and their transactions in scope sorted by date along with the converted copies
+func (s *PortfolioService) valuationInputs(ctx context.Context, userID uuid.UUID, scope PortfolioScope) (*CostBasisEngine, *FXConverter, []models.Transaction, []models.Transaction, error) {
+	settings, err := s.GetSettings(userID)
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("failed to load portfolio settings: %w", err)
+	}
+
+	engine, err := s.costBasisEngine(userID)
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("failed to load cost basis settings: %w", err)
+	}
+
+	transactions, err := s.scopedTransactions(userID, scope)
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+	transactions = sortTransactionsByDate(transactions)
+
+	fx := s.fxConverter(ctx, settings.BaseCurrency)
+	converted, err := fx.ConvertTransactions(transactions)
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("failed to convert transactions into %s: %w", fx.BaseCurrency(), err)
+	}
+
+	return engine, fx, transactions, converted, nil
+}
+
+// RecomputeSnapshots rebuilds a user's daily snapshots from the day of since through today.
+// A zero since rebuilds them all.
+func (s *PortfolioService) RecomputeSnapshots(ctx context.Context, userID uuid.UUID, since time.Time) error {
+	lock := s.snapshotLock(userID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	engine, fx, transactions, converted, err := s.valuationInputs(ctx, userID, PortfolioScope{})
+	if err != nil {
+		return err
+	}
+	return s.recomputeSnapshots(ctx, userID, engine, fx, transactions, converted, since)
+}
+
+// snapshotRecomputer recomputes the snapshots of the users its queue hands it
+type snapshotRecomputer struct {
+	s *PortfolioService
+}
+
+func (r snapshotRecomputer) OnTransactionsChanged(userID uuid.UUID, since time.Time) error {
+	return r.s.RecomputeSnapshots(context.Background(), userID, since)
+}
+
+// queueSnapshotRecompute has a user's snapshots from since on recomputed in the background
+func (s *PortfolioService) queueSnapshotRecompute(userID uuid.UUID, since time.Time) {
+	s.snapshotQueue.OnTransactionsChanged(userID, since)
+}
+
+// RunSnapshotRecomputes recomputes the snapshots queued after changes until ctx is done
+func (s *PortfolioService) RunSnapshotRecomputes(ctx context.Context) {
+	s.snapshotQueue.Run(ctx)
+}
+
+// RefreshSnapshots computes a user's missing snapshots and refreshes their provisional ones
+func (s *PortfolioService) RefreshSnapshots(ctx context.Context, userID uuid.UUID) error {
+	engine, fx, transactions, converted, err := s.valuationInputs(ctx, userID, PortfolioScope{})
+	if err != nil {
+		return err
+	}
+	return s.refreshSnapshots(ctx, userID, engine, fx, transactions, converted)
+}
+
+// refreshSnapshots is RefreshSnapshots over already loaded transactions, sorted by date
+func (s *PortfolioService) refreshSnapshots(ctx context.Context, userID uuid.UUID, engine *CostBasisEngine, fx *FXConverter, transactions, converted []models.Transaction) error {
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	lock := s.snapshotLock(userID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	latest, err := s.snapshotRepo.GetLatestByUserID(userID)
+	if err != nil {
+		return err
+	}
+
+	from, ok := StaleSnapshotsFrom(latest, transactions[0].TransactionDate, fx.BaseCurrency(), time.Now())
+	if !ok {
+		return nil
+	}
+	return s.recomputeSnapshots(ctx, userID, engine, fx, transactions, converted, from)
+}
+
+// recomputeSnapshots values the portfolio at the end of every day from since through today and
+// replaces the stored snapshots from since on. The caller holds the user's snapshot lock.
+func (s *PortfolioService) recomputeSnapshots(ctx context.Context, userID uuid.UUID, engine *CostBasisEngine, fx *FXConverter, transactions, converted []models.Transaction, since time.Time) error {
+	from := snapshotDay(since)
+	today := snapshotDay(time.Now())
+
+	// Drop the affected snapshots first, so a failed recompute leaves a gap that the next read
+	// fills in rather than snapshots that no longer match the transactions
+	if err := s.snapshotRepo.DeleteFromDate(userID, from); err != nil {
+		return err
+	}
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	start := from
+	if first := snapshotDay(transactions[0].TransactionDate); start.Before(first) {
+		start = first
+	}
+	if start.After(today) {
+		return nil
+	}
+
+	var days, endsOfDay []time.Time
+	for day := start; !day.After(today); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+		endsOfDay = append(endsOfDay, day.AddDate(0, 0, 1).Add(-time.Nanosecond))
+	}
+
+	tracker := s.newPerformanceTracker(ctx, engine, fx, transactions, converted, start, today)
+	if err := tracker.valueAll(endsOfDay); err != nil {
+		return fmt.Errorf("failed to value portfolio snapshots: %w", err)
+	}
+
+	snapshots := make([]models.PortfolioSnapshot, len(days))
+	for i, day := range days {
+		valuation, err := tracker.valuationAt(endsOfDay[i])
+		if err != nil {
+			return err
+		}
+
+		holdings := make(models.SnapshotHoldings, len(valuation.Holdings))
+		for j, holding := range valuation.Holdings {
+			holdings[j] = models.SnapshotHolding{
+				Symbol:      holding.Symbol,
+				Quantity:    utils.RoundTo10(holding.Quantity),
+				Price:       utils.RoundTo10(holding.Price),
+				MarketValue: utils.RoundTo4(holding.MarketValue),
+				CostBasis:   utils.RoundTo4(holding.CostBasis),
+			}
+		}
+
+		snapshots[i] = models.PortfolioSnapshot{
+			UserID:         userID,
+			SnapshotDate:   day,
+			Currency:       fx.BaseCurrency(),
+			MarketValue:    utils.RoundTo4(valuation.MarketValue),
+			CostBasis:      utils.RoundTo4(valuation.CostBasis),
+			CashBalance:    utils.RoundTo4(valuation.CashBalance),
+			FXGainLoss:     utils.RoundTo4(valuation.FXGainLoss),
+			DividendIncome: utils.RoundTo4(valuation.DividendIncome),
+			Holdings:       holdings,
+		}
+	}
+
+	return s.snapshotRepo.ReplaceFromDate(userID, from, snapshots)
+}
+
+// snapshotValuations brings a user's snapshots up to date and returns those between from and to
+// keyed by day. Failures are logged and leave the caller to value the portfolio itself, as do
+// snapshots still waiting to be recomputed after a change.
+func (s *PortfolioService) snapshotValuations(ctx context.Context, userID uuid.UUID, engine *CostBasisEngine, fx *FXConverter, transactions, converted []models.Transaction, from, to time.Time) map[string]portfolioValuation {
+	if s.snapshotQueue.Queued(userID) {
+		return nil
+	}
+	if err := s.refreshSnapshots(ctx, userID, engine, fx, sortTransactionsByDate(transactions), converted); err != nil {
+		fmt.Printf("Warning: failed to refresh portfolio snapshots for user %s: %v\n", userID, err)
+		return nil
+	}
+
+	snapshots, err := s.snapshotRepo.GetByUserIDAndDateRange(userID, snapshotDay(from), snapshotDay(to))
+	if err != nil {
+		fmt.Printf("Warning: failed to read portfolio snapshots for user %s: %v\n", userID, err)
+		return nil
+	}
+
+	valuations := make(map[string]portfolioValuation, len(snapshots))
+	for _, snapshot := range snapshots {
+		if snapshot.Currency != fx.BaseCurrency() {
+			continue
+		}
+		valuations[snapshotKey(snapshot.SnapshotDate)] = portfolioValuation{
+			MarketValue:    snapshot.MarketValue,
+			CostBasis:      snapshot.CostBasis,
+			FXGainLoss:     snapshot.FXGainLoss,
+			DividendIncome: snapshot.DividendIncome,
+			CashBalance:    snapshot.CashBalance,
+			Holdings:       snapshot.Holdings,
+		}
+	}
+	return valuations
+}
+
+// BackfillSnapshots brings the snapshots of every user with transactions up to date.
+// A failure for one user is logged and does not stop the others.
+func (s *PortfolioService) BackfillSnapshots(ctx context.Context) error {
+	userIDs, err := s.transactionRepo.GetUserIDs()
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := s.RefreshSnapshots(ctx, userID); err != nil {
+			fmt.Printf("Warning: failed to backfill portfolio snapshots for user %s: %v\n", userID, err)
+		}
+	}
+	return nil
+}
+
+// RunSnapshotBackfill backfills snapshots immediately and then every interval until ctx is done
+func (s *PortfolioService) RunSnapshotBackfill(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.BackfillSnapshots(ctx); err != nil && ctx.Err() == nil {
+			fmt.Printf("Warning: failed to backfill portfolio snapshots: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
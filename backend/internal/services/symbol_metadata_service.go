@@ -0,0 +1,53 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/repositories"
+	"github.com/transaction-tracker/backend/internal/utils"
+)
+
+// countryCodeRegex matches ISO 3166-1 alpha-2 country codes
+var countryCodeRegex = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// SymbolMetadataService handles the symbol metadata used to break portfolios down
+type SymbolMetadataService struct {
+	symbolMetadataRepo *repositories.SymbolMetadataRepository
+}
+
+// NewSymbolMetadataService creates a new symbol metadata service
+func NewSymbolMetadataService(symbolMetadataRepo *repositories.SymbolMetadataRepository) *SymbolMetadataService {
+	return &SymbolMetadataService{
+		symbolMetadataRepo: symbolMetadataRepo,
+	}
+}
+
+// ListSymbolMetadata retrieves the metadata of every symbol
+func (s *SymbolMetadataService) ListSymbolMetadata() ([]models.SymbolMetadata, error) {
+	return s.symbolMetadataRepo.GetAll()
+}
+
+// UpsertSymbolMetadata validates and records the metadata of a symbol, replacing any recorded before
+func (s *SymbolMetadataService) UpsertSymbolMetadata(metadata models.SymbolMetadata) (*models.SymbolMetadata, error) {
+	metadata.Symbol = strings.ToUpper(strings.TrimSpace(metadata.Symbol))
+	metadata.Name = strings.TrimSpace(metadata.Name)
+	metadata.Sector = strings.TrimSpace(metadata.Sector)
+	metadata.Industry = strings.TrimSpace(metadata.Industry)
+	metadata.Country = strings.ToUpper(strings.TrimSpace(metadata.Country))
+	metadata.AssetClass = strings.ToLower(strings.TrimSpace(metadata.AssetClass))
+
+	if !utils.SymbolRegex.MatchString(metadata.Symbol) {
+		return nil, fmt.Errorf("invalid symbol metadata: symbol %q is not valid", metadata.Symbol)
+	}
+	if metadata.Country != "" && !countryCodeRegex.MatchString(metadata.Country) {
+		return nil, fmt.Errorf("invalid symbol metadata: country must be a two-letter ISO country code")
+	}
+
+	if err := s.symbolMetadataRepo.Upsert(&metadata); err != nil {
+		return nil, err
+	}
+	return &metadata, nil
+}
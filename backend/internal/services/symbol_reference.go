@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/constants"
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/provider"
+	"github.com/transaction-tracker/backend/internal/utils"
+)
+
+// SymbolInfoLookup looks up the reference data of a symbol in the Price Service symbol master,
+// returning provider.ErrNotFound for symbols it does not know
+type SymbolInfoLookup interface {
+	GetSymbolInfo(ctx context.Context, symbol string) (*provider.SymbolInfo, error)
+}
+
+// SymbolReferenceService combines the symbol metadata users record with the symbol master, and
+// checks that the symbols of new and edited transactions exist
+type SymbolReferenceService struct {
+	recorded SymbolMetadataSource
+	lookup   SymbolInfoLookup
+	known    map[string]provider.SymbolInfo
+	mutex    sync.RWMutex
+}
+
+// NewSymbolReferenceService creates a new symbol reference service. Without a lookup only the
+// recorded metadata is used and transactions are not checked.
+func NewSymbolReferenceService(recorded SymbolMetadataSource, lookup SymbolInfoLookup) *SymbolReferenceService {
+	return &SymbolReferenceService{
+		recorded: recorded,
+		lookup:   lookup,
+		known:    make(map[string]provider.SymbolInfo),
+	}
+}
+
+// GetBySymbols returns the metadata of symbols, filling what users have not recorded from the
+// symbol master. Recorded fields take precedence, so users can correct the symbol master.
+func (s *SymbolReferenceService) GetBySymbols(symbols []string) ([]models.SymbolMetadata, error) {
+	recorded, err := s.recorded.GetBySymbols(symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := make(map[string]models.SymbolMetadata, len(symbols))
+	for _, m := range recorded {
+		metadata[m.Symbol] = m
+	}
+
+	for symbol, info := range s.symbolInfo(symbols) {
+		m, ok := metadata[symbol]
+		if !ok {
+			m = models.SymbolMetadata{Symbol: symbol}
+		}
+		metadata[symbol] = mergeSymbolInfo(m, info)
+	}
+
+	result := make([]models.SymbolMetadata, 0, len(metadata))
+	for _, m := range metadata {
+		result = append(result, m)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Symbol < result[j].Symbol })
+	return result, nil
+}
+
+// mergeSymbolInfo fills the fields of recorded metadata that are empty from the symbol master
+func mergeSymbolInfo(metadata models.SymbolMetadata, info provider.SymbolInfo) models.SymbolMetadata {
+	fill := func(field *string, value string) {
+		if *field == "" {
+			*field = value
+		}
+	}
+	fill(&metadata.Name, info.Name)
+	fill(&metadata.Sector, info.Sector)
+	fill(&metadata.Industry, info.Industry)
+	fill(&metadata.Country, info.Country)
+	fill(&metadata.AssetClass, info.AssetClass)
+	return metadata
+}
+
+// symbolInfo looks up the symbol master entries of symbols keyed by symbol, leaving out unknown
+// symbols and option contracts. Once the Price Service fails the remaining symbols are skipped,
+// so an outage costs one failed lookup rather than one per symbol.
+func (s *SymbolReferenceService) symbolInfo(symbols []string) map[string]provider.SymbolInfo {
+	found := make(map[string]provider.SymbolInfo, len(symbols))
+	if s.lookup == nil {
+		return found
+	}
+
+	for _, symbol := range symbols {
+		if utils.OptionSymbolRegex.MatchString(symbol) {
+			continue
+		}
+		info, err := s.getSymbolInfo(symbol)
+		if errors.Is(err, provider.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			fmt.Printf("Warning: failed to look up symbol info: %v\n", err)
+			break
+		}
+		found[symbol] = *info
+	}
+	return found
+}
+
+// getSymbolInfo looks up a symbol in the symbol master, remembering up to MaxKnownSymbols of the
+// symbols found
+func (s *SymbolReferenceService) getSymbolInfo(symbol string) (*provider.SymbolInfo, error) {
+	s.mutex.RLock()
+	info, ok := s.known[symbol]
+	s.mutex.RUnlock()
+	if ok {
+		return &info, nil
+	}
+
+	fetched, err := s.lookup.GetSymbolInfo(context.Background(), symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	if _, ok := s.known[symbol]; !ok && len(s.known) >= constants.MaxKnownSymbols {
+		// Make room by forgetting an arbitrary symbol; it is looked up again when next needed
+		for forgotten := range s.known {
+			delete(s.known, forgotten)
+			break
+		}
+	}
+	s.known[symbol] = *fetched
+	s.mutex.Unlock()
+	return fetched, nil
+}
+
+// ValidateTransactions checks that the symbols of a user's new or edited transactions are known,
+// either to the symbol master, under the symbol they are quoted by on their exchange, or through
+// metadata recorded for them. Transactions are let through when the Price Service cannot be
+// reached, so an outage does not block recording trades.
+func (s *SymbolReferenceService) ValidateTransactions(userID uuid.UUID, transactions []models.Transaction) error {
+	if s.lookup == nil {
+		return nil
+	}
+
+	// Symbols as recorded, keyed by the symbol they are quoted by, e.g. 2330 on TPE by 2330.TW
+	recordedAs := make(map[string]string)
+	var quoteSymbols, symbols []string
+	for _, tx := range transactions {
+		// Cash moves are recorded under their currency and options under their OCC symbol
+		if tx.TradeType.IsCash() || tx.TradeType.IsOption() {
+			continue
+		}
+		quoteSymbol := provider.QuoteSymbol(tx.Symbol, tx.Exchange)
+		if _, seen := recordedAs[quoteSymbol]; seen {
+			continue
+		}
+		recordedAs[quoteSymbol] = tx.Symbol
+		quoteSymbols = append(quoteSymbols, quoteSymbol)
+		symbols = append(symbols, tx.Symbol)
+		if quoteSymbol != tx.Symbol {
+			symbols = append(symbols, quoteSymbol)
+		}
+	}
+	if len(quoteSymbols) == 0 {
+		return nil
+	}
+
+	recorded, err := s.recorded.GetBySymbols(symbols)
+	if err != nil {
+		return fmt.Errorf("failed to get symbol metadata for validation: %w", err)
+	}
+	hasMetadata := make(map[string]bool, len(recorded))
+	for _, m := range recorded {
+		hasMetadata[m.Symbol] = true
+	}
+
+	for _, quoteSymbol := range quoteSymbols {
+		symbol := recordedAs[quoteSymbol]
+		if hasMetadata[symbol] || hasMetadata[quoteSymbol] {
+			continue
+		}
+		_, err := s.getSymbolInfo(quoteSymbol)
+		if errors.Is(err, provider.ErrNotFound) {
+			return fmt.Errorf("invalid symbol: %s is not a known symbol", symbol)
+		}
+		if err != nil {
+			fmt.Printf("Warning: failed to validate symbol %s: %v\n", symbol, err)
+			return nil
+		}
+	}
+	return nil
+}
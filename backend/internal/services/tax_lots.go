@@ -0,0 +1,277 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/types"
+	"github.com/transaction-tracker/backend/internal/utils"
+)
+
+// BuildTaxLedger replays a user's transactions into tax lots and the disposals that consumed them.
+// Every buy opens a lot; every sell records one disposal per lot it drew shares from. Short sales
+// open short lots in the same way, which buys to cover dispose of; their gains are always short term.
+// Option contracts bought or written to open are lots too, disposed of when closed, expired or assigned.
+// Lots are grouped under today's symbol and their remaining quantity is in post-split shares.
+// Amounts are recorded in the currency of the transactions, which callers convert beforehand.
+func BuildTaxLedger(userID uuid.UUID, engine *CostBasisEngine, transactions []models.Transaction) ([]models.TaxLot, []models.LotDisposal) {
+	transactionsBySymbol := engine.GroupBySymbol(transactions, time.Now())
+
+	symbols := make([]string, 0, len(transactionsBySymbol))
+	for symbol := range transactionsBySymbol {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	lots := []models.TaxLot{}
+	disposals := []models.LotDisposal{}
+
+	for _, symbol := range symbols {
+		symbolTransactions := sortTransactionsByDate(transactionsBySymbol[symbol])
+		result := engine.Calculate(symbolTransactions)
+		currency := holdingCurrency(symbolTransactions)
+
+		remaining := make(map[uuid.UUID]models.OpenLot, len(result.OpenLots))
+		for _, lot := range result.OpenLots {
+			remaining[lot.TransactionID] = lot
+		}
+
+		lotIDs := make(map[uuid.UUID]uuid.UUID)
+		for _, tx := range symbolTransactions {
+			short := tx.TradeType == types.TradeTypeSellShort || tx.TradeType == types.TradeTypeSellToOpen
+			if (tx.TradeType != types.TradeTypeBuy && tx.TradeType != types.TradeTypeBuyToOpen && !short) || tx.Quantity <= 0 {
+				continue
+			}
+
+			lot := models.TaxLot{
+				ID:               uuid.New(),
+				UserID:           userID,
+				BuyTransactionID: tx.TransactionID,
+				Symbol:           symbol,
+				Short:            short,
+				AcquiredAt:       tx.TransactionDate,
+				Quantity:         tx.Quantity,
+				CostBasis:        utils.RoundTo4(tx.NetAmount()),
+				Currency:         normalizeCurrency(tx.Currency),
+			}
+			if open, ok := remaining[tx.TransactionID]; ok {
+				lot.RemainingQuantity = utils.RoundTo10(math.Abs(open.Quantity))
+				lot.RemainingCostBasis = utils.RoundTo4(math.Abs(open.CostBasis()))
+			}
+
+			lotIDs[tx.TransactionID] = lot.ID
+			lots = append(lots, lot)
+		}
+
+		for _, closed := range result.ClosedLots {
+			openingTransactionID, holdingPeriod := closed.BuyTransactionID, models.HoldingPeriodFor(closed.AcquiredAt, closed.DisposedAt)
+			if closed.Short {
+				openingTransactionID, holdingPeriod = closed.SellTransactionID, models.HoldingPeriodShortTerm
+			}
+			disposals = append(disposals, models.LotDisposal{
+				UserID:            userID,
+				TaxLotID:          lotIDs[openingTransactionID],
+				BuyTransactionID:  closed.BuyTransactionID,
+				SellTransactionID: closed.SellTransactionID,
+				Symbol:            closed.Symbol,
+				Short:             closed.Short,
+				AcquiredAt:        closed.AcquiredAt,
+				DisposedAt:        closed.DisposedAt,
+				Quantity:          utils.RoundTo10(closed.Quantity),
+				Proceeds:          utils.RoundTo4(closed.Proceeds),
+				CostBasis:         utils.RoundTo4(closed.CostBasis),
+				GainLoss:          utils.RoundTo4(closed.GainLoss()),
+				HoldingPeriod:     holdingPeriod,
+				HoldingDays:       int(closed.DisposedAt.Sub(closed.AcquiredAt).Hours() / 24),
+				Currency:          currency,
+			})
+		}
+	}
+
+	return lots, disposals
+}
+
+// SummarizeRealizedGains totals disposals into short-term, long-term and overall figures
+func SummarizeRealizedGains(year int, currency string, method models.CostBasisMethod, disposals []models.LotDisposal) *models.RealizedGainsResponse {
+	response := &models.RealizedGainsResponse{
+		Year:            year,
+		Currency:        currency,
+		CostBasisMethod: method,
+		Disposals:       disposals,
+	}
+	if response.Disposals == nil {
+		response.Disposals = []models.LotDisposal{}
+	}
+
+	add := func(totals *models.RealizedGainsTotals, disposal models.LotDisposal) {
+		totals.Proceeds += disposal.Proceeds
+		totals.CostBasis += disposal.CostBasis
+		totals.GainLoss += disposal.GainLoss
+		totals.Count++
+	}
+
+	for _, disposal := range disposals {
+		if disposal.HoldingPeriod == models.HoldingPeriodLongTerm {
+			add(&response.LongTerm, disposal)
+		} else {
+			add(&response.ShortTerm, disposal)
+		}
+		add(&response.Total, disposal)
+	}
+
+	for _, totals := range []*models.RealizedGainsTotals{&response.ShortTerm, &response.LongTerm, &response.Total} {
+		totals.Proceeds = utils.RoundTo4(totals.Proceeds)
+		totals.CostBasis = utils.RoundTo4(totals.CostBasis)
+		totals.GainLoss = utils.RoundTo4(totals.GainLoss)
+	}
+
+	return response
+}
+
+// taxLedger builds the tax lot ledger of a user's transactions in scope under their cost basis
+// method. Proceeds and costs are converted into the base currency at the rate of each trade date.
+func (s *PortfolioService) taxLedger(userID uuid.UUID, scope PortfolioScope) ([]models.TaxLot, []models.LotDisposal, error) {
+	transactions, err := s.scopedTransactions(userID, scope)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get transactions for tax lots: %w", err)
+	}
+
+	settings, err := s.GetSettings(userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load portfolio settings: %w", err)
+	}
+
+	engine, err := s.costBasisEngine(userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load cost basis settings: %w", err)
+	}
+
+	converted, err := s.fxConverter(context.Background(), settings.BaseCurrency).ConvertTransactions(transactions)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to convert transactions into %s: %w", settings.BaseCurrency, err)
+	}
+
+	lots, disposals := BuildTaxLedger(userID, engine, converted)
+	return lots, disposals, nil
+}
+
+// RebuildTaxLots recomputes a user's tax lot ledger under their cost basis method and persists
+// the part of it from since on. The whole ledger is replayed, since lots acquired earlier may be
+// consumed from since on, but lots and disposals dated before since keep their persisted rows.
+// A zero since, or a persisted ledger that no longer matches before since, replaces it all.
+func (s *PortfolioService) RebuildTaxLots(userID uuid.UUID, since time.Time) error {
+	lots, disposals, err := s.taxLedger(userID, PortfolioScope{})
+	if err != nil {
+		return err
+	}
+	if since.IsZero() {
+		return s.taxLotRepo.ReplaceForUser(userID, lots, disposals)
+	}
+
+	persisted, err := s.taxLotRepo.GetByUserID(userID)
+	if err != nil {
+		return err
+	}
+	if !MatchPersistedTaxLots(lots, disposals, persisted, since) {
+		return s.taxLotRepo.ReplaceForUser(userID, lots, disposals)
+	}
+	return s.taxLotRepo.ReplaceForUserSince(userID, since, lots, disposals)
+}
+
+// MatchPersistedTaxLots gives the lots of a rebuilt ledger acquired before since the IDs of the
+// persisted lots of the same transactions, and points the ledger's disposals at them. It reports
+// false, leaving the ledger as is, when the lots acquired before since differ from the persisted
+// ones, e.g. after a rename or a change of cost basis method, so the ledger must be replaced whole.
+func MatchPersistedTaxLots(lots []models.TaxLot, disposals []models.LotDisposal, persisted []models.TaxLot, since time.Time) bool {
+	persistedByTransaction := make(map[uuid.UUID]models.TaxLot)
+	for _, lot := range persisted {
+		if lot.AcquiredAt.Before(since) {
+			persistedByTransaction[lot.BuyTransactionID] = lot
+		}
+	}
+
+	lotIDs := make(map[uuid.UUID]uuid.UUID)
+	for _, lot := range lots {
+		if !lot.AcquiredAt.Before(since) {
+			continue
+		}
+		match, ok := persistedByTransaction[lot.BuyTransactionID]
+		if !ok || match.Symbol != lot.Symbol || match.Short != lot.Short ||
+			math.Abs(match.Quantity-lot.Quantity) > quantityEpsilon || math.Abs(match.CostBasis-lot.CostBasis) > 0.0001 {
+			return false
+		}
+		lotIDs[lot.ID] = match.ID
+	}
+	if len(lotIDs) != len(persistedByTransaction) {
+		return false
+	}
+
+	for i := range lots {
+		if id, ok := lotIDs[lots[i].ID]; ok {
+			lots[i].ID = id
+		}
+	}
+	for i := range disposals {
+		if id, ok := lotIDs[disposals[i].TaxLotID]; ok {
+			disposals[i].TaxLotID = id
+		}
+	}
+	return true
+}
+
+// OnTransactionsChanged keeps the persisted ledgers in sync after a user's transactions change.
+// Lots, disposals and snapshots before since are unaffected, so only those from since on are
+// rebuilt. Tax lots are rebuilt right away; snapshots, which need prices, in the background.
+func (s *PortfolioService) OnTransactionsChanged(userID uuid.UUID, since time.Time) error {
+	s.queueSnapshotRecompute(userID, since)
+	return s.RebuildTaxLots(userID, since)
+}
+
+// GetRealizedGains retrieves the disposals of a tax year with short-term and long-term totals
+func (s *PortfolioService) GetRealizedGains(userID uuid.UUID, scope PortfolioScope, year int) (*models.RealizedGainsResponse, error) {
+	settings, err := s.GetSettings(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cost basis settings: %w", err)
+	}
+
+	startDate := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	endDate := startDate.AddDate(1, 0, 0)
+
+	// The persisted ledger covers all of the user's transactions; part of them is replayed on read
+	if !scope.IsAll() {
+		_, disposals, err := s.taxLedger(userID, scope)
+		if err != nil {
+			return nil, err
+		}
+		var inYear []models.LotDisposal
+		for _, disposal := range disposals {
+			if !disposal.DisposedAt.Before(startDate) && disposal.DisposedAt.Before(endDate) {
+				inYear = append(inYear, disposal)
+			}
+		}
+		return SummarizeRealizedGains(year, settings.BaseCurrency, settings.CostBasisMethod, inYear), nil
+	}
+
+	// Ledgers are built on write; users whose transactions predate the ledger get it built on first read
+	count, err := s.taxLotRepo.CountByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		if err := s.RebuildTaxLots(userID, time.Time{}); err != nil {
+			return nil, err
+		}
+	}
+
+	disposals, err := s.taxLotRepo.GetDisposalsByUserIDAndDateRange(userID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return SummarizeRealizedGains(year, settings.BaseCurrency, settings.CostBasisMethod, disposals), nil
+}
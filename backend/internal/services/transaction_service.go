@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/transaction-tracker/backend/internal/models"
 	"github.com/transaction-tracker/backend/internal/repositories"
+	"github.com/transaction-tracker/backend/internal/types"
 )
 
 // TransactionFilter represents filters for transaction queries
@@ -17,6 +18,7 @@ type TransactionFilter struct {
 	Exchanges      []string // Support multiple exchanges
 	Brokers        []string // Support multiple brokers
 	Currencies     []string // Support multiple currencies
+	AccountIDs     []uuid.UUID
 	StartDate      *time.Time
 	EndDate        *time.Time
 	MinAmount      *float64
@@ -27,27 +29,138 @@ type TransactionFilter struct {
 	OrderDirection string
 }
 
+// TransactionChangeListener is notified after a user's transactions are created, updated or deleted.
+// since is the earliest transaction date affected by the change.
+type TransactionChangeListener interface {
+	OnTransactionsChanged(userID uuid.UUID, since time.Time) error
+}
+
+// TransactionValidator checks a user's new or edited transactions against the rest of their
+// history before they are written
+type TransactionValidator interface {
+	ValidateTransactions(userID uuid.UUID, transactions []models.Transaction) error
+}
+
+// TransactionDeletionValidator is a TransactionValidator that also checks deleting some of a
+// user's transactions leaves the rest of their history consistent
+type TransactionDeletionValidator interface {
+	ValidateDeletion(userID uuid.UUID, transactionIDs []uuid.UUID) error
+}
+
 // TransactionService handles transaction-related business logic
 type TransactionService struct {
 	transactionRepo *repositories.TransactionRepository
+	accountRepo     *repositories.AccountRepository
+	portfolioRepo   *repositories.PortfolioRepository
+	listeners       []TransactionChangeListener
+	validators      []TransactionValidator
 }
 
 // NewTransactionService creates a new transaction service
-func NewTransactionService(transactionRepo *repositories.TransactionRepository) *TransactionService {
+func NewTransactionService(transactionRepo *repositories.TransactionRepository, accountRepo *repositories.AccountRepository, portfolioRepo *repositories.PortfolioRepository) *TransactionService {
 	return &TransactionService{
 		transactionRepo: transactionRepo,
+		accountRepo:     accountRepo,
+		portfolioRepo:   portfolioRepo,
+	}
+}
+
+// AddChangeListener registers a listener for transaction changes
+func (s *TransactionService) AddChangeListener(listener TransactionChangeListener) {
+	s.listeners = append(s.listeners, listener)
+}
+
+// AddValidator registers a validator for new and edited transactions; validators that implement
+// TransactionDeletionValidator also check deletions
+func (s *TransactionService) AddValidator(validator TransactionValidator) {
+	s.validators = append(s.validators, validator)
+}
+
+// validate runs the registered validators, stopping at the first that rejects the transactions
+func (s *TransactionService) validate(userID uuid.UUID, transactions []models.Transaction) error {
+	for _, validator := range s.validators {
+		if err := validator.ValidateTransactions(userID, transactions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateDeletion runs the registered validators that check deletions, stopping at the first
+// that rejects it
+func (s *TransactionService) validateDeletion(userID uuid.UUID, transactionIDs []uuid.UUID) error {
+	for _, validator := range s.validators {
+		if deletionValidator, ok := validator.(TransactionDeletionValidator); ok {
+			if err := deletionValidator.ValidateDeletion(userID, transactionIDs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// notifyChange informs listeners about a change; failures are logged so the write itself still succeeds
+func (s *TransactionService) notifyChange(userID uuid.UUID, since time.Time) {
+	for _, listener := range s.listeners {
+		if err := listener.OnTransactionsChanged(userID, since); err != nil {
+			fmt.Printf("Warning: failed to process transaction change for user %s: %v\n", userID, err)
+		}
 	}
 }
 
+// earliestTransactionDate returns the earliest trade date among transactions
+func earliestTransactionDate(transactions []models.Transaction) time.Time {
+	var earliest time.Time
+	for _, tx := range transactions {
+		if earliest.IsZero() || tx.TransactionDate.Before(earliest) {
+			earliest = tx.TransactionDate
+		}
+	}
+	return earliest
+}
+
+// linkAccount checks that the transaction's account and portfolio belong to the user and, when the
+// transaction names no broker, records it under the account's broker
+func (s *TransactionService) linkAccount(userID uuid.UUID, transaction *models.Transaction) error {
+	if transaction.PortfolioID != nil {
+		if _, err := s.portfolioRepo.GetByIDAndUserID(*transaction.PortfolioID, userID); err != nil {
+			return fmt.Errorf("invalid portfolio: portfolio %s not found", *transaction.PortfolioID)
+		}
+	}
+	if transaction.AccountID == nil {
+		return nil
+	}
+	account, err := s.accountRepo.GetByIDAndUserID(*transaction.AccountID, userID)
+	if err != nil {
+		return fmt.Errorf("invalid account: account %s not found", *transaction.AccountID)
+	}
+	if transaction.Broker == "" {
+		transaction.Broker = account.Broker
+	}
+	return nil
+}
+
 // CreateTransactions creates multiple transactions in a batch (business logic)
 func (s *TransactionService) CreateTransactions(userID uuid.UUID, transactions []models.Transaction) ([]models.Transaction, error) {
 	// Set user ID for each transaction (business logic)
 	for i := range transactions {
 		transactions[i].UserID = userID
+		if err := s.linkAccount(userID, &transactions[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.validate(userID, transactions); err != nil {
+		return nil, err
 	}
 
 	// Delegate to repository for database operations
-	return s.transactionRepo.CreateMany(transactions)
+	created, err := s.transactionRepo.CreateMany(transactions)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyChange(userID, earliestTransactionDate(created))
+	return created, nil
 }
 
 // GetTransactionsWithFilter retrieves transactions with advanced filtering (business logic method)
@@ -59,6 +172,7 @@ func (s *TransactionService) GetTransactionsWithFilter(filter TransactionFilter)
 		filter.Exchanges,
 		filter.Brokers,
 		filter.Currencies,
+		filter.AccountIDs,
 		filter.StartDate,
 		filter.EndDate,
 		filter.MinAmount,
@@ -79,6 +193,7 @@ func (s *TransactionService) CountTransactions(filter TransactionFilter) (int64,
 		filter.Exchanges,
 		filter.Brokers,
 		filter.Currencies,
+		filter.AccountIDs,
 		filter.StartDate,
 		filter.EndDate,
 		filter.MinAmount,
@@ -87,7 +202,7 @@ func (s *TransactionService) CountTransactions(filter TransactionFilter) (int64,
 }
 
 // UpdateTransaction updates a transaction by ID for a specific user
-func (s *TransactionService) UpdateTransaction(userID uuid.UUID, transactionID uuid.UUID, symbol, exchange, broker, currency, tradeDate string, tradeType string, quantity, price, amount float64, userNotes string) (*models.Transaction, error) {
+func (s *TransactionService) UpdateTransaction(userID uuid.UUID, transactionID uuid.UUID, accountID, portfolioID *uuid.UUID, symbol, exchange, broker, currency, tradeDate string, tradeType string, quantity, price, amount float64, costs models.TransactionCosts, userNotes string) (*models.Transaction, error) {
 	// Get transaction and check ownership
 	tx, err := s.transactionRepo.GetByID(transactionID)
 	if err != nil {
@@ -97,17 +212,41 @@ func (s *TransactionService) UpdateTransaction(userID uuid.UUID, transactionID u
 		return nil, fmt.Errorf("forbidden")
 	}
 
+	linked := models.Transaction{AccountID: accountID, PortfolioID: portfolioID, Broker: broker}
+	if err := s.linkAccount(userID, &linked); err != nil {
+		return nil, err
+	}
+
+	// Check the edited transaction against the user's positions before writing it
+	edited := *tx
+	edited.AccountID, edited.PortfolioID, edited.Broker = linked.AccountID, linked.PortfolioID, linked.Broker
+	edited.Symbol, edited.Exchange, edited.Currency = symbol, exchange, currency
+	edited.TradeType, edited.Quantity, edited.Price, edited.Amount = types.TradeType(tradeType), quantity, price, amount
+	edited.TransactionCosts, edited.UserNotes = costs, userNotes
+	if date, err := time.Parse("2006-01-02", tradeDate); err == nil {
+		edited.TransactionDate = date
+	}
+	if err := s.validate(userID, []models.Transaction{edited}); err != nil {
+		return nil, err
+	}
+
 	// Prepare updates
 	updates := map[string]interface{}{
+		"account_id":       linked.AccountID,
+		"portfolio_id":     linked.PortfolioID,
 		"symbol":           symbol,
 		"exchange":         exchange,
-		"broker":           broker,
+		"broker":           linked.Broker,
 		"currency":         currency,
 		"transaction_date": tradeDate,
 		"trade_type":       tradeType,
 		"quantity":         quantity,
 		"price":            price,
 		"amount":           amount,
+		"commission":       costs.Commission,
+		"fee":              costs.Fee,
+		"tax":              costs.Tax,
+		"withholding_tax":  costs.WithholdingTax,
 		"user_notes":       userNotes,
 	}
 
@@ -117,7 +256,13 @@ func (s *TransactionService) UpdateTransaction(userID uuid.UUID, transactionID u
 	}
 
 	// Return updated transaction
-	return s.transactionRepo.GetByID(transactionID)
+	updated, err := s.transactionRepo.GetByID(transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyChange(userID, earliestTransactionDate([]models.Transaction{*tx, *updated}))
+	return updated, nil
 }
 
 // DeleteTransaction deletes a transaction by ID for a specific user
@@ -131,12 +276,47 @@ func (s *TransactionService) DeleteTransaction(userID uuid.UUID, transactionID u
 		return fmt.Errorf("forbidden")
 	}
 
+	if err := s.validateDeletion(userID, []uuid.UUID{transactionID}); err != nil {
+		return err
+	}
+
 	// Delete the transaction
-	return s.transactionRepo.DeleteByIDAndUserID(transactionID, userID)
+	if err := s.transactionRepo.DeleteByIDAndUserID(transactionID, userID); err != nil {
+		return err
+	}
+
+	s.notifyChange(userID, tx.TransactionDate)
+	return nil
 }
 
 // DeleteTransactions deletes multiple transactions by IDs for a specific user
 func (s *TransactionService) DeleteTransactions(userID uuid.UUID, transactionIDs []uuid.UUID) ([]uuid.UUID, error) {
+	// Collect the affected dates before the rows disappear
+	var affected []models.Transaction
+	for _, id := range transactionIDs {
+		if tx, err := s.transactionRepo.GetByIDAndUserID(id, userID); err == nil {
+			affected = append(affected, *tx)
+		}
+	}
+
+	affectedIDs := make([]uuid.UUID, len(affected))
+	for i, tx := range affected {
+		affectedIDs[i] = tx.TransactionID
+	}
+	if len(affectedIDs) > 0 {
+		if err := s.validateDeletion(userID, affectedIDs); err != nil {
+			return nil, err
+		}
+	}
+
 	// Use repository method that handles batch deletion with ownership checks
-	return s.transactionRepo.DeleteByIDsAndUserID(transactionIDs, userID)
+	deletedIDs, err := s.transactionRepo.DeleteByIDsAndUserID(transactionIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(deletedIDs) > 0 {
+		s.notifyChange(userID, earliestTransactionDate(affected))
+	}
+	return deletedIDs, nil
 }
@@ -11,8 +11,81 @@ const (
 	TradeTypeBuy      TradeType = "Buy"
 	TradeTypeSell     TradeType = "Sell"
 	TradeTypeDividend TradeType = "Dividends"
+
+	// Short sales open a short position, which buys to cover close
+	TradeTypeSellShort  TradeType = "Sell Short"
+	TradeTypeBuyToCover TradeType = "Buy to Cover"
+
+	// Option trades open and close positions in option contracts, counted in contracts.
+	// Expiration and assignment close them without a trade: an expiration at no value,
+	// an assignment by delivering the underlying shares at the strike.
+	TradeTypeBuyToOpen   TradeType = "Buy to Open"
+	TradeTypeSellToOpen  TradeType = "Sell to Open"
+	TradeTypeBuyToClose  TradeType = "Buy to Close"
+	TradeTypeSellToClose TradeType = "Sell to Close"
+	TradeTypeExpiration  TradeType = "Expiration"
+	TradeTypeAssignment  TradeType = "Assignment"
+
+	// Cash transactions move cash in or out of an account without trading a security
+	TradeTypeDeposit    TradeType = "Deposit"
+	TradeTypeWithdrawal TradeType = "Withdrawal"
+	TradeTypeInterest   TradeType = "Interest"
+	TradeTypeFee        TradeType = "Fee"
 )
 
+// CashTradeTypes returns the trade types that only move cash
+func CashTradeTypes() []TradeType {
+	return []TradeType{TradeTypeDeposit, TradeTypeWithdrawal, TradeTypeInterest, TradeTypeFee}
+}
+
+// IsCash reports whether the trade type only moves cash, without trading a security
+func (t TradeType) IsCash() bool {
+	switch t {
+	case TradeTypeDeposit, TradeTypeWithdrawal, TradeTypeInterest, TradeTypeFee:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsTrade reports whether the trade type buys or sells shares or option contracts
+func (t TradeType) IsTrade() bool {
+	switch t {
+	case TradeTypeBuy, TradeTypeSell, TradeTypeSellShort, TradeTypeBuyToCover,
+		TradeTypeBuyToOpen, TradeTypeSellToOpen, TradeTypeBuyToClose, TradeTypeSellToClose:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsOptionEvent reports whether the trade type closes option contracts without a trade
+func (t TradeType) IsOptionEvent() bool {
+	return t == TradeTypeExpiration || t == TradeTypeAssignment
+}
+
+// IsOption reports whether the trade type applies to option contracts rather than shares
+func (t TradeType) IsOption() bool {
+	switch t {
+	case TradeTypeBuyToOpen, TradeTypeSellToOpen, TradeTypeBuyToClose, TradeTypeSellToClose:
+		return true
+	default:
+		return t.IsOptionEvent()
+	}
+}
+
+// IsOutflow reports whether the trade type takes cash out of the account. An assignment hands
+// the premium received for the contracts over to the underlying shares delivered.
+func (t TradeType) IsOutflow() bool {
+	switch t {
+	case TradeTypeBuy, TradeTypeBuyToCover, TradeTypeBuyToOpen, TradeTypeBuyToClose, TradeTypeAssignment,
+		TradeTypeWithdrawal, TradeTypeFee:
+		return true
+	default:
+		return false
+	}
+}
+
 // ExtractResponseData represents the data part of extract response
 type ExtractResponseData struct {
 	Transactions     []TransactionData `json:"transactions"`
@@ -35,12 +108,19 @@ type TransactionData struct {
 	TradeType       TradeType `json:"trade_type"`       // Maps to Transaction.Type
 	Quantity        float64   `json:"quantity"`         // Maps to Transaction.Quantity
 	Price           float64   `json:"price"`            // Maps to Transaction.Price
-	Amount          float64   `json:"amount"`           // Maps to Transaction.Amount
+	Amount          float64   `json:"amount"`           // Maps to Transaction.Amount (gross, before costs)
+	Commission      float64   `json:"commission"`       // Maps to Transaction.Commission
+	Fee             float64   `json:"fee"`              // Maps to Transaction.Fee
+	Tax             float64   `json:"tax"`              // Maps to Transaction.Tax
+	WithholdingTax  float64   `json:"withholding_tax"`  // Maps to Transaction.WithholdingTax
 	Currency        string    `json:"currency"`         // Maps to Transaction.Currency
 	Broker          string    `json:"broker"`           // Maps to Transaction.Broker
+	AccountID       string    `json:"account_id"`       // Maps to Transaction.AccountID (empty when none)
+	PortfolioID     string    `json:"portfolio_id"`     // Maps to Transaction.PortfolioID (empty when none)
 	TransactionDate string    `json:"transaction_date"` // Maps to Transaction.TransactionDate (as string for JSON)
 	UserNotes       string    `json:"user_notes"`       // Maps to Transaction.UserNotes
 	Exchange        string    `json:"exchange"`         // Maps to Transaction.Exchange
+	SymbolLabel     string    `json:"symbol_label"`     // Name of the security as printed, not stored
 }
 
 // FileInput represents an image file for processing
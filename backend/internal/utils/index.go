@@ -13,6 +13,30 @@ func TradeTypeFromString(s string) (types.TradeType, bool) {
 		return types.TradeTypeSell, true
 	case "Dividends":
 		return types.TradeTypeDividend, true
+	case "Sell Short":
+		return types.TradeTypeSellShort, true
+	case "Buy to Cover":
+		return types.TradeTypeBuyToCover, true
+	case "Buy to Open":
+		return types.TradeTypeBuyToOpen, true
+	case "Sell to Open":
+		return types.TradeTypeSellToOpen, true
+	case "Buy to Close":
+		return types.TradeTypeBuyToClose, true
+	case "Sell to Close":
+		return types.TradeTypeSellToClose, true
+	case "Expiration":
+		return types.TradeTypeExpiration, true
+	case "Assignment":
+		return types.TradeTypeAssignment, true
+	case "Deposit":
+		return types.TradeTypeDeposit, true
+	case "Withdrawal":
+		return types.TradeTypeWithdrawal, true
+	case "Interest":
+		return types.TradeTypeInterest, true
+	case "Fee":
+		return types.TradeTypeFee, true
 	default:
 		return "", false
 	}
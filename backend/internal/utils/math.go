@@ -66,6 +66,32 @@ func StandardDeviation(values []float64) float64 {
 	return math.Sqrt(variance)
 }
 
+// Mean calculates the arithmetic mean of a slice of float64.
+func Mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	return sum / float64(len(values))
+}
+
+// Covariance calculates the covariance of two equally long slices of float64.
+func Covariance(x, y []float64) float64 {
+	if len(x) == 0 || len(x) != len(y) {
+		return 0
+	}
+	meanX := Mean(x)
+	meanY := Mean(y)
+	sum := 0.0
+	for i := range x {
+		sum += (x[i] - meanX) * (y[i] - meanY)
+	}
+	return sum / float64(len(x))
+}
+
 // Abs returns the absolute value of x
 func Abs(x float64) float64 {
 	if x < 0 {
@@ -92,3 +118,9 @@ func ParseUint(s string, defaultValue uint) (uint, error) {
 func RoundTo4(val float64) float64 {
 	return math.Round(val*1e4) / 1e4
 }
+
+// RoundTo10 rounds a float64 to 10 decimal places, the precision quantities and prices are stored
+// with so that fractions of a coin and prices of coins worth a fraction of a cent are kept
+func RoundTo10(val float64) float64 {
+	return math.Round(val*1e10) / 1e10
+}
@@ -3,6 +3,14 @@ package utils
 import "regexp"
 
 var (
-	SymbolRegex   = regexp.MustCompile(`^[A-Z0-9]{1,8}(\.[A-Z0-9]{1,2})?$`)
+	// SymbolRegex matches exchange-listed symbols with an optional exchange suffix (e.g. BRK.B)
+	// and crypto pairs (e.g. BTC-USD)
+	SymbolRegex   = regexp.MustCompile(`^([A-Z0-9]{1,8}(\.[A-Z0-9]{1,2})?|[A-Z0-9]{2,10}-[A-Z]{3,4})$`)
 	CurrencyRegex = regexp.MustCompile(`^[A-Z]{3}$`)
+	// OptionSymbolRegex matches OCC option symbols without padding: root, expiry as YYMMDD,
+	// C or P, and the strike times 1000 in eight digits, e.g. AAPL240119C00150000
+	OptionSymbolRegex = regexp.MustCompile(`^[A-Z0-9]{1,6}\d{6}[CP]\d{8}$`)
+	// CryptoSymbolRegex matches crypto pairs of a coin and the currency it is quoted in, e.g. BTC-USD.
+	// Crypto trades around the clock, so these symbols are priced every day of the week.
+	CryptoSymbolRegex = regexp.MustCompile(`^[A-Z0-9]{2,10}-[A-Z]{3,4}$`)
 )
@@ -1,10 +1,14 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/transaction-tracker/backend/api/routes"
 	"github.com/transaction-tracker/backend/config"
@@ -32,21 +36,32 @@ func main() {
 	}()
 
 	// Initialize router
-	router := routes.SetupRouter(cfg)
+	router, handlersProvider := routes.SetupRouter(cfg)
 
-	// Setup graceful shutdown
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	// Setup graceful shutdown; background jobs stop with the server
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go handlersProvider.RunBackgroundJobs(ctx)
+
+	server := &http.Server{
+		Addr:    cfg.ServerAddress,
+		Handler: router,
+	}
 
 	go func() {
-		<-c
+		<-ctx.Done()
 		log.Println("Shutting down gracefully...")
-		os.Exit(0)
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Error shutting down server: %v", err)
+		}
 	}()
 
 	// Start server
 	log.Printf("Server starting on %s", cfg.ServerAddress)
-	if err := router.Run(cfg.ServerAddress); err != nil {
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }
@@ -0,0 +1,21 @@
+-- Cost basis method per user and specific lot selections for sales
+
+ALTER TABLE users ADD COLUMN cost_basis_method VARCHAR(20) NOT NULL DEFAULT 'average';
+
+-- Lot selections table (UUID PK, FK to users and transactions, VARCHAR(36))
+CREATE TABLE IF NOT EXISTS lot_selections (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    sell_transaction_id VARCHAR(36) NOT NULL,
+    buy_transaction_id VARCHAR(36) NOT NULL,
+    quantity DECIMAL(15,4) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    INDEX idx_lot_selections_user_id (user_id),
+    INDEX idx_lot_selections_sell_transaction_id (sell_transaction_id),
+    INDEX idx_lot_selections_deleted_at (deleted_at),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON UPDATE CASCADE ON DELETE RESTRICT,
+    FOREIGN KEY (sell_transaction_id) REFERENCES transactions(transaction_id) ON UPDATE CASCADE ON DELETE CASCADE,
+    FOREIGN KEY (buy_transaction_id) REFERENCES transactions(transaction_id) ON UPDATE CASCADE ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,52 @@
+-- Tax lot ledger: one lot per buy, one disposal per lot consumed by a sell
+
+-- Tax lots table (UUID PK, FK to users and transactions, VARCHAR(36))
+CREATE TABLE IF NOT EXISTS tax_lots (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    buy_transaction_id VARCHAR(36) NOT NULL,
+    symbol VARCHAR(20) NOT NULL,
+    acquired_at TIMESTAMP NOT NULL,
+    quantity DECIMAL(15,4) NOT NULL,
+    cost_basis DECIMAL(15,4) NOT NULL,
+    remaining_quantity DECIMAL(15,4) NOT NULL,
+    remaining_cost_basis DECIMAL(15,4) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    INDEX idx_tax_lots_user_id (user_id),
+    INDEX idx_tax_lots_buy_transaction_id (buy_transaction_id),
+    INDEX idx_tax_lots_user_symbol (user_id, symbol),
+    INDEX idx_tax_lots_deleted_at (deleted_at),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON UPDATE CASCADE ON DELETE RESTRICT,
+    FOREIGN KEY (buy_transaction_id) REFERENCES transactions(transaction_id) ON UPDATE CASCADE ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Lot disposals table (UUID PK, FK to users, tax_lots and transactions, VARCHAR(36))
+CREATE TABLE IF NOT EXISTS lot_disposals (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    tax_lot_id VARCHAR(36) NOT NULL,
+    buy_transaction_id VARCHAR(36) NOT NULL,
+    sell_transaction_id VARCHAR(36) NOT NULL,
+    symbol VARCHAR(20) NOT NULL,
+    acquired_at TIMESTAMP NOT NULL,
+    disposed_at TIMESTAMP NOT NULL,
+    quantity DECIMAL(15,4) NOT NULL,
+    proceeds DECIMAL(15,4) NOT NULL,
+    cost_basis DECIMAL(15,4) NOT NULL,
+    gain_loss DECIMAL(15,4) NOT NULL,
+    holding_period VARCHAR(20) NOT NULL,
+    holding_days INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    INDEX idx_lot_disposals_user_id (user_id),
+    INDEX idx_lot_disposals_tax_lot_id (tax_lot_id),
+    INDEX idx_lot_disposals_sell_transaction_id (sell_transaction_id),
+    INDEX idx_lot_disposals_user_disposed (user_id, disposed_at),
+    INDEX idx_lot_disposals_deleted_at (deleted_at),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON UPDATE CASCADE ON DELETE RESTRICT,
+    FOREIGN KEY (tax_lot_id) REFERENCES tax_lots(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    FOREIGN KEY (sell_transaction_id) REFERENCES transactions(transaction_id) ON UPDATE CASCADE ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,19 @@
+-- Corporate actions: splits, reverse splits, renames and mergers applied on top of user transactions
+
+CREATE TABLE IF NOT EXISTS corporate_actions (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    symbol VARCHAR(20) NOT NULL,
+    action_type VARCHAR(20) NOT NULL,
+    effective_date TIMESTAMP NOT NULL,
+    ratio_from DECIMAL(15,6) NOT NULL DEFAULT 1,
+    ratio_to DECIMAL(15,6) NOT NULL DEFAULT 1,
+    new_symbol VARCHAR(20),
+    notes TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    INDEX idx_corporate_actions_symbol (symbol),
+    INDEX idx_corporate_actions_new_symbol (new_symbol),
+    INDEX idx_corporate_actions_effective_date (effective_date),
+    INDEX idx_corporate_actions_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
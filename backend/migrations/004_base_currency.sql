@@ -0,0 +1,7 @@
+-- Base currency per user; tax lot amounts are recorded in the currency they were converted to
+
+ALTER TABLE users ADD COLUMN base_currency VARCHAR(3) NOT NULL DEFAULT 'USD';
+
+ALTER TABLE tax_lots ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'USD';
+
+ALTER TABLE lot_disposals ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'USD';
@@ -0,0 +1,9 @@
+-- Fees and taxes charged on transactions, kept apart from the gross amount
+
+ALTER TABLE transactions ADD COLUMN commission DECIMAL(15,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE transactions ADD COLUMN fee DECIMAL(15,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE transactions ADD COLUMN tax DECIMAL(15,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE transactions ADD COLUMN withholding_tax DECIMAL(15,2) NOT NULL DEFAULT 0;
@@ -0,0 +1,21 @@
+-- Daily portfolio snapshots: one row per user and day, valued in the user's base currency
+
+-- Portfolio snapshots table (UUID PK, FK to users, VARCHAR(36))
+CREATE TABLE IF NOT EXISTS portfolio_snapshots (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    snapshot_date DATE NOT NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'USD',
+    market_value DECIMAL(15,4) NOT NULL DEFAULT 0,
+    cost_basis DECIMAL(15,4) NOT NULL DEFAULT 0,
+    cash_balance DECIMAL(15,4) NOT NULL DEFAULT 0,
+    fx_gain_loss DECIMAL(15,4) NOT NULL DEFAULT 0,
+    dividend_income DECIMAL(15,4) NOT NULL DEFAULT 0,
+    holdings JSON NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    UNIQUE KEY uk_portfolio_snapshots_user_date (user_id, snapshot_date),
+    INDEX idx_portfolio_snapshots_deleted_at (deleted_at),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON UPDATE CASCADE ON DELETE RESTRICT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
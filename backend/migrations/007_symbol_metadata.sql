@@ -0,0 +1,15 @@
+-- Symbol metadata: descriptive data about symbols used to group holdings, e.g. by sector or country
+
+CREATE TABLE IF NOT EXISTS symbol_metadata (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    symbol VARCHAR(20) NOT NULL,
+    name VARCHAR(255),
+    sector VARCHAR(100),
+    industry VARCHAR(100),
+    country VARCHAR(2),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    UNIQUE KEY uk_symbol_metadata_symbol (symbol),
+    INDEX idx_symbol_metadata_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,17 @@
+-- Target allocations: the weight a user aims for per symbol or per asset class, used to plan rebalancing
+
+CREATE TABLE IF NOT EXISTS target_allocations (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    target_type VARCHAR(20) NOT NULL,
+    target_key VARCHAR(100) NOT NULL,
+    weight DECIMAL(7,4) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    UNIQUE KEY uk_target_allocations_user_key (user_id, target_type, target_key),
+    INDEX idx_target_allocations_deleted_at (deleted_at),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON UPDATE CASCADE ON DELETE RESTRICT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE symbol_metadata ADD COLUMN asset_class VARCHAR(50);
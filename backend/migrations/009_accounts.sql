@@ -0,0 +1,22 @@
+-- Accounts: the brokerage accounts a user keeps transactions in, each with its own type and tax treatment
+
+CREATE TABLE IF NOT EXISTS accounts (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    broker VARCHAR(100),
+    account_type VARCHAR(20) NOT NULL,
+    base_currency VARCHAR(3) NOT NULL DEFAULT 'USD',
+    tax_treatment VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    INDEX idx_accounts_user_id (user_id),
+    INDEX idx_accounts_deleted_at (deleted_at),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON UPDATE CASCADE ON DELETE RESTRICT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE transactions
+    ADD COLUMN account_id VARCHAR(36) NULL AFTER broker,
+    ADD INDEX idx_transactions_account_id (account_id),
+    ADD CONSTRAINT fk_transactions_account FOREIGN KEY (account_id) REFERENCES accounts(id) ON UPDATE CASCADE ON DELETE RESTRICT;
@@ -0,0 +1,24 @@
+-- Portfolios: named groups of a user's accounts and transactions, such as a long-term core and a trading sandbox
+
+CREATE TABLE IF NOT EXISTS portfolios (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    INDEX idx_portfolios_user_id (user_id),
+    INDEX idx_portfolios_deleted_at (deleted_at),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON UPDATE CASCADE ON DELETE RESTRICT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE accounts
+    ADD COLUMN portfolio_id VARCHAR(36) NULL AFTER user_id,
+    ADD INDEX idx_accounts_portfolio_id (portfolio_id),
+    ADD CONSTRAINT fk_accounts_portfolio FOREIGN KEY (portfolio_id) REFERENCES portfolios(id) ON UPDATE CASCADE ON DELETE SET NULL;
+
+ALTER TABLE transactions
+    ADD COLUMN portfolio_id VARCHAR(36) NULL AFTER account_id,
+    ADD INDEX idx_transactions_portfolio_id (portfolio_id),
+    ADD CONSTRAINT fk_transactions_portfolio FOREIGN KEY (portfolio_id) REFERENCES portfolios(id) ON UPDATE CASCADE ON DELETE SET NULL;
@@ -0,0 +1,5 @@
+-- Short positions: tax lots opened by short sales, and the disposals that covered them
+
+ALTER TABLE tax_lots ADD COLUMN short BOOLEAN NOT NULL DEFAULT FALSE AFTER symbol;
+
+ALTER TABLE lot_disposals ADD COLUMN short BOOLEAN NOT NULL DEFAULT FALSE AFTER symbol;
@@ -0,0 +1,24 @@
+-- Option contracts: the underlying, expiry, strike, right and multiplier of the options traded,
+-- with symbols widened to hold OCC option symbols such as AAPL240119C00150000
+
+CREATE TABLE IF NOT EXISTS option_contracts (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    symbol VARCHAR(32) NOT NULL,
+    underlying VARCHAR(20) NOT NULL,
+    expiry DATE NOT NULL,
+    strike DECIMAL(15,4) NOT NULL,
+    option_type VARCHAR(4) NOT NULL,
+    multiplier DECIMAL(15,4) NOT NULL DEFAULT 100,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    UNIQUE KEY uk_option_contracts_symbol (symbol),
+    INDEX idx_option_contracts_underlying (underlying),
+    INDEX idx_option_contracts_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE transactions MODIFY COLUMN symbol VARCHAR(32) NOT NULL;
+
+ALTER TABLE tax_lots MODIFY COLUMN symbol VARCHAR(32) NOT NULL;
+
+ALTER TABLE lot_disposals MODIFY COLUMN symbol VARCHAR(32) NOT NULL;
@@ -0,0 +1,14 @@
+-- Crypto quantities: quantities and prices widened to ten decimal places, so that fractions of a coin
+-- such as 0.00012345 BTC and prices of coins worth a fraction of a cent are stored without truncation
+
+ALTER TABLE transactions
+    MODIFY COLUMN quantity DECIMAL(24,10) NOT NULL,
+    MODIFY COLUMN price DECIMAL(24,10) NOT NULL;
+
+ALTER TABLE tax_lots
+    MODIFY COLUMN quantity DECIMAL(24,10) NOT NULL,
+    MODIFY COLUMN remaining_quantity DECIMAL(24,10) NOT NULL;
+
+ALTER TABLE lot_disposals MODIFY COLUMN quantity DECIMAL(24,10) NOT NULL;
+
+ALTER TABLE lot_selections MODIFY COLUMN quantity DECIMAL(24,10) NOT NULL;
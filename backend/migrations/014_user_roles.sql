@@ -0,0 +1,4 @@
+-- User roles: admins may modify data shared by all users, such as corporate actions and symbol
+-- metadata. Grant the role with UPDATE users SET is_admin = TRUE WHERE username = '...'
+
+ALTER TABLE users ADD COLUMN is_admin BOOLEAN NOT NULL DEFAULT FALSE AFTER is_active;
@@ -23,6 +23,197 @@ func GetAllMigrations() []Migration {
 				return db.Exec("DROP TABLE IF EXISTS jwt_tokens; DROP TABLE IF EXISTS transactions; DROP TABLE IF EXISTS users;").Error
 			},
 		},
+		{
+			ID:          "001_cost_basis",
+			Description: "Per-user cost basis method and specific lot selections",
+			Up: func(db *gorm.DB) error {
+				return executeSQLFile(db, "001_cost_basis.sql")
+			},
+			Down: func(db *gorm.DB) error {
+				if err := db.Exec("DROP TABLE IF EXISTS lot_selections").Error; err != nil {
+					return err
+				}
+				return db.Exec("ALTER TABLE users DROP COLUMN cost_basis_method").Error
+			},
+		},
+		{
+			ID:          "002_tax_lots",
+			Description: "Persisted tax lots and lot disposals",
+			Up: func(db *gorm.DB) error {
+				return executeSQLFile(db, "002_tax_lots.sql")
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec("DROP TABLE IF EXISTS lot_disposals; DROP TABLE IF EXISTS tax_lots;").Error
+			},
+		},
+		{
+			ID:          "003_corporate_actions",
+			Description: "Corporate actions: splits, reverse splits, renames and mergers",
+			Up: func(db *gorm.DB) error {
+				return executeSQLFile(db, "003_corporate_actions.sql")
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec("DROP TABLE IF EXISTS corporate_actions").Error
+			},
+		},
+		{
+			ID:          "004_base_currency",
+			Description: "Per-user base currency and currency of tax lot amounts",
+			Up: func(db *gorm.DB) error {
+				return executeSQLFile(db, "004_base_currency.sql")
+			},
+			Down: func(db *gorm.DB) error {
+				if err := db.Exec("ALTER TABLE lot_disposals DROP COLUMN currency").Error; err != nil {
+					return err
+				}
+				if err := db.Exec("ALTER TABLE tax_lots DROP COLUMN currency").Error; err != nil {
+					return err
+				}
+				return db.Exec("ALTER TABLE users DROP COLUMN base_currency").Error
+			},
+		},
+		{
+			ID:          "005_transaction_costs",
+			Description: "Commission, fee, transaction tax and withholding tax on transactions",
+			Up: func(db *gorm.DB) error {
+				return executeSQLFile(db, "005_transaction_costs.sql")
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec("ALTER TABLE transactions DROP COLUMN commission, DROP COLUMN fee, DROP COLUMN tax, DROP COLUMN withholding_tax").Error
+			},
+		},
+		{
+			ID:          "006_portfolio_snapshots",
+			Description: "Daily per-user portfolio snapshots of holdings, market value, cost basis and cash",
+			Up: func(db *gorm.DB) error {
+				return executeSQLFile(db, "006_portfolio_snapshots.sql")
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec("DROP TABLE IF EXISTS portfolio_snapshots").Error
+			},
+		},
+		{
+			ID:          "007_symbol_metadata",
+			Description: "Sector, industry and country of symbols for allocation breakdowns",
+			Up: func(db *gorm.DB) error {
+				return executeSQLFile(db, "007_symbol_metadata.sql")
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec("DROP TABLE IF EXISTS symbol_metadata").Error
+			},
+		},
+		{
+			ID:          "008_target_allocations",
+			Description: "Per-user target weights by symbol or asset class, and the asset class of symbols",
+			Up: func(db *gorm.DB) error {
+				return executeSQLFile(db, "008_target_allocations.sql")
+			},
+			Down: func(db *gorm.DB) error {
+				if err := db.Exec("ALTER TABLE symbol_metadata DROP COLUMN asset_class").Error; err != nil {
+					return err
+				}
+				return db.Exec("DROP TABLE IF EXISTS target_allocations").Error
+			},
+		},
+		{
+			ID:          "009_accounts",
+			Description: "Brokerage accounts, with transactions linked to the account they were made in",
+			Up: func(db *gorm.DB) error {
+				return executeSQLFile(db, "009_accounts.sql")
+			},
+			Down: func(db *gorm.DB) error {
+				if err := db.Exec("ALTER TABLE transactions DROP FOREIGN KEY fk_transactions_account").Error; err != nil {
+					return err
+				}
+				if err := db.Exec("ALTER TABLE transactions DROP COLUMN account_id").Error; err != nil {
+					return err
+				}
+				return db.Exec("DROP TABLE IF EXISTS accounts").Error
+			},
+		},
+		{
+			ID:          "010_portfolios",
+			Description: "Named portfolios grouping a user's accounts and transactions",
+			Up: func(db *gorm.DB) error {
+				return executeSQLFile(db, "010_portfolios.sql")
+			},
+			Down: func(db *gorm.DB) error {
+				if err := db.Exec("ALTER TABLE transactions DROP FOREIGN KEY fk_transactions_portfolio").Error; err != nil {
+					return err
+				}
+				if err := db.Exec("ALTER TABLE transactions DROP COLUMN portfolio_id").Error; err != nil {
+					return err
+				}
+				if err := db.Exec("ALTER TABLE accounts DROP FOREIGN KEY fk_accounts_portfolio").Error; err != nil {
+					return err
+				}
+				if err := db.Exec("ALTER TABLE accounts DROP COLUMN portfolio_id").Error; err != nil {
+					return err
+				}
+				return db.Exec("DROP TABLE IF EXISTS portfolios").Error
+			},
+		},
+		{
+			ID:          "011_short_positions",
+			Description: "Short flag on tax lots and lot disposals for short sales and their covers",
+			Up: func(db *gorm.DB) error {
+				return executeSQLFile(db, "011_short_positions.sql")
+			},
+			Down: func(db *gorm.DB) error {
+				if err := db.Exec("ALTER TABLE lot_disposals DROP COLUMN short").Error; err != nil {
+					return err
+				}
+				return db.Exec("ALTER TABLE tax_lots DROP COLUMN short").Error
+			},
+		},
+		{
+			ID:          "012_option_contracts",
+			Description: "Option contracts, and symbols wide enough for OCC option symbols",
+			Up: func(db *gorm.DB) error {
+				return executeSQLFile(db, "012_option_contracts.sql")
+			},
+			Down: func(db *gorm.DB) error {
+				for _, table := range []string{"lot_disposals", "tax_lots", "transactions"} {
+					if err := db.Exec(fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN symbol VARCHAR(20) NOT NULL", table)).Error; err != nil {
+						return err
+					}
+				}
+				return db.Exec("DROP TABLE IF EXISTS option_contracts").Error
+			},
+		},
+		{
+			ID:          "013_crypto_quantities",
+			Description: "Quantities and prices with ten decimal places for crypto",
+			Up: func(db *gorm.DB) error {
+				return executeSQLFile(db, "013_crypto_quantities.sql")
+			},
+			Down: func(db *gorm.DB) error {
+				columns := []struct{ table, column string }{
+					{"transactions", "quantity"},
+					{"transactions", "price"},
+					{"tax_lots", "quantity"},
+					{"tax_lots", "remaining_quantity"},
+					{"lot_disposals", "quantity"},
+					{"lot_selections", "quantity"},
+				}
+				for _, c := range columns {
+					if err := db.Exec(fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s DECIMAL(15,4) NOT NULL", c.table, c.column)).Error; err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			ID:          "014_user_roles",
+			Description: "Admin users allowed to modify data shared by all users",
+			Up: func(db *gorm.DB) error {
+				return executeSQLFile(db, "014_user_roles.sql")
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec("ALTER TABLE users DROP COLUMN is_admin").Error
+			},
+		},
 	}
 }
 
@@ -0,0 +1,107 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/services"
+	"github.com/transaction-tracker/backend/internal/types"
+)
+
+func TestPortfolioScopeFilter(t *testing.T) {
+	ira, taxable := uuid.New(), uuid.New()
+	transactions := []models.Transaction{
+		inAccount(costBasisTx(types.TradeTypeBuy, 1, 10, 100), ira),
+		inAccount(costBasisTx(types.TradeTypeBuy, 2, 5, 100), taxable),
+		costBasisTx(types.TradeTypeBuy, 3, 1, 100),
+	}
+
+	if all := (services.PortfolioScope{}).Filter(transactions); len(all) != 3 {
+		t.Errorf("expected the zero scope to keep all 3 transactions, got %d", len(all))
+	}
+
+	scoped := services.PortfolioScope{AccountID: &ira}.Filter(transactions)
+	if len(scoped) != 1 || scoped[0].Quantity != 10 {
+		t.Errorf("expected only the IRA buy, got %+v", scoped)
+	}
+}
+
+func TestPortfolioScopeFilterByPortfolio(t *testing.T) {
+	core, sandbox := uuid.New(), uuid.New()
+	ira, taxable := uuid.New(), uuid.New()
+
+	// A trade assigned to a portfolio itself belongs to it, whichever account it is recorded in
+	traded := inAccount(costBasisTx(types.TradeTypeBuy, 3, 2, 100), ira)
+	traded.PortfolioID = &sandbox
+	transactions := []models.Transaction{
+		inAccount(costBasisTx(types.TradeTypeBuy, 1, 10, 100), ira),
+		inAccount(costBasisTx(types.TradeTypeBuy, 2, 5, 100), taxable),
+		traded,
+		costBasisTx(types.TradeTypeBuy, 4, 1, 100),
+	}
+
+	coreScope := services.PortfolioScope{PortfolioID: &core}.WithPortfolioAccounts([]uuid.UUID{ira})
+	if scoped := coreScope.Filter(transactions); len(scoped) != 1 || scoped[0].Quantity != 10 {
+		t.Errorf("expected only the IRA buy in the core portfolio, got %+v", scoped)
+	}
+
+	sandboxScope := services.PortfolioScope{PortfolioID: &sandbox}.WithPortfolioAccounts([]uuid.UUID{taxable})
+	if scoped := sandboxScope.Filter(transactions); len(scoped) != 2 {
+		t.Errorf("expected the taxable buy and the assigned trade in the sandbox, got %+v", scoped)
+	}
+
+	// Narrowed to one account of the portfolio
+	sandboxScope.AccountID = &ira
+	if scoped := sandboxScope.Filter(transactions); len(scoped) != 1 || scoped[0].Quantity != 2 {
+		t.Errorf("expected only the assigned IRA trade, got %+v", scoped)
+	}
+}
+
+func TestCashBalancesSplitByAccount(t *testing.T) {
+	ira, taxable := uuid.New(), uuid.New()
+	transactions := []models.Transaction{
+		inAccount(cashTx(types.TradeTypeDeposit, "Schwab", 1, 1000), ira),
+		inAccount(cashTx(types.TradeTypeDeposit, "Schwab", 2, 300), taxable),
+		inAccount(cashTx(types.TradeTypeWithdrawal, "Schwab", 3, 100), taxable),
+	}
+
+	balances := services.CashBalances(transactions, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	if len(balances) != 2 {
+		t.Fatalf("expected a balance per account, got %+v", balances)
+	}
+	for _, balance := range balances {
+		switch *balance.AccountID {
+		case ira:
+			assertClose(t, "IRA balance", balance.Balance, 1000)
+		case taxable:
+			assertClose(t, "taxable balance", balance.Balance, 200)
+		default:
+			t.Errorf("unexpected account in %+v", balance)
+		}
+	}
+}
+
+func TestAllocationByAccount(t *testing.T) {
+	ira, taxable := uuid.New(), uuid.New()
+	transactions := []models.Transaction{
+		inAccount(costBasisTx(types.TradeTypeBuy, 1, 30, 100), ira),
+		inAccount(costBasisTx(types.TradeTypeBuy, 2, 10, 100), taxable),
+	}
+	holdings := []models.SingleHolding{{Symbol: "AAPL", Currency: "USD", TotalQuantity: 40, MarketValue: 8000}}
+	engine := services.NewCostBasisEngine(models.CostBasisFIFO, nil)
+	positions := services.AllocationPositions(engine, holdings, transactions, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	cash := []models.CashBalance{{Currency: "USD", AccountID: &taxable, Balance: 2000, Value: 2000}}
+
+	groups := services.BuildAllocation(models.AllocationGroupByAccount, positions, cash, nil)
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 account groups, got %+v", groups)
+	}
+	if groups[0].Key != ira.String() || groups[1].Key != taxable.String() {
+		t.Fatalf("expected groups keyed by account ID, got %+v", groups)
+	}
+	assertClose(t, "IRA value", groups[0].MarketValue, 6000)
+	assertClose(t, "taxable value", groups[1].MarketValue, 4000)
+	assertClose(t, "taxable weight", groups[1].Weight, 40)
+}
@@ -0,0 +1,67 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/transaction-tracker/backend/api/middlewares"
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/repositories"
+)
+
+// adminTestUserRepository finds users among a fixed set
+type adminTestUserRepository struct {
+	repositories.UserRepository
+	users map[uuid.UUID]*models.User
+}
+
+func (r *adminTestUserRepository) FindByUserID(userID uuid.UUID) (*models.User, error) {
+	if user, ok := r.users[userID]; ok {
+		return user, nil
+	}
+	return nil, fmt.Errorf("user not found")
+}
+
+func TestAdminMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	admin := &models.User{UserID: uuid.New(), IsAdmin: true}
+	member := &models.User{UserID: uuid.New()}
+	userRepo := &adminTestUserRepository{users: map[uuid.UUID]*models.User{
+		admin.UserID:  admin,
+		member.UserID: member,
+	}}
+
+	for name, tc := range map[string]struct {
+		userID     interface{}
+		wantStatus int
+	}{
+		"admin":          {admin.UserID, http.StatusOK},
+		"member":         {member.UserID, http.StatusForbidden},
+		"unknown user":   {uuid.New(), http.StatusForbidden},
+		"not logged in":  {nil, http.StatusUnauthorized},
+		"malformed user": {"not-a-uuid", http.StatusUnauthorized},
+	} {
+		t.Run(name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				if tc.userID != nil {
+					c.Set("user_id", tc.userID)
+				}
+				c.Next()
+			})
+			router.PUT("/protected", middlewares.AdminMiddleware(userRepo), func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"message": "success"})
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/protected", nil))
+			assert.Equal(t, tc.wantStatus, w.Code)
+		})
+	}
+}
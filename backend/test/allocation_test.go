@@ -0,0 +1,81 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/services"
+	"github.com/transaction-tracker/backend/internal/types"
+)
+
+func TestAllocationPositionsSplitByBroker(t *testing.T) {
+	atFirstrade := costBasisTx(types.TradeTypeBuy, 1, 30, 100)
+	atFirstrade.Broker = "Firstrade"
+	atFirstrade.Exchange = "NASDAQ"
+	atSchwab := costBasisTx(types.TradeTypeBuy, 2, 10, 100)
+	atSchwab.Broker = "Schwab"
+	soldAtSchwab := costBasisTx(types.TradeTypeSell, 3, 5, 110)
+	soldAtSchwab.Broker = "Schwab"
+
+	holdings := []models.SingleHolding{{Symbol: "AAPL", Currency: "USD", TotalQuantity: 35, MarketValue: 7000}}
+	engine := services.NewCostBasisEngine(models.CostBasisFIFO, nil)
+	positions := services.AllocationPositions(engine, holdings, []models.Transaction{atFirstrade, atSchwab, soldAtSchwab}, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+
+	if len(positions) != 2 {
+		t.Fatalf("expected 2 positions, got %+v", positions)
+	}
+	if positions[0].Broker != "Firstrade" || positions[1].Broker != "Schwab" {
+		t.Fatalf("unexpected position order: %+v", positions)
+	}
+	assertClose(t, "Firstrade value", positions[0].MarketValue, 6000)
+	assertClose(t, "Schwab value", positions[1].MarketValue, 1000)
+	if positions[1].Exchange != "NASDAQ" {
+		t.Errorf("expected the holding's exchange on every position, got %q", positions[1].Exchange)
+	}
+}
+
+func TestBuildAllocation(t *testing.T) {
+	positions := []services.AllocationPosition{
+		{Symbol: "2330.TW", Broker: "Sinopac", Exchange: "TWSE", Currency: "TWD", MarketValue: 3000},
+		{Symbol: "AAPL", Broker: "Firstrade", Exchange: "NASDAQ", Currency: "USD", MarketValue: 4000},
+		{Symbol: "MSFT", Broker: "Firstrade", Exchange: "NASDAQ", Currency: "USD", MarketValue: 2000},
+	}
+	cash := []models.CashBalance{{Broker: "Firstrade", Currency: "USD", Balance: 1000, Value: 1000}}
+	metadata := map[string]models.SymbolMetadata{
+		"AAPL":    {Symbol: "AAPL", Sector: "Technology", Country: "US"},
+		"2330.TW": {Symbol: "2330.TW", Sector: "Technology"},
+	}
+
+	tests := []struct {
+		groupBy models.AllocationGroupBy
+		want    map[string]float64
+	}{
+		{models.AllocationGroupBySector, map[string]float64{"Technology": 7000, "Unknown": 2000, "Cash": 1000}},
+		{models.AllocationGroupByCountry, map[string]float64{"US": 6000, "TW": 3000, "Cash": 1000}},
+		{models.AllocationGroupByCurrency, map[string]float64{"USD": 7000, "TWD": 3000}},
+		{models.AllocationGroupByBroker, map[string]float64{"Firstrade": 7000, "Sinopac": 3000}},
+		{models.AllocationGroupByExchange, map[string]float64{"NASDAQ": 6000, "TWSE": 3000, "Cash": 1000}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.groupBy), func(t *testing.T) {
+			groups := services.BuildAllocation(tt.groupBy, positions, cash, metadata)
+			if len(groups) != len(tt.want) {
+				t.Fatalf("expected %d groups, got %+v", len(tt.want), groups)
+			}
+			for i, group := range groups {
+				assertClose(t, group.Key+" value", group.MarketValue, tt.want[group.Key])
+				assertClose(t, group.Key+" weight", group.Weight, tt.want[group.Key]/10000*100)
+				if i > 0 && group.MarketValue > groups[i-1].MarketValue {
+					t.Errorf("expected groups largest first, got %+v", groups)
+				}
+			}
+		})
+	}
+
+	sectors := services.BuildAllocation(models.AllocationGroupBySector, positions, nil, metadata)
+	if symbols := sectors[0].Symbols; len(symbols) != 2 || symbols[0] != "2330.TW" || symbols[1] != "AAPL" {
+		t.Errorf("expected the technology group to list 2330.TW and AAPL, got %v", symbols)
+	}
+}
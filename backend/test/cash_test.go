@@ -0,0 +1,63 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/services"
+	"github.com/transaction-tracker/backend/internal/types"
+)
+
+func TestCashBalances(t *testing.T) {
+	buy := costBasisTx(types.TradeTypeBuy, 3, 10, 100)
+	buy.Broker = "Firstrade"
+	buy.Currency = "USD"
+	buy.Commission = 5
+	sell := costBasisTx(types.TradeTypeSell, 10, 5, 120)
+	sell.Broker = "Firstrade"
+	sell.Currency = "USD"
+	dividend := dividendTx("AAPL", time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC), 10)
+	dividend.Broker = "Firstrade"
+	dividend.WithholdingTax = 3
+
+	transactions := []models.Transaction{
+		cashTx(types.TradeTypeDeposit, "Firstrade", 1, 2000),
+		buy,
+		sell,
+		dividend,
+		cashTx(types.TradeTypeInterest, "Firstrade", 15, 2),
+		cashTx(types.TradeTypeFee, "Firstrade", 16, 1),
+		cashTx(types.TradeTypeWithdrawal, "Firstrade", 20, 500),
+		cashTx(types.TradeTypeDeposit, "Schwab", 2, 300),
+	}
+
+	balances := services.CashBalances(transactions, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	if len(balances) != 2 {
+		t.Fatalf("expected 2 cash balances, got %d", len(balances))
+	}
+	if balances[0].Broker != "Firstrade" || balances[1].Broker != "Schwab" {
+		t.Fatalf("unexpected balance order: %+v", balances)
+	}
+
+	// 2000 − 1005 + 600 + 7 + 2 − 1 − 500
+	assertClose(t, "Firstrade balance", balances[0].Balance, 1103)
+	assertClose(t, "Schwab balance", balances[1].Balance, 300)
+
+	// Only flows dated on or before asOf count
+	early := services.CashBalances(transactions, time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC))
+	assertClose(t, "Firstrade balance on Jan 5", early[0].Balance, 995)
+}
+
+func TestCashTransactionsAreNotHoldings(t *testing.T) {
+	transactions := []models.Transaction{
+		cashTx(types.TradeTypeDeposit, "Firstrade", 1, 2000),
+		costBasisTx(types.TradeTypeBuy, 3, 10, 100),
+	}
+
+	engine := services.NewCostBasisEngine(models.CostBasisFIFO, nil)
+	grouped := engine.GroupBySymbol(transactions, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	if len(grouped) != 1 || len(grouped["AAPL"]) != 1 {
+		t.Errorf("expected only the AAPL holding, got %v", grouped)
+	}
+}
@@ -0,0 +1,74 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/transaction-tracker/backend/internal/services"
+)
+
+// recordingChangeListener records the changes handed to it
+type recordingChangeListener struct {
+	mu      sync.Mutex
+	changes []time.Time
+	users   []uuid.UUID
+}
+
+func (l *recordingChangeListener) OnTransactionsChanged(userID uuid.UUID, since time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.users = append(l.users, userID)
+	l.changes = append(l.changes, since)
+	return nil
+}
+
+func (l *recordingChangeListener) handled() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.users)
+}
+
+func TestChangeQueueCoalescesChangesPerUser(t *testing.T) {
+	listener := &recordingChangeListener{}
+	queue := services.NewChangeQueue(listener)
+
+	first, second := uuid.New(), uuid.New()
+	// Nothing is handled before the queue runs, and later changes of a queued user coalesce
+	// into one since the earliest date
+	require.NoError(t, queue.OnTransactionsChanged(first, januaryDay(10)))
+	require.NoError(t, queue.OnTransactionsChanged(second, januaryDay(5)))
+	require.NoError(t, queue.OnTransactionsChanged(first, januaryDay(3)))
+	require.NoError(t, queue.OnTransactionsChanged(first, januaryDay(20)))
+	assert.Equal(t, 2, queue.Pending())
+	assert.True(t, queue.Queued(first))
+	assert.Equal(t, 0, listener.handled())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		queue.Run(ctx)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool { return listener.handled() == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, []uuid.UUID{first, second}, listener.users)
+	assert.Equal(t, []time.Time{januaryDay(3), januaryDay(5)}, listener.changes)
+	assert.False(t, queue.Queued(first))
+
+	// Changes queued while running are handled too
+	require.NoError(t, queue.OnTransactionsChanged(second, time.Time{}))
+	require.Eventually(t, func() bool { return listener.handled() == 3 }, time.Second, time.Millisecond)
+	assert.True(t, listener.changes[2].IsZero())
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("queue did not stop when its context was cancelled")
+	}
+}
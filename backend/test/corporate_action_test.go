@@ -0,0 +1,72 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/services"
+	"github.com/transaction-tracker/backend/internal/types"
+)
+
+func TestCostBasisEngineAppliesSplit(t *testing.T) {
+	transactions := []models.Transaction{
+		costBasisTx(types.TradeTypeBuy, 1, 10, 400),
+		// Trades on the effective date are already in post-split shares
+		costBasisTx(types.TradeTypeSell, 5, 20, 110),
+	}
+	actions := []models.CorporateAction{corporateAction(models.CorporateActionSplit, "AAPL", "", 5, 1, 4)}
+	engine := services.NewCostBasisEngine(models.CostBasisFIFO, nil).WithCorporateActions(actions)
+
+	result := engine.Calculate(transactions)
+	assertClose(t, "quantity", result.TotalQuantity(), 20)
+	assertClose(t, "unit cost", result.UnitCost(), 100)
+	assertClose(t, "realized gain", result.RealizedGainLoss(), 20*10)
+
+	// Before the split the original share count applies
+	before := engine.CalculateAt(transactions[:1], time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC))
+	assertClose(t, "pre-split quantity", before.TotalQuantity(), 10)
+	assertClose(t, "pre-split cost", before.TotalCost(), 4000)
+}
+
+func TestCostBasisEngineAppliesReverseSplit(t *testing.T) {
+	transactions := []models.Transaction{costBasisTx(types.TradeTypeBuy, 1, 100, 2)}
+	actions := []models.CorporateAction{corporateAction(models.CorporateActionReverseSplit, "AAPL", "", 3, 10, 1)}
+
+	result := services.NewCostBasisEngine(models.CostBasisAverage, nil).WithCorporateActions(actions).Calculate(transactions)
+	assertClose(t, "quantity", result.TotalQuantity(), 10)
+	assertClose(t, "total cost", result.TotalCost(), 200)
+}
+
+func TestCostBasisEngineFollowsRenameAndMerger(t *testing.T) {
+	buy := costBasisTx(types.TradeTypeBuy, 1, 10, 100)
+	buy.Symbol = "FB"
+	target := costBasisTx(types.TradeTypeBuy, 2, 5, 50)
+	target.Symbol = "XYZ"
+	sell := costBasisTx(types.TradeTypeSell, 20, 5, 300)
+	sell.Symbol = "META"
+
+	actions := []models.CorporateAction{
+		corporateAction(models.CorporateActionRename, "FB", "META", 10, 1, 1),
+		corporateAction(models.CorporateActionMerger, "XYZ", "META", 15, 2, 1),
+	}
+	engine := services.NewCostBasisEngine(models.CostBasisFIFO, nil).WithCorporateActions(actions)
+	now := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	grouped := engine.GroupBySymbol([]models.Transaction{buy, target, sell}, now)
+	if len(grouped) != 1 || len(grouped["META"]) != 3 {
+		t.Fatalf("expected all transactions grouped under META, got %v", grouped)
+	}
+
+	result := engine.CalculateAt(grouped["META"], now)
+	// 10 renamed shares plus 5 XYZ shares merged 2:1 into 2.5 META, minus 5 sold from the oldest lot
+	assertClose(t, "quantity", result.TotalQuantity(), 7.5)
+	assertClose(t, "total cost", result.TotalCost(), 500+250)
+	assertClose(t, "realized gain", result.RealizedGainLoss(), 1500-500)
+
+	// Before the rename the holding is still valued under its old symbol
+	early := time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)
+	if symbol := engine.SymbolAt("FB", buy.TransactionDate, early); symbol != "FB" {
+		t.Errorf("SymbolAt before rename = %s, want FB", symbol)
+	}
+}
@@ -0,0 +1,101 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/services"
+	"github.com/transaction-tracker/backend/internal/types"
+)
+
+// costBasisFixture buys 10@100, 10@150, 10@120 and then sells 15@200
+func costBasisFixture() []models.Transaction {
+	return []models.Transaction{
+		costBasisTx(types.TradeTypeBuy, 1, 10, 100),
+		costBasisTx(types.TradeTypeBuy, 2, 10, 150),
+		costBasisTx(types.TradeTypeBuy, 3, 10, 120),
+		costBasisTx(types.TradeTypeSell, 4, 15, 200),
+	}
+}
+
+func TestCostBasisEngineMethods(t *testing.T) {
+	cases := []struct {
+		method        models.CostBasisMethod
+		remainingCost float64
+		realized      float64
+	}{
+		// average: 3700/30 per share, 15 shares remain
+		{models.CostBasisAverage, 1850, 3000 - 1850},
+		// FIFO: sells 10@100 + 5@150, keeps 5@150 + 10@120
+		{models.CostBasisFIFO, 750 + 1200, 3000 - 1750},
+		// LIFO: sells 10@120 + 5@150, keeps 10@100 + 5@150
+		{models.CostBasisLIFO, 1000 + 750, 3000 - 1950},
+		// HIFO: sells 10@150 + 5@120, keeps 10@100 + 5@120
+		{models.CostBasisHIFO, 1000 + 600, 3000 - 2100},
+	}
+
+	for _, c := range cases {
+		t.Run(string(c.method), func(t *testing.T) {
+			engine := services.NewCostBasisEngine(c.method, nil)
+			result := engine.Calculate(costBasisFixture())
+
+			assertClose(t, "quantity", result.TotalQuantity(), 15)
+			assertClose(t, "remaining cost", result.TotalCost(), c.remainingCost)
+			assertClose(t, "realized gain", result.RealizedGainLoss(), c.realized)
+			assertClose(t, "unmatched", result.UnmatchedQuantity, 0)
+		})
+	}
+}
+
+func TestCostBasisEngineSpecificLot(t *testing.T) {
+	transactions := costBasisFixture()
+	sell := transactions[3]
+	selections := []models.LotSelection{
+		{SellTransactionID: sell.TransactionID, BuyTransactionID: transactions[2].TransactionID, Quantity: 10},
+		{SellTransactionID: sell.TransactionID, BuyTransactionID: transactions[1].TransactionID, Quantity: 5},
+	}
+
+	result := services.NewCostBasisEngine(models.CostBasisSpecificLot, selections).Calculate(transactions)
+	// sells 10@120 + 5@150, keeps 10@100 + 5@150
+	assertClose(t, "remaining cost", result.TotalCost(), 1750)
+	assertClose(t, "realized gain", result.RealizedGainLoss(), 3000-1950)
+
+	// Without selections the specific lot method falls back to FIFO
+	fallback := services.NewCostBasisEngine(models.CostBasisSpecificLot, nil).Calculate(transactions)
+	assertClose(t, "fallback remaining cost", fallback.TotalCost(), 1950)
+}
+
+func TestRemainingLotQuantity(t *testing.T) {
+	buy := costBasisTx(types.TradeTypeBuy, 1, 10, 100)
+	first := costBasisTx(types.TradeTypeSell, 2, 6, 110)
+	second := costBasisTx(types.TradeTypeSell, 3, 6, 120)
+	selections := []models.LotSelection{
+		{SellTransactionID: first.TransactionID, BuyTransactionID: buy.TransactionID, Quantity: 6},
+		{SellTransactionID: second.TransactionID, BuyTransactionID: uuid.New(), Quantity: 6},
+	}
+
+	// The first sale took 6 of the lot, leaving 4 for the second
+	assertClose(t, "remaining for second sale", services.RemainingLotQuantity(buy, second.TransactionID, selections), 4)
+	// A sale's own selections are replaced, so they do not count against it
+	assertClose(t, "remaining for first sale", services.RemainingLotQuantity(buy, first.TransactionID, selections), 10)
+}
+
+func TestCostBasisEngineOversell(t *testing.T) {
+	transactions := []models.Transaction{
+		costBasisTx(types.TradeTypeBuy, 1, 5, 100),
+		costBasisTx(types.TradeTypeSell, 2, 8, 110),
+	}
+
+	result := services.NewCostBasisEngine(models.CostBasisFIFO, nil).Calculate(transactions)
+	assertClose(t, "quantity", result.TotalQuantity(), 0)
+	assertClose(t, "unmatched", result.UnmatchedQuantity, 3)
+	assertClose(t, "realized gain", result.RealizedGainLoss(), 50)
+}
+
+func TestCostBasisEngineInvalidMethodDefaultsToAverage(t *testing.T) {
+	engine := services.NewCostBasisEngine(models.CostBasisMethod("unknown"), nil)
+	if engine.Method() != models.DefaultCostBasisMethod {
+		t.Errorf("Method() = %v, want %v", engine.Method(), models.DefaultCostBasisMethod)
+	}
+}
@@ -0,0 +1,60 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/services"
+	"github.com/transaction-tracker/backend/internal/types"
+	"github.com/transaction-tracker/backend/internal/utils"
+)
+
+func TestCryptoSymbolRegex(t *testing.T) {
+	for _, symbol := range []string{"BTC-USD", "ETH-USD", "DOGE-USDT", "SHIB-EUR"} {
+		if !utils.CryptoSymbolRegex.MatchString(symbol) || !utils.SymbolRegex.MatchString(symbol) {
+			t.Errorf("expected %s to be a crypto symbol", symbol)
+		}
+	}
+	for _, symbol := range []string{"AAPL", "BRK.B", "2330.TW", "BTC-", "-USD", "btc-usd", "BTC-US1"} {
+		if utils.CryptoSymbolRegex.MatchString(symbol) {
+			t.Errorf("expected %s not to be a crypto symbol", symbol)
+		}
+	}
+}
+
+func TestRoundTo10(t *testing.T) {
+	if got := utils.RoundTo10(0.123456789012); got != 0.123456789 {
+		t.Errorf("RoundTo10 = %v, want 0.123456789", got)
+	}
+	if got := utils.RoundTo10(0.00012345); got != 0.00012345 {
+		t.Errorf("expected satoshis to be kept, got %v", got)
+	}
+}
+
+func TestCostBasisEngineCryptoQuantities(t *testing.T) {
+	engine := services.NewCostBasisEngine(models.CostBasisFIFO, nil)
+	result := engine.Calculate([]models.Transaction{
+		cryptoTx(types.TradeTypeBuy, 1, 0.5, 42000),
+		cryptoTx(types.TradeTypeBuy, 6, 0.00012345, 44000),
+		cryptoTx(types.TradeTypeSell, 13, 0.49999999, 46000),
+	})
+
+	// A satoshi of the first lot and all of the second are left
+	assertWithin(t, "remaining BTC", utils.RoundTo10(result.TotalQuantity()), 0.00012346, 1e-12)
+	assertClose(t, "realized gain", result.RealizedGainLoss(), 0.49999999*(46000-42000))
+}
+
+func TestPlanRebalanceCryptoFractions(t *testing.T) {
+	holdings := []services.RebalanceHolding{
+		rebalanceHolding("VTI", "equity", 40, 250),
+		rebalanceHolding("BTC-USD", models.AssetClassCrypto, 0, 40000),
+	}
+	targets := rebalanceTargets(models.TargetTypeSymbol, "VTI", 90, "BTC-USD", 10)
+
+	// Crypto is bought in fractions of a coin rather than whole coins
+	_, orders := services.PlanRebalance(targets, holdings, 0, services.RebalanceOptions{})
+	assertOrders(t, orders, []models.RebalanceOrder{
+		{Symbol: "VTI", Action: types.TradeTypeSell, Quantity: 4, EstimatedAmount: 1000},
+		{Symbol: "BTC-USD", Action: types.TradeTypeBuy, Quantity: 0.025, EstimatedAmount: 1000},
+	})
+}
@@ -0,0 +1,38 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/services"
+	"github.com/transaction-tracker/backend/internal/types"
+)
+
+func TestAggregateDividendIncome(t *testing.T) {
+	transactions := []models.Transaction{
+		dividendTx("AAPL", time.Date(2023, 11, 10, 0, 0, 0, 0, time.UTC), 24),
+		dividendTx("AAPL", time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC), 25),
+		dividendTx("MSFT", time.Date(2024, 2, 20, 0, 0, 0, 0, time.UTC), 30),
+		dividendTx("MSFT", time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC), 30),
+		// Trades are not income and must be ignored
+		costBasisTx(types.TradeTypeBuy, 3, 10, 100),
+	}
+
+	monthly := services.AggregateDividendIncome(transactions, "USD", models.DividendAggregationMonthly)
+	assertClose(t, "monthly total", monthly.TotalIncome, 109)
+	if len(monthly.Periods) != 3 {
+		t.Fatalf("expected 3 monthly periods, got %d", len(monthly.Periods))
+	}
+	if monthly.Periods[1].Period != "2024-02" || monthly.Periods[1].Payments != 2 {
+		t.Errorf("unexpected February period: %+v", monthly.Periods[1])
+	}
+	assertClose(t, "February income", monthly.Periods[1].Income, 55)
+	assertClose(t, "February MSFT income", monthly.Periods[1].BySymbol["MSFT"], 30)
+
+	yearly := services.AggregateDividendIncome(transactions, "USD", models.DividendAggregationYearly)
+	if len(yearly.Periods) != 2 || yearly.Periods[0].Period != "2023" || yearly.Periods[1].Period != "2024" {
+		t.Fatalf("unexpected yearly periods: %+v", yearly.Periods)
+	}
+	assertClose(t, "2024 income", yearly.Periods[1].Income, 85)
+}
@@ -0,0 +1,142 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/provider"
+	"github.com/transaction-tracker/backend/internal/services"
+	"github.com/transaction-tracker/backend/internal/types"
+)
+
+// twdToUSD returns a fetcher with USD-per-TWD rates for a few January 2024 trading days
+func twdToUSD(calls *int) services.FXRateFetcher {
+	return func(currency string) ([]provider.FXRate, error) {
+		*calls++
+		if currency != "TWD" {
+			return nil, fmt.Errorf("unexpected currency %s", currency)
+		}
+		// Newest first, as the price service returns them
+		return []provider.FXRate{
+			{Date: "2024-01-10", Rate: 0.033},
+			{Date: "2024-01-05", Rate: 0.032},
+			{Date: "2024-01-02", Rate: 0.030},
+		}, nil
+	}
+}
+
+func TestFXConverterRateAt(t *testing.T) {
+	calls := 0
+	fx := services.NewFXConverter("usd", twdToUSD(&calls))
+
+	if fx.BaseCurrency() != "USD" {
+		t.Errorf("expected base currency USD, got %s", fx.BaseCurrency())
+	}
+
+	tests := []struct {
+		name string
+		date time.Time
+		want float64
+	}{
+		{"exact date", time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), 0.032},
+		{"weekend carries the last rate forward", time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC), 0.032},
+		{"intraday time uses the day's rate", time.Date(2024, 1, 10, 15, 30, 0, 0, time.UTC), 0.033},
+		{"after the series uses the latest rate", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), 0.033},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := fx.RateAt("TWD", tt.date)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertClose(t, "rate", rate, tt.want)
+		})
+	}
+
+	// There is no rate before the series starts; the error names the currency and date
+	_, err := fx.RateAt("TWD", time.Date(2023, 12, 29, 0, 0, 0, 0, time.UTC))
+	if !errors.Is(err, services.ErrFXRateUnavailable) {
+		t.Errorf("expected ErrFXRateUnavailable before the series, got %v", err)
+	} else if !strings.Contains(err.Error(), "no TWD/USD rate on 2023-12-29") {
+		t.Errorf("expected the error to name the currency and date, got %v", err)
+	}
+
+	// Amounts already in the base currency never hit the rate source
+	rate, err := fx.RateAt("", time.Now())
+	if err != nil || rate != 1 {
+		t.Errorf("expected base currency rate 1, got %v (%v)", rate, err)
+	}
+
+	if calls != 1 {
+		t.Errorf("expected the TWD series to be fetched once, got %d fetches", calls)
+	}
+}
+
+func TestFXConverterRateAtMissingSeries(t *testing.T) {
+	fx := services.NewFXConverter("USD", func(currency string) ([]provider.FXRate, error) {
+		return []provider.FXRate{}, nil
+	})
+
+	if _, err := fx.RateAt("CAD", time.Now()); err == nil {
+		t.Error("expected an error when no rates are available")
+	}
+}
+
+func TestFXConverterConvertTransactions(t *testing.T) {
+	calls := 0
+	fx := services.NewFXConverter("USD", twdToUSD(&calls))
+
+	buy := costBasisTx(types.TradeTypeBuy, 2, 1000, 600)
+	buy.Symbol = "2330.TW"
+	buy.Currency = "TWD"
+	usdBuy := costBasisTx(types.TradeTypeBuy, 3, 10, 100)
+
+	converted, err := fx.ConvertTransactions([]models.Transaction{buy, usdBuy})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 600,000 TWD at 0.030 on 2024-01-02
+	assertClose(t, "converted amount", converted[0].Amount, 18000)
+	assertClose(t, "converted price", converted[0].Price, 18)
+	if converted[0].Currency != "USD" {
+		t.Errorf("expected converted currency USD, got %s", converted[0].Currency)
+	}
+	assertClose(t, "USD amount", converted[1].Amount, 1000)
+
+	// The originals are left untouched
+	assertClose(t, "original amount", buy.Amount, 600000)
+}
+
+func TestTaxLedgerRecordsConvertedCurrency(t *testing.T) {
+	calls := 0
+	fx := services.NewFXConverter("USD", twdToUSD(&calls))
+
+	buy := costBasisTx(types.TradeTypeBuy, 2, 1000, 600)
+	buy.Currency = "TWD"
+	sell := costBasisTx(types.TradeTypeSell, 10, 1000, 600)
+	sell.Currency = "TWD"
+
+	converted, err := fx.ConvertTransactions([]models.Transaction{buy, sell})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	engine := services.NewCostBasisEngine(models.CostBasisFIFO, nil)
+	lots, disposals := services.BuildTaxLedger(buy.UserID, engine, converted)
+	if len(lots) != 1 || len(disposals) != 1 {
+		t.Fatalf("expected 1 lot and 1 disposal, got %d and %d", len(lots), len(disposals))
+	}
+
+	if lots[0].Currency != "USD" || disposals[0].Currency != "USD" {
+		t.Errorf("expected ledger in USD, got lot %s and disposal %s", lots[0].Currency, disposals[0].Currency)
+	}
+
+	// Selling at an unchanged TWD price still realizes the exchange rate gain: 600,000 × (0.033 − 0.030)
+	assertClose(t, "realized gain", disposals[0].GainLoss, 1800)
+}
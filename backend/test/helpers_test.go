@@ -0,0 +1,104 @@
+package test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/types"
+)
+
+// Fixture builders and assertions shared by the portfolio tests. Unless a test says otherwise,
+// fixtures trade AAPL in January 2024.
+
+// januaryDay returns midnight UTC of a day of January 2024
+func januaryDay(day int) time.Time {
+	return time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC)
+}
+
+// costBasisTx trades shares of AAPL at a price on a day of January 2024
+func costBasisTx(tradeType types.TradeType, day int, quantity, price float64) models.Transaction {
+	return models.Transaction{
+		TransactionID:   uuid.New(),
+		Symbol:          "AAPL",
+		TradeType:       tradeType,
+		Quantity:        quantity,
+		Price:           price,
+		Amount:          quantity * price,
+		TransactionDate: januaryDay(day),
+	}
+}
+
+// cryptoTx trades an amount of BTC at a price on a day of January 2024
+func cryptoTx(tradeType types.TradeType, day int, quantity, price float64) models.Transaction {
+	tx := costBasisTx(tradeType, day, quantity, price)
+	tx.Symbol = "BTC-USD"
+	return tx
+}
+
+// optionTx trades contracts of a standard option at a per-share premium on a day of January 2024
+func optionTx(tradeType types.TradeType, symbol string, day int, contracts, premium float64) models.Transaction {
+	tx := costBasisTx(tradeType, day, contracts, premium)
+	tx.Symbol = symbol
+	tx.Amount = contracts * premium * models.DefaultOptionMultiplier
+	if tradeType.IsOptionEvent() {
+		tx.Price, tx.Amount = 0, 0
+	}
+	return tx
+}
+
+// dividendTx pays a dividend of symbol on a date
+func dividendTx(symbol string, date time.Time, amount float64) models.Transaction {
+	return models.Transaction{
+		Symbol:          symbol,
+		TradeType:       types.TradeTypeDividend,
+		Amount:          amount,
+		TransactionDate: date,
+	}
+}
+
+// cashTx moves an amount of USD with a broker on a day of January 2024
+func cashTx(tradeType types.TradeType, broker string, day int, amount float64) models.Transaction {
+	return models.Transaction{
+		Symbol:          "USD",
+		TradeType:       tradeType,
+		Amount:          amount,
+		Currency:        "USD",
+		Broker:          broker,
+		TransactionDate: januaryDay(day),
+	}
+}
+
+// inAccount records a transaction in an account
+func inAccount(tx models.Transaction, accountID uuid.UUID) models.Transaction {
+	tx.AccountID = &accountID
+	return tx
+}
+
+// corporateAction builds an action of symbol effective on a day of January 2024
+func corporateAction(actionType models.CorporateActionType, symbol, newSymbol string, day int, from, to float64) models.CorporateAction {
+	return models.CorporateAction{
+		Symbol:        symbol,
+		ActionType:    actionType,
+		EffectiveDate: januaryDay(day),
+		RatioFrom:     from,
+		RatioTo:       to,
+		NewSymbol:     newSymbol,
+	}
+}
+
+// assertClose fails the test when got is not within 1e-6 of want
+func assertClose(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	assertWithin(t, name, got, want, 1e-6)
+}
+
+// assertWithin fails the test when got is not within tolerance of want
+func assertWithin(t *testing.T, name string, got, want, tolerance float64) {
+	t.Helper()
+	if math.Abs(got-want) > tolerance {
+		t.Errorf("%s = %v, want %v", name, got, want)
+	}
+}
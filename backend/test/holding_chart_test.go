@@ -0,0 +1,81 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/provider"
+	"github.com/transaction-tracker/backend/internal/services"
+	"github.com/transaction-tracker/backend/internal/types"
+)
+
+func TestHoldingTimeline(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2024, 1, d, 12, 0, 0, 0, time.UTC)
+	}
+	dividend := dividendTx("AAPL", time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), 12)
+	transactions := []models.Transaction{
+		costBasisTx(types.TradeTypeBuy, 3, 10, 100),
+		costBasisTx(types.TradeTypeBuy, 5, 10, 120),
+		dividend,
+		costBasisTx(types.TradeTypeSell, 10, 5, 130),
+	}
+
+	prices := services.NewPriceHistory(day(1), day(12), func(symbol, from, to string) ([]provider.ClosePrice, error) {
+		return []provider.ClosePrice{
+			{Date: "2024-01-10", Price: 130},
+			{Date: "2024-01-05", Price: 125},
+			{Date: "2024-01-03", Price: 105},
+		}, nil
+	})
+	engine := services.NewCostBasisEngine(models.CostBasisFIFO, nil)
+	fx := services.NewFXConverter("USD", nil)
+
+	points, err := services.HoldingTimeline(engine, fx, prices, transactions, []time.Time{day(2), day(4), day(6), day(12)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(points) != 4 {
+		t.Fatalf("expected 4 points, got %d", len(points))
+	}
+
+	// Nothing held before the first buy
+	assertClose(t, "quantity before buying", points[0].Quantity, 0)
+	assertClose(t, "market value before buying", points[0].MarketValue, 0)
+
+	assertClose(t, "quantity after first buy", points[1].Quantity, 10)
+	assertClose(t, "price after first buy", points[1].Price, 105)
+	assertClose(t, "unrealized after first buy", points[1].UnrealizedGainLoss, 50)
+
+	assertClose(t, "cost basis after second buy", points[2].CostBasis, 2200)
+	assertClose(t, "market value after second buy", points[2].MarketValue, 2500)
+
+	// FIFO sells 5 of the shares bought at 100
+	assertClose(t, "quantity after sell", points[3].Quantity, 15)
+	assertClose(t, "realized after sell", points[3].RealizedGainLoss, 150)
+	assertClose(t, "unrealized after sell", points[3].UnrealizedGainLoss, 15*130-1700)
+	assertClose(t, "dividends after sell", points[3].DividendIncome, 12)
+}
+
+func TestHoldingMarkers(t *testing.T) {
+	transactions := []models.Transaction{
+		costBasisTx(types.TradeTypeSell, 10, 5, 130),
+		costBasisTx(types.TradeTypeBuy, 3, 10, 100),
+		dividendTx("AAPL", time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), 12),
+		costBasisTx(types.TradeTypeBuy, 20, 1, 140),
+	}
+
+	markers := services.HoldingMarkers(transactions, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))
+	if len(markers) != 3 {
+		t.Fatalf("expected 3 markers within the period, got %d", len(markers))
+	}
+
+	wantTypes := []types.TradeType{types.TradeTypeBuy, types.TradeTypeDividend, types.TradeTypeSell}
+	for i, marker := range markers {
+		if marker.TradeType != wantTypes[i] {
+			t.Errorf("marker %d: expected %s, got %s", i, wantTypes[i], marker.TradeType)
+		}
+	}
+	assertClose(t, "sell marker price", markers[2].Price, 130)
+}
@@ -26,6 +26,21 @@ func TestStandardDeviation(t *testing.T) {
 	}
 }
 
+func TestMeanAndCovariance(t *testing.T) {
+	x := []float64{1, 2, 3, 4}
+	y := []float64{2, 4, 6, 8}
+
+	if mean := utils.Mean(x); math.Abs(mean-2.5) > 1e-9 {
+		t.Errorf("Mean(%v) = %v, want 2.5", x, mean)
+	}
+	if covariance := utils.Covariance(x, y); math.Abs(covariance-2.5) > 1e-9 {
+		t.Errorf("Covariance(%v, %v) = %v, want 2.5", x, y, covariance)
+	}
+	if covariance := utils.Covariance(x, y[:3]); covariance != 0 {
+		t.Errorf("Covariance of unequal lengths = %v, want 0", covariance)
+	}
+}
+
 func TestXIRR(t *testing.T) {
 	// Example: invest -1000, after 1 year get 1100, IRR should be about 10%
 	cashFlows := []struct {
@@ -0,0 +1,188 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/services"
+	"github.com/transaction-tracker/backend/internal/types"
+)
+
+const (
+	aaplCall = "AAPL240119C00150000"
+	aaplPut  = "AAPL240119P00150000"
+)
+
+func TestParseOCCSymbol(t *testing.T) {
+	contract, ok := models.ParseOCCSymbol(aaplCall)
+	if !ok {
+		t.Fatalf("expected %s to parse", aaplCall)
+	}
+	if contract.Underlying != "AAPL" || contract.OptionType != models.OptionTypeCall {
+		t.Errorf("expected an AAPL call, got %+v", contract)
+	}
+	if !contract.Expiry.Equal(time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected expiry 2024-01-19, got %s", contract.Expiry)
+	}
+	assertClose(t, "strike", contract.Strike, 150)
+	assertClose(t, "multiplier", contract.Multiplier, 100)
+	if contract.OCCSymbol() != aaplCall {
+		t.Errorf("expected the symbol to round-trip, got %s", contract.OCCSymbol())
+	}
+
+	for _, symbol := range []string{"AAPL", "AAPL240119X00150000", "AAPL241319C00150000"} {
+		if _, ok := models.ParseOCCSymbol(symbol); ok {
+			t.Errorf("expected %s not to parse", symbol)
+		}
+	}
+}
+
+func TestCostBasisEngineOptionMultiplier(t *testing.T) {
+	adjusted := "AAPL1240119C00150000"
+	engine := services.NewCostBasisEngine(models.CostBasisFIFO, nil).WithOptionContracts([]models.OptionContract{
+		{Symbol: adjusted, Underlying: "AAPL", Strike: 150, OptionType: models.OptionTypeCall, Multiplier: 150},
+	})
+
+	assertClose(t, "standard contract", engine.Multiplier(aaplCall), 100)
+	assertClose(t, "adjusted contract", engine.Multiplier(adjusted), 150)
+	assertClose(t, "shares", engine.Multiplier("AAPL"), 1)
+}
+
+func TestCostBasisEngineOptionExpiration(t *testing.T) {
+	engine := services.NewCostBasisEngine(models.CostBasisFIFO, nil)
+
+	written := optionTx(types.TradeTypeSellToOpen, aaplCall, 2, 2, 1.5)
+	result := engine.Calculate([]models.Transaction{written})
+	assertClose(t, "written contracts", result.TotalQuantity(), -2)
+	assertClose(t, "premium per contract", result.UnitCost(), 150)
+
+	// Written contracts that expire keep the whole premium
+	expired := optionTx(types.TradeTypeExpiration, aaplCall, 19, 2, 0)
+	result = engine.Calculate([]models.Transaction{written, expired})
+	assertClose(t, "open contracts", result.TotalQuantity(), 0)
+	assertClose(t, "written gain", result.RealizedGainLoss(), 300)
+
+	// Bought contracts that expire lose it
+	bought := optionTx(types.TradeTypeBuyToOpen, aaplPut, 2, 1, 2)
+	result = engine.Calculate([]models.Transaction{bought, optionTx(types.TradeTypeExpiration, aaplPut, 19, 1, 0)})
+	assertClose(t, "bought loss", result.RealizedGainLoss(), -200)
+}
+
+func TestExpandAssignmentsCashSecuredPut(t *testing.T) {
+	engine := services.NewCostBasisEngine(models.CostBasisFIFO, nil)
+	written := optionTx(types.TradeTypeSellToOpen, aaplPut, 2, 1, 2)
+	assigned := optionTx(types.TradeTypeAssignment, aaplPut, 19, 1, 0)
+	assigned.Commission = 5
+
+	expanded := engine.ExpandAssignments([]models.Transaction{written, assigned})
+	if len(expanded) != 3 {
+		t.Fatalf("expected the assignment to deliver a trade of shares, got %+v", expanded)
+	}
+
+	asOf := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	grouped := engine.GroupBySymbol(expanded, asOf)
+
+	// The premium carries into the shares bought at the strike, so the option realizes nothing
+	option := engine.CalculateAt(grouped[aaplPut], asOf)
+	assertClose(t, "open contracts", option.TotalQuantity(), 0)
+	assertClose(t, "option gain", option.RealizedGainLoss(), 0)
+
+	shares := engine.CalculateAt(grouped["AAPL"], asOf)
+	assertClose(t, "shares", shares.TotalQuantity(), 100)
+	assertClose(t, "share cost", shares.TotalCost(), 15000-200+5)
+	if shares.OpenLots[0].TransactionID != assigned.TransactionID {
+		t.Errorf("expected the shares to be acquired by the assignment, got %+v", shares.OpenLots[0])
+	}
+
+	// Cash moves by the premium received and then the strike paid
+	var cash float64
+	for _, balance := range services.CashBalances(expanded, asOf) {
+		cash += balance.Balance
+	}
+	assertClose(t, "cash", cash, 200-15000-5)
+}
+
+func TestExpandAssignmentsCoveredCall(t *testing.T) {
+	engine := services.NewCostBasisEngine(models.CostBasisFIFO, nil)
+	transactions := engine.ExpandAssignments([]models.Transaction{
+		costBasisTx(types.TradeTypeBuy, 1, 100, 140),
+		optionTx(types.TradeTypeSellToOpen, aaplCall, 2, 1, 3),
+		optionTx(types.TradeTypeAssignment, aaplCall, 19, 1, 0),
+	})
+
+	asOf := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	shares := engine.CalculateAt(engine.GroupBySymbol(transactions, asOf)["AAPL"], asOf)
+
+	// The shares are called away at the strike plus the premium
+	assertClose(t, "shares", shares.TotalQuantity(), 0)
+	assertClose(t, "realized gain", shares.RealizedGainLoss(), 15000+300-14000)
+}
+
+func TestValidateOptionTrades(t *testing.T) {
+	engine := services.NewCostBasisEngine(models.CostBasisFIFO, nil)
+
+	mispriced := optionTx(types.TradeTypeSellToOpen, aaplCall, 2, 1, 3)
+	mispriced.Amount = 3
+	early := optionTx(types.TradeTypeExpiration, aaplCall, 18, 1, 0)
+
+	cases := []struct {
+		name    string
+		tx      models.Transaction
+		wantErr string
+	}{
+		{"option trade", optionTx(types.TradeTypeSellToOpen, aaplCall, 2, 1, 3), ""},
+		{"amount without the multiplier", mispriced, "multiplier"},
+		{"option trade type on shares", costBasisTx(types.TradeTypeBuyToOpen, 2, 1, 3), "not an option contract"},
+		{"share trade type on an option", optionTx(types.TradeTypeBuy, aaplCall, 2, 1, 3), "is an option contract"},
+		{"expiration before expiry", early, "cannot expire before 2024-01-19"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := services.ValidateOptionTrades(engine, []models.Transaction{c.tx})
+			if c.wantErr == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+				t.Errorf("expected an error about %q, got %v", c.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidatePositionsOptionAssignment(t *testing.T) {
+	engine := services.NewCostBasisEngine(models.CostBasisFIFO, nil)
+	written := optionTx(types.TradeTypeSellToOpen, aaplCall, 2, 1, 3)
+	assigned := optionTx(types.TradeTypeAssignment, aaplCall, 19, 1, 0)
+
+	// A call assigned without the shares to deliver
+	err := services.ValidatePositions(engine, []models.Transaction{written}, []models.Transaction{assigned})
+	if err == nil || !strings.Contains(err.Error(), "delivers 100 shares of AAPL") {
+		t.Errorf("expected the naked call assignment to be rejected, got %v", err)
+	}
+
+	// Covered by shares held, and then selling shares the put assignment delivered
+	existing := []models.Transaction{costBasisTx(types.TradeTypeBuy, 1, 100, 140), written}
+	if err := services.ValidatePositions(engine, existing, []models.Transaction{assigned}); err != nil {
+		t.Errorf("expected the covered call assignment to pass, got %v", err)
+	}
+
+	put := []models.Transaction{
+		optionTx(types.TradeTypeSellToOpen, aaplPut, 2, 1, 2),
+		optionTx(types.TradeTypeAssignment, aaplPut, 19, 1, 0),
+	}
+	if err := services.ValidatePositions(engine, put, []models.Transaction{costBasisTx(types.TradeTypeSell, 25, 100, 155)}); err != nil {
+		t.Errorf("expected the assigned shares to be sellable, got %v", err)
+	}
+
+	// Closing more contracts than were written
+	closing := optionTx(types.TradeTypeBuyToClose, aaplCall, 5, 2, 1)
+	if err := services.ValidatePositions(engine, []models.Transaction{written}, []models.Transaction{closing}); err == nil || !strings.Contains(err.Error(), "contracts written") {
+		t.Errorf("expected closing 2 of 1 written contracts to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/transaction-tracker/backend/internal/services"
+)
+
+func TestCumulativeTimeWeightedReturns(t *testing.T) {
+	points := []services.PerformancePoint{
+		// Starts empty, then funded with 1000
+		{Time: januaryDay(1), Value: 0},
+		{Time: januaryDay(2), Value: 1000, Flow: 1000},
+		// Grows 10%
+		{Time: januaryDay(10), Value: 1100},
+		// A well-timed deposit of 1100 does not count as performance
+		{Time: januaryDay(11), Value: 2200, Flow: 1100},
+		// Falls 5%, then 500 is withdrawn
+		{Time: januaryDay(20), Value: 1590, Flow: -500},
+	}
+
+	returns := services.CumulativeTimeWeightedReturns(points)
+	if len(returns) != len(points) {
+		t.Fatalf("expected %d returns, got %d", len(points), len(returns))
+	}
+
+	assertClose(t, "before funding", returns[1], 0)
+	assertClose(t, "after growth", returns[2], 10)
+	assertClose(t, "after deposit", returns[3], 10)
+	// 1.10 × 0.95 − 1
+	assertClose(t, "after withdrawal", returns[4], 4.5)
+}
+
+func TestCumulativeTimeWeightedReturnsEmpty(t *testing.T) {
+	if returns := services.CumulativeTimeWeightedReturns(nil); len(returns) != 0 {
+		t.Errorf("expected no returns, got %v", returns)
+	}
+}
+
+func TestReplayCashFlows(t *testing.T) {
+	points := []services.PerformancePoint{
+		{Time: januaryDay(1), Value: 1000},
+		{Time: januaryDay(5), Flow: 500},
+		{Time: januaryDay(10)},
+		{Time: januaryDay(15), Flow: -300},
+	}
+	// The benchmark has no price on day 10, so day 5's price carries forward
+	prices := []float64{100, 125, 0, 150}
+
+	values := services.ReplayCashFlows(points, prices)
+
+	// 10 units bought at the start, 4 more with the deposit, 2 sold with the withdrawal
+	assertClose(t, "start", values[0], 1000)
+	assertClose(t, "after deposit", values[1], 1750)
+	assertClose(t, "missing price", values[2], 1750)
+	assertClose(t, "after withdrawal", values[3], 1800)
+}
+
+func TestReplayCashFlowsWaitsForFirstPrice(t *testing.T) {
+	points := []services.PerformancePoint{
+		{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Value: 1000},
+		{Time: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Flow: 200},
+	}
+
+	values := services.ReplayCashFlows(points, []float64{0, 50})
+	assertClose(t, "unpriced start", values[0], 0)
+	assertClose(t, "invested at first price", values[1], 1200)
+}
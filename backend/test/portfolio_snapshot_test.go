@@ -0,0 +1,75 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/transaction-tracker/backend/internal/constants"
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/services"
+)
+
+func TestStaleSnapshotsFrom(t *testing.T) {
+	now := time.Date(2024, 3, 20, 15, 0, 0, 0, time.UTC)
+	firstTransaction := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2024, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	snapshot := func(date time.Time, currency string, updatedAt time.Time) *models.PortfolioSnapshot {
+		s := &models.PortfolioSnapshot{SnapshotDate: date, Currency: currency}
+		s.UpdatedAt = updatedAt
+		return s
+	}
+	provisional := day(3, 20).AddDate(0, 0, -constants.SnapshotProvisionalDays)
+
+	tests := []struct {
+		name     string
+		latest   *models.PortfolioSnapshot
+		wantFrom time.Time
+		wantOK   bool
+	}{
+		{"no snapshots", nil, day(1, 3), true},
+		{"base currency changed", snapshot(day(3, 20), "TWD", now), day(1, 3), true},
+		{"missing days", snapshot(day(3, 10), "USD", now), day(3, 11), true},
+		{"up to date", snapshot(day(3, 20), "USD", now.Add(-time.Minute)), time.Time{}, false},
+		{"provisional days refreshed", snapshot(day(3, 20), "USD", now.Add(-constants.SnapshotRefreshInterval-time.Minute)), provisional, true},
+		{"missing days before the provisional ones", snapshot(day(3, 1), "USD", now.Add(-time.Hour)), day(3, 2), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, ok := services.StaleSnapshotsFrom(tt.latest, firstTransaction, "USD", now)
+			if ok != tt.wantOK {
+				t.Fatalf("expected ok %v, got %v", tt.wantOK, ok)
+			}
+			if !from.Equal(tt.wantFrom) {
+				t.Errorf("expected snapshots stale from %s, got %s", tt.wantFrom.Format("2006-01-02"), from.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestSnapshotHoldingsRoundTrip(t *testing.T) {
+	holdings := models.SnapshotHoldings{
+		{Symbol: "AAPL", Quantity: 10, Price: 150, MarketValue: 1500, CostBasis: 1200},
+		{Symbol: "MSFT", Quantity: 2.5, Price: 400, MarketValue: 1000, CostBasis: 900},
+	}
+
+	value, err := holdings.Value()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var scanned models.SnapshotHoldings
+	if err := scanned.Scan([]byte(value.(string))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(scanned) != 2 || scanned[1] != holdings[1] {
+		t.Errorf("expected %v, got %v", holdings, scanned)
+	}
+
+	empty, err := models.SnapshotHoldings(nil).Value()
+	if err != nil || empty != "[]" {
+		t.Errorf("expected nil holdings to be stored as an empty list, got %v (%v)", empty, err)
+	}
+}
@@ -0,0 +1,153 @@
+package test
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/transaction-tracker/backend/internal/provider"
+	"github.com/transaction-tracker/backend/internal/services"
+)
+
+func TestPriceHistoryCloseAt(t *testing.T) {
+	var calls int32
+	var fromDate, toDate string
+	history := services.NewPriceHistory(
+		time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		func(symbol, from, to string) ([]provider.ClosePrice, error) {
+			atomic.AddInt32(&calls, 1)
+			fromDate, toDate = from, to
+			// Newest first, as the price service returns them
+			return []provider.ClosePrice{
+				{Date: "2024-01-12", Price: 110},
+				{Date: "2024-01-05", Price: 105},
+				{Date: "2024-01-02", Price: 100},
+			}, nil
+		},
+	)
+
+	tests := []struct {
+		name string
+		date time.Time
+		want float64
+	}{
+		{"exact date", time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), 105},
+		{"weekend carries the last close forward", time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC), 105},
+		{"intraday time uses the day's close", time.Date(2024, 1, 12, 15, 30, 0, 0, time.UTC), 110},
+		{"after the last close", time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), 110},
+		{"lookback close before the range", time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC), 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, err := history.CloseAt("AAPL", tt.date)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertClose(t, "price", price, tt.want)
+		})
+	}
+
+	if _, err := history.CloseAt("AAPL", time.Date(2023, 12, 29, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Error("expected an error before the first close")
+	}
+
+	if calls != 1 {
+		t.Errorf("expected one fetch for the whole range, got %d", calls)
+	}
+	if fromDate != "2023-12-26" || toDate != "2024-01-31" {
+		t.Errorf("expected range 2023-12-26 to 2024-01-31, got %s to %s", fromDate, toDate)
+	}
+}
+
+func TestPriceHistoryConcurrentLoadsFetchOnce(t *testing.T) {
+	var calls int32
+	history := services.NewPriceHistory(
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		func(symbol, from, to string) ([]provider.ClosePrice, error) {
+			atomic.AddInt32(&calls, 1)
+			if symbol == "MISSING" {
+				return nil, fmt.Errorf("symbol not found")
+			}
+			return []provider.ClosePrice{{Date: "2024-01-02", Price: 100}}, nil
+		},
+	)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := history.CloseAt("AAPL", time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if _, err := history.CloseAt("MISSING", time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)); err == nil {
+				t.Error("expected an error for a symbol without prices")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls != 2 {
+		t.Errorf("expected one fetch per symbol, got %d", calls)
+	}
+}
+
+func TestPriceHistoryPriceAtIntraday(t *testing.T) {
+	daily := func(symbol, from, to string) ([]provider.ClosePrice, error) {
+		return []provider.ClosePrice{
+			{Date: "2024-01-12", Price: 110},
+			{Date: "2024-01-11", Price: 108},
+		}, nil
+	}
+	open := time.Date(2024, 1, 12, 14, 30, 0, 0, time.UTC)
+	history := services.NewPriceHistory(
+		time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC),
+		daily,
+	).WithIntraday(15*time.Minute, func(symbol, from, to string) ([]provider.IntradayPrice, error) {
+		if symbol != "AAPL" {
+			return nil, fmt.Errorf("no intraday bars for %s", symbol)
+		}
+		// Newest first, keyed by the moment each bar opens
+		return []provider.IntradayPrice{
+			{Time: open.Add(15 * time.Minute), Price: 109.5},
+			{Time: open, Price: 109},
+		}, nil
+	})
+
+	tests := []struct {
+		name   string
+		symbol string
+		at     time.Time
+		want   float64
+	}{
+		{"before the first bar ends", "AAPL", open.Add(10 * time.Minute), 110},
+		{"first bar ended", "AAPL", open.Add(15 * time.Minute), 109},
+		{"between bars", "AAPL", open.Add(25 * time.Minute), 109},
+		{"after the last bar", "AAPL", open.Add(3 * time.Hour), 109.5},
+		{"no bars falls back to the daily close", "MSFT", open.Add(3 * time.Hour), 110},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, err := history.PriceAt(tt.symbol, tt.at)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertClose(t, "price", price, tt.want)
+		})
+	}
+
+	// Without intraday bars PriceAt is the day's close
+	dailyOnly := services.NewPriceHistory(time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC), daily)
+	price, err := dailyOnly.PriceAt("AAPL", open.Add(10*time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertClose(t, "daily price", price, 110)
+}
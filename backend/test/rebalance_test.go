@@ -0,0 +1,177 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/services"
+	"github.com/transaction-tracker/backend/internal/types"
+)
+
+func rebalanceTargets(targetType models.TargetType, weights ...interface{}) []models.TargetAllocation {
+	var targets []models.TargetAllocation
+	for i := 0; i < len(weights); i += 2 {
+		targets = append(targets, models.TargetAllocation{
+			TargetType: targetType,
+			TargetKey:  weights[i].(string),
+			Weight:     float64(weights[i+1].(int)),
+		})
+	}
+	return targets
+}
+
+func rebalanceHolding(symbol, assetClass string, quantity, price float64) services.RebalanceHolding {
+	return services.RebalanceHolding{
+		Symbol:      symbol,
+		AssetClass:  assetClass,
+		Quantity:    quantity,
+		Price:       price,
+		Currency:    "USD",
+		FXRate:      1,
+		MarketValue: quantity * price,
+	}
+}
+
+func assertOrders(t *testing.T, orders []models.RebalanceOrder, want []models.RebalanceOrder) {
+	t.Helper()
+	if len(orders) != len(want) {
+		t.Fatalf("expected %d orders, got %d: %+v", len(want), len(orders), orders)
+	}
+	for i := range want {
+		got := orders[i]
+		if got.Symbol != want[i].Symbol || got.Action != want[i].Action {
+			t.Errorf("order %d: expected %s %s, got %s %s", i, want[i].Action, want[i].Symbol, got.Action, got.Symbol)
+		}
+		assertClose(t, "order quantity", got.Quantity, want[i].Quantity)
+		assertClose(t, "order amount", got.EstimatedAmount, want[i].EstimatedAmount)
+	}
+}
+
+func findDrift(t *testing.T, drifts []models.RebalanceDrift, key string) models.RebalanceDrift {
+	t.Helper()
+	for _, d := range drifts {
+		if d.Key == key {
+			return d
+		}
+	}
+	t.Fatalf("expected a drift for %s in %+v", key, drifts)
+	return models.RebalanceDrift{}
+}
+
+func TestPlanRebalanceBySymbol(t *testing.T) {
+	targets := rebalanceTargets(models.TargetTypeSymbol, "AAPL", 60, "2330.TW", 40)
+	holdings := []services.RebalanceHolding{
+		rebalanceHolding("AAPL", "", 10, 100),
+		// Not held yet, quoted in TWD at 30 TWD per USD
+		{Symbol: "2330.TW", Price: 600, Currency: "TWD", FXRate: 1.0 / 30},
+	}
+
+	drifts, orders := services.PlanRebalance(targets, holdings, 0, services.RebalanceOptions{})
+
+	aapl := findDrift(t, drifts, "AAPL")
+	assertClose(t, "AAPL current weight", aapl.CurrentWeight, 100)
+	assertClose(t, "AAPL drift", aapl.Drift, 40)
+	assertClose(t, "AAPL trade value", aapl.TradeValue, -400)
+	assertClose(t, "cash target weight", findDrift(t, drifts, services.AllocationGroupCash).TargetWeight, 0)
+
+	// The AAPL proceeds buy 20 shares at 600 TWD, 20 USD each
+	assertOrders(t, orders, []models.RebalanceOrder{
+		{Symbol: "AAPL", Action: types.TradeTypeSell, Quantity: 4, EstimatedAmount: 400},
+		{Symbol: "2330.TW", Action: types.TradeTypeBuy, Quantity: 20, EstimatedAmount: 400},
+	})
+	if orders[1].Price != 600 || orders[1].Currency != "TWD" {
+		t.Errorf("expected the buy priced at 600 TWD, got %v %s", orders[1].Price, orders[1].Currency)
+	}
+}
+
+func TestPlanRebalanceNoSells(t *testing.T) {
+	targets := rebalanceTargets(models.TargetTypeSymbol, "AAPL", 60, "MSFT", 40)
+	holdings := []services.RebalanceHolding{
+		rebalanceHolding("AAPL", "", 10, 100),
+		rebalanceHolding("MSFT", "", 0, 50),
+	}
+
+	_, orders := services.PlanRebalance(targets, holdings, 0, services.RebalanceOptions{NoSells: true})
+	assertOrders(t, orders, nil)
+
+	// Buys are limited to the available cash: 200 of the 480 MSFT is short of its target
+	_, orders = services.PlanRebalance(targets, holdings, 200, services.RebalanceOptions{NoSells: true})
+	assertOrders(t, orders, []models.RebalanceOrder{
+		{Symbol: "MSFT", Action: types.TradeTypeBuy, Quantity: 4, EstimatedAmount: 200},
+	})
+}
+
+func TestPlanRebalanceKeepsCashTarget(t *testing.T) {
+	// 10% is left unassigned and so targeted as cash
+	targets := rebalanceTargets(models.TargetTypeSymbol, "AAPL", 90)
+	holdings := []services.RebalanceHolding{rebalanceHolding("AAPL", "", 5, 100)}
+
+	drifts, orders := services.PlanRebalance(targets, holdings, 500, services.RebalanceOptions{})
+
+	cash := findDrift(t, drifts, services.AllocationGroupCash)
+	assertClose(t, "cash weight", cash.CurrentWeight, 50)
+	assertClose(t, "cash target weight", cash.TargetWeight, 10)
+	assertOrders(t, orders, []models.RebalanceOrder{
+		{Symbol: "AAPL", Action: types.TradeTypeBuy, Quantity: 4, EstimatedAmount: 400},
+	})
+}
+
+func TestPlanRebalanceMinimumTradeValue(t *testing.T) {
+	targets := rebalanceTargets(models.TargetTypeSymbol, "AAPL", 50, "MSFT", 50)
+	holdings := []services.RebalanceHolding{
+		rebalanceHolding("AAPL", "", 51, 10),
+		rebalanceHolding("MSFT", "", 49, 10),
+	}
+
+	_, orders := services.PlanRebalance(targets, holdings, 0, services.RebalanceOptions{MinTradeValue: 50})
+	assertOrders(t, orders, nil)
+
+	_, orders = services.PlanRebalance(targets, holdings, 0, services.RebalanceOptions{})
+	assertOrders(t, orders, []models.RebalanceOrder{
+		{Symbol: "AAPL", Action: types.TradeTypeSell, Quantity: 1, EstimatedAmount: 10},
+		{Symbol: "MSFT", Action: types.TradeTypeBuy, Quantity: 1, EstimatedAmount: 10},
+	})
+}
+
+func TestPlanRebalanceSellsUntargetedHoldings(t *testing.T) {
+	targets := rebalanceTargets(models.TargetTypeSymbol, "AAPL", 100)
+	holdings := []services.RebalanceHolding{
+		rebalanceHolding("AAPL", "", 10, 100),
+		rebalanceHolding("XYZ", "", 2.5, 40),
+	}
+
+	_, orders := services.PlanRebalance(targets, holdings, 0, services.RebalanceOptions{})
+
+	// The fractional shares are sold along with the rest of the position
+	assertOrders(t, orders, []models.RebalanceOrder{
+		{Symbol: "XYZ", Action: types.TradeTypeSell, Quantity: 2.5, EstimatedAmount: 100},
+		{Symbol: "AAPL", Action: types.TradeTypeBuy, Quantity: 1, EstimatedAmount: 100},
+	})
+}
+
+func TestPlanRebalanceByAssetClass(t *testing.T) {
+	targets := rebalanceTargets(models.TargetTypeAssetClass, "equity", 50, "bond", 30, "commodity", 20)
+	holdings := []services.RebalanceHolding{
+		rebalanceHolding("VTI", "equity", 8, 100),
+		rebalanceHolding("VEA", "equity", 4, 50),
+		rebalanceHolding("BND", "bond", 2, 100),
+	}
+
+	drifts, orders := services.PlanRebalance(targets, holdings, 0, services.RebalanceOptions{})
+
+	// Equity is 400 over its 600 target, sold across its symbols in proportion to their value
+	equity := findDrift(t, drifts, "equity")
+	assertClose(t, "equity weight", equity.CurrentWeight, 83.3333)
+	assertClose(t, "equity trade value", equity.TradeValue, -400)
+
+	// Nothing is held in commodities to buy more of
+	commodity := findDrift(t, drifts, "commodity")
+	assertClose(t, "commodity drift", commodity.Drift, -20)
+	assertClose(t, "commodity trade value", commodity.TradeValue, 240)
+
+	assertOrders(t, orders, []models.RebalanceOrder{
+		{Symbol: "VTI", Action: types.TradeTypeSell, Quantity: 3, EstimatedAmount: 300},
+		{Symbol: "VEA", Action: types.TradeTypeSell, Quantity: 1, EstimatedAmount: 50},
+		{Symbol: "BND", Action: types.TradeTypeBuy, Quantity: 1, EstimatedAmount: 100},
+	})
+}
@@ -0,0 +1,82 @@
+package test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/transaction-tracker/backend/internal/constants"
+	"github.com/transaction-tracker/backend/internal/services"
+)
+
+func TestCalculateRiskMetricsDrawdown(t *testing.T) {
+	// Growth of 1 unit: 1.00, 1.10, 1.21, 0.968, 1.05, 0.847
+	cumulative := []float64{0, 10, 21, -3.2, 5, -15.3}
+
+	metrics := services.CalculateRiskMetrics(cumulative, 0)
+	if metrics.Observations != 5 {
+		t.Fatalf("expected 5 observations, got %d", metrics.Observations)
+	}
+
+	// From the 1.21 peak down to 0.847
+	assertClose(t, "max drawdown", metrics.MaxDrawdown, (0.847/1.21-1)*100)
+	if metrics.PeakIndex != 2 || metrics.TroughIndex != 5 {
+		t.Errorf("expected drawdown from point 2 to 5, got %d to %d", metrics.PeakIndex, metrics.TroughIndex)
+	}
+}
+
+func TestCalculateRiskMetricsRatios(t *testing.T) {
+	// Alternating +2% and −1% days
+	cumulative := []float64{0}
+	growth := 1.0
+	for i := 0; i < 10; i++ {
+		if i%2 == 0 {
+			growth *= 1.02
+		} else {
+			growth *= 0.99
+		}
+		cumulative = append(cumulative, (growth-1)*100)
+	}
+
+	metrics := services.CalculateRiskMetrics(cumulative, 0)
+
+	days := float64(constants.TradingDaysPerYear)
+	// Population standard deviation of the daily returns is 1.5%
+	assertClose(t, "annualized volatility", metrics.AnnualizedVolatility, 1.5*math.Sqrt(days))
+	assertClose(t, "sharpe ratio", metrics.SharpeRatio, 0.005/0.015*math.Sqrt(days))
+	// Downside deviation: sqrt(5 × 0.01² / 10)
+	assertClose(t, "sortino ratio", metrics.SortinoRatio, 0.005/math.Sqrt(0.00005)*math.Sqrt(days))
+	assertClose(t, "annualized return", metrics.AnnualizedReturn, (math.Pow(growth, days/10)-1)*100)
+
+	// A risk-free rate lowers both ratios
+	withRate := services.CalculateRiskMetrics(cumulative, 5)
+	if withRate.SharpeRatio >= metrics.SharpeRatio || withRate.SortinoRatio >= metrics.SortinoRatio {
+		t.Errorf("expected lower ratios with a risk-free rate, got %+v", withRate)
+	}
+}
+
+func TestCalculateRiskMetricsWithoutReturns(t *testing.T) {
+	metrics := services.CalculateRiskMetrics([]float64{0}, 0)
+	if metrics.Observations != 0 || metrics.SharpeRatio != 0 || metrics.PeakIndex != -1 {
+		t.Errorf("expected empty metrics, got %+v", metrics)
+	}
+}
+
+func TestCalculateBeta(t *testing.T) {
+	benchmark := []float64{0, 1, -1, 2, 0}
+	// Moves twice as much as the benchmark each period
+	portfolio := []float64{0}
+	growth := 1.0
+	for i := 1; i < len(benchmark); i++ {
+		benchmarkReturn := (1+benchmark[i]/100)/(1+benchmark[i-1]/100) - 1
+		growth *= 1 + 2*benchmarkReturn
+		portfolio = append(portfolio, (growth-1)*100)
+	}
+
+	beta, correlation := services.CalculateBeta(portfolio, benchmark)
+	assertClose(t, "beta", beta, 2)
+	assertClose(t, "correlation", correlation, 1)
+
+	if beta, correlation := services.CalculateBeta(portfolio, benchmark[:2]); beta != 0 || correlation != 0 {
+		t.Errorf("expected no beta for misaligned series, got %v and %v", beta, correlation)
+	}
+}
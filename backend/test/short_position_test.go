@@ -0,0 +1,162 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/services"
+	"github.com/transaction-tracker/backend/internal/types"
+)
+
+func TestCostBasisEngineShortPosition(t *testing.T) {
+	short := costBasisTx(types.TradeTypeSellShort, 1, 10, 100)
+	cover := costBasisTx(types.TradeTypeBuyToCover, 5, 4, 80)
+
+	result := services.NewCostBasisEngine(models.CostBasisFIFO, nil).Calculate([]models.Transaction{short, cover})
+
+	// 6 shares remain sold short at 100, a 600 credit
+	assertClose(t, "quantity", result.TotalQuantity(), -6)
+	assertClose(t, "cost", result.TotalCost(), -600)
+	assertClose(t, "unit cost", result.UnitCost(), 100)
+
+	// Covering 4 shares at 80 that were sold at 100
+	assertClose(t, "realized gain", result.RealizedGainLoss(), 80)
+	if len(result.ClosedLots) != 1 || !result.ClosedLots[0].Short {
+		t.Fatalf("expected one short closed lot, got %+v", result.ClosedLots)
+	}
+	closed := result.ClosedLots[0]
+	if closed.SellTransactionID != short.TransactionID || closed.BuyTransactionID != cover.TransactionID {
+		t.Errorf("expected the short sale as the sell and the cover as the buy, got %+v", closed)
+	}
+
+	// At 120 the open short has lost 20 a share
+	marketValue := result.TotalQuantity() * 120
+	assertClose(t, "unrealized loss", marketValue-result.TotalCost(), -120)
+}
+
+func TestCostBasisEngineShortsAndLongsStayApart(t *testing.T) {
+	buy := costBasisTx(types.TradeTypeBuy, 1, 5, 100)
+	short := costBasisTx(types.TradeTypeSellShort, 2, 3, 110)
+	sell := costBasisTx(types.TradeTypeSell, 3, 5, 120)
+	overCover := costBasisTx(types.TradeTypeBuyToCover, 4, 4, 90)
+
+	result := services.NewCostBasisEngine(models.CostBasisFIFO, nil).Calculate([]models.Transaction{buy, short, sell, overCover})
+
+	// The sale closes the long lot only, and the cover the short lot only
+	assertClose(t, "quantity", result.TotalQuantity(), 0)
+	assertClose(t, "realized gain", result.RealizedGainLoss(), 5*20+3*20)
+	assertClose(t, "unmatched", result.UnmatchedQuantity, 1)
+	assertClose(t, "unmatched cover", result.UnmatchedTrades[overCover.TransactionID], 1)
+}
+
+func TestBuildTaxLedgerShortLots(t *testing.T) {
+	short := costBasisTx(types.TradeTypeSellShort, 1, 10, 100)
+	short.TransactionDate = time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	cover := costBasisTx(types.TradeTypeBuyToCover, 10, 10, 60)
+
+	engine := services.NewCostBasisEngine(models.CostBasisFIFO, nil)
+	lots, disposals := services.BuildTaxLedger(uuid.New(), engine, []models.Transaction{short, cover})
+
+	if len(lots) != 1 || !lots[0].Short || lots[0].BuyTransactionID != short.TransactionID {
+		t.Fatalf("expected one short lot opened by the short sale, got %+v", lots)
+	}
+	assertClose(t, "lot basis", lots[0].CostBasis, 1000)
+	assertClose(t, "remaining quantity", lots[0].RemainingQuantity, 0)
+
+	if len(disposals) != 1 {
+		t.Fatalf("expected 1 disposal, got %d", len(disposals))
+	}
+	disposal := disposals[0]
+	if disposal.TaxLotID != lots[0].ID || !disposal.Short {
+		t.Errorf("expected the cover to dispose of the short lot, got %+v", disposal)
+	}
+	// Held open for more than a year, but short sale gains are short term
+	if disposal.HoldingPeriod != models.HoldingPeriodShortTerm {
+		t.Errorf("expected a short term disposal, got %s", disposal.HoldingPeriod)
+	}
+	assertClose(t, "gain", disposal.GainLoss, 400)
+}
+
+func TestValidatePositions(t *testing.T) {
+	engine := services.NewCostBasisEngine(models.CostBasisFIFO, nil)
+	existing := []models.Transaction{costBasisTx(types.TradeTypeBuy, 1, 5, 100)}
+
+	cases := []struct {
+		name    string
+		changed models.Transaction
+		wantErr string
+	}{
+		{"sale within the shares held", costBasisTx(types.TradeTypeSell, 2, 5, 110), ""},
+		{"accidental oversell", costBasisTx(types.TradeTypeSell, 2, 8, 110), "record it as Sell Short"},
+		{"intentional short", costBasisTx(types.TradeTypeSellShort, 2, 8, 110), ""},
+		{"cover without a short", costBasisTx(types.TradeTypeBuyToCover, 2, 1, 90), "exceeds the shares sold short"},
+		{"sale on the day of the buy", costBasisTx(types.TradeTypeSell, 1, 1, 90), ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			changed := c.changed
+			changed.TransactionID = uuid.Nil
+			err := services.ValidatePositions(engine, existing, []models.Transaction{changed})
+			if c.wantErr == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+				t.Errorf("expected an error about %q, got %v", c.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidatePositionsEditingBuy(t *testing.T) {
+	engine := services.NewCostBasisEngine(models.CostBasisFIFO, nil)
+	buy := costBasisTx(types.TradeTypeBuy, 1, 5, 100)
+	sell := costBasisTx(types.TradeTypeSell, 2, 5, 110)
+	existing := []models.Transaction{buy, sell}
+
+	// Shrinking the buy would leave the later sale oversold
+	edited := buy
+	edited.Quantity, edited.Amount = 3, 300
+	if err := services.ValidatePositions(engine, existing, []models.Transaction{edited}); err == nil {
+		t.Error("expected shrinking the buy below the later sale to be rejected")
+	}
+
+	// Records that were oversold already can still be edited
+	legacy := []models.Transaction{edited, sell}
+	notes := edited
+	notes.UserNotes = "partial fill"
+	if err := services.ValidatePositions(engine, legacy, []models.Transaction{notes}); err != nil {
+		t.Errorf("expected an edit that oversells no further to pass, got %v", err)
+	}
+}
+
+func TestValidateRemainingPositionsDeletingBuy(t *testing.T) {
+	engine := services.NewCostBasisEngine(models.CostBasisFIFO, nil)
+	first := costBasisTx(types.TradeTypeBuy, 1, 5, 100)
+	second := costBasisTx(types.TradeTypeBuy, 2, 5, 105)
+	sell := costBasisTx(types.TradeTypeSell, 3, 5, 110)
+	existing := []models.Transaction{first, second, sell}
+
+	// Either buy alone covers the sale
+	if err := services.ValidateRemainingPositions(engine, existing, []uuid.UUID{first.TransactionID}); err != nil {
+		t.Errorf("expected deleting one of two buys to pass, got %v", err)
+	}
+
+	// Deleting both leaves the sale oversold
+	err := services.ValidateRemainingPositions(engine, existing, []uuid.UUID{first.TransactionID, second.TransactionID})
+	if err == nil || !strings.Contains(err.Error(), "invalid position") || !strings.Contains(err.Error(), "exceeds the shares held by 5") {
+		t.Errorf("expected deleting both buys to be rejected, got %v", err)
+	}
+
+	// Deleting the sale along with the buys leaves nothing to oversell
+	all := []uuid.UUID{first.TransactionID, second.TransactionID, sell.TransactionID}
+	if err := services.ValidateRemainingPositions(engine, existing, all); err != nil {
+		t.Errorf("expected deleting every transaction to pass, got %v", err)
+	}
+}
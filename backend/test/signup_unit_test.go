@@ -48,6 +48,11 @@ func (m *MockUserRepository) Update(user *models.User) error {
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) UpdateFields(userID uuid.UUID, updates map[string]interface{}) error {
+	args := m.Called(userID, updates)
+	return args.Error(0)
+}
+
 func (m *MockUserRepository) Delete(id uint) error {
 	args := m.Called(id)
 	return args.Error(0)
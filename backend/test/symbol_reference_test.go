@@ -0,0 +1,208 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/constants"
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/provider"
+	"github.com/transaction-tracker/backend/internal/services"
+	"github.com/transaction-tracker/backend/internal/types"
+)
+
+// recordedMetadata is a SymbolMetadataSource holding the metadata users recorded
+type recordedMetadata []models.SymbolMetadata
+
+func (r recordedMetadata) GetBySymbols(symbols []string) ([]models.SymbolMetadata, error) {
+	var found []models.SymbolMetadata
+	for _, m := range r {
+		for _, symbol := range symbols {
+			if m.Symbol == symbol {
+				found = append(found, m)
+			}
+		}
+	}
+	return found, nil
+}
+
+// symbolMaster is a SymbolInfoLookup knowing a fixed set of symbols, or failing every lookup
+type symbolMaster struct {
+	symbols map[string]provider.SymbolInfo
+	lookups []string
+	err     error
+}
+
+func (m *symbolMaster) GetSymbolInfo(ctx context.Context, symbol string) (*provider.SymbolInfo, error) {
+	m.lookups = append(m.lookups, symbol)
+	if m.err != nil {
+		return nil, m.err
+	}
+	info, ok := m.symbols[symbol]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", provider.ErrNotFound, symbol)
+	}
+	return &info, nil
+}
+
+func newSymbolMaster() *symbolMaster {
+	return &symbolMaster{symbols: map[string]provider.SymbolInfo{
+		"AAPL": {Symbol: "AAPL", Name: "Apple Inc", AssetClass: "equity", Country: "US", Sector: "Information Technology", Industry: "Technology Hardware"},
+		"BND":  {Symbol: "BND", Name: "Vanguard Total Bond Market ETF", AssetClass: "bond", Country: "US"},
+	}}
+}
+
+func TestSymbolReferenceMergesRecordedMetadata(t *testing.T) {
+	master := newSymbolMaster()
+	recorded := recordedMetadata{{Symbol: "AAPL", Sector: "Technology"}, {Symbol: "PRIVATE", Name: "Private Holding"}}
+	service := services.NewSymbolReferenceService(recorded, master)
+
+	metadata, err := service.GetBySymbols([]string{"AAPL", "BND", "PRIVATE", "NOPE", "AAPL240119C00150000"})
+	if err != nil {
+		t.Fatalf("GetBySymbols failed: %v", err)
+	}
+	if len(metadata) != 3 {
+		t.Fatalf("expected metadata for AAPL, BND and PRIVATE, got %+v", metadata)
+	}
+
+	// Recorded fields win; the symbol master fills in the rest
+	aapl := metadata[0]
+	if aapl.Symbol != "AAPL" || aapl.Sector != "Technology" || aapl.Name != "Apple Inc" || aapl.Industry != "Technology Hardware" {
+		t.Errorf("unexpected AAPL metadata: %+v", aapl)
+	}
+	if bnd := metadata[1]; bnd.AssetClass != "bond" || bnd.Name != "Vanguard Total Bond Market ETF" {
+		t.Errorf("unexpected BND metadata: %+v", bnd)
+	}
+	if private := metadata[2]; private.Name != "Private Holding" {
+		t.Errorf("unexpected PRIVATE metadata: %+v", private)
+	}
+
+	// Option contracts are not looked up, and found symbols are remembered
+	if _, err := service.GetBySymbols([]string{"AAPL"}); err != nil {
+		t.Fatalf("GetBySymbols failed: %v", err)
+	}
+	if strings.Join(master.lookups, ",") != "AAPL,BND,PRIVATE,NOPE" {
+		t.Errorf("unexpected lookups: %v", master.lookups)
+	}
+}
+
+func TestSymbolReferenceWithoutPriceService(t *testing.T) {
+	master := &symbolMaster{err: errors.New("price service unavailable")}
+	service := services.NewSymbolReferenceService(recordedMetadata{{Symbol: "AAPL", Sector: "Technology"}}, master)
+
+	// Recorded metadata is still returned, after a single failed lookup
+	metadata, err := service.GetBySymbols([]string{"AAPL", "BND"})
+	if err != nil {
+		t.Fatalf("GetBySymbols failed: %v", err)
+	}
+	if len(metadata) != 1 || metadata[0].Sector != "Technology" {
+		t.Errorf("unexpected metadata: %+v", metadata)
+	}
+	if len(master.lookups) != 1 {
+		t.Errorf("expected one lookup, got %v", master.lookups)
+	}
+
+	// Transactions are let through while the symbol master cannot be reached
+	if err := service.ValidateTransactions(uuid.New(), []models.Transaction{costBasisTx(types.TradeTypeBuy, 2, 10, 100)}); err != nil {
+		t.Errorf("expected transactions to be accepted, got %v", err)
+	}
+}
+
+func TestSymbolReferenceValidateTransactions(t *testing.T) {
+	service := services.NewSymbolReferenceService(recordedMetadata{{Symbol: "PRIVATE", Name: "Private Holding"}}, newSymbolMaster())
+	userID := uuid.New()
+
+	withSymbol := func(tx models.Transaction, symbol string) models.Transaction {
+		tx.Symbol = symbol
+		return tx
+	}
+	deposit := withSymbol(costBasisTx(types.TradeTypeDeposit, 2, 0, 0), "USD")
+	option := withSymbol(costBasisTx(types.TradeTypeBuyToOpen, 2, 1, 5), "AAPL240119C00150000")
+
+	valid := []models.Transaction{
+		costBasisTx(types.TradeTypeBuy, 2, 10, 100),
+		withSymbol(costBasisTx(types.TradeTypeBuy, 3, 10, 70), "PRIVATE"),
+		deposit,
+		option,
+	}
+	if err := service.ValidateTransactions(userID, valid); err != nil {
+		t.Errorf("expected known symbols to be accepted, got %v", err)
+	}
+
+	err := service.ValidateTransactions(userID, []models.Transaction{withSymbol(costBasisTx(types.TradeTypeBuy, 2, 10, 100), "APPL")})
+	if err == nil || !strings.Contains(err.Error(), "invalid symbol") {
+		t.Errorf("expected an invalid symbol error, got %v", err)
+	}
+}
+
+func TestSymbolReferenceValidatesQuoteSymbols(t *testing.T) {
+	master := newSymbolMaster()
+	master.symbols["VOD.L"] = provider.SymbolInfo{Symbol: "VOD.L", Name: "Vodafone Group", AssetClass: "equity", Country: "GB"}
+	service := services.NewSymbolReferenceService(recordedMetadata{}, master)
+
+	// VOD on the LSE is quoted as VOD.L, however it was recorded
+	onLSE := func(tx models.Transaction, symbol string) models.Transaction {
+		tx.Symbol, tx.Exchange = symbol, "LSE"
+		return tx
+	}
+	transactions := []models.Transaction{
+		onLSE(costBasisTx(types.TradeTypeBuy, 2, 10, 70), "VOD"),
+		onLSE(costBasisTx(types.TradeTypeBuy, 3, 10, 72), "VOD.L"),
+	}
+	if err := service.ValidateTransactions(uuid.New(), transactions); err != nil {
+		t.Errorf("expected VOD on the LSE to be accepted, got %v", err)
+	}
+	if strings.Join(master.lookups, ",") != "VOD.L" {
+		t.Errorf("expected a single lookup of VOD.L, got %v", master.lookups)
+	}
+}
+
+func TestSymbolReferenceForgetsSymbolsBeyondLimit(t *testing.T) {
+	master := &symbolMaster{symbols: make(map[string]provider.SymbolInfo)}
+	symbols := make([]string, constants.MaxKnownSymbols+1)
+	for i := range symbols {
+		symbols[i] = fmt.Sprintf("S%d", i)
+		master.symbols[symbols[i]] = provider.SymbolInfo{Symbol: symbols[i]}
+	}
+	service := services.NewSymbolReferenceService(recordedMetadata{}, master)
+
+	if _, err := service.GetBySymbols(symbols); err != nil {
+		t.Fatalf("GetBySymbols failed: %v", err)
+	}
+	master.lookups = nil
+
+	// Symbols forgotten to stay within the limit are looked up again, the rest are remembered
+	if _, err := service.GetBySymbols(symbols); err != nil {
+		t.Fatalf("GetBySymbols failed: %v", err)
+	}
+	if len(master.lookups) == 0 || len(master.lookups) == len(symbols) {
+		t.Errorf("expected some but not all symbols to be looked up again, got %d lookups", len(master.lookups))
+	}
+}
+
+func TestSymbolReferenceValidatesTaiwanTransactions(t *testing.T) {
+	master := newSymbolMaster()
+	master.symbols["2330.TW"] = provider.SymbolInfo{Symbol: "2330.TW", Name: "Taiwan Semiconductor Manufacturing", AssetClass: "equity", Country: "TW"}
+	service := services.NewSymbolReferenceService(recordedMetadata{}, master)
+
+	// 2330 is recorded as traded on TPE, and known to the symbol master as 2330.TW
+	onTPE := func(tx models.Transaction, symbol string) models.Transaction {
+		tx.Symbol, tx.Exchange, tx.Currency = symbol, "TPE", "TWD"
+		return tx
+	}
+	if err := service.ValidateTransactions(uuid.New(), []models.Transaction{onTPE(costBasisTx(types.TradeTypeBuy, 2, 1000, 580), "2330")}); err != nil {
+		t.Errorf("expected 2330 on TPE to be accepted, got %v", err)
+	}
+	if strings.Join(master.lookups, ",") != "2330.TW" {
+		t.Errorf("expected 2330 to be looked up as 2330.TW, got %v", master.lookups)
+	}
+
+	err := service.ValidateTransactions(uuid.New(), []models.Transaction{onTPE(costBasisTx(types.TradeTypeBuy, 2, 1000, 50), "9999")})
+	if err == nil || !strings.Contains(err.Error(), "9999 is not a known symbol") {
+		t.Errorf("expected 9999 on TPE to be rejected, got %v", err)
+	}
+}
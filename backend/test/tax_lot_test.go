@@ -0,0 +1,103 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/services"
+	"github.com/transaction-tracker/backend/internal/types"
+)
+
+func TestHoldingPeriodFor(t *testing.T) {
+	acquired := time.Date(2023, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		disposed time.Time
+		expected models.HoldingPeriod
+	}{
+		{time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC), models.HoldingPeriodShortTerm},
+		// Exactly one year is still short term; it must be more than one year
+		{time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), models.HoldingPeriodShortTerm},
+		{time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC), models.HoldingPeriodLongTerm},
+	}
+	for _, c := range cases {
+		if got := models.HoldingPeriodFor(acquired, c.disposed); got != c.expected {
+			t.Errorf("HoldingPeriodFor(%v) = %v, want %v", c.disposed, got, c.expected)
+		}
+	}
+}
+
+func TestBuildTaxLedger(t *testing.T) {
+	userID := uuid.New()
+	oldBuy := costBasisTx(types.TradeTypeBuy, 1, 10, 100)
+	oldBuy.TransactionDate = time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	newBuy := costBasisTx(types.TradeTypeBuy, 10, 10, 150)
+	sell := costBasisTx(types.TradeTypeSell, 20, 15, 200)
+
+	engine := services.NewCostBasisEngine(models.CostBasisFIFO, nil)
+	lots, disposals := services.BuildTaxLedger(userID, engine, []models.Transaction{sell, newBuy, oldBuy})
+
+	if len(lots) != 2 {
+		t.Fatalf("expected 2 tax lots, got %d", len(lots))
+	}
+	if lots[0].BuyTransactionID != oldBuy.TransactionID || lots[0].RemainingQuantity != 0 {
+		t.Errorf("expected the 2022 lot to be fully consumed, got %+v", lots[0])
+	}
+	assertClose(t, "remaining quantity", lots[1].RemainingQuantity, 5)
+	assertClose(t, "remaining cost basis", lots[1].RemainingCostBasis, 750)
+
+	if len(disposals) != 2 {
+		t.Fatalf("expected 2 disposals, got %d", len(disposals))
+	}
+	if disposals[0].TaxLotID != lots[0].ID || disposals[0].HoldingPeriod != models.HoldingPeriodLongTerm {
+		t.Errorf("unexpected first disposal: %+v", disposals[0])
+	}
+	if disposals[1].TaxLotID != lots[1].ID || disposals[1].HoldingPeriod != models.HoldingPeriodShortTerm {
+		t.Errorf("unexpected second disposal: %+v", disposals[1])
+	}
+
+	report := services.SummarizeRealizedGains(2024, "USD", engine.Method(), disposals)
+	assertClose(t, "long term gain", report.LongTerm.GainLoss, 2000-1000)
+	assertClose(t, "short term gain", report.ShortTerm.GainLoss, 1000-750)
+	assertClose(t, "total proceeds", report.Total.Proceeds, 3000)
+	if report.Total.Count != 2 {
+		t.Errorf("expected 2 disposals in total, got %d", report.Total.Count)
+	}
+}
+
+func TestMatchPersistedTaxLots(t *testing.T) {
+	userID := uuid.New()
+	engine := services.NewCostBasisEngine(models.CostBasisFIFO, nil)
+	firstBuy := costBasisTx(types.TradeTypeBuy, 1, 10, 100)
+	secondBuy := costBasisTx(types.TradeTypeBuy, 10, 10, 150)
+	persisted, _ := services.BuildTaxLedger(userID, engine, []models.Transaction{firstBuy, secondBuy})
+
+	// A sale added on day 20 consumes the first lot; it keeps its persisted row
+	sell := costBasisTx(types.TradeTypeSell, 20, 15, 200)
+	since := sell.TransactionDate
+	lots, disposals := services.BuildTaxLedger(userID, engine, []models.Transaction{firstBuy, secondBuy, sell})
+	if !services.MatchPersistedTaxLots(lots, disposals, persisted, since) {
+		t.Fatal("expected the lots before the sale to match the persisted ledger")
+	}
+	if lots[0].ID != persisted[0].ID || lots[1].ID != persisted[1].ID {
+		t.Errorf("expected the lots to keep their persisted IDs, got %v and %v", lots[0].ID, lots[1].ID)
+	}
+	if disposals[0].TaxLotID != persisted[0].ID || disposals[1].TaxLotID != persisted[1].ID {
+		t.Errorf("expected the disposals to point at the persisted lots, got %+v", disposals)
+	}
+
+	// A renamed holding changes lots before since, so the ledger is replaced whole
+	renamed := []models.CorporateAction{corporateAction(models.CorporateActionRename, "AAPL", "APPL", 15, 1, 1)}
+	lots, disposals = services.BuildTaxLedger(userID, engine.WithCorporateActions(renamed), []models.Transaction{firstBuy, secondBuy, sell})
+	if services.MatchPersistedTaxLots(lots, disposals, persisted, since) {
+		t.Error("expected a renamed holding not to match the persisted ledger")
+	}
+
+	// So does a lot missing from the persisted ledger
+	lots, disposals = services.BuildTaxLedger(userID, services.NewCostBasisEngine(models.CostBasisFIFO, nil), []models.Transaction{firstBuy, secondBuy, sell})
+	if services.MatchPersistedTaxLots(lots, disposals, persisted[:1], since) {
+		t.Error("expected a missing lot not to match the persisted ledger")
+	}
+}
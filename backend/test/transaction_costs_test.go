@@ -0,0 +1,77 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/services"
+	"github.com/transaction-tracker/backend/internal/types"
+)
+
+func TestTransactionNetAmount(t *testing.T) {
+	buy := costBasisTx(types.TradeTypeBuy, 1, 10, 100)
+	buy.Commission = 5
+	buy.Fee = 1
+	assertClose(t, "buy net amount", buy.NetAmount(), 1006)
+
+	sell := costBasisTx(types.TradeTypeSell, 2, 10, 100)
+	sell.Commission = 5
+	sell.Tax = 3
+	assertClose(t, "sell net amount", sell.NetAmount(), 992)
+	assertClose(t, "sell fees", sell.Fees(), 5)
+	assertClose(t, "sell taxes", sell.Taxes(), 3)
+}
+
+func TestCostBasisIncludesCommissionAndTax(t *testing.T) {
+	buy := costBasisTx(types.TradeTypeBuy, 1, 10, 100)
+	buy.Commission = 10
+	sell := costBasisTx(types.TradeTypeSell, 2, 5, 120)
+	sell.Commission = 4
+	sell.Tax = 2
+
+	engine := services.NewCostBasisEngine(models.CostBasisFIFO, nil)
+	result := engine.Calculate([]models.Transaction{buy, sell})
+
+	// Buy commission raises the unit cost to 101; sell costs reduce proceeds to 594
+	assertClose(t, "remaining cost", result.TotalCost(), 505)
+	if len(result.ClosedLots) != 1 {
+		t.Fatalf("expected 1 closed lot, got %d", len(result.ClosedLots))
+	}
+	assertClose(t, "cost basis", result.ClosedLots[0].CostBasis, 505)
+	assertClose(t, "proceeds", result.ClosedLots[0].Proceeds, 594)
+	assertClose(t, "realized gain", result.RealizedGainLoss(), 89)
+}
+
+func TestAggregateDividendIncomeWithholding(t *testing.T) {
+	dividend := dividendTx("AAPL", time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC), 100)
+	dividend.WithholdingTax = 30
+
+	income := services.AggregateDividendIncome([]models.Transaction{dividend}, "USD", models.DividendAggregationMonthly)
+	assertClose(t, "net income", income.TotalIncome, 70)
+	assertClose(t, "gross income", income.GrossIncome, 100)
+	assertClose(t, "withholding tax", income.WithholdingTax, 30)
+	if len(income.Periods) != 1 {
+		t.Fatalf("expected 1 period, got %d", len(income.Periods))
+	}
+	assertClose(t, "period net income", income.Periods[0].Income, 70)
+	assertClose(t, "period AAPL income", income.Periods[0].BySymbol["AAPL"], 70)
+}
+
+func TestFXConverterConvertsCosts(t *testing.T) {
+	calls := 0
+	fx := services.NewFXConverter("USD", twdToUSD(&calls))
+
+	sell := costBasisTx(types.TradeTypeSell, 2, 1000, 600)
+	sell.Currency = "TWD"
+	sell.Commission = 855
+	sell.Tax = 1800
+
+	converted, err := fx.ConvertTransactions([]models.Transaction{sell})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertClose(t, "converted commission", converted[0].Commission, 25.65)
+	assertClose(t, "converted tax", converted[0].Tax, 54)
+}
@@ -27,7 +27,7 @@ func setupTestTransactionsHandler(t *testing.T) (*handlers.TransactionsHandler,
 
 	// Create repositories and services
 	transactionRepo := repositories.NewTransactionRepository(db)
-	transactionService := services.NewTransactionService(transactionRepo)
+	transactionService := services.NewTransactionService(transactionRepo, repositories.NewAccountRepository(db), repositories.NewPortfolioRepository(db))
 
 	// Create transactions handler without AI client (extraction moved to separate handler)
 	transactionsHandler := handlers.NewTransactionsHandler(transactionService)
@@ -15,7 +15,10 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/transaction-tracker/backend/api/handlers"
 	"github.com/transaction-tracker/backend/internal/models"
+	"github.com/transaction-tracker/backend/internal/repositories"
+	"github.com/transaction-tracker/backend/internal/services"
 	"github.com/transaction-tracker/backend/internal/types"
+	"github.com/transaction-tracker/backend/internal/utils"
 	"gorm.io/gorm"
 )
 
@@ -356,3 +359,59 @@ func TestDeleteTransactionOwnership(t *testing.T) {
 		assert.Equal(t, "Transaction does not exist", response.Message)
 	})
 }
+
+// optionTradeValidator checks option trades against a fixed set of option contracts
+type optionTradeValidator struct {
+	engine *services.CostBasisEngine
+}
+
+func (v optionTradeValidator) ValidateTransactions(userID uuid.UUID, transactions []models.Transaction) error {
+	return services.ValidateOptionTrades(v.engine, transactions)
+}
+
+func TestUpdateOptionTradePrice(t *testing.T) {
+	db := utils.SetupTestDB(t)
+	transactionService := services.NewTransactionService(repositories.NewTransactionRepository(db), repositories.NewAccountRepository(db), repositories.NewPortfolioRepository(db))
+	transactionService.AddValidator(optionTradeValidator{engine: services.NewCostBasisEngine(models.CostBasisFIFO, nil)})
+
+	user, err := createTestUserWithUsername(db, "optiontrader", "optiontrader@example.com")
+	require.NoError(t, err)
+
+	// 2 contracts bought at 1.50
+	const symbol = "AAPL240119C00150000"
+	transaction := &models.Transaction{
+		UserID:          user.UserID,
+		Symbol:          symbol,
+		TradeType:       types.TradeTypeBuyToOpen,
+		Quantity:        2,
+		Price:           1.5,
+		Amount:          300,
+		Currency:        "USD",
+		TransactionDate: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+	require.NoError(t, db.Create(transaction).Error)
+
+	update := func(price, amount float64) error {
+		_, err := transactionService.UpdateTransaction(user.UserID, transaction.TransactionID, nil, nil, symbol, "", "", "USD",
+			"2024-01-02", string(types.TradeTypeBuyToOpen), 2, price, amount, models.TransactionCosts{}, "")
+		return err
+	}
+
+	// The edited price is checked against the edited amount, not the recorded price
+	assert.NoError(t, update(2, 400))
+	assert.ErrorContains(t, update(1.5, 400), "does not match")
+}
+
+func TestTransactionRequestBindsOptionEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Expirations and assignments carry no amount; validateTransaction checks it per trade type
+	body := `{"symbol": "AAPL240119C00150000", "currency": "USD", "transaction_date": "2024-01-19", "trade_type": "Expiration", "quantity": 2, "price": 0, "amount": 0}`
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPut, "/transaction-history/1", bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	var request handlers.TransactionRequest
+	assert.NoError(t, c.ShouldBindJSON(&request))
+	assert.Equal(t, types.TradeTypeExpiration, request.TradeType)
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/transaction-tracker/price_service/internal/cache"
+	"github.com/transaction-tracker/price_service/internal/models"
+	"github.com/transaction-tracker/price_service/internal/provider"
+)
+
+// currencyCodePattern matches ISO 4217 currency codes
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+type FXHandler struct {
+	cache    *cache.Service
+	provider provider.FXRateProvider
+}
+
+func NewFXHandler(cache *cache.Service, provider provider.FXRateProvider) *FXHandler {
+	return &FXHandler{
+		cache:    cache,
+		provider: provider,
+	}
+}
+
+// GetFXRates handles GET /api/v1/fx/rates
+func (h *FXHandler) GetFXRates(c *gin.Context) {
+	base := strings.TrimSpace(strings.ToUpper(c.Query("base")))
+	quote := strings.TrimSpace(strings.ToUpper(c.Query("quote")))
+	fromParam := strings.TrimSpace(c.Query("from"))
+	toParam := strings.TrimSpace(c.Query("to"))
+
+	if err := ValidateFXParameters(base, quote, fromParam, toParam); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    models.ErrInvalidInput,
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	rates, err := h.cache.GetFXRates(c.Request.Context(), base, quote)
+	if err != nil || rates == nil {
+		rates, err = h.provider.GetHistoricalFXRates(c.Request.Context(), base, quote)
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+				Success: false,
+				Error: models.ErrorDetail{
+					Code:    models.ErrServiceUnavailable,
+					Message: "failed to fetch FX rates",
+				},
+			})
+			return
+		}
+
+		if err := h.cache.SetFXRates(c.Request.Context(), base, quote, rates); err != nil {
+			log.Printf("error caching FX rates for %s/%s: %v", base, quote, err)
+		}
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success:   true,
+		Data:      FilterFXRatesByDateRange(rates, fromParam, toParam),
+		Timestamp: time.Now(),
+	})
+}
+
+// ValidateFXParameters validates the currency pair and optional date range of an FX rate query
+func ValidateFXParameters(base, quote, from, to string) error {
+	if !currencyCodePattern.MatchString(base) {
+		return fmt.Errorf("base must be a 3-letter currency code")
+	}
+	if !currencyCodePattern.MatchString(quote) {
+		return fmt.Errorf("quote must be a 3-letter currency code")
+	}
+	if base == quote {
+		return fmt.Errorf("base and quote currencies must differ")
+	}
+
+	if (from == "") != (to == "") {
+		return fmt.Errorf("both 'from' and 'to' parameters are required for date range queries")
+	}
+	if from == "" {
+		return nil
+	}
+
+	fromDate, err := time.Parse(DateFormat, from)
+	if err != nil {
+		return fmt.Errorf("invalid 'from' date: invalid date format, use YYYY-MM-DD")
+	}
+	toDate, err := time.Parse(DateFormat, to)
+	if err != nil {
+		return fmt.Errorf("invalid 'to' date: invalid date format, use YYYY-MM-DD")
+	}
+	if fromDate.After(toDate) {
+		return fmt.Errorf("'from' date must be earlier than or equal to 'to' date")
+	}
+
+	return nil
+}
+
+// FilterFXRatesByDateRange keeps the rates within the inclusive date range.
+// The latest rate before the range is kept as well, so callers can carry it
+// forward to dates in the range that fall on weekends or holidays.
+func FilterFXRatesByDateRange(data *models.CurrencyPairRates, from, to string) *models.CurrencyPairRates {
+	if from == "" || to == "" {
+		return data
+	}
+
+	filtered := &models.CurrencyPairRates{
+		Base:     data.Base,
+		Quote:    data.Quote,
+		Rates:    []models.FXRate{},
+		Provider: data.Provider,
+	}
+
+	// Rates are sorted newest to oldest and dates compare lexically
+	for _, rate := range data.Rates {
+		if rate.Date > to {
+			continue
+		}
+		filtered.Rates = append(filtered.Rates, rate)
+		if rate.Date < from {
+			break
+		}
+	}
+
+	return filtered
+}
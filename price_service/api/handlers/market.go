@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/transaction-tracker/price_service/internal/calendar"
+	"github.com/transaction-tracker/price_service/internal/models"
+)
+
+// maxCalendarYears limits the range of a market calendar request
+const maxCalendarYears = 10
+
+type MarketHandler struct{}
+
+func NewMarketHandler() *MarketHandler {
+	return &MarketHandler{}
+}
+
+// GetMarketCalendar handles GET /api/v1/market/calendar
+func (h *MarketHandler) GetMarketCalendar(c *gin.Context) {
+	marketCalendar, err := resolveCalendar(c.Query("exchange"), c.Query("symbol"))
+	if err == nil && marketCalendar == nil {
+		err = fmt.Errorf("exchange or symbol parameter is required")
+	}
+	if err != nil {
+		respondInvalidInput(c, err)
+		return
+	}
+
+	// The current year on the exchange by default
+	today := marketCalendar.Today(time.Now())
+	from := time.Date(today.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(today.Year(), time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	fromParam := strings.TrimSpace(c.Query("from"))
+	toParam := strings.TrimSpace(c.Query("to"))
+	if fromParam != "" || toParam != "" {
+		from, to, err = parseCalendarRange(fromParam, toParam)
+		if err != nil {
+			respondInvalidInput(c, err)
+			return
+		}
+	}
+
+	holidays := []models.MarketHoliday{}
+	for _, holiday := range marketCalendar.Holidays(from, to) {
+		holidays = append(holidays, models.MarketHoliday{Date: holiday.Date, Name: holiday.Name})
+	}
+	earlyCloses := []models.MarketEarlyClose{}
+	for _, earlyClose := range marketCalendar.EarlyCloses(from, to) {
+		earlyCloses = append(earlyCloses, models.MarketEarlyClose{
+			Date:  earlyClose.Date,
+			Name:  earlyClose.Name,
+			Close: formatTimeOfDay(earlyClose.Close),
+		})
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success: true,
+		Data: models.MarketCalendar{
+			Exchange:    marketCalendar.Code,
+			Name:        marketCalendar.Name,
+			Timezone:    marketCalendar.Location.String(),
+			Session:     marketSession(marketCalendar.Session),
+			From:        from.Format(DateFormat),
+			To:          to.Format(DateFormat),
+			Holidays:    holidays,
+			EarlyCloses: earlyCloses,
+		},
+		Timestamp: time.Now(),
+	})
+}
+
+// GetMarketStatus handles GET /api/v1/market/status. Without an exchange or symbol the status of
+// every supported exchange is returned.
+func (h *MarketHandler) GetMarketStatus(c *gin.Context) {
+	marketCalendar, err := resolveCalendar(c.Query("exchange"), c.Query("symbol"))
+	if err != nil {
+		respondInvalidInput(c, err)
+		return
+	}
+
+	now := time.Now()
+	if marketCalendar != nil {
+		c.JSON(http.StatusOK, models.SuccessResponse{
+			Success:   true,
+			Data:      MarketStatusAt(marketCalendar, now),
+			Timestamp: now,
+		})
+		return
+	}
+
+	statuses := make([]models.MarketStatus, 0, len(calendar.Exchanges()))
+	for _, code := range calendar.Exchanges() {
+		exchangeCalendar, _ := calendar.Get(code)
+		statuses = append(statuses, MarketStatusAt(exchangeCalendar, now))
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success:   true,
+		Data:      statuses,
+		Timestamp: now,
+	})
+}
+
+// MarketStatusAt describes whether an exchange is trading at a moment
+func MarketStatusAt(marketCalendar *calendar.Calendar, now time.Time) models.MarketStatus {
+	status := marketCalendar.Status(now)
+	result := models.MarketStatus{
+		Exchange:  marketCalendar.Code,
+		Name:      marketCalendar.Name,
+		Timezone:  marketCalendar.Location.String(),
+		IsOpen:    status.Open,
+		LocalTime: now.In(marketCalendar.Location),
+		NextOpen:  status.NextOpen,
+		NextClose: status.NextClose,
+	}
+	if session, ok := marketCalendar.SessionOn(marketCalendar.Today(now)); ok {
+		todaySession := marketSession(session)
+		result.Session = &todaySession
+	}
+	return result
+}
+
+// resolveCalendar returns the calendar of an exchange, or of the exchange a symbol trades on.
+// Neither being given returns no calendar.
+func resolveCalendar(exchange, symbol string) (*calendar.Calendar, error) {
+	exchange = strings.TrimSpace(exchange)
+	symbol = strings.TrimSpace(strings.ToUpper(symbol))
+
+	switch {
+	case exchange != "" && symbol != "":
+		return nil, fmt.Errorf("exchange and symbol cannot be used together")
+	case exchange != "":
+		marketCalendar, ok := calendar.Get(exchange)
+		if !ok {
+			return nil, fmt.Errorf("unsupported exchange %s, must be one of: %s", exchange, strings.Join(calendar.Exchanges(), ", "))
+		}
+		return marketCalendar, nil
+	case symbol != "":
+		if !symbolPattern.MatchString(symbol) {
+			return nil, fmt.Errorf("invalid symbol")
+		}
+		return calendar.ForSymbol(symbol), nil
+	}
+	return nil, nil
+}
+
+// parseCalendarRange parses the from and to dates of a market calendar request
+func parseCalendarRange(fromParam, toParam string) (time.Time, time.Time, error) {
+	if fromParam == "" || toParam == "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("from and to parameters must be used together")
+	}
+	from, err := time.Parse(DateFormat, fromParam)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid from date format, expected YYYY-MM-DD")
+	}
+	to, err := time.Parse(DateFormat, toParam)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid to date format, expected YYYY-MM-DD")
+	}
+	if from.After(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("from date cannot be after to date")
+	}
+	if to.After(from.AddDate(maxCalendarYears, 0, 0)) {
+		return time.Time{}, time.Time{}, fmt.Errorf("date range cannot exceed %d years", maxCalendarYears)
+	}
+	return from, to, nil
+}
+
+func marketSession(session calendar.Session) models.MarketSession {
+	return models.MarketSession{
+		Open:  formatTimeOfDay(session.Open),
+		Close: formatTimeOfDay(session.Close),
+	}
+}
+
+// formatTimeOfDay formats an offset from midnight as HH:MM
+func formatTimeOfDay(offset time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(offset.Hours()), int(offset.Minutes())%60)
+}
+
+func respondInvalidInput(c *gin.Context, err error) {
+	c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		Success: false,
+		Error: models.ErrorDetail{
+			Code:    models.ErrInvalidInput,
+			Message: err.Error(),
+		},
+	})
+}
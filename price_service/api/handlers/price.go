@@ -9,6 +9,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/transaction-tracker/price_service/internal/cache"
+	"github.com/transaction-tracker/price_service/internal/calendar"
 	"github.com/transaction-tracker/price_service/internal/config"
 	"github.com/transaction-tracker/price_service/internal/models"
 	"github.com/transaction-tracker/price_service/internal/provider"
@@ -162,6 +163,12 @@ func (h *PriceHandler) GetHistoricalPrices(c *gin.Context) {
 		return
 	}
 
+	// Intraday bars, over the whole fetched window or the days the date parameters select
+	if models.Resolution(strings.TrimSpace(c.Query("resolution"))) == models.ResolutionIntraday {
+		h.handleIntradayQuery(c, symbol, dateParam, fromParam, toParam)
+		return
+	}
+
 	// Handle single date query
 	if dateParam != "" {
 		h.handleSingleDateQuery(c, symbol, dateParam)
@@ -194,20 +201,20 @@ func (h *PriceHandler) handleSingleDateQuery(c *gin.Context, symbol, dateParam s
 
 	// Adjust the requested date to the last trading day
 	requestedDate, _ := time.Parse(DateFormat, dateParam)
-	adjustedDate := h.getLastTradingDay(requestedDate)
+	adjustedDate := h.getLastTradingDayForSymbol(symbol, requestedDate)
 	adjustedDateStr := adjustedDate.Format(DateFormat)
 
-	// If the adjusted date is today and今天還沒收盤，回傳即時價
+	// Until the market closes today, today's price is the current one. Crypto markets never
+	// close, so today's price is always the current one.
 	now := time.Now()
-	todayStr := now.Format(DateFormat)
+	marketCalendar := calendar.ForSymbol(symbol)
+	todayStr := marketCalendar.Today(now).Format(DateFormat)
+	crypto := provider.IsCryptoSymbol(symbol)
+	if crypto {
+		todayStr = now.Format(DateFormat)
+	}
 	isToday := adjustedDateStr == todayStr
-	// 假設美股收盤時間為美東 16:00 (UTC-4)，這裡以台灣時間為例，請依實際需求調整
-	// 這裡用 UTC 時間 20:00 當作美股收盤
-	marketCloseHourUTC := 20
-	marketCloseMinuteUTC := 0
-	marketCloseTime := time.Date(now.UTC().Year(), now.UTC().Month(), now.UTC().Day(), marketCloseHourUTC, marketCloseMinuteUTC, 0, 0, time.UTC)
-	if isToday && now.UTC().Before(marketCloseTime) {
-		// 回傳即時價
+	if isToday && (crypto || !marketCalendar.HasClosed(now)) {
 		currentPrice, err := h.provider.GetCurrentPrices(c.Request.Context(), []string{symbol})
 		if err != nil || len(currentPrice) == 0 {
 			c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
@@ -228,6 +235,7 @@ func (h *PriceHandler) handleSingleDateQuery(c *gin.Context, symbol, dateParam s
 					Price: currentPrice[0].CurrentPrice,
 				},
 			},
+			Provider: currentPrice[0].Provider,
 		}
 		c.JSON(http.StatusOK, models.SuccessResponse{
 			Success:   true,
@@ -258,6 +266,7 @@ func (h *PriceHandler) handleSingleDateQuery(c *gin.Context, symbol, dateParam s
 									Price: priceData.Price,
 								},
 							},
+							Provider: cachedData.Provider,
 						}
 
 						c.JSON(http.StatusOK, models.SuccessResponse{
@@ -306,6 +315,7 @@ func (h *PriceHandler) handleSingleDateQuery(c *gin.Context, symbol, dateParam s
 						Price: priceData.Price,
 					},
 				},
+				Provider: historicalData.Provider,
 			}
 
 			c.JSON(http.StatusOK, models.SuccessResponse{
@@ -422,7 +432,7 @@ func (h *PriceHandler) handleResolutionQuery(c *gin.Context, symbol string) {
 			Success: false,
 			Error: models.ErrorDetail{
 				Code:    models.ErrInvalidInput,
-				Message: "invalid resolution (daily, weekly, monthly allowed)",
+				Message: "invalid resolution (daily, weekly, monthly, intraday allowed)",
 			},
 		})
 		return
@@ -463,6 +473,60 @@ func (h *PriceHandler) handleResolutionQuery(c *gin.Context, symbol string) {
 	})
 }
 
+// handleIntradayQuery processes intraday price requests for an interval
+func (h *PriceHandler) handleIntradayQuery(c *gin.Context, symbol, dateParam, fromParam, toParam string) {
+	interval := models.Interval(strings.TrimSpace(c.Query("interval")))
+	if interval.Duration() == 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    models.ErrInvalidInput,
+				Message: "invalid interval (1min, 5min, 15min, 60min allowed)",
+			},
+		})
+		return
+	}
+
+	intradayData, err := h.cache.GetIntradayPrice(c.Request.Context(), symbol, interval)
+	if err != nil || intradayData == nil {
+		intradayData, err = h.provider.GetIntradayPrices(c.Request.Context(), symbol, interval)
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+				Success: false,
+				Error: models.ErrorDetail{
+					Code:    models.ErrServiceUnavailable,
+					Message: "failed to fetch intraday data",
+				},
+			})
+			return
+		}
+
+		if err := h.cache.SetIntradayPrice(c.Request.Context(), symbol, interval, intradayData, IntradayCacheTTL(symbol, interval, time.Now())); err != nil {
+			log.Printf("error caching intraday price for %s with interval %s: %v", symbol, interval, err)
+		}
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success:   true,
+		Data:      h.filterIntradayPrices(intradayData, dateParam, fromParam, toParam),
+		Timestamp: time.Now(),
+	})
+}
+
+// IntradayCacheTTL returns how long intraday bars stay fresh. While the market trades a new bar
+// is due every interval; once it has closed, the bars cannot change until it opens again. Crypto
+// markets never close.
+func IntradayCacheTTL(symbol string, interval models.Interval, now time.Time) time.Duration {
+	if provider.IsCryptoSymbol(symbol) {
+		return interval.Duration()
+	}
+	status := calendar.ForSymbol(symbol).Status(now)
+	if status.Open || !status.NextOpen.After(now) {
+		return interval.Duration()
+	}
+	return status.NextOpen.Sub(now)
+}
+
 // ValidateDateParameters validates the combination of date parameters
 func (h *PriceHandler) ValidateDateParameters(date, from, to string) error {
 	return h.validateDateParameters(date, from, to)
@@ -476,16 +540,25 @@ func (h *PriceHandler) FilterHistoricalDataByDateParams(data *models.SymbolHisto
 	return h.filterHistoricalDataByDateParams(data, dateParam, fromDate, toDate)
 }
 
+func (h *PriceHandler) FilterIntradayPrices(data *models.SymbolIntradayPrice, dateParam, fromDate, toDate string) *models.SymbolIntradayPrice {
+	return h.filterIntradayPrices(data, dateParam, fromDate, toDate)
+}
+
 func (h *PriceHandler) CheckCacheCoverage(data *models.SymbolHistoricalPrice, fromDate, toDate string) CacheCoverage {
 	return h.checkCacheCoverage(data, fromDate, toDate)
 }
 
 func (h *PriceHandler) IsUSMarketHoliday(date time.Time) bool {
-	return h.isUSMarketHoliday(date)
+	_, holiday := calendar.Default().Holiday(date)
+	return holiday
 }
 
 func (h *PriceHandler) GetLastTradingDay(date time.Time) time.Time {
-	return h.getLastTradingDay(date)
+	return calendar.Default().LastTradingDay(date)
+}
+
+func (h *PriceHandler) GetLastTradingDayForSymbol(symbol string, date time.Time) time.Time {
+	return h.getLastTradingDayForSymbol(symbol, date)
 }
 
 // validateDateParameters validates the combination of date parameters
@@ -564,6 +637,7 @@ func (h *PriceHandler) filterHistoricalDataByDateParams(data *models.SymbolHisto
 			Symbol:           data.Symbol,
 			Resolution:       models.ResolutionDaily, // Always daily for date-specific queries
 			HistoricalPrices: filteredPrices,
+			Provider:         data.Provider,
 		}
 	}
 
@@ -590,6 +664,40 @@ func (h *PriceHandler) filterHistoricalDataByDateParams(data *models.SymbolHisto
 		Symbol:           data.Symbol,
 		Resolution:       models.ResolutionDaily, // Always daily for date-specific queries
 		HistoricalPrices: filteredPrices,
+		Provider:         data.Provider,
+	}
+}
+
+// filterIntradayPrices keeps the bars of a single date, or of a date range (inclusive). Dates are
+// those of the exchange the symbol trades on, and UTC dates for crypto pairs.
+func (h *PriceHandler) filterIntradayPrices(data *models.SymbolIntradayPrice, dateParam, fromDate, toDate string) *models.SymbolIntradayPrice {
+	if dateParam == "" && fromDate == "" {
+		return data
+	}
+	if dateParam != "" {
+		fromDate, toDate = dateParam, dateParam
+	}
+
+	location := calendar.ForSymbol(data.Symbol).Location
+	if provider.IsCryptoSymbol(data.Symbol) {
+		location = time.UTC
+	}
+
+	filteredPrices := []models.IntradayPrice{}
+	for _, priceData := range data.IntradayPrices {
+		// Dates are YYYY-MM-DD, so string order is chronological order
+		date := priceData.Time.In(location).Format(DateFormat)
+		if date >= fromDate && date <= toDate {
+			filteredPrices = append(filteredPrices, priceData)
+		}
+	}
+
+	return &models.SymbolIntradayPrice{
+		Symbol:         data.Symbol,
+		Resolution:     models.ResolutionIntraday,
+		Interval:       data.Interval,
+		IntradayPrices: filteredPrices,
+		Provider:       data.Provider,
 	}
 }
 
@@ -610,7 +718,7 @@ func (h *PriceHandler) checkCacheCoverage(data *models.SymbolHistoricalPrice, fr
 
 	// Compare requested date range with last price record
 	// Adjust toDate to the last trading day if it falls on weekend or holiday
-	adjustedToDate := h.getLastTradingDay(toTime)
+	adjustedToDate := h.getLastTradingDayForSymbol(data.Symbol, toTime)
 
 	// 1. If both fromDate and toDate are later than last price record → no coverage
 	if fromTime.After(lastPriceDate) && adjustedToDate.After(lastPriceDate) {
@@ -631,87 +739,12 @@ func (h *PriceHandler) checkCacheCoverage(data *models.SymbolHistoricalPrice, fr
 	return CacheCoveragePartial
 }
 
-// isUSMarketHoliday checks if a given date is a US stock market holiday
-func (h *PriceHandler) isUSMarketHoliday(date time.Time) bool {
-	month := date.Month()
-	day := date.Day()
-
-	// New Year's Day (January 1)
-	if month == time.January && day == 1 {
-		return true
-	}
-
-	// Martin Luther King Jr. Day (3rd Monday in January)
-	if month == time.January && date.Weekday() == time.Monday {
-		if day >= 15 && day <= 21 {
-			return true
-		}
-	}
-
-	// Presidents' Day (3rd Monday in February)
-	if month == time.February && date.Weekday() == time.Monday {
-		if day >= 15 && day <= 21 {
-			return true
-		}
+// getLastTradingDayForSymbol returns the last day on or before the given date that the symbol's
+// market was open, following the calendar of the exchange it trades on. Crypto markets are always
+// open, so every day is a trading day for crypto pairs.
+func (h *PriceHandler) getLastTradingDayForSymbol(symbol string, date time.Time) time.Time {
+	if provider.IsCryptoSymbol(symbol) {
+		return date
 	}
-
-	// Good Friday (Friday before Easter)
-	// Memorial Day (last Monday in May)
-	if month == time.May && date.Weekday() == time.Monday && day >= 25 {
-		return true
-	}
-
-	// Juneteenth (June 19)
-	if month == time.June && day == 19 {
-		return true
-	}
-
-	// Independence Day (July 4)
-	if month == time.July && day == 4 {
-		return true
-	}
-
-	// Labor Day (1st Monday in September)
-	if month == time.September && date.Weekday() == time.Monday && day <= 7 {
-		return true
-	}
-
-	// Thanksgiving (4th Thursday in November)
-	if month == time.November && date.Weekday() == time.Thursday {
-		if day >= 22 && day <= 28 {
-			return true
-		}
-	}
-
-	// Christmas Day (December 25)
-	if month == time.December && day == 25 {
-		return true
-	}
-
-	return false
-}
-
-// getLastTradingDay returns the last trading day on or before the given date
-func (h *PriceHandler) getLastTradingDay(date time.Time) time.Time {
-	adjustedDate := date
-
-	// Keep going back until we find a trading day
-	for {
-		// Skip weekends
-		if adjustedDate.Weekday() == time.Saturday || adjustedDate.Weekday() == time.Sunday {
-			adjustedDate = adjustedDate.AddDate(0, 0, -1)
-			continue
-		}
-
-		// Skip US market holidays
-		if h.isUSMarketHoliday(adjustedDate) {
-			adjustedDate = adjustedDate.AddDate(0, 0, -1)
-			continue
-		}
-
-		// Found a trading day
-		break
-	}
-
-	return adjustedDate
+	return calendar.ForSymbol(symbol).LastTradingDay(date)
 }
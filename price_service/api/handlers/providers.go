@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/transaction-tracker/price_service/internal/models"
+)
+
+// ProviderStatusSource reports the health of the price providers and the chains they serve in
+type ProviderStatusSource interface {
+	Status() models.ProvidersOverview
+}
+
+type ProvidersHandler struct {
+	source ProviderStatusSource
+}
+
+func NewProvidersHandler(source ProviderStatusSource) *ProvidersHandler {
+	return &ProvidersHandler{source: source}
+}
+
+// GetProviders handles GET /api/v1/providers
+func (h *ProvidersHandler) GetProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success:   true,
+		Data:      h.source.Status(),
+		Timestamp: time.Now(),
+	})
+}
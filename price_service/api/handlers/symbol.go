@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/transaction-tracker/price_service/internal/models"
+	"github.com/transaction-tracker/price_service/internal/provider"
+	"github.com/transaction-tracker/price_service/internal/symbols"
+)
+
+// symbolPattern matches the symbols that can be looked up: tickers with an optional exchange
+// suffix such as BRK.B or 2330.TW, and crypto pairs such as BTC-USD
+var symbolPattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9.\-]{0,19}$`)
+
+const (
+	defaultSymbolSearchLimit = 10
+	maxSymbolSearchLimit     = 50
+)
+
+type SymbolHandler struct {
+	store    *symbols.Store
+	provider provider.SymbolInfoProvider
+}
+
+func NewSymbolHandler(store *symbols.Store, provider provider.SymbolInfoProvider) *SymbolHandler {
+	return &SymbolHandler{
+		store:    store,
+		provider: provider,
+	}
+}
+
+// GetSymbol handles GET /api/v1/symbols/:symbol
+func (h *SymbolHandler) GetSymbol(c *gin.Context) {
+	symbol := strings.TrimSpace(strings.ToUpper(c.Param("symbol")))
+	if !symbolPattern.MatchString(symbol) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    models.ErrInvalidInput,
+				Message: "invalid symbol",
+			},
+		})
+		return
+	}
+
+	// The local store first, then the provider, whose answer is kept for next time
+	info, ok := h.store.Get(symbol)
+	if !ok {
+		fetched, err := h.provider.GetSymbolInfo(c.Request.Context(), symbol)
+		if errors.Is(err, provider.ErrSymbolNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Success: false,
+				Error: models.ErrorDetail{
+					Code:    models.ErrSymbolNotFound,
+					Message: fmt.Sprintf("symbol %s not found", symbol),
+				},
+			})
+			return
+		}
+		if err != nil {
+			log.Printf("error looking up symbol %s: %v", symbol, err)
+			c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+				Success: false,
+				Error: models.ErrorDetail{
+					Code:    models.ErrServiceUnavailable,
+					Message: "failed to look up symbol",
+				},
+			})
+			return
+		}
+
+		h.store.Put(*fetched)
+		info, _ = h.store.Get(symbol)
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success:   true,
+		Data:      info,
+		Timestamp: time.Now(),
+	})
+}
+
+// SearchSymbols handles GET /api/v1/symbols/search
+func (h *SymbolHandler) SearchSymbols(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Success: false,
+			Error: models.ErrorDetail{
+				Code:    models.ErrInvalidInput,
+				Message: "q parameter is required",
+			},
+		})
+		return
+	}
+
+	limit := defaultSymbolSearchLimit
+	if limitParam := c.Query("limit"); limitParam != "" {
+		parsed, err := strconv.Atoi(limitParam)
+		if err != nil || parsed < 1 || parsed > maxSymbolSearchLimit {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Success: false,
+				Error: models.ErrorDetail{
+					Code:    models.ErrInvalidInput,
+					Message: fmt.Sprintf("limit must be between 1 and %d", maxSymbolSearchLimit),
+				},
+			})
+			return
+		}
+		limit = parsed
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Success:   true,
+		Data:      h.searchSymbols(c.Request.Context(), query, limit),
+		Timestamp: time.Now(),
+	})
+}
+
+// searchSymbols returns local matches first, topped up with the provider's when there are too few.
+// A failing provider only leaves the local matches.
+func (h *SymbolHandler) searchSymbols(ctx context.Context, query string, limit int) []models.SymbolInfo {
+	results := h.store.Search(query, limit)
+	if len(results) == limit {
+		return results
+	}
+
+	found, err := h.provider.SearchSymbols(ctx, query)
+	if err != nil {
+		log.Printf("error searching symbols for %q: %v", query, err)
+		return results
+	}
+
+	seen := make(map[string]bool, len(results))
+	for _, info := range results {
+		seen[info.Symbol] = true
+	}
+	for _, info := range found {
+		if len(results) == limit {
+			break
+		}
+		if seen[info.Symbol] {
+			continue
+		}
+		seen[info.Symbol] = true
+		// Symbols known locally carry their full reference data
+		if local, ok := h.store.Get(info.Symbol); ok {
+			info = local
+		}
+		results = append(results, info)
+	}
+	return results
+}
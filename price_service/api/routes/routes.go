@@ -9,6 +9,7 @@ import (
 	"github.com/transaction-tracker/price_service/internal/cache"
 	"github.com/transaction-tracker/price_service/internal/config"
 	"github.com/transaction-tracker/price_service/internal/provider"
+	"github.com/transaction-tracker/price_service/internal/symbols"
 )
 
 func SetupRouter(cfg *config.Config) *gin.Engine {
@@ -26,7 +27,16 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 		panic("Failed to initialize stock price provider: " + err.Error())
 	}
 
+	symbolStore, err := symbols.NewStore(cfg.Symbols.SeedFile)
+	if err != nil {
+		panic("Failed to initialize symbol store: " + err.Error())
+	}
+
 	priceHandler := handlers.NewPriceHandler(cacheService, thirdPartyProviderMap, cfg)
+	fxHandler := handlers.NewFXHandler(cacheService, thirdPartyProviderMap)
+	symbolHandler := handlers.NewSymbolHandler(symbolStore, thirdPartyProviderMap)
+	marketHandler := handlers.NewMarketHandler()
+	providersHandler := handlers.NewProvidersHandler(thirdPartyProviderMap)
 	cacheHandler := handlers.NewCacheHandler(cacheService)
 
 	rateLimiter := middlewares.NewRateLimiter(cfg.RateLimit.RequestsPerWindow, cfg.RateLimit.WindowDuration)
@@ -52,6 +62,29 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 		priceGroup.GET("/historical", priceHandler.GetHistoricalPrices)
 	}
 
+	// FX rate endpoints
+	fxGroup := api.Group("/fx")
+	{
+		fxGroup.GET("/rates", fxHandler.GetFXRates)
+	}
+
+	// Symbol reference data endpoints
+	symbolGroup := api.Group("/symbols")
+	{
+		symbolGroup.GET("/search", symbolHandler.SearchSymbols)
+		symbolGroup.GET("/:symbol", symbolHandler.GetSymbol)
+	}
+
+	// Price provider health and failover chains
+	api.GET("/providers", providersHandler.GetProviders)
+
+	// Market calendar endpoints
+	marketGroup := api.Group("/market")
+	{
+		marketGroup.GET("/calendar", marketHandler.GetMarketCalendar)
+		marketGroup.GET("/status", marketHandler.GetMarketStatus)
+	}
+
 	// Cache management endpoints
 	api.POST("/invalid-cache", cacheHandler.InvalidateCache)
 
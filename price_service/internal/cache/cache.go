@@ -108,6 +108,70 @@ func (s *Service) DeleteHistoricalPrice(ctx context.Context, symbol string, reso
 	return s.client.Del(ctx, key).Err()
 }
 
+// Intraday price cache methods
+func (s *Service) GetIntradayPrice(ctx context.Context, symbol string, interval models.Interval) (*models.SymbolIntradayPrice, error) {
+	key := fmt.Sprintf("price_service:intraday-price:%s:%s", symbol, string(interval))
+	val, err := s.client.Get(ctx, key).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil // Not found
+		}
+		return nil, err
+	}
+
+	var price models.SymbolIntradayPrice
+	if err := json.Unmarshal([]byte(val), &price); err != nil {
+		return nil, err
+	}
+
+	return &price, nil
+}
+
+// SetIntradayPrice caches intraday bars for a TTL set by the caller, since new bars arrive only
+// while the market is open
+func (s *Service) SetIntradayPrice(ctx context.Context, symbol string, interval models.Interval, price *models.SymbolIntradayPrice, ttl time.Duration) error {
+	key := fmt.Sprintf("price_service:intraday-price:%s:%s", symbol, string(interval))
+	data, err := json.Marshal(price)
+	if err != nil {
+		return err
+	}
+
+	return s.client.Set(ctx, key, data, ttl).Err()
+}
+
+// FX rate cache methods
+func (s *Service) GetFXRates(ctx context.Context, base, quote string) (*models.CurrencyPairRates, error) {
+	today := time.Now().Format("2006-01-02")
+	key := fmt.Sprintf("price_service:fx-rate:%s:%s:%s", base, quote, today)
+	val, err := s.client.Get(ctx, key).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil // Not found
+		}
+		return nil, err
+	}
+
+	var rates models.CurrencyPairRates
+	if err := json.Unmarshal([]byte(val), &rates); err != nil {
+		return nil, err
+	}
+
+	return &rates, nil
+}
+
+func (s *Service) SetFXRates(ctx context.Context, base, quote string, rates *models.CurrencyPairRates) error {
+	today := time.Now().Format("2006-01-02")
+	key := fmt.Sprintf("price_service:fx-rate:%s:%s:%s", base, quote, today)
+	data, err := json.Marshal(rates)
+	if err != nil {
+		return err
+	}
+
+	// FX data uses the same 24 hour TTL as historical prices
+	fxTTL := 24 * time.Hour
+	return s.client.Set(ctx, key, data, fxTTL).Err()
+}
+
 // Cache management methods
 func (s *Service) InvalidateAll(ctx context.Context) error {
 	// Delete all price-related keys
@@ -121,7 +185,19 @@ func (s *Service) InvalidateAll(ctx context.Context) error {
 		return err
 	}
 
+	intradayPriceKeys, err := s.client.Keys(ctx, "price_service:intraday-price:*:*").Result()
+	if err != nil {
+		return err
+	}
+
+	fxRateKeys, err := s.client.Keys(ctx, "price_service:fx-rate:*:*:*").Result()
+	if err != nil {
+		return err
+	}
+
 	allKeys := append(currentPriceKeys, historicalPriceKeys...)
+	allKeys = append(allKeys, intradayPriceKeys...)
+	allKeys = append(allKeys, fxRateKeys...)
 	if len(allKeys) > 0 {
 		return s.client.Del(ctx, allKeys...).Err()
 	}
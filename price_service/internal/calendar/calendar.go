@@ -0,0 +1,262 @@
+package calendar
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const dateFormat = "2006-01-02"
+
+// Session is the regular trading hours of an exchange, as offsets from local midnight
+type Session struct {
+	Open  time.Duration
+	Close time.Duration
+}
+
+// Holiday is a weekday an exchange is closed
+type Holiday struct {
+	Date string // YYYY-MM-DD format
+	Name string
+}
+
+// EarlyClose is a trading day whose session ends before the regular close
+type EarlyClose struct {
+	Date  string // YYYY-MM-DD format
+	Name  string
+	Close time.Duration
+}
+
+// Status describes whether an exchange is trading at a moment, with its next open and close
+type Status struct {
+	Open      bool
+	NextOpen  time.Time
+	NextClose time.Time
+}
+
+// Calendar holds the trading days and session hours of an exchange. Holidays follow yearly rules,
+// complemented by listed dates for those no rule can describe, such as lunar holidays and
+// one-off closures. Dates are taken as civil dates: only their year, month and day are used.
+type Calendar struct {
+	Code     string
+	Name     string
+	Location *time.Location
+	Session  Session
+
+	rules       []holidayRule
+	earlyCloses []earlyCloseRule
+	listed      map[string]string // listed closures, date → name
+
+	// Calendars with holidays no rule describes need them listed every year; holidays of years
+	// after the last one listed are incomplete
+	listsYearly   bool
+	listedThrough int
+
+	mutex sync.Mutex
+	years map[int]map[string]string // holidays by year, date → name
+}
+
+func newCalendar(code, name, timezone string, session Session, rules []holidayRule, earlyCloses []earlyCloseRule) *Calendar {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		panic("unknown time zone " + timezone + ": " + err.Error())
+	}
+	return &Calendar{
+		Code:        code,
+		Name:        name,
+		Location:    location,
+		Session:     session,
+		rules:       rules,
+		earlyCloses: earlyCloses,
+		listed:      make(map[string]string),
+		years:       make(map[int]map[string]string),
+	}
+}
+
+// holidays returns the holidays of a year keyed by date, computing them on first use
+func (c *Calendar) holidays(year int) map[string]string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if holidays, ok := c.years[year]; ok {
+		return holidays
+	}
+
+	holidays := make(map[string]string)
+	for _, rule := range c.rules {
+		if year < rule.fromYear {
+			continue
+		}
+		if day, ok := observedDate(rule.date(year), rule.observe, holidays); ok {
+			holidays[day.Format(dateFormat)] = rule.name
+		}
+	}
+	prefix := fmt.Sprintf("%04d-", year)
+	for day, name := range c.listed {
+		if strings.HasPrefix(day, prefix) {
+			holidays[day] = name
+		}
+	}
+
+	if !c.coversYear(year) {
+		log.Printf("Warning: %s closures are listed through %d only, so holidays of %d are incomplete", c.Code, c.listedThrough, year)
+	}
+
+	c.years[year] = holidays
+	return holidays
+}
+
+// Covers reports whether all holidays of a year are known. Calendars relying on listed closures
+// only know those of the years closures are listed for.
+func (c *Calendar) Covers(year int) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.coversYear(year)
+}
+
+func (c *Calendar) coversYear(year int) bool {
+	return !c.listsYearly || year <= c.listedThrough
+}
+
+// Holiday returns the name of the holiday the exchange is closed for on a weekday
+func (c *Calendar) Holiday(day time.Time) (string, bool) {
+	name, ok := c.holidays(day.Year())[day.Format(dateFormat)]
+	return name, ok
+}
+
+// IsTradingDay reports whether the exchange trades on a day
+func (c *Calendar) IsTradingDay(day time.Time) bool {
+	if isWeekend(day) {
+		return false
+	}
+	_, holiday := c.Holiday(day)
+	return !holiday
+}
+
+// LastTradingDay returns the last trading day on or before a day
+func (c *Calendar) LastTradingDay(day time.Time) time.Time {
+	for !c.IsTradingDay(day) {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
+
+// NextTradingDay returns the first trading day after a day
+func (c *Calendar) NextTradingDay(day time.Time) time.Time {
+	day = day.AddDate(0, 0, 1)
+	for !c.IsTradingDay(day) {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+// SessionOn returns the hours the exchange trades on a day, with early closes applied.
+// Days it does not trade have no session.
+func (c *Calendar) SessionOn(day time.Time) (Session, bool) {
+	if !c.IsTradingDay(day) {
+		return Session{}, false
+	}
+	session := c.Session
+	if earlyClose, ok := c.earlyClose(day); ok {
+		session.Close = earlyClose.Close
+	}
+	return session, true
+}
+
+// earlyClose returns the early close of a trading day, if its session ends early
+func (c *Calendar) earlyClose(day time.Time) (EarlyClose, bool) {
+	key := day.Format(dateFormat)
+	for _, rule := range c.earlyCloses {
+		if rule.date(day.Year()).Format(dateFormat) == key {
+			return EarlyClose{Date: key, Name: rule.name, Close: rule.close}, true
+		}
+	}
+	return EarlyClose{}, false
+}
+
+// Holidays returns the weekday holidays between two days inclusive, earliest first
+func (c *Calendar) Holidays(from, to time.Time) []Holiday {
+	fromKey, toKey := from.Format(dateFormat), to.Format(dateFormat)
+
+	result := []Holiday{}
+	for year := from.Year(); year <= to.Year(); year++ {
+		for day, name := range c.holidays(year) {
+			if day >= fromKey && day <= toKey {
+				result = append(result, Holiday{Date: day, Name: name})
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Date < result[j].Date })
+	return result
+}
+
+// EarlyCloses returns the trading days between two days inclusive whose session ends early, earliest first
+func (c *Calendar) EarlyCloses(from, to time.Time) []EarlyClose {
+	fromKey, toKey := from.Format(dateFormat), to.Format(dateFormat)
+
+	result := []EarlyClose{}
+	for year := from.Year(); year <= to.Year(); year++ {
+		for _, rule := range c.earlyCloses {
+			day := rule.date(year)
+			key := day.Format(dateFormat)
+			if key >= fromKey && key <= toKey && c.IsTradingDay(day) {
+				result = append(result, EarlyClose{Date: key, Name: rule.name, Close: rule.close})
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Date < result[j].Date })
+	return result
+}
+
+// sessionTimes returns the moments the session of a trading day opens and closes
+func (c *Calendar) sessionTimes(day time.Time, session Session) (time.Time, time.Time) {
+	midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, c.Location)
+	return midnight.Add(session.Open), midnight.Add(session.Close)
+}
+
+// Today returns the date of a moment in the exchange's time zone, as a civil date
+func (c *Calendar) Today(now time.Time) time.Time {
+	local := now.In(c.Location)
+	return date(local.Year(), local.Month(), local.Day())
+}
+
+// HasClosed reports whether the exchange is done trading for its current day: the session has
+// ended, or the day is not a trading day
+func (c *Calendar) HasClosed(now time.Time) bool {
+	today := c.Today(now)
+	session, ok := c.SessionOn(today)
+	if !ok {
+		return true
+	}
+	_, closeAt := c.sessionTimes(today, session)
+	return !now.Before(closeAt)
+}
+
+// Status returns whether the exchange is trading at a moment, with the next time it opens and closes
+func (c *Calendar) Status(now time.Time) Status {
+	today := c.Today(now)
+
+	if session, ok := c.SessionOn(today); ok {
+		openAt, closeAt := c.sessionTimes(today, session)
+		if now.Before(openAt) {
+			return Status{Open: false, NextOpen: openAt, NextClose: closeAt}
+		}
+		if now.Before(closeAt) {
+			nextOpen, _ := c.nextSession(today)
+			return Status{Open: true, NextOpen: nextOpen, NextClose: closeAt}
+		}
+	}
+
+	nextOpen, nextClose := c.nextSession(today)
+	return Status{Open: false, NextOpen: nextOpen, NextClose: nextClose}
+}
+
+// nextSession returns the open and close of the first session after a day
+func (c *Calendar) nextSession(day time.Time) (time.Time, time.Time) {
+	next := c.NextTradingDay(day)
+	session, _ := c.SessionOn(next)
+	return c.sessionTimes(next, session)
+}
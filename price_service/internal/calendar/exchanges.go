@@ -0,0 +1,179 @@
+package calendar
+
+import (
+	_ "embed"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // exchange time zones must resolve on hosts without a zoneinfo database
+)
+
+//go:embed data/closures.json
+var closuresData []byte
+
+// hoursMinutes returns a time of day as an offset from midnight
+func hoursMinutes(hours, minutes int) time.Duration {
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute
+}
+
+// usHolidays are the holidays NYSE and NASDAQ share
+var usHolidays = []holidayRule{
+	{name: "New Year's Day", date: fixed(time.January, 1), observe: observeSundayToMonday},
+	{name: "Martin Luther King Jr. Day", date: nthWeekday(time.January, time.Monday, 3), fromYear: 1998},
+	{name: "Washington's Birthday", date: nthWeekday(time.February, time.Monday, 3)},
+	{name: "Good Friday", date: easterOffset(-2)},
+	{name: "Memorial Day", date: lastWeekday(time.May, time.Monday)},
+	{name: "Juneteenth National Independence Day", date: fixed(time.June, 19), observe: observeNearestWeekday, fromYear: 2022},
+	{name: "Independence Day", date: fixed(time.July, 4), observe: observeNearestWeekday},
+	{name: "Labor Day", date: nthWeekday(time.September, time.Monday, 1)},
+	{name: "Thanksgiving Day", date: nthWeekday(time.November, time.Thursday, 4)},
+	{name: "Christmas Day", date: fixed(time.December, 25), observe: observeNearestWeekday},
+}
+
+// usEarlyCloses are the sessions NYSE and NASDAQ close at 1 p.m.
+var usEarlyCloses = []earlyCloseRule{
+	{name: "Day before Independence Day", date: fixed(time.July, 3), close: hoursMinutes(13, 0)},
+	{name: "Day after Thanksgiving", date: dayAfter(nthWeekday(time.November, time.Thursday, 4)), close: hoursMinutes(13, 0)},
+	{name: "Christmas Eve", date: fixed(time.December, 24), close: hoursMinutes(13, 0)},
+}
+
+var (
+	nyse = newCalendar("NYSE", "New York Stock Exchange", "America/New_York",
+		Session{Open: hoursMinutes(9, 30), Close: hoursMinutes(16, 0)}, usHolidays, usEarlyCloses)
+
+	nasdaq = newCalendar("NASDAQ", "Nasdaq Stock Market", "America/New_York",
+		Session{Open: hoursMinutes(9, 30), Close: hoursMinutes(16, 0)}, usHolidays, usEarlyCloses)
+
+	// Lunar New Year, Tomb Sweeping Day, Dragon Boat and Mid-Autumn festivals, make-up days
+	// and typhoon closures are announced yearly, so they are listed in data/closures.json
+	twse = newCalendar("TWSE", "Taiwan Stock Exchange", "Asia/Taipei",
+		Session{Open: hoursMinutes(9, 0), Close: hoursMinutes(13, 30)},
+		[]holidayRule{
+			{name: "Founding Day of the Republic of China", date: fixed(time.January, 1), observe: observeNearestWeekday},
+			{name: "Peace Memorial Day", date: fixed(time.February, 28), observe: observeNearestWeekday},
+			{name: "Labor Day", date: fixed(time.May, 1)},
+			{name: "Confucius' Birthday / Teachers' Day", date: fixed(time.September, 28), observe: observeNearestWeekday, fromYear: 2025},
+			{name: "National Day", date: fixed(time.October, 10), observe: observeNearestWeekday},
+			{name: "Taiwan Retrocession Day", date: fixed(time.October, 25), observe: observeNearestWeekday, fromYear: 2025},
+			{name: "Constitution Day", date: fixed(time.December, 25), observe: observeNearestWeekday, fromYear: 2025},
+		}, nil)
+
+	tsx = newCalendar("TSX", "Toronto Stock Exchange", "America/Toronto",
+		Session{Open: hoursMinutes(9, 30), Close: hoursMinutes(16, 0)},
+		[]holidayRule{
+			{name: "New Year's Day", date: fixed(time.January, 1), observe: observeNextWeekday},
+			{name: "Family Day", date: nthWeekday(time.February, time.Monday, 3), fromYear: 2008},
+			{name: "Good Friday", date: easterOffset(-2)},
+			{name: "Victoria Day", date: weekdayBefore(time.May, 25, time.Monday)},
+			{name: "Canada Day", date: fixed(time.July, 1), observe: observeNextWeekday},
+			{name: "Civic Holiday", date: nthWeekday(time.August, time.Monday, 1)},
+			{name: "Labour Day", date: nthWeekday(time.September, time.Monday, 1)},
+			{name: "Thanksgiving Day", date: nthWeekday(time.October, time.Monday, 2)},
+			{name: "Christmas Day", date: fixed(time.December, 25), observe: observeNextWeekday},
+			{name: "Boxing Day", date: fixed(time.December, 26), observe: observeNextWeekday},
+		},
+		[]earlyCloseRule{
+			{name: "Christmas Eve", date: fixed(time.December, 24), close: hoursMinutes(13, 0)},
+		})
+
+	lse = newCalendar("LSE", "London Stock Exchange", "Europe/London",
+		Session{Open: hoursMinutes(8, 0), Close: hoursMinutes(16, 30)},
+		[]holidayRule{
+			{name: "New Year's Day", date: fixed(time.January, 1), observe: observeNextWeekday},
+			{name: "Good Friday", date: easterOffset(-2)},
+			{name: "Easter Monday", date: easterOffset(1)},
+			{name: "Early May Bank Holiday", date: nthWeekday(time.May, time.Monday, 1)},
+			{name: "Spring Bank Holiday", date: lastWeekday(time.May, time.Monday)},
+			{name: "Summer Bank Holiday", date: lastWeekday(time.August, time.Monday)},
+			{name: "Christmas Day", date: fixed(time.December, 25), observe: observeNextWeekday},
+			{name: "Boxing Day", date: fixed(time.December, 26), observe: observeNextWeekday},
+		},
+		[]earlyCloseRule{
+			{name: "Christmas Eve", date: fixed(time.December, 24), close: hoursMinutes(12, 30)},
+			{name: "New Year's Eve", date: fixed(time.December, 31), close: hoursMinutes(12, 30)},
+		})
+)
+
+// listedYearly are the calendars whose closures are listed a year at a time in data/closures.json
+var listedYearly = []*Calendar{twse}
+
+// calendars holds the calendar of each supported exchange by code
+var calendars = map[string]*Calendar{}
+
+func init() {
+	for _, c := range []*Calendar{nyse, nasdaq, twse, tsx, lse} {
+		calendars[c.Code] = c
+	}
+
+	// Closures listed by exchange code, each a list of {"date", "name"}
+	var closures map[string][]Holiday
+	if err := json.Unmarshal(closuresData, &closures); err != nil {
+		panic("failed to load market closures: " + err.Error())
+	}
+	for code, holidays := range closures {
+		c, ok := calendars[code]
+		if !ok {
+			panic("market closures listed for unknown exchange " + code)
+		}
+		for _, holiday := range holidays {
+			if _, err := time.Parse(dateFormat, holiday.Date); err != nil {
+				panic("invalid market closure date " + holiday.Date + " for " + code)
+			}
+			c.listed[holiday.Date] = holiday.Name
+		}
+	}
+
+	for _, c := range listedYearly {
+		c.listsYearly = true
+		for day := range c.listed {
+			if year, _ := strconv.Atoi(day[:4]); year > c.listedThrough {
+				c.listedThrough = year
+			}
+		}
+	}
+}
+
+// symbolSuffixExchanges maps exchange suffixes of symbols to the exchange whose calendar they
+// trade on. TPEx (.TWO) follows the TWSE calendar, and the TSX Venture Exchange (.V) the TSX one.
+var symbolSuffixExchanges = map[string]string{
+	".TW":  "TWSE",
+	".TWO": "TWSE",
+	".TO":  "TSX",
+	".V":   "TSX",
+	".L":   "LSE",
+}
+
+// Get returns the calendar of an exchange by its code, such as NYSE or TWSE
+func Get(code string) (*Calendar, bool) {
+	c, ok := calendars[strings.ToUpper(strings.TrimSpace(code))]
+	return c, ok
+}
+
+// Default returns the calendar used for symbols without an exchange suffix
+func Default() *Calendar {
+	return nyse
+}
+
+// ForSymbol returns the calendar of the exchange a symbol trades on, from its suffix.
+// Symbols without a suffix, or with one of an exchange not supported, use the NYSE calendar.
+func ForSymbol(symbol string) *Calendar {
+	symbol = strings.ToUpper(symbol)
+	if dot := strings.LastIndex(symbol, "."); dot >= 0 {
+		if code, ok := symbolSuffixExchanges[symbol[dot:]]; ok {
+			return calendars[code]
+		}
+	}
+	return nyse
+}
+
+// Exchanges returns the codes of the supported exchanges, sorted
+func Exchanges() []string {
+	codes := make([]string, 0, len(calendars))
+	for code := range calendars {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
@@ -0,0 +1,135 @@
+package calendar
+
+import "time"
+
+// observance says which weekday a holiday falling on a weekend is made up on
+type observance int
+
+const (
+	observeNone           observance = iota // weekend dates are not made up
+	observeNearestWeekday                   // Saturday dates move to Friday, Sunday dates to Monday
+	observeSundayToMonday                   // Sunday dates move to Monday; Saturday dates are not made up
+	observeNextWeekday                      // weekend dates move to the next weekday that is not already a holiday
+)
+
+// holidayRule describes a yearly holiday and the first year it applies
+type holidayRule struct {
+	name     string
+	date     func(year int) time.Time
+	observe  observance
+	fromYear int
+}
+
+// earlyCloseRule describes a yearly session that closes early, when the day is a trading day
+type earlyCloseRule struct {
+	name  string
+	date  func(year int) time.Time
+	close time.Duration
+}
+
+// date returns midnight UTC of a civil date, the form dates take throughout the price service
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func isWeekend(day time.Time) bool {
+	return day.Weekday() == time.Saturday || day.Weekday() == time.Sunday
+}
+
+// fixed is a holiday on the same date every year
+func fixed(month time.Month, day int) func(int) time.Time {
+	return func(year int) time.Time {
+		return date(year, month, day)
+	}
+}
+
+// nthWeekday is a holiday on the nth weekday of a month, such as the third Monday of January
+func nthWeekday(month time.Month, weekday time.Weekday, n int) func(int) time.Time {
+	return func(year int) time.Time {
+		first := date(year, month, 1)
+		offset := (int(weekday) - int(first.Weekday()) + 7) % 7
+		return first.AddDate(0, 0, offset+7*(n-1))
+	}
+}
+
+// lastWeekday is a holiday on the last weekday of a month, such as the last Monday of May
+func lastWeekday(month time.Month, weekday time.Weekday) func(int) time.Time {
+	return func(year int) time.Time {
+		last := date(year, month+1, 0)
+		offset := (int(last.Weekday()) - int(weekday) + 7) % 7
+		return last.AddDate(0, 0, -offset)
+	}
+}
+
+// weekdayBefore is a holiday on the last weekday before a date, such as the Monday before May 25
+func weekdayBefore(month time.Month, day int, weekday time.Weekday) func(int) time.Time {
+	return func(year int) time.Time {
+		before := date(year, month, day-1)
+		offset := (int(before.Weekday()) - int(weekday) + 7) % 7
+		return before.AddDate(0, 0, -offset)
+	}
+}
+
+// dayAfter is the day after another holiday, such as the Friday after Thanksgiving
+func dayAfter(holiday func(int) time.Time) func(int) time.Time {
+	return func(year int) time.Time {
+		return holiday(year).AddDate(0, 0, 1)
+	}
+}
+
+// easterOffset is a holiday a number of days from Western Easter Sunday, such as Good Friday at -2
+func easterOffset(days int) func(int) time.Time {
+	return func(year int) time.Time {
+		return easter(year).AddDate(0, 0, days)
+	}
+}
+
+// easter returns Western Easter Sunday of a year (anonymous Gregorian algorithm)
+func easter(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return date(year, time.Month(month), day)
+}
+
+// observedDate returns the day a holiday is made up on, and false when it is not made up.
+// taken holds the holidays of the year placed so far, which observeNextWeekday steps over.
+func observedDate(day time.Time, observe observance, taken map[string]string) (time.Time, bool) {
+	switch observe {
+	case observeNearestWeekday:
+		switch day.Weekday() {
+		case time.Saturday:
+			return day.AddDate(0, 0, -1), true
+		case time.Sunday:
+			return day.AddDate(0, 0, 1), true
+		}
+	case observeSundayToMonday:
+		switch day.Weekday() {
+		case time.Saturday:
+			return day, false
+		case time.Sunday:
+			return day.AddDate(0, 0, 1), true
+		}
+	case observeNextWeekday:
+		for isWeekend(day) || taken[day.Format(dateFormat)] != "" {
+			day = day.AddDate(0, 0, 1)
+		}
+		return day, true
+	default:
+		if isWeekend(day) {
+			return day, false
+		}
+	}
+	return day, true
+}
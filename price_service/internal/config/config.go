@@ -1,8 +1,10 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -12,6 +14,7 @@ type Config struct {
 	Server    ServerConfig
 	Redis     RedisConfig
 	StockAPI  StockAPIConfig
+	Symbols   SymbolsConfig
 	Cache     CacheConfig
 	RateLimit RateLimitConfig
 }
@@ -31,6 +34,21 @@ type RedisConfig struct {
 type StockAPIConfig struct {
 	AlphaVantage ProviderConfig
 	Finnhub      ProviderConfig
+	Coinbase     ProviderConfig
+	TWSE         ProviderConfig
+	TPEx         ProviderConfig
+	Yahoo        ProviderConfig
+	Chains       ProviderChainsConfig
+}
+
+// ProviderChainsConfig orders the providers tried for each capability. Each maps a market, such as
+// DEFAULT, CRYPTO or an exchange code like TWSE, to provider names tried in turn. Markets left out
+// keep their built-in chain.
+type ProviderChainsConfig struct {
+	Current    map[string][]string
+	Historical map[string][]string
+	Intraday   map[string][]string
+	FX         map[string][]string
 }
 
 type ProviderConfig struct {
@@ -38,6 +56,12 @@ type ProviderConfig struct {
 	BaseURL string
 }
 
+// SymbolsConfig configures the symbol reference store. SeedFile optionally names a JSON file of
+// symbols that is loaded over the built-in dataset at startup.
+type SymbolsConfig struct {
+	SeedFile string
+}
+
 type CacheConfig struct {
 	DefaultTTL       time.Duration
 	MaxSymbolsPerReq int
@@ -52,6 +76,23 @@ func Load() (*Config, error) {
 	// Load .env file if it exists
 	_ = godotenv.Load()
 
+	var chains ProviderChainsConfig
+	for _, chain := range []struct {
+		name   string
+		target *map[string][]string
+	}{
+		{"PROVIDER_CHAIN_CURRENT", &chains.Current},
+		{"PROVIDER_CHAIN_HISTORICAL", &chains.Historical},
+		{"PROVIDER_CHAIN_INTRADAY", &chains.Intraday},
+		{"PROVIDER_CHAIN_FX", &chains.FX},
+	} {
+		parsed, err := ParseProviderChains(getEnv(chain.name, ""))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", chain.name, err)
+		}
+		*chain.target = parsed
+	}
+
 	config := &Config{
 		Server: ServerConfig{
 			Port:   getEnv("PORT", "8081"),
@@ -72,6 +113,22 @@ func Load() (*Config, error) {
 				APIKey:  getEnv("FINNHUB_API_KEY", ""),
 				BaseURL: getEnv("FINNHUB_BASE_URL", "https://finnhub.io/api/v1"),
 			},
+			Coinbase: ProviderConfig{
+				BaseURL: getEnv("COINBASE_BASE_URL", "https://api.exchange.coinbase.com"),
+			},
+			TWSE: ProviderConfig{
+				BaseURL: getEnv("TWSE_BASE_URL", "https://www.twse.com.tw"),
+			},
+			TPEx: ProviderConfig{
+				BaseURL: getEnv("TPEX_BASE_URL", "https://www.tpex.org.tw"),
+			},
+			Yahoo: ProviderConfig{
+				BaseURL: getEnv("YAHOO_BASE_URL", "https://query1.finance.yahoo.com"),
+			},
+			Chains: chains,
+		},
+		Symbols: SymbolsConfig{
+			SeedFile: getEnv("SYMBOLS_SEED_FILE", ""),
 		},
 		Cache: CacheConfig{
 			DefaultTTL:       time.Duration(getEnvAsInt("DEFAULT_TTL_MINUTES", 60)) * time.Minute,
@@ -86,6 +143,38 @@ func Load() (*Config, error) {
 	return config, nil
 }
 
+// ParseProviderChains parses provider chains of the form "DEFAULT=finnhub,alphavantage;CRYPTO=coinbase".
+// A list without a market is the DEFAULT chain. Markets are upper-cased and provider names lower-cased.
+func ParseProviderChains(value string) (map[string][]string, error) {
+	chains := make(map[string][]string)
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		market, list := "DEFAULT", entry
+		if eq := strings.Index(entry, "="); eq >= 0 {
+			market, list = strings.ToUpper(strings.TrimSpace(entry[:eq])), entry[eq+1:]
+		}
+		if market == "" {
+			return nil, fmt.Errorf("missing market in %q", entry)
+		}
+
+		var providers []string
+		for _, name := range strings.Split(list, ",") {
+			if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+				providers = append(providers, name)
+			}
+		}
+		if len(providers) == 0 {
+			return nil, fmt.Errorf("no providers listed for market %s", market)
+		}
+		chains[market] = providers
+	}
+	return chains, nil
+}
+
 func getEnv(key, fallback string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -21,6 +21,7 @@ type SymbolCurrentPrice struct {
 	ChangePercent float64   `json:"change_percent"` // relative difference (change/previous_close)
 	PreviousClose float64   `json:"previous_close"`
 	Timestamp     time.Time `json:"timestamp"`
+	Provider      string    `json:"provider"` // third-party provider that served the price
 }
 
 // ClosePrice represents a date-price pair
@@ -34,6 +35,50 @@ type SymbolHistoricalPrice struct {
 	Symbol           string       `json:"symbol"`
 	Resolution       Resolution   `json:"resolution"`
 	HistoricalPrices []ClosePrice `json:"historical_prices"`
+	Provider         string       `json:"provider"` // third-party provider that served the prices
+}
+
+// Interval is the width of the bars of intraday prices
+type Interval string
+
+const (
+	Interval1Min  Interval = "1min"
+	Interval5Min  Interval = "5min"
+	Interval15Min Interval = "15min"
+	Interval60Min Interval = "60min"
+)
+
+// Intervals lists the supported intraday intervals, narrowest first
+var Intervals = []Interval{Interval1Min, Interval5Min, Interval15Min, Interval60Min}
+
+// Duration returns the width of a bar, or zero for an unsupported interval
+func (i Interval) Duration() time.Duration {
+	switch i {
+	case Interval1Min:
+		return time.Minute
+	case Interval5Min:
+		return 5 * time.Minute
+	case Interval15Min:
+		return 15 * time.Minute
+	case Interval60Min:
+		return time.Hour
+	}
+	return 0
+}
+
+// IntradayPrice represents the closing price of an intraday bar, keyed by the moment the bar opens
+type IntradayPrice struct {
+	Time  time.Time `json:"time"`
+	Price float64   `json:"price"`
+}
+
+// SymbolIntradayPrice represents intraday price bars for a symbol
+type SymbolIntradayPrice struct {
+	Symbol         string          `json:"symbol"`
+	Resolution     Resolution      `json:"resolution"`
+	Interval       Interval        `json:"interval"`
+	IntradayPrices []IntradayPrice `json:"intraday_prices"` // newest to oldest
+	Provider       string          `json:"provider"`        // third-party provider that served the prices
 }
 
 // Error response structure
@@ -70,3 +115,96 @@ type SuccessResponse struct {
 type UpdateTTLRequest struct {
 	Minutes int `json:"minutes" binding:"required,min=1,max=1440"` // 1 minute to 24 hours
 }
+
+// FXRate represents the closing exchange rate of a currency pair on a date
+type FXRate struct {
+	Date string  `json:"date"` // YYYY-MM-DD format
+	Rate float64 `json:"rate"` // units of quote currency per unit of base currency
+}
+
+// CurrencyPairRates represents historical exchange rates for a currency pair
+type CurrencyPairRates struct {
+	Base     string   `json:"base"`
+	Quote    string   `json:"quote"`
+	Rates    []FXRate `json:"rates"`
+	Provider string   `json:"provider"` // third-party provider that served the rates
+}
+
+// SymbolInfo represents the reference data of a symbol: what it is and where it is listed
+type SymbolInfo struct {
+	Symbol     string `json:"symbol"`
+	Name       string `json:"name"`
+	AssetClass string `json:"asset_class"` // lowercase class such as equity, etf, bond or crypto
+	Exchange   string `json:"exchange"`
+	Currency   string `json:"currency"`
+	Country    string `json:"country"` // ISO 3166-1 alpha-2 code, empty when not listed in a country
+	Sector     string `json:"sector"`
+	Industry   string `json:"industry"`
+}
+
+// MarketSession represents the trading hours of an exchange in its local time, HH:MM format
+type MarketSession struct {
+	Open  string `json:"open"`
+	Close string `json:"close"`
+}
+
+// MarketHoliday represents a weekday an exchange is closed
+type MarketHoliday struct {
+	Date string `json:"date"` // YYYY-MM-DD format
+	Name string `json:"name"`
+}
+
+// MarketEarlyClose represents a trading day whose session ends before the regular close
+type MarketEarlyClose struct {
+	Date  string `json:"date"` // YYYY-MM-DD format
+	Name  string `json:"name"`
+	Close string `json:"close"` // HH:MM local time
+}
+
+// MarketCalendar represents the trading calendar of an exchange over a date range
+type MarketCalendar struct {
+	Exchange    string             `json:"exchange"`
+	Name        string             `json:"name"`
+	Timezone    string             `json:"timezone"`
+	Session     MarketSession      `json:"session"` // regular trading hours
+	From        string             `json:"from"`
+	To          string             `json:"to"`
+	Holidays    []MarketHoliday    `json:"holidays"`
+	EarlyCloses []MarketEarlyClose `json:"early_closes"`
+}
+
+// MarketStatus represents whether an exchange is trading, with its next open and close
+type MarketStatus struct {
+	Exchange  string         `json:"exchange"`
+	Name      string         `json:"name"`
+	Timezone  string         `json:"timezone"`
+	IsOpen    bool           `json:"is_open"`
+	LocalTime time.Time      `json:"local_time"`
+	Session   *MarketSession `json:"session"` // today's trading hours, null when the exchange does not trade today
+	NextOpen  time.Time      `json:"next_open"`
+	NextClose time.Time      `json:"next_close"`
+}
+
+// ProviderStatus represents the health of a third-party price provider
+type ProviderStatus struct {
+	Name                string     `json:"name"`
+	Capabilities        []string   `json:"capabilities"`
+	Healthy             bool       `json:"healthy"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastError           string     `json:"last_error,omitempty"`
+	LastFailure         *time.Time `json:"last_failure"`
+	RetryAfter          *time.Time `json:"retry_after"` // when an unhealthy provider is back in rotation
+}
+
+// ProviderChain represents the providers tried in order for a capability in a market
+type ProviderChain struct {
+	Capability string   `json:"capability"`
+	Market     string   `json:"market"`
+	Providers  []string `json:"providers"`
+}
+
+// ProvidersOverview represents the price providers with their health, and the chains they serve in
+type ProvidersOverview struct {
+	Providers []ProviderStatus `json:"providers"`
+	Chains    []ProviderChain  `json:"chains"`
+}
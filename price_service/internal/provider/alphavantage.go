@@ -4,6 +4,7 @@ import (
 	"context"
 	_ "embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -14,6 +15,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/transaction-tracker/price_service/internal/calendar"
 	"github.com/transaction-tracker/price_service/internal/models"
 )
 
@@ -37,6 +39,77 @@ func NewAlphaVantageProvider(apiKey string) *AlphaVantageProvider {
 	}
 }
 
+// AlphaVantageQuoteResponse represents the response structure from Alpha Vantage GLOBAL_QUOTE
+type AlphaVantageQuoteResponse struct {
+	GlobalQuote struct {
+		Symbol           string `json:"01. symbol"`
+		Price            string `json:"05. price"`
+		LatestTradingDay string `json:"07. latest trading day"`
+		PreviousClose    string `json:"08. previous close"`
+		Change           string `json:"09. change"`
+		ChangePercent    string `json:"10. change percent"` // e.g. 0.4567%
+	} `json:"Global Quote"`
+}
+
+// GetCurrentPrices retrieves the latest quote of each symbol, one request per symbol. Symbols
+// without a quote are left out.
+func (a *AlphaVantageProvider) GetCurrentPrices(ctx context.Context, symbols []string) ([]models.SymbolCurrentPrice, error) {
+	var prices []models.SymbolCurrentPrice
+	var lastErr error
+
+	for _, symbol := range symbols {
+		price, err := a.getCurrentPriceForSymbol(ctx, symbol)
+		if err != nil {
+			log.Printf("Error fetching current price for symbol %s: %v", symbol, err)
+			if !errors.Is(err, ErrSymbolNotFound) {
+				lastErr = err
+			}
+			continue
+		}
+		prices = append(prices, price)
+	}
+
+	if len(prices) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return prices, nil
+}
+
+func (a *AlphaVantageProvider) getCurrentPriceForSymbol(ctx context.Context, symbol string) (models.SymbolCurrentPrice, error) {
+	params := url.Values{}
+	params.Set("function", "GLOBAL_QUOTE")
+	params.Set("symbol", symbol)
+	params.Set("apikey", a.APIKey)
+
+	resp, err := a.makeRequest(ctx, params)
+	if err != nil {
+		return models.SymbolCurrentPrice{}, err
+	}
+
+	var quote AlphaVantageQuoteResponse
+	if err := json.Unmarshal(resp, &quote); err != nil {
+		return models.SymbolCurrentPrice{}, fmt.Errorf("failed to parse Alpha Vantage quote: %w", err)
+	}
+
+	// Unknown symbols have an empty quote
+	price, err := strconv.ParseFloat(quote.GlobalQuote.Price, 64)
+	if err != nil || price == 0 {
+		return models.SymbolCurrentPrice{}, fmt.Errorf("%w: %s", ErrSymbolNotFound, symbol)
+	}
+	previousClose, _ := strconv.ParseFloat(quote.GlobalQuote.PreviousClose, 64)
+	change, _ := strconv.ParseFloat(quote.GlobalQuote.Change, 64)
+	changePercent, _ := strconv.ParseFloat(strings.TrimSuffix(quote.GlobalQuote.ChangePercent, "%"), 64)
+
+	return models.SymbolCurrentPrice{
+		Symbol:        strings.ToUpper(symbol),
+		CurrentPrice:  price,
+		Change:        change,
+		ChangePercent: changePercent,
+		PreviousClose: previousClose,
+		Timestamp:     time.Now(),
+	}, nil
+}
+
 func (a *AlphaVantageProvider) GetHistoricalPrices(ctx context.Context, symbol string, resolution models.Resolution) (*models.SymbolHistoricalPrice, error) {
 
 	params := url.Values{}
@@ -159,6 +232,124 @@ func (a *AlphaVantageProvider) GetHistoricalPrices(ctx context.Context, symbol s
 	}, nil
 }
 
+// GetIntradayPrices retrieves the regular-session intraday bars of the last 30 days, sorted newest
+// to oldest. Alpha Vantage stamps bars with the local time of the exchange.
+func (a *AlphaVantageProvider) GetIntradayPrices(ctx context.Context, symbol string, interval models.Interval) (*models.SymbolIntradayPrice, error) {
+	if interval.Duration() == 0 {
+		return nil, fmt.Errorf("unsupported interval: %s", interval)
+	}
+
+	params := url.Values{}
+	params.Set("function", "TIME_SERIES_INTRADAY")
+	params.Set("symbol", symbol)
+	params.Set("interval", string(interval))
+	params.Set("extended_hours", "false")
+	params.Set("apikey", a.APIKey)
+	params.Set("outputsize", "full")
+
+	resp, err := a.makeRequest(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result map[string]json.RawMessage
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse Alpha Vantage response: %w", err)
+	}
+	var metaData map[string]string
+	if raw, ok := result["Meta Data"]; ok {
+		json.Unmarshal(raw, &metaData)
+	}
+	var timeSeries map[string]map[string]string
+	if raw, ok := result[fmt.Sprintf("Time Series (%s)", interval)]; ok {
+		if err := json.Unmarshal(raw, &timeSeries); err != nil {
+			return nil, fmt.Errorf("failed to parse Alpha Vantage intraday series: %w", err)
+		}
+	}
+	if len(timeSeries) == 0 {
+		return nil, fmt.Errorf("no intraday data available for %s", symbol)
+	}
+
+	location := calendar.ForSymbol(symbol).Location
+	if timezone := metaData["6. Time Zone"]; timezone != "" {
+		if loaded, err := time.LoadLocation(timezone); err == nil {
+			location = loaded
+		}
+	}
+
+	prices := make([]models.IntradayPrice, 0, len(timeSeries))
+	for timestamp, data := range timeSeries {
+		at, err := time.ParseInLocation("2006-01-02 15:04:05", timestamp, location)
+		if err != nil {
+			continue
+		}
+		if closePrice, ok := data["4. close"]; ok {
+			if price, err := strconv.ParseFloat(closePrice, 64); err == nil {
+				prices = append(prices, models.IntradayPrice{Time: at.UTC(), Price: price})
+			}
+		}
+	}
+
+	sort.Slice(prices, func(i, j int) bool {
+		return prices[i].Time.After(prices[j].Time)
+	})
+
+	return &models.SymbolIntradayPrice{
+		Symbol:         symbol,
+		Resolution:     models.ResolutionIntraday,
+		Interval:       interval,
+		IntradayPrices: prices,
+	}, nil
+}
+
+// GetHistoricalFXRates retrieves the daily FX series for a currency pair, sorted newest to oldest
+func (a *AlphaVantageProvider) GetHistoricalFXRates(ctx context.Context, base, quote string) (*models.CurrencyPairRates, error) {
+	params := url.Values{}
+	params.Set("function", "FX_DAILY")
+	params.Set("from_symbol", base)
+	params.Set("to_symbol", quote)
+	params.Set("apikey", a.APIKey)
+	params.Set("outputsize", "full")
+
+	resp, err := a.makeRequest(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		TimeSeries map[string]map[string]string `json:"Time Series FX (Daily)"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse Alpha Vantage FX response: %w", err)
+	}
+	if len(result.TimeSeries) == 0 {
+		return nil, fmt.Errorf("no FX data available for %s/%s", base, quote)
+	}
+
+	rates := make([]models.FXRate, 0, len(result.TimeSeries))
+	for dateStr, data := range result.TimeSeries {
+		if closeRate, ok := data["4. close"]; ok {
+			if rate, err := strconv.ParseFloat(closeRate, 64); err == nil {
+				rates = append(rates, models.FXRate{
+					Date: dateStr,
+					Rate: rate,
+				})
+			}
+		}
+	}
+
+	// Dates are YYYY-MM-DD, so string order is chronological order
+	sort.Slice(rates, func(i, j int) bool {
+		return rates[i].Date > rates[j].Date
+	})
+
+	return &models.CurrencyPairRates{
+		Base:  base,
+		Quote: quote,
+		Rates: rates,
+	}, nil
+}
+
 func (a *AlphaVantageProvider) makeRequest(ctx context.Context, params url.Values) ([]byte, error) {
 	reqURL := fmt.Sprintf("%s?%s", a.BaseURL, params.Encode())
 
@@ -177,6 +368,9 @@ func (a *AlphaVantageProvider) makeRequest(ctx context.Context, params url.Value
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("%w: alpha vantage API status %d", ErrRateLimited, resp.StatusCode)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("alpha vantage API error: %d", resp.StatusCode)
 	}
@@ -186,6 +380,23 @@ func (a *AlphaVantageProvider) makeRequest(ctx context.Context, params url.Value
 		return nil, err
 	}
 
+	// Alpha Vantage answers errors with status 200 and a message in place of the data
+	var message struct {
+		ErrorMessage string `json:"Error Message"`
+		Note         string `json:"Note"`
+		Information  string `json:"Information"`
+	}
+	if json.Unmarshal(body, &message) == nil {
+		switch {
+		case message.ErrorMessage != "":
+			return nil, fmt.Errorf("alpha vantage API error: %s", message.ErrorMessage)
+		case message.Note != "":
+			return nil, fmt.Errorf("%w: %s", ErrRateLimited, message.Note)
+		case message.Information != "":
+			return nil, fmt.Errorf("%w: %s", ErrRateLimited, message.Information)
+		}
+	}
+
 	return body, nil
 }
 
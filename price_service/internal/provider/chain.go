@@ -0,0 +1,235 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/transaction-tracker/price_service/internal/calendar"
+	"github.com/transaction-tracker/price_service/internal/config"
+	"github.com/transaction-tracker/price_service/internal/models"
+)
+
+// Capability is a kind of price data providers serve, each with chains of its own
+type Capability string
+
+const (
+	CapabilityCurrent    Capability = "current"
+	CapabilityHistorical Capability = "historical"
+	CapabilityIntraday   Capability = "intraday"
+	CapabilityFX         Capability = "fx"
+)
+
+// Capabilities lists every capability in the order chains are reported
+var Capabilities = []Capability{CapabilityCurrent, CapabilityHistorical, CapabilityIntraday, CapabilityFX}
+
+const (
+	// DefaultMarket names the chain used for markets without a chain of their own
+	DefaultMarket = "DEFAULT"
+	// CryptoMarket names the chain used for crypto pairs
+	CryptoMarket = "CRYPTO"
+	// TPExMarket names the chain used for stocks traded on the Taipei Exchange (.TWO), which follow
+	// the TWSE calendar but are quoted by TPEx
+	TPExMarket = "TPEX"
+)
+
+const (
+	// providerFailureThreshold is the number of consecutive failures that takes a provider out of rotation
+	providerFailureThreshold = 3
+	// providerCooldown is how long a provider out of rotation is only tried after the healthy ones
+	providerCooldown = time.Minute
+)
+
+// defaultChains are the chains of each capability and market the configuration does not override
+var defaultChains = map[Capability]map[string][]string{
+	CapabilityCurrent: {
+		DefaultMarket: {"finnhub", "alphavantage"},
+		CryptoMarket:  {"coinbase"},
+		"TWSE":        {"twse", "yahoo"},
+		TPExMarket:    {"tpex", "yahoo"},
+		"TSX":         {"yahoo", "alphavantage"},
+		"LSE":         {"yahoo", "alphavantage"},
+	},
+	CapabilityHistorical: {
+		DefaultMarket: {"alphavantage"},
+		CryptoMarket:  {"coinbase"},
+		// The exchanges' reports only reach back a year, so history older than that comes from Yahoo
+		"TWSE":     {"yahoo", "twse"},
+		TPExMarket: {"yahoo", "tpex"},
+		"TSX":      {"yahoo", "alphavantage"},
+		"LSE":      {"yahoo", "alphavantage"},
+	},
+	CapabilityIntraday: {
+		DefaultMarket: {"alphavantage"},
+		CryptoMarket:  {"coinbase"},
+		"TWSE":        {"yahoo"},
+		TPExMarket:    {"yahoo"},
+		"TSX":         {"yahoo", "alphavantage"},
+		"LSE":         {"yahoo", "alphavantage"},
+	},
+	CapabilityFX: {
+		DefaultMarket: {"alphavantage"},
+	},
+}
+
+// MarketForSymbol returns the market whose chain serves a symbol: CRYPTO for crypto pairs, TPEX
+// for .TWO symbols, and otherwise the code of the exchange whose calendar it trades on, such as
+// NYSE or TWSE
+func MarketForSymbol(symbol string) string {
+	if IsCryptoSymbol(symbol) {
+		return CryptoMarket
+	}
+	if strings.HasSuffix(strings.ToUpper(symbol), ".TWO") {
+		return TPExMarket
+	}
+	return calendar.ForSymbol(symbol).Code
+}
+
+// supports reports whether a provider implements the interface of a capability
+func supports(p interface{}, capability Capability) bool {
+	var ok bool
+	switch capability {
+	case CapabilityCurrent:
+		_, ok = p.(CurrentPriceProvider)
+	case CapabilityHistorical:
+		_, ok = p.(HistoricalPriceProvider)
+	case CapabilityIntraday:
+		_, ok = p.(IntradayPriceProvider)
+	case CapabilityFX:
+		_, ok = p.(FXRateProvider)
+	}
+	return ok
+}
+
+// buildChains merges the configured chains over the default ones, checking that every provider
+// named is registered and serves the capability of its chain
+func buildChains(configured config.ProviderChainsConfig, providers map[string]interface{}) (map[Capability]map[string][]string, error) {
+	overrides := map[Capability]map[string][]string{
+		CapabilityCurrent:    configured.Current,
+		CapabilityHistorical: configured.Historical,
+		CapabilityIntraday:   configured.Intraday,
+		CapabilityFX:         configured.FX,
+	}
+
+	chains := make(map[Capability]map[string][]string, len(Capabilities))
+	for _, capability := range Capabilities {
+		chains[capability] = make(map[string][]string)
+		for market, names := range defaultChains[capability] {
+			chains[capability][market] = names
+		}
+		for market, names := range overrides[capability] {
+			chains[capability][strings.ToUpper(market)] = names
+		}
+
+		for market, names := range chains[capability] {
+			for _, name := range names {
+				p, ok := providers[name]
+				if !ok {
+					return nil, fmt.Errorf("unknown provider %s in the %s chain of %s", name, capability, market)
+				}
+				if !supports(p, capability) {
+					return nil, fmt.Errorf("provider %s does not serve %s prices, in the chain of %s", name, capability, market)
+				}
+			}
+		}
+	}
+	return chains, nil
+}
+
+// providerHealth tracks the recent failures of a provider. A provider that fails
+// providerFailureThreshold times in a row, or is rate limited, leaves rotation for providerCooldown.
+type providerHealth struct {
+	mutex               sync.Mutex
+	consecutiveFailures int
+	lastError           string
+	lastFailure         time.Time
+	unhealthyUntil      time.Time
+}
+
+func (h *providerHealth) healthy(now time.Time) bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return !now.Before(h.unhealthyUntil)
+}
+
+func (h *providerHealth) recordSuccess() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.consecutiveFailures = 0
+	h.unhealthyUntil = time.Time{}
+}
+
+func (h *providerHealth) recordFailure(err error, now time.Time) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.consecutiveFailures++
+	h.lastError = err.Error()
+	h.lastFailure = now
+	if h.consecutiveFailures >= providerFailureThreshold || errors.Is(err, ErrRateLimited) {
+		h.unhealthyUntil = now.Add(providerCooldown)
+	}
+}
+
+func (h *providerHealth) status(name string, now time.Time) models.ProviderStatus {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	status := models.ProviderStatus{
+		Name:                name,
+		Healthy:             !now.Before(h.unhealthyUntil),
+		ConsecutiveFailures: h.consecutiveFailures,
+		LastError:           h.lastError,
+	}
+	if !h.lastFailure.IsZero() {
+		lastFailure := h.lastFailure
+		status.LastFailure = &lastFailure
+	}
+	if !status.Healthy {
+		retryAfter := h.unhealthyUntil
+		status.RetryAfter = &retryAfter
+	}
+	return status
+}
+
+// chainStatus lists the chains sorted by capability then market, and each provider's health
+func chainStatus(chains map[Capability]map[string][]string, providers map[string]interface{}, health map[string]*providerHealth, now time.Time) models.ProvidersOverview {
+	overview := models.ProvidersOverview{
+		Providers: make([]models.ProviderStatus, 0, len(providers)),
+		Chains:    []models.ProviderChain{},
+	}
+
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		status := health[name].status(name, now)
+		status.Capabilities = []string{}
+		for _, capability := range Capabilities {
+			if supports(providers[name], capability) {
+				status.Capabilities = append(status.Capabilities, string(capability))
+			}
+		}
+		overview.Providers = append(overview.Providers, status)
+	}
+
+	for _, capability := range Capabilities {
+		markets := make([]string, 0, len(chains[capability]))
+		for market := range chains[capability] {
+			markets = append(markets, market)
+		}
+		sort.Strings(markets)
+		for _, market := range markets {
+			overview.Chains = append(overview.Chains, models.ProviderChain{
+				Capability: string(capability),
+				Market:     market,
+				Providers:  chains[capability][market],
+			})
+		}
+	}
+	return overview
+}
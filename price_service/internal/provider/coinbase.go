@@ -0,0 +1,366 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/transaction-tracker/price_service/internal/models"
+)
+
+// cryptoSymbolRegex matches crypto pairs of a coin and the currency it is quoted in, e.g. BTC-USD
+var cryptoSymbolRegex = regexp.MustCompile(`^[A-Z0-9]{2,10}-[A-Z]{3,4}$`)
+
+// IsCryptoSymbol reports whether a symbol is a crypto pair such as BTC-USD. Crypto trades around
+// the clock, so these symbols have a price for every day of the week and no market calendar.
+func IsCryptoSymbol(symbol string) bool {
+	return cryptoSymbolRegex.MatchString(strings.ToUpper(symbol))
+}
+
+const (
+	// coinbaseDailyGranularity is the candle width in seconds for daily candles
+	coinbaseDailyGranularity = 86400
+	// coinbaseMaxCandles is the most candles Coinbase returns for a single request
+	coinbaseMaxCandles = 300
+	// coinbaseMaxPages bounds how far back daily history is paged, about ten years
+	coinbaseMaxPages = 13
+)
+
+// coinbaseIntradayLookback is how far back intraday candles are fetched for each interval, keeping
+// each fetch to a handful of pages
+var coinbaseIntradayLookback = map[models.Interval]time.Duration{
+	models.Interval1Min:  24 * time.Hour,
+	models.Interval5Min:  7 * 24 * time.Hour,
+	models.Interval15Min: 14 * 24 * time.Hour,
+	models.Interval60Min: 30 * 24 * time.Hour,
+}
+
+// CoinbaseProvider handles crypto prices from the public Coinbase Exchange market data API,
+// which needs no API key
+type CoinbaseProvider struct {
+	BaseURL string
+	client  *http.Client
+}
+
+// CoinbaseProductResponse represents a trading pair from Coinbase
+type CoinbaseProductResponse struct {
+	ID            string `json:"id"`
+	BaseCurrency  string `json:"base_currency"`
+	QuoteCurrency string `json:"quote_currency"`
+	DisplayName   string `json:"display_name"`
+	Status        string `json:"status"`
+}
+
+// CoinbaseStatsResponse represents the 24 hour stats of a product from Coinbase
+type CoinbaseStatsResponse struct {
+	Open   string `json:"open"` // Price 24 hours ago
+	High   string `json:"high"`
+	Low    string `json:"low"`
+	Last   string `json:"last"` // Last traded price
+	Volume string `json:"volume"`
+}
+
+func NewCoinbaseProvider(baseURL string) *CoinbaseProvider {
+	return &CoinbaseProvider{
+		BaseURL: baseURL,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// GetCurrentPrices retrieves the last traded price of crypto pairs. With no daily close, the
+// change is measured against the price 24 hours ago.
+func (c *CoinbaseProvider) GetCurrentPrices(ctx context.Context, symbols []string) ([]models.SymbolCurrentPrice, error) {
+	var prices []models.SymbolCurrentPrice
+	var lastErr error
+
+	for _, symbol := range symbols {
+		price, err := c.getCurrentPriceForSymbol(ctx, symbol)
+		if err != nil {
+			log.Printf("Error fetching current price for symbol %s: %v", symbol, err)
+			// Continue with other symbols instead of failing completely
+			if !errors.Is(err, ErrSymbolNotFound) {
+				lastErr = err
+			}
+			continue
+		}
+		prices = append(prices, price)
+	}
+
+	// Failing for every symbol, such as when rate limited, fails the request
+	if len(prices) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return prices, nil
+}
+
+func (c *CoinbaseProvider) getCurrentPriceForSymbol(ctx context.Context, symbol string) (models.SymbolCurrentPrice, error) {
+	symbol = strings.ToUpper(symbol)
+	body, err := c.makeRequest(ctx, fmt.Sprintf("%s/products/%s/stats", c.BaseURL, url.PathEscape(symbol)))
+	if err != nil {
+		return models.SymbolCurrentPrice{}, err
+	}
+
+	var stats CoinbaseStatsResponse
+	if err := json.Unmarshal(body, &stats); err != nil {
+		return models.SymbolCurrentPrice{}, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	last, err := strconv.ParseFloat(stats.Last, 64)
+	if err != nil || last == 0 {
+		return models.SymbolCurrentPrice{}, fmt.Errorf("%w: %s", ErrSymbolNotFound, symbol)
+	}
+	open, _ := strconv.ParseFloat(stats.Open, 64)
+
+	price := models.SymbolCurrentPrice{
+		Symbol:        symbol,
+		CurrentPrice:  last,
+		Currency:      symbol[strings.LastIndex(symbol, "-")+1:],
+		PreviousClose: open,
+		Timestamp:     time.Now(),
+	}
+	if open > 0 {
+		price.Change = last - open
+		price.ChangePercent = price.Change / open * 100
+	}
+	return price, nil
+}
+
+// GetSymbolInfo retrieves the reference data of a crypto pair from its Coinbase product. Coins
+// are named by their ticker, e.g. BTC.
+func (c *CoinbaseProvider) GetSymbolInfo(ctx context.Context, symbol string) (*models.SymbolInfo, error) {
+	symbol = strings.ToUpper(symbol)
+	body, err := c.makeRequest(ctx, fmt.Sprintf("%s/products/%s", c.BaseURL, url.PathEscape(symbol)))
+	if err != nil {
+		return nil, err
+	}
+
+	var product CoinbaseProductResponse
+	if err := json.Unmarshal(body, &product); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	return &models.SymbolInfo{
+		Symbol:     symbol,
+		Name:       product.BaseCurrency,
+		AssetClass: "crypto",
+		Exchange:   "Coinbase",
+		Currency:   strings.ToUpper(product.QuoteCurrency),
+	}, nil
+}
+
+// GetHistoricalPrices retrieves the daily closes of a crypto pair, sorted newest to oldest. Weekly
+// and monthly closes are the last daily close of each week (ending Sunday) and month.
+func (c *CoinbaseProvider) GetHistoricalPrices(ctx context.Context, symbol string, resolution models.Resolution) (*models.SymbolHistoricalPrice, error) {
+	if resolution != models.ResolutionDaily && resolution != models.ResolutionWeekly && resolution != models.ResolutionMonthly {
+		return nil, fmt.Errorf("unsupported resolution: %s", resolution)
+	}
+
+	symbol = strings.ToUpper(symbol)
+	daily, err := c.getDailyCloses(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	if len(daily) == 0 {
+		return nil, fmt.Errorf("no historical data available for %s", symbol)
+	}
+
+	prices := daily
+	if resolution != models.ResolutionDaily {
+		prices = lastClosePerPeriod(daily, resolution)
+	}
+
+	return &models.SymbolHistoricalPrice{
+		Symbol:           symbol,
+		Resolution:       resolution,
+		HistoricalPrices: prices,
+	}, nil
+}
+
+// getDailyCloses pages back through daily candles until Coinbase has no older ones
+func (c *CoinbaseProvider) getDailyCloses(ctx context.Context, symbol string) ([]models.ClosePrice, error) {
+	closes := make(map[string]float64)
+	end := time.Now().UTC()
+	for page := 0; page < coinbaseMaxPages; page++ {
+		start := end.Add(-coinbaseMaxCandles * coinbaseDailyGranularity * time.Second)
+
+		params := url.Values{}
+		params.Set("granularity", strconv.Itoa(coinbaseDailyGranularity))
+		params.Set("start", start.Format(time.RFC3339))
+		params.Set("end", end.Format(time.RFC3339))
+		body, err := c.makeRequest(ctx, fmt.Sprintf("%s/products/%s/candles?%s", c.BaseURL, url.PathEscape(symbol), params.Encode()))
+		if err != nil {
+			// Older pages failing still leaves the recent history
+			if page > 0 {
+				log.Printf("Error fetching older candles for %s: %v", symbol, err)
+				break
+			}
+			return nil, err
+		}
+
+		// Each candle is [time, low, high, open, close, volume]
+		var candles [][]float64
+		if err := json.Unmarshal(body, &candles); err != nil {
+			return nil, fmt.Errorf("failed to parse Coinbase candles: %w", err)
+		}
+		if len(candles) == 0 {
+			break
+		}
+		for _, candle := range candles {
+			if len(candle) < 5 {
+				continue
+			}
+			date := time.Unix(int64(candle[0]), 0).UTC().Format("2006-01-02")
+			closes[date] = candle[4]
+		}
+		end = start
+	}
+
+	prices := make([]models.ClosePrice, 0, len(closes))
+	for date, price := range closes {
+		prices = append(prices, models.ClosePrice{Date: date, Price: price})
+	}
+
+	// Dates are YYYY-MM-DD, so string order is chronological order
+	sort.Slice(prices, func(i, j int) bool {
+		return prices[i].Date > prices[j].Date
+	})
+	return prices, nil
+}
+
+// GetIntradayPrices retrieves the intraday candles of a crypto pair over the lookback of the
+// interval, sorted newest to oldest. Candle widths match the intervals: 60, 300, 900 and 3600 seconds.
+func (c *CoinbaseProvider) GetIntradayPrices(ctx context.Context, symbol string, interval models.Interval) (*models.SymbolIntradayPrice, error) {
+	lookback, ok := coinbaseIntradayLookback[interval]
+	if !ok {
+		return nil, fmt.Errorf("unsupported interval: %s", interval)
+	}
+
+	symbol = strings.ToUpper(symbol)
+	width := interval.Duration()
+	closes := make(map[int64]float64)
+	end := time.Now().UTC()
+	oldest := end.Add(-lookback)
+	for end.After(oldest) {
+		start := end.Add(-coinbaseMaxCandles * width)
+		if start.Before(oldest) {
+			start = oldest
+		}
+
+		params := url.Values{}
+		params.Set("granularity", strconv.Itoa(int(width/time.Second)))
+		params.Set("start", start.Format(time.RFC3339))
+		params.Set("end", end.Format(time.RFC3339))
+		body, err := c.makeRequest(ctx, fmt.Sprintf("%s/products/%s/candles?%s", c.BaseURL, url.PathEscape(symbol), params.Encode()))
+		if err != nil {
+			// Older pages failing still leaves the recent bars
+			if len(closes) > 0 {
+				log.Printf("Error fetching older intraday candles for %s: %v", symbol, err)
+				break
+			}
+			return nil, err
+		}
+
+		// Each candle is [time, low, high, open, close, volume]
+		var candles [][]float64
+		if err := json.Unmarshal(body, &candles); err != nil {
+			return nil, fmt.Errorf("failed to parse Coinbase candles: %w", err)
+		}
+		for _, candle := range candles {
+			if len(candle) < 5 {
+				continue
+			}
+			closes[int64(candle[0])] = candle[4]
+		}
+		end = start
+	}
+	if len(closes) == 0 {
+		return nil, fmt.Errorf("no intraday data available for %s", symbol)
+	}
+
+	prices := make([]models.IntradayPrice, 0, len(closes))
+	for at, price := range closes {
+		prices = append(prices, models.IntradayPrice{Time: time.Unix(at, 0).UTC(), Price: price})
+	}
+	sort.Slice(prices, func(i, j int) bool {
+		return prices[i].Time.After(prices[j].Time)
+	})
+
+	return &models.SymbolIntradayPrice{
+		Symbol:         symbol,
+		Resolution:     models.ResolutionIntraday,
+		Interval:       interval,
+		IntradayPrices: prices,
+	}, nil
+}
+
+// lastClosePerPeriod keeps the latest of daily closes sorted newest to oldest in each week or month
+func lastClosePerPeriod(daily []models.ClosePrice, resolution models.Resolution) []models.ClosePrice {
+	var prices []models.ClosePrice
+	seen := make(map[string]bool)
+	for _, price := range daily {
+		date, err := time.Parse("2006-01-02", price.Date)
+		if err != nil {
+			continue
+		}
+		var period string
+		if resolution == models.ResolutionMonthly {
+			period = date.Format("2006-01")
+		} else {
+			// ISO weeks run Monday to Sunday
+			year, week := date.ISOWeek()
+			period = fmt.Sprintf("%d-%02d", year, week)
+		}
+		if !seen[period] {
+			seen[period] = true
+			prices = append(prices, price)
+		}
+	}
+	return prices
+}
+
+func (c *CoinbaseProvider) makeRequest(ctx context.Context, url string) ([]byte, error) {
+	log.Printf("Coinbase API Request: %s", url)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	// Coinbase rejects requests without a user agent
+	req.Header.Set("User-Agent", "transaction-tracker-price-service")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrSymbolNotFound, string(body))
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("%w: %s", ErrRateLimited, string(body))
+	}
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("Coinbase API Error Response: %s", string(body))
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return body, nil
+}
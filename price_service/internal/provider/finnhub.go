@@ -3,10 +3,12 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -32,6 +34,30 @@ type FinnhubQuoteResponse struct {
 	Timestamp     int64   `json:"t"`  // Timestamp
 }
 
+// FinnhubProfileResponse represents the response structure from Finnhub company profile API
+type FinnhubProfileResponse struct {
+	Country  string `json:"country"`
+	Currency string `json:"currency"`
+	Exchange string `json:"exchange"`
+	Industry string `json:"finnhubIndustry"`
+	Name     string `json:"name"`
+	Ticker   string `json:"ticker"`
+}
+
+// FinnhubSearchResult represents a symbol found by Finnhub symbol search API
+type FinnhubSearchResult struct {
+	Description   string `json:"description"`
+	DisplaySymbol string `json:"displaySymbol"`
+	Symbol        string `json:"symbol"`
+	Type          string `json:"type"` // Security type, e.g. Common Stock or ETP
+}
+
+// FinnhubSearchResponse represents the response structure from Finnhub symbol search API
+type FinnhubSearchResponse struct {
+	Count  int                   `json:"count"`
+	Result []FinnhubSearchResult `json:"result"`
+}
+
 func NewFinnhubProvider(apiKey, baseURL string) *FinnhubProvider {
 	return &FinnhubProvider{
 		APIKey:  apiKey,
@@ -44,6 +70,7 @@ func NewFinnhubProvider(apiKey, baseURL string) *FinnhubProvider {
 
 func (f *FinnhubProvider) GetCurrentPrices(ctx context.Context, symbols []string) ([]models.SymbolCurrentPrice, error) {
 	var prices []models.SymbolCurrentPrice
+	var lastErr error
 
 	// Finnhub requires individual requests for each symbol for the quote endpoint
 	for _, symbol := range symbols {
@@ -51,11 +78,18 @@ func (f *FinnhubProvider) GetCurrentPrices(ctx context.Context, symbols []string
 		if err != nil {
 			log.Printf("Error fetching current price for symbol %s: %v", symbol, err)
 			// Continue with other symbols instead of failing completely
+			if !errors.Is(err, ErrSymbolNotFound) {
+				lastErr = err
+			}
 			continue
 		}
 		prices = append(prices, price)
 	}
 
+	// Failing for every symbol, such as when rate limited, fails the request
+	if len(prices) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
 	return prices, nil
 }
 
@@ -74,7 +108,7 @@ func (f *FinnhubProvider) getCurrentPriceForSymbol(ctx context.Context, symbol s
 
 	// Check if we got valid data (Finnhub returns 0 values for invalid symbols)
 	if quoteResp.CurrentPrice == 0 && quoteResp.Timestamp == 0 {
-		return models.SymbolCurrentPrice{}, fmt.Errorf("invalid or not found symbol: %s", symbol)
+		return models.SymbolCurrentPrice{}, fmt.Errorf("%w: %s", ErrSymbolNotFound, symbol)
 	}
 
 	return models.SymbolCurrentPrice{
@@ -87,6 +121,84 @@ func (f *FinnhubProvider) getCurrentPriceForSymbol(ctx context.Context, symbol s
 	}, nil
 }
 
+// GetSymbolInfo retrieves the reference data of a symbol from its company profile. Funds have no
+// profile, so those are looked up through symbol search instead.
+func (f *FinnhubProvider) GetSymbolInfo(ctx context.Context, symbol string) (*models.SymbolInfo, error) {
+	symbol = strings.ToUpper(symbol)
+	reqURL := fmt.Sprintf("%s/stock/profile2?symbol=%s&token=%s", f.BaseURL, url.QueryEscape(symbol), f.APIKey)
+
+	body, err := f.makeRequest(ctx, reqURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var profile FinnhubProfileResponse
+	if err := json.Unmarshal(body, &profile); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	// Finnhub returns an empty profile for symbols without one
+	if profile.Name != "" {
+		return &models.SymbolInfo{
+			Symbol:     symbol,
+			Name:       profile.Name,
+			AssetClass: "equity",
+			Exchange:   profile.Exchange,
+			Currency:   strings.ToUpper(profile.Currency),
+			Country:    strings.ToUpper(profile.Country),
+			Sector:     profile.Industry,
+		}, nil
+	}
+
+	results, err := f.SearchSymbols(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		if result.Symbol == symbol {
+			return &result, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrSymbolNotFound, symbol)
+}
+
+// SearchSymbols finds symbols whose ticker or name matches a query
+func (f *FinnhubProvider) SearchSymbols(ctx context.Context, query string) ([]models.SymbolInfo, error) {
+	reqURL := fmt.Sprintf("%s/search?q=%s&token=%s", f.BaseURL, url.QueryEscape(query), f.APIKey)
+
+	body, err := f.makeRequest(ctx, reqURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var searchResp FinnhubSearchResponse
+	if err := json.Unmarshal(body, &searchResp); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	results := make([]models.SymbolInfo, 0, len(searchResp.Result))
+	for _, result := range searchResp.Result {
+		results = append(results, models.SymbolInfo{
+			Symbol:     strings.ToUpper(result.Symbol),
+			Name:       result.Description,
+			AssetClass: finnhubAssetClass(result.Type),
+		})
+	}
+	return results, nil
+}
+
+// finnhubAssetClass maps a Finnhub security type onto an asset class
+func finnhubAssetClass(securityType string) string {
+	switch securityType {
+	case "":
+		return ""
+	case "ETP":
+		return "etf"
+	default:
+		return "equity"
+	}
+}
+
 func (f *FinnhubProvider) makeRequest(ctx context.Context, url string) ([]byte, error) {
 	// Log the request URL for debugging (sanitize API key for security)
 	sanitizedURL := strings.Replace(url, f.APIKey, "[API_KEY]", -1)
@@ -103,6 +215,9 @@ func (f *FinnhubProvider) makeRequest(ctx context.Context, url string) ([]byte,
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("%w: finnhub API status %d", ErrRateLimited, resp.StatusCode)
+	}
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		log.Printf("Finnhub API Error Response: %s", string(body))
@@ -2,29 +2,85 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
 
 	"github.com/transaction-tracker/price_service/internal/config"
 	"github.com/transaction-tracker/price_service/internal/models"
 )
 
-// StockPriceProvider defines the interface for stock price data providers
-type StockPriceProvider interface {
-	// GetCurrentPrices retrieves current prices for multiple symbols
+// CurrentPriceProvider defines the interface for providers of current prices
+type CurrentPriceProvider interface {
+	// GetCurrentPrices retrieves current prices for multiple symbols, leaving out those it has no price for
 	GetCurrentPrices(ctx context.Context, symbols []string) ([]models.SymbolCurrentPrice, error)
+}
 
+// HistoricalPriceProvider defines the interface for providers of daily, weekly and monthly closes
+type HistoricalPriceProvider interface {
 	// GetHistoricalPrices retrieves historical prices for a single symbol
 	GetHistoricalPrices(ctx context.Context, symbol string, resolution models.Resolution) (*models.SymbolHistoricalPrice, error)
 }
 
-// ThirdPartyProviderMap handles all price-related operations with built-in provider routing
-// Implements StockPriceProvider by routing to appropriate third-party providers
+// IntradayPriceProvider defines the interface for providers of intraday bars
+type IntradayPriceProvider interface {
+	// GetIntradayPrices retrieves the recent intraday bars of a single symbol
+	GetIntradayPrices(ctx context.Context, symbol string, interval models.Interval) (*models.SymbolIntradayPrice, error)
+}
+
+// StockPriceProvider defines the interface for stock price data providers
+type StockPriceProvider interface {
+	CurrentPriceProvider
+	HistoricalPriceProvider
+	IntradayPriceProvider
+}
+
+// FXRateProvider defines the interface for foreign exchange rate providers
+type FXRateProvider interface {
+	// GetHistoricalFXRates retrieves daily closing rates for converting base into quote currency
+	GetHistoricalFXRates(ctx context.Context, base, quote string) (*models.CurrencyPairRates, error)
+}
+
+// ErrSymbolNotFound is returned by providers for symbols they do not know
+var ErrSymbolNotFound = errors.New("symbol not found")
+
+// ErrRateLimited is returned by providers whose API refused a request for exceeding its rate limit
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// SymbolInfoProvider defines the interface for symbol reference data providers
+type SymbolInfoProvider interface {
+	// GetSymbolInfo retrieves the reference data of a symbol, or ErrSymbolNotFound
+	GetSymbolInfo(ctx context.Context, symbol string) (*models.SymbolInfo, error)
+
+	// SearchSymbols finds symbols whose ticker or name matches a query
+	SearchSymbols(ctx context.Context, query string) ([]models.SymbolInfo, error)
+}
+
+// NamedProvider is a provider registered under the name chains refer to it by. Provider implements
+// the interfaces of the capabilities it serves, such as CurrentPriceProvider.
+type NamedProvider struct {
+	Name     string
+	Provider interface{}
+}
+
+// ThirdPartyProviderMap handles all price-related operations with built-in provider routing.
+// Each capability has an ordered chain of providers per market; a provider that fails, or has no
+// price for a symbol, falls back to the next one. Providers failing repeatedly or rate limited are
+// tried last until they cool down. Implements StockPriceProvider and FXRateProvider.
 type ThirdPartyProviderMap struct {
-	alphaVantage *AlphaVantageProvider
-	finnhub      *FinnhubProvider
+	finnhub  *FinnhubProvider
+	coinbase *CoinbaseProvider
+
+	providers map[string]interface{}
+	health    map[string]*providerHealth
+	chains    map[Capability]map[string][]string
 }
 
-// NewThirdPartyProviderMap creates a new price service with both providers
-func NewThirdPartyProviderMap(cfg *config.Config) (*ThirdPartyProviderMap, error) {
+// NewThirdPartyProviderMap creates a new price service with the stock and crypto providers, plus
+// any extra providers the configured chains can name
+func NewThirdPartyProviderMap(cfg *config.Config, extra ...NamedProvider) (*ThirdPartyProviderMap, error) {
 	// Initialize Alpha Vantage for historical data
 	alphaVantage := NewAlphaVantageProvider(cfg.StockAPI.AlphaVantage.APIKey)
 	if cfg.StockAPI.AlphaVantage.BaseURL != "" {
@@ -34,18 +90,213 @@ func NewThirdPartyProviderMap(cfg *config.Config) (*ThirdPartyProviderMap, error
 	// Initialize Finnhub for current prices
 	finnhub := NewFinnhubProvider(cfg.StockAPI.Finnhub.APIKey, cfg.StockAPI.Finnhub.BaseURL)
 
+	// Initialize Coinbase for crypto pairs, current and historical
+	coinbase := NewCoinbaseProvider(cfg.StockAPI.Coinbase.BaseURL)
+
+	providers := map[string]interface{}{
+		"alphavantage": alphaVantage,
+		"finnhub":      finnhub,
+		"coinbase":     coinbase,
+		// Daily quotes of Taiwan listed and OTC stocks
+		"twse": NewTWSEProvider(cfg.StockAPI.TWSE.BaseURL),
+		"tpex": NewTPExProvider(cfg.StockAPI.TPEx.BaseURL),
+		// Prices on most exchanges, for markets the others do not serve
+		"yahoo": NewYahooProvider(cfg.StockAPI.Yahoo.BaseURL),
+	}
+	for _, named := range extra {
+		name := strings.ToLower(named.Name)
+		if _, ok := providers[name]; ok {
+			return nil, fmt.Errorf("provider %s is already registered", name)
+		}
+		providers[name] = named.Provider
+	}
+
+	chains, err := buildChains(cfg.StockAPI.Chains, providers)
+	if err != nil {
+		return nil, err
+	}
+
+	health := make(map[string]*providerHealth, len(providers))
+	for name := range providers {
+		health[name] = &providerHealth{}
+	}
+
 	return &ThirdPartyProviderMap{
-		alphaVantage: alphaVantage,
-		finnhub:      finnhub,
+		finnhub:   finnhub,
+		coinbase:  coinbase,
+		providers: providers,
+		health:    health,
+		chains:    chains,
 	}, nil
 }
 
-// GetCurrentPrices uses Finnhub (fast, real-time)
+// chain returns the providers to try for a capability in a market: its own chain or the default
+// one, with providers out of rotation moved to the end
+func (t *ThirdPartyProviderMap) chain(capability Capability, market string) []string {
+	names, ok := t.chains[capability][market]
+	if !ok {
+		names = t.chains[capability][DefaultMarket]
+	}
+
+	now := time.Now()
+	var healthy, unhealthy []string
+	for _, name := range names {
+		if t.health[name].healthy(now) {
+			healthy = append(healthy, name)
+		} else {
+			unhealthy = append(unhealthy, name)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+// recordFailure counts a failed call against a provider. Not knowing a symbol is not a failure.
+func (t *ThirdPartyProviderMap) recordFailure(capability Capability, name string, err error) {
+	log.Printf("Provider %s failed to serve %s prices: %v", name, capability, err)
+	if !errors.Is(err, ErrSymbolNotFound) {
+		t.health[name].recordFailure(err, time.Now())
+	}
+}
+
+// serve calls fn with each provider in the chain of a capability and market until one succeeds,
+// returning the name of the provider that served
+func (t *ThirdPartyProviderMap) serve(capability Capability, market string, fn func(p interface{}) error) (string, error) {
+	var lastErr error
+	for _, name := range t.chain(capability, market) {
+		err := fn(t.providers[name])
+		if err == nil {
+			t.health[name].recordSuccess()
+			return name, nil
+		}
+		t.recordFailure(capability, name, err)
+		lastErr = err
+	}
+	if lastErr == nil {
+		return "", fmt.Errorf("no %s price provider for market %s", capability, market)
+	}
+	return "", fmt.Errorf("all %s price providers failed: %w", capability, lastErr)
+}
+
+// GetCurrentPrices tries the current price chain of each symbol's market, passing the symbols one
+// provider has no price for on to the next. It fails only when no price is found and a provider failed.
 func (t *ThirdPartyProviderMap) GetCurrentPrices(ctx context.Context, symbols []string) ([]models.SymbolCurrentPrice, error) {
-	return t.finnhub.GetCurrentPrices(ctx, symbols)
+	var markets []string
+	byMarket := make(map[string][]string)
+	for _, symbol := range symbols {
+		market := MarketForSymbol(symbol)
+		if _, ok := byMarket[market]; !ok {
+			markets = append(markets, market)
+		}
+		byMarket[market] = append(byMarket[market], symbol)
+	}
+
+	found := make(map[string]models.SymbolCurrentPrice)
+	var lastErr error
+	for _, market := range markets {
+		remaining := byMarket[market]
+		for _, name := range t.chain(CapabilityCurrent, market) {
+			if len(remaining) == 0 {
+				break
+			}
+
+			prices, err := t.providers[name].(CurrentPriceProvider).GetCurrentPrices(ctx, remaining)
+			if err != nil {
+				t.recordFailure(CapabilityCurrent, name, err)
+				lastErr = err
+				continue
+			}
+			if len(prices) > 0 {
+				t.health[name].recordSuccess()
+			}
+
+			for _, price := range prices {
+				price.Provider = name
+				found[strings.ToUpper(price.Symbol)] = price
+			}
+			var missing []string
+			for _, symbol := range remaining {
+				if _, ok := found[strings.ToUpper(symbol)]; !ok {
+					missing = append(missing, symbol)
+				}
+			}
+			remaining = missing
+		}
+	}
+
+	if len(found) == 0 && lastErr != nil {
+		return nil, fmt.Errorf("all current price providers failed: %w", lastErr)
+	}
+
+	// Prices in the order the symbols were requested
+	prices := make([]models.SymbolCurrentPrice, 0, len(found))
+	for _, symbol := range symbols {
+		if price, ok := found[strings.ToUpper(symbol)]; ok {
+			prices = append(prices, price)
+			delete(found, strings.ToUpper(symbol))
+		}
+	}
+	return prices, nil
 }
 
-// GetHistoricalPrices uses Alpha Vantage (comprehensive historical data)
+// GetHistoricalPrices tries the historical price chain of the symbol's market
 func (t *ThirdPartyProviderMap) GetHistoricalPrices(ctx context.Context, symbol string, resolution models.Resolution) (*models.SymbolHistoricalPrice, error) {
-	return t.alphaVantage.GetHistoricalPrices(ctx, symbol, resolution)
+	var result *models.SymbolHistoricalPrice
+	name, err := t.serve(CapabilityHistorical, MarketForSymbol(symbol), func(p interface{}) error {
+		var err error
+		result, err = p.(HistoricalPriceProvider).GetHistoricalPrices(ctx, symbol, resolution)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Provider = name
+	return result, nil
+}
+
+// GetIntradayPrices tries the intraday price chain of the symbol's market
+func (t *ThirdPartyProviderMap) GetIntradayPrices(ctx context.Context, symbol string, interval models.Interval) (*models.SymbolIntradayPrice, error) {
+	var result *models.SymbolIntradayPrice
+	name, err := t.serve(CapabilityIntraday, MarketForSymbol(symbol), func(p interface{}) error {
+		var err error
+		result, err = p.(IntradayPriceProvider).GetIntradayPrices(ctx, symbol, interval)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Provider = name
+	return result, nil
+}
+
+// GetHistoricalFXRates tries the FX chain, which has no markets of its own
+func (t *ThirdPartyProviderMap) GetHistoricalFXRates(ctx context.Context, base, quote string) (*models.CurrencyPairRates, error) {
+	var result *models.CurrencyPairRates
+	name, err := t.serve(CapabilityFX, DefaultMarket, func(p interface{}) error {
+		var err error
+		result, err = p.(FXRateProvider).GetHistoricalFXRates(ctx, base, quote)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Provider = name
+	return result, nil
+}
+
+// Status returns each provider's health and the chains they serve in
+func (t *ThirdPartyProviderMap) Status() models.ProvidersOverview {
+	return chainStatus(t.chains, t.providers, t.health, time.Now())
+}
+
+// GetSymbolInfo uses Finnhub company profiles for stocks and funds and Coinbase for crypto pairs
+func (t *ThirdPartyProviderMap) GetSymbolInfo(ctx context.Context, symbol string) (*models.SymbolInfo, error) {
+	if IsCryptoSymbol(symbol) {
+		return t.coinbase.GetSymbolInfo(ctx, symbol)
+	}
+	return t.finnhub.GetSymbolInfo(ctx, symbol)
+}
+
+// SearchSymbols uses Finnhub symbol search
+func (t *ThirdPartyProviderMap) SearchSymbols(ctx context.Context, query string) ([]models.SymbolInfo, error) {
+	return t.finnhub.SearchSymbols(ctx, query)
 }
@@ -0,0 +1,250 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/transaction-tracker/price_service/internal/calendar"
+	"github.com/transaction-tracker/price_service/internal/models"
+)
+
+// taiwanHistoryMonths bounds how far back daily history is paged, a month per request: a year
+// plus the current month. The exchanges throttle clients making more than a few requests a second.
+const taiwanHistoryMonths = 13
+
+// taiwanDailyBar is the closing price of a Taiwan listed stock on a trading day
+type taiwanDailyBar struct {
+	Date  string // YYYY-MM-DD format
+	Close float64
+}
+
+// taiwanMonthFetcher returns the daily bars of a stock in the month of a date, oldest first.
+// Months without trading, such as before the stock listed, have no bars.
+type taiwanMonthFetcher func(ctx context.Context, code string, month time.Time) ([]taiwanDailyBar, error)
+
+// taiwanLocation returns the time zone both Taiwan exchanges trade in
+func taiwanLocation() *time.Location {
+	twse, _ := calendar.Get("TWSE")
+	return twse.Location
+}
+
+// taiwanStockCode returns the exchange's code of a symbol, e.g. 2330 for 2330.TW and 6488 for 6488.TWO
+func taiwanStockCode(symbol string) string {
+	symbol = strings.ToUpper(symbol)
+	for _, suffix := range []string{".TWO", ".TW"} {
+		if strings.HasSuffix(symbol, suffix) {
+			return strings.TrimSuffix(symbol, suffix)
+		}
+	}
+	return symbol
+}
+
+// parseROCDate converts a date in the Republic of China calendar, e.g. 114/07/01, into YYYY-MM-DD.
+// ROC years count from 1912, so year 114 is 2025.
+func parseROCDate(value string) (string, error) {
+	parts := strings.Split(strings.TrimSpace(value), "/")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("invalid ROC date: %s", value)
+	}
+	year, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return "", fmt.Errorf("invalid ROC date: %s", value)
+	}
+	date, err := time.Parse("2006-01-02", fmt.Sprintf("%04d-%s-%s", year+1911, parts[1], parts[2]))
+	if err != nil {
+		return "", fmt.Errorf("invalid ROC date: %s", value)
+	}
+	return date.Format("2006-01-02"), nil
+}
+
+// parseTaiwanPrice parses a price with thousands separators, e.g. 1,060.00. Days without a trade
+// show -- instead, which is reported as not ok.
+func parseTaiwanPrice(value string) (float64, bool) {
+	price, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(value), ",", ""), 64)
+	if err != nil || price <= 0 {
+		return 0, false
+	}
+	return price, true
+}
+
+// parseTaiwanRows reads the date and closing price columns of daily trading rows, skipping days
+// without a trade
+func parseTaiwanRows(rows [][]string, closeColumn int) []taiwanDailyBar {
+	bars := make([]taiwanDailyBar, 0, len(rows))
+	for _, row := range rows {
+		if len(row) <= closeColumn {
+			continue
+		}
+		date, err := parseROCDate(row[0])
+		if err != nil {
+			continue
+		}
+		price, ok := parseTaiwanPrice(row[closeColumn])
+		if !ok {
+			continue
+		}
+		bars = append(bars, taiwanDailyBar{Date: date, Close: price})
+	}
+	return bars
+}
+
+// taiwanCurrentPrices retrieves the latest daily close of each symbol, one symbol at a time.
+// Symbols without a close are left out.
+func taiwanCurrentPrices(ctx context.Context, fetch taiwanMonthFetcher, symbols []string) ([]models.SymbolCurrentPrice, error) {
+	var prices []models.SymbolCurrentPrice
+	var lastErr error
+
+	for _, symbol := range symbols {
+		price, err := taiwanCurrentPrice(ctx, fetch, symbol)
+		if err != nil {
+			log.Printf("Error fetching current price for symbol %s: %v", symbol, err)
+			if !errors.Is(err, ErrSymbolNotFound) {
+				lastErr = err
+			}
+			continue
+		}
+		prices = append(prices, price)
+	}
+
+	if len(prices) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return prices, nil
+}
+
+// taiwanCurrentPrice returns the latest daily close of a symbol, with the change from the close
+// before it. Early in a month the previous month supplies the earlier closes.
+func taiwanCurrentPrice(ctx context.Context, fetch taiwanMonthFetcher, symbol string) (models.SymbolCurrentPrice, error) {
+	symbol = strings.ToUpper(symbol)
+	code := taiwanStockCode(symbol)
+	location := taiwanLocation()
+	month := time.Now().In(location)
+
+	bars, err := fetch(ctx, code, month)
+	if err != nil {
+		return models.SymbolCurrentPrice{}, err
+	}
+	if len(bars) < 2 {
+		earlier, err := fetch(ctx, code, month.AddDate(0, 0, -month.Day()))
+		if err != nil {
+			return models.SymbolCurrentPrice{}, err
+		}
+		bars = append(earlier, bars...)
+	}
+	if len(bars) == 0 {
+		return models.SymbolCurrentPrice{}, fmt.Errorf("%w: %s", ErrSymbolNotFound, symbol)
+	}
+
+	latest := bars[len(bars)-1]
+	date, _ := time.ParseInLocation("2006-01-02", latest.Date, location)
+	price := models.SymbolCurrentPrice{
+		Symbol:       symbol,
+		CurrentPrice: latest.Close,
+		Currency:     "TWD",
+		// Both exchanges close at 13:30
+		Timestamp: date.Add(13*time.Hour + 30*time.Minute),
+	}
+	if len(bars) > 1 {
+		price.PreviousClose = bars[len(bars)-2].Close
+		price.Change = latest.Close - price.PreviousClose
+		price.ChangePercent = price.Change / price.PreviousClose * 100
+	}
+	return price, nil
+}
+
+// taiwanHistoricalPrices pages back through a symbol's months of daily closes, sorted newest to
+// oldest. Weekly and monthly closes are the last daily close of each week and month.
+func taiwanHistoricalPrices(ctx context.Context, fetch taiwanMonthFetcher, symbol string, resolution models.Resolution) (*models.SymbolHistoricalPrice, error) {
+	if resolution != models.ResolutionDaily && resolution != models.ResolutionWeekly && resolution != models.ResolutionMonthly {
+		return nil, fmt.Errorf("unsupported resolution: %s", resolution)
+	}
+
+	symbol = strings.ToUpper(symbol)
+	code := taiwanStockCode(symbol)
+	now := time.Now().In(taiwanLocation())
+	firstOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	var daily []models.ClosePrice
+	for page := 0; page < taiwanHistoryMonths; page++ {
+		bars, err := fetch(ctx, code, firstOfMonth.AddDate(0, -page, 0))
+		if err != nil {
+			// Older months failing still leaves the recent history
+			if len(daily) > 0 {
+				log.Printf("Error fetching older daily quotes for %s: %v", symbol, err)
+				break
+			}
+			return nil, err
+		}
+		// No trading in a month past the current one means the stock had not listed yet
+		if len(bars) == 0 && page > 0 {
+			break
+		}
+		for _, bar := range bars {
+			daily = append(daily, models.ClosePrice{Date: bar.Date, Price: bar.Close})
+		}
+	}
+	if len(daily) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrSymbolNotFound, symbol)
+	}
+
+	// Dates are YYYY-MM-DD, so string order is chronological order
+	sort.Slice(daily, func(i, j int) bool {
+		return daily[i].Date > daily[j].Date
+	})
+
+	prices := daily
+	if resolution != models.ResolutionDaily {
+		prices = lastClosePerPeriod(daily, resolution)
+	}
+
+	return &models.SymbolHistoricalPrice{
+		Symbol:           symbol,
+		Resolution:       resolution,
+		HistoricalPrices: prices,
+	}, nil
+}
+
+// taiwanRequest fetches a JSON document from a Taiwan exchange. When a client exceeds their rate
+// limits, the exchanges answer with an HTML page rather than JSON.
+func taiwanRequest(ctx context.Context, client *http.Client, source, url string) ([]byte, error) {
+	log.Printf("%s API Request: %s", source, url)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "transaction-tracker-price-service")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("%w: %s", ErrRateLimited, string(body))
+	}
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("%s API Error Response: %s", source, string(body))
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+	if strings.HasPrefix(strings.TrimSpace(string(body)), "<") {
+		return nil, fmt.Errorf("%w: %s answered with a page instead of JSON", ErrRateLimited, source)
+	}
+
+	return body, nil
+}
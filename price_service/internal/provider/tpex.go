@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/transaction-tracker/price_service/internal/models"
+)
+
+// TPExProvider handles daily quotes of stocks traded on the Taipei Exchange (.TWO symbols) from
+// its public after-trading reports, which need no API key
+type TPExProvider struct {
+	BaseURL string
+	client  *http.Client
+}
+
+// TPExTradingStockResponse represents a month of daily trading of a stock from TPEx. Stat is ok
+// whether or not the stock traded; unknown stocks come back without tables or rows.
+type TPExTradingStockResponse struct {
+	Stat   string `json:"stat"`
+	Code   string `json:"code"`
+	Name   string `json:"name"`
+	Tables []struct {
+		Title  string     `json:"title"`
+		Fields []string   `json:"fields"`
+		Data   [][]string `json:"data"` // date, lots, value (thousands), open, high, low, close, change, trades
+	} `json:"tables"`
+}
+
+// tpexCloseColumn is the column of the closing price in TPEx daily trading rows
+const tpexCloseColumn = 6
+
+func NewTPExProvider(baseURL string) *TPExProvider {
+	return &TPExProvider{
+		BaseURL: baseURL,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// GetCurrentPrices retrieves the latest daily close of TPEx traded stocks. Symbols without a
+// close are left out.
+func (t *TPExProvider) GetCurrentPrices(ctx context.Context, symbols []string) ([]models.SymbolCurrentPrice, error) {
+	return taiwanCurrentPrices(ctx, t.getMonth, symbols)
+}
+
+// GetHistoricalPrices retrieves the daily closes of a TPEx traded stock over the past year, sorted
+// newest to oldest
+func (t *TPExProvider) GetHistoricalPrices(ctx context.Context, symbol string, resolution models.Resolution) (*models.SymbolHistoricalPrice, error) {
+	return taiwanHistoricalPrices(ctx, t.getMonth, symbol, resolution)
+}
+
+// getMonth fetches the daily trading of a stock in the month of a date
+func (t *TPExProvider) getMonth(ctx context.Context, code string, month time.Time) ([]taiwanDailyBar, error) {
+	params := url.Values{}
+	params.Set("response", "json")
+	params.Set("date", month.Format("2006/01/02"))
+	params.Set("code", code)
+	body, err := taiwanRequest(ctx, t.client, "TPEx", fmt.Sprintf("%s/www/zh-tw/afterTrading/tradingStock?%s", t.BaseURL, params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	var response TPExTradingStockResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+	if !strings.EqualFold(response.Stat, "ok") || len(response.Tables) == 0 {
+		return nil, nil
+	}
+	return parseTaiwanRows(response.Tables[0].Data, tpexCloseColumn), nil
+}
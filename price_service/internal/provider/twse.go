@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/transaction-tracker/price_service/internal/models"
+)
+
+// TWSEProvider handles daily quotes of stocks listed on the Taiwan Stock Exchange (.TW symbols)
+// from its public after-trading reports, which need no API key
+type TWSEProvider struct {
+	BaseURL string
+	client  *http.Client
+}
+
+// TWSEStockDayResponse represents a month of daily trading of a stock from TWSE. Stat is OK when
+// there are rows, and otherwise explains why there are none, e.g. for unknown stocks.
+type TWSEStockDayResponse struct {
+	Stat   string     `json:"stat"`
+	Date   string     `json:"date"`
+	Title  string     `json:"title"`
+	Fields []string   `json:"fields"`
+	Data   [][]string `json:"data"` // date, shares, value, open, high, low, close, change, trades
+}
+
+// twseCloseColumn is the column of the closing price in TWSE daily trading rows
+const twseCloseColumn = 6
+
+func NewTWSEProvider(baseURL string) *TWSEProvider {
+	return &TWSEProvider{
+		BaseURL: baseURL,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// GetCurrentPrices retrieves the latest daily close of TWSE listed stocks. Symbols without a
+// close are left out.
+func (t *TWSEProvider) GetCurrentPrices(ctx context.Context, symbols []string) ([]models.SymbolCurrentPrice, error) {
+	return taiwanCurrentPrices(ctx, t.getMonth, symbols)
+}
+
+// GetHistoricalPrices retrieves the daily closes of a TWSE listed stock over the past year, sorted
+// newest to oldest
+func (t *TWSEProvider) GetHistoricalPrices(ctx context.Context, symbol string, resolution models.Resolution) (*models.SymbolHistoricalPrice, error) {
+	return taiwanHistoricalPrices(ctx, t.getMonth, symbol, resolution)
+}
+
+// getMonth fetches the daily trading of a stock in the month of a date
+func (t *TWSEProvider) getMonth(ctx context.Context, code string, month time.Time) ([]taiwanDailyBar, error) {
+	params := url.Values{}
+	params.Set("response", "json")
+	params.Set("date", month.Format("20060102"))
+	params.Set("stockNo", code)
+	body, err := taiwanRequest(ctx, t.client, "TWSE", fmt.Sprintf("%s/rwd/zh/afterTrading/STOCK_DAY?%s", t.BaseURL, params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	var response TWSEStockDayResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+	if response.Stat != "OK" {
+		return nil, nil
+	}
+	return parseTaiwanRows(response.Data, twseCloseColumn), nil
+}
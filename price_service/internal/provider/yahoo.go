@@ -0,0 +1,309 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/transaction-tracker/price_service/internal/models"
+)
+
+// yahooIntervals maps intervals to Yahoo chart intervals and how far back their bars are fetched.
+// Yahoo keeps a week of one-minute bars and 60 days of the others.
+var yahooIntervals = map[models.Interval]struct {
+	interval string
+	lookback string
+}{
+	models.Interval1Min:  {"1m", "5d"},
+	models.Interval5Min:  {"5m", "1mo"},
+	models.Interval15Min: {"15m", "1mo"},
+	models.Interval60Min: {"60m", "1mo"},
+}
+
+// YahooProvider handles prices of stocks, ETFs and crypto pairs on most exchanges from the Yahoo
+// Finance chart API, which needs no API key. Symbols carry Yahoo's exchange suffixes, e.g. 2330.TW,
+// 6488.TWO, SHOP.TO or VOD.L.
+type YahooProvider struct {
+	BaseURL string
+	client  *http.Client
+}
+
+// YahooChartResponse represents a price chart from Yahoo. Bars without a trade have null closes.
+type YahooChartResponse struct {
+	Chart struct {
+		Result []YahooChartResult `json:"result"`
+		Error  *struct {
+			Code        string `json:"code"`
+			Description string `json:"description"`
+		} `json:"error"`
+	} `json:"chart"`
+}
+
+// YahooChartResult represents the bars of a symbol's chart and its latest quote, with the splits
+// in its range
+type YahooChartResult struct {
+	Meta struct {
+		Symbol               string  `json:"symbol"`
+		Currency             string  `json:"currency"`
+		ExchangeTimezoneName string  `json:"exchangeTimezoneName"`
+		RegularMarketPrice   float64 `json:"regularMarketPrice"`
+		RegularMarketTime    int64   `json:"regularMarketTime"`
+		ChartPreviousClose   float64 `json:"chartPreviousClose"`
+	} `json:"meta"`
+	Timestamp  []int64 `json:"timestamp"`
+	Indicators struct {
+		Quote []struct {
+			Close []*float64 `json:"close"`
+		} `json:"quote"`
+	} `json:"indicators"`
+	Events struct {
+		// Splits keyed by the timestamp of their first bar; a 4:1 split has numerator 4
+		Splits map[string]struct {
+			Date        int64   `json:"date"`
+			Numerator   float64 `json:"numerator"`
+			Denominator float64 `json:"denominator"`
+		} `json:"splits"`
+	} `json:"events"`
+}
+
+func NewYahooProvider(baseURL string) *YahooProvider {
+	return &YahooProvider{
+		BaseURL: baseURL,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// GetCurrentPrices retrieves the latest quote of each symbol, one request per symbol. Symbols
+// without a quote are left out.
+func (y *YahooProvider) GetCurrentPrices(ctx context.Context, symbols []string) ([]models.SymbolCurrentPrice, error) {
+	var prices []models.SymbolCurrentPrice
+	var lastErr error
+
+	for _, symbol := range symbols {
+		price, err := y.getCurrentPriceForSymbol(ctx, symbol)
+		if err != nil {
+			log.Printf("Error fetching current price for symbol %s: %v", symbol, err)
+			if !errors.Is(err, ErrSymbolNotFound) {
+				lastErr = err
+			}
+			continue
+		}
+		prices = append(prices, price)
+	}
+
+	if len(prices) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return prices, nil
+}
+
+func (y *YahooProvider) getCurrentPriceForSymbol(ctx context.Context, symbol string) (models.SymbolCurrentPrice, error) {
+	symbol = strings.ToUpper(symbol)
+	chart, err := y.getChart(ctx, symbol, "1d", "1d")
+	if err != nil {
+		return models.SymbolCurrentPrice{}, err
+	}
+	if chart.Meta.RegularMarketPrice <= 0 {
+		return models.SymbolCurrentPrice{}, fmt.Errorf("%w: %s", ErrSymbolNotFound, symbol)
+	}
+
+	price := models.SymbolCurrentPrice{
+		Symbol:        symbol,
+		CurrentPrice:  chart.Meta.RegularMarketPrice,
+		Currency:      strings.ToUpper(chart.Meta.Currency),
+		PreviousClose: chart.Meta.ChartPreviousClose,
+		Timestamp:     time.Unix(chart.Meta.RegularMarketTime, 0).UTC(),
+	}
+	if price.PreviousClose > 0 {
+		price.Change = price.CurrentPrice - price.PreviousClose
+		price.ChangePercent = price.Change / price.PreviousClose * 100
+	}
+	return price, nil
+}
+
+// GetHistoricalPrices retrieves ten years of daily closes of a symbol, sorted newest to oldest.
+// Bars are dated in the exchange's time zone. Yahoo adjusts closes for later splits; they are
+// restored to the prices traded, as the other providers report them. Weekly and monthly closes
+// are the last daily close of each week and month.
+func (y *YahooProvider) GetHistoricalPrices(ctx context.Context, symbol string, resolution models.Resolution) (*models.SymbolHistoricalPrice, error) {
+	if resolution != models.ResolutionDaily && resolution != models.ResolutionWeekly && resolution != models.ResolutionMonthly {
+		return nil, fmt.Errorf("unsupported resolution: %s", resolution)
+	}
+
+	symbol = strings.ToUpper(symbol)
+	chart, err := y.getChart(ctx, symbol, "1d", "10y")
+	if err != nil {
+		return nil, err
+	}
+
+	location := chart.location()
+	closes := make(map[string]float64)
+	for i, at := range chart.Timestamp {
+		if price, ok := chart.closeAt(i); ok {
+			closes[time.Unix(at, 0).In(location).Format("2006-01-02")] = price * chart.splitFactor(at)
+		}
+	}
+	if len(closes) == 0 {
+		return nil, fmt.Errorf("no historical data available for %s", symbol)
+	}
+
+	daily := make([]models.ClosePrice, 0, len(closes))
+	for date, price := range closes {
+		daily = append(daily, models.ClosePrice{Date: date, Price: price})
+	}
+	// Dates are YYYY-MM-DD, so string order is chronological order
+	sort.Slice(daily, func(i, j int) bool {
+		return daily[i].Date > daily[j].Date
+	})
+
+	prices := daily
+	if resolution != models.ResolutionDaily {
+		prices = lastClosePerPeriod(daily, resolution)
+	}
+
+	return &models.SymbolHistoricalPrice{
+		Symbol:           symbol,
+		Resolution:       resolution,
+		HistoricalPrices: prices,
+	}, nil
+}
+
+// GetIntradayPrices retrieves the intraday bars of a symbol over the lookback of the interval,
+// sorted newest to oldest. Bars are keyed by the moment they open, in UTC.
+func (y *YahooProvider) GetIntradayPrices(ctx context.Context, symbol string, interval models.Interval) (*models.SymbolIntradayPrice, error) {
+	yahooInterval, ok := yahooIntervals[interval]
+	if !ok {
+		return nil, fmt.Errorf("unsupported interval: %s", interval)
+	}
+
+	symbol = strings.ToUpper(symbol)
+	chart, err := y.getChart(ctx, symbol, yahooInterval.interval, yahooInterval.lookback)
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make([]models.IntradayPrice, 0, len(chart.Timestamp))
+	for i, at := range chart.Timestamp {
+		if price, ok := chart.closeAt(i); ok {
+			prices = append(prices, models.IntradayPrice{Time: time.Unix(at, 0).UTC(), Price: price})
+		}
+	}
+	if len(prices) == 0 {
+		return nil, fmt.Errorf("no intraday data available for %s", symbol)
+	}
+	sort.Slice(prices, func(i, j int) bool {
+		return prices[i].Time.After(prices[j].Time)
+	})
+
+	return &models.SymbolIntradayPrice{
+		Symbol:         symbol,
+		Resolution:     models.ResolutionIntraday,
+		Interval:       interval,
+		IntradayPrices: prices,
+	}, nil
+}
+
+// location returns the time zone of the exchange the chart's symbol trades on, or UTC if unknown
+func (r *YahooChartResult) location() *time.Location {
+	if r.Meta.ExchangeTimezoneName != "" {
+		if location, err := time.LoadLocation(r.Meta.ExchangeTimezoneName); err == nil {
+			return location
+		}
+	}
+	return time.UTC
+}
+
+// closeAt returns the close of the i-th bar, or not ok for bars without a trade
+func (r *YahooChartResult) closeAt(i int) (float64, bool) {
+	if len(r.Indicators.Quote) == 0 || i >= len(r.Indicators.Quote[0].Close) {
+		return 0, false
+	}
+	price := r.Indicators.Quote[0].Close[i]
+	if price == nil || *price <= 0 {
+		return 0, false
+	}
+	return *price, true
+}
+
+// splitFactor returns how many shares of today one share at a moment became through the splits
+// after it, the factor an adjusted close of that moment is multiplied by to restore it
+func (r *YahooChartResult) splitFactor(at int64) float64 {
+	factor := 1.0
+	for _, split := range r.Events.Splits {
+		if split.Date > at && split.Numerator > 0 && split.Denominator > 0 {
+			factor *= split.Numerator / split.Denominator
+		}
+	}
+	return factor
+}
+
+// getChart fetches the chart of a symbol with bars of interval over a range such as 1d or 10y,
+// along with the splits in the range
+func (y *YahooProvider) getChart(ctx context.Context, symbol, interval, chartRange string) (*YahooChartResult, error) {
+	params := url.Values{}
+	params.Set("interval", interval)
+	params.Set("range", chartRange)
+	params.Set("events", "split")
+	body, err := y.makeRequest(ctx, fmt.Sprintf("%s/v8/finance/chart/%s?%s", y.BaseURL, url.PathEscape(symbol), params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	var response YahooChartResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+	if response.Chart.Error != nil {
+		return nil, fmt.Errorf("chart request failed: %s", response.Chart.Error.Description)
+	}
+	if len(response.Chart.Result) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrSymbolNotFound, symbol)
+	}
+	return &response.Chart.Result[0], nil
+}
+
+func (y *YahooProvider) makeRequest(ctx context.Context, url string) ([]byte, error) {
+	log.Printf("Yahoo API Request: %s", url)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	// Yahoo rejects requests without a user agent
+	req.Header.Set("User-Agent", "transaction-tracker-price-service")
+
+	resp, err := y.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	// Unknown and delisted symbols come back as not found
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrSymbolNotFound, string(body))
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("%w: %s", ErrRateLimited, string(body))
+	}
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("Yahoo API Error Response: %s", string(body))
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return body, nil
+}
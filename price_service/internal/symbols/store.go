@@ -0,0 +1,124 @@
+package symbols
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/transaction-tracker/price_service/internal/models"
+)
+
+//go:embed data/symbols.json
+var seedData []byte
+
+// Store holds symbol reference data in memory, keyed by upper-case symbol. It starts from the
+// built-in dataset and keeps the symbols looked up from providers since.
+type Store struct {
+	symbols map[string]models.SymbolInfo
+	mutex   sync.RWMutex
+}
+
+// NewStore creates a store seeded with the built-in dataset and, when seedFile is set, the symbols
+// in that JSON file, which take precedence over the built-in ones
+func NewStore(seedFile string) (*Store, error) {
+	store := &Store{symbols: make(map[string]models.SymbolInfo)}
+	if err := store.Load(seedData); err != nil {
+		return nil, fmt.Errorf("failed to load built-in symbols: %w", err)
+	}
+
+	if seedFile != "" {
+		data, err := os.ReadFile(seedFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read symbols seed file: %w", err)
+		}
+		if err := store.Load(data); err != nil {
+			return nil, fmt.Errorf("failed to load symbols seed file %s: %w", seedFile, err)
+		}
+	}
+
+	return store, nil
+}
+
+// Load adds the symbols of a JSON array of symbol info to the store, replacing any already held
+func (s *Store) Load(data []byte) error {
+	var infos []models.SymbolInfo
+	if err := json.Unmarshal(data, &infos); err != nil {
+		return err
+	}
+	for _, info := range infos {
+		if strings.TrimSpace(info.Symbol) == "" {
+			return fmt.Errorf("symbol info without a symbol: %+v", info)
+		}
+		s.Put(info)
+	}
+	return nil
+}
+
+// Get returns the reference data of a symbol, if the store holds it
+func (s *Store) Get(symbol string) (models.SymbolInfo, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	info, ok := s.symbols[strings.ToUpper(strings.TrimSpace(symbol))]
+	return info, ok
+}
+
+// Put records the reference data of a symbol
+func (s *Store) Put(info models.SymbolInfo) {
+	info.Symbol = strings.ToUpper(strings.TrimSpace(info.Symbol))
+	info.AssetClass = strings.ToLower(info.AssetClass)
+	info.Currency = strings.ToUpper(info.Currency)
+	info.Country = strings.ToUpper(info.Country)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.symbols[info.Symbol] = info
+}
+
+// Search returns up to limit symbols matching a query, best matches first: the symbol itself, then
+// symbols starting with the query, then names containing it. Ties are broken by symbol.
+func (s *Store) Search(query string, limit int) []models.SymbolInfo {
+	query = strings.ToUpper(strings.TrimSpace(query))
+	if query == "" || limit <= 0 {
+		return []models.SymbolInfo{}
+	}
+
+	type match struct {
+		info models.SymbolInfo
+		rank int
+	}
+
+	s.mutex.RLock()
+	var matches []match
+	for symbol, info := range s.symbols {
+		switch {
+		case symbol == query:
+			matches = append(matches, match{info, 0})
+		case strings.HasPrefix(symbol, query):
+			matches = append(matches, match{info, 1})
+		case strings.Contains(strings.ToUpper(info.Name), query):
+			matches = append(matches, match{info, 2})
+		}
+	}
+	s.mutex.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].rank != matches[j].rank {
+			return matches[i].rank < matches[j].rank
+		}
+		return matches[i].info.Symbol < matches[j].info.Symbol
+	})
+
+	results := make([]models.SymbolInfo, 0, limit)
+	for _, m := range matches {
+		if len(results) == limit {
+			break
+		}
+		results = append(results, m.info)
+	}
+	return results
+}
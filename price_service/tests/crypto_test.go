@@ -0,0 +1,163 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/transaction-tracker/price_service/api/handlers"
+	"github.com/transaction-tracker/price_service/internal/config"
+	"github.com/transaction-tracker/price_service/internal/models"
+	"github.com/transaction-tracker/price_service/internal/provider"
+)
+
+// lastSaturday returns the most recent Saturday before today (UTC), at midnight
+func lastSaturday() time.Time {
+	day := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	for day.Weekday() != time.Saturday {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
+
+// newCoinbaseServer serves BTC-USD stats, and the daily candles from the last Saturday back to the
+// Thursday before it that fall between start and end, newest first as Coinbase returns them
+func newCoinbaseServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	saturday := lastSaturday()
+	// Each candle is [time, low, high, open, close, volume]
+	coinbaseCandles := [][]float64{
+		{float64(saturday.Unix()), 117000, 118500, 117500, 118000.25, 100},
+		{float64(saturday.AddDate(0, 0, -1).Unix()), 115000, 118000, 115500, 117500.5, 100},
+		{float64(saturday.AddDate(0, 0, -2).Unix()), 114000, 116000, 114500, 115500, 100},
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/products/BTC-USD/stats":
+			w.Write([]byte(`{"open":"60000.00","high":"63000.00","low":"59000.00","last":"61500.50","volume":"1234.5"}`))
+		case "/products/BTC-USD/candles":
+			assert.Equal(t, "86400", r.URL.Query().Get("granularity"))
+			start, err := time.Parse(time.RFC3339, r.URL.Query().Get("start"))
+			require.NoError(t, err)
+			end, err := time.Parse(time.RFC3339, r.URL.Query().Get("end"))
+			require.NoError(t, err)
+			candles := [][]float64{}
+			for _, candle := range coinbaseCandles {
+				if at := int64(candle[0]); at >= start.Unix() && at <= end.Unix() {
+					candles = append(candles, candle)
+				}
+			}
+			json.NewEncoder(w).Encode(candles)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestIsCryptoSymbol(t *testing.T) {
+	for _, symbol := range []string{"BTC-USD", "eth-usd", "DOGE-USDT"} {
+		assert.True(t, provider.IsCryptoSymbol(symbol), symbol)
+	}
+	for _, symbol := range []string{"AAPL", "BRK.B", "2330.TW", "EURUSD"} {
+		assert.False(t, provider.IsCryptoSymbol(symbol), symbol)
+	}
+}
+
+func TestCoinbaseProviderCurrentPrices(t *testing.T) {
+	server := newCoinbaseServer(t)
+	defer server.Close()
+
+	prices, err := provider.NewCoinbaseProvider(server.URL).GetCurrentPrices(context.Background(), []string{"btc-usd", "NOPE-USD"})
+	require.NoError(t, err)
+	require.Len(t, prices, 1, "unknown pairs are left out")
+
+	price := prices[0]
+	assert.Equal(t, "BTC-USD", price.Symbol)
+	assert.Equal(t, "USD", price.Currency)
+	assert.Equal(t, 61500.50, price.CurrentPrice)
+	assert.Equal(t, 60000.00, price.PreviousClose)
+	assert.InDelta(t, 1500.50, price.Change, 1e-9)
+	assert.InDelta(t, 2.5008, price.ChangePercent, 1e-4)
+}
+
+func TestCoinbaseProviderHistoricalPrices(t *testing.T) {
+	server := newCoinbaseServer(t)
+	defer server.Close()
+	coinbase := provider.NewCoinbaseProvider(server.URL)
+
+	saturday := lastSaturday()
+	date := func(daysBefore int) string {
+		return saturday.AddDate(0, 0, -daysBefore).Format(handlers.DateFormat)
+	}
+
+	// Weekends have prices of their own
+	daily, err := coinbase.GetHistoricalPrices(context.Background(), "BTC-USD", models.ResolutionDaily)
+	require.NoError(t, err)
+	assert.Equal(t, []models.ClosePrice{
+		{Date: date(0), Price: 118000.25},
+		{Date: date(1), Price: 117500.5},
+		{Date: date(2), Price: 115500},
+	}, daily.HistoricalPrices)
+
+	// The week closes on its latest day
+	weekly, err := coinbase.GetHistoricalPrices(context.Background(), "BTC-USD", models.ResolutionWeekly)
+	require.NoError(t, err)
+	assert.Equal(t, []models.ClosePrice{{Date: date(0), Price: 118000.25}}, weekly.HistoricalPrices)
+}
+
+func TestThirdPartyProviderMapRoutesCrypto(t *testing.T) {
+	server := newCoinbaseServer(t)
+	defer server.Close()
+
+	var finnhubSymbols []string
+	finnhub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		finnhubSymbols = append(finnhubSymbols, r.URL.Query().Get("symbol"))
+		w.Write([]byte(`{"c":210.5,"d":1.5,"dp":0.72,"h":211,"l":208,"o":209,"pc":209,"t":1753300000}`))
+	}))
+	defer finnhub.Close()
+
+	priceProvider, err := provider.NewThirdPartyProviderMap(&config.Config{
+		StockAPI: config.StockAPIConfig{
+			Finnhub:  config.ProviderConfig{APIKey: "test-finnhub-key", BaseURL: finnhub.URL},
+			Coinbase: config.ProviderConfig{BaseURL: server.URL},
+		},
+	})
+	require.NoError(t, err)
+
+	prices, err := priceProvider.GetCurrentPrices(context.Background(), []string{"AAPL", "BTC-USD"})
+	require.NoError(t, err)
+	require.Len(t, prices, 2)
+	assert.Equal(t, []string{"AAPL"}, finnhubSymbols, "crypto pairs are not sent to Finnhub")
+	assert.Equal(t, "BTC-USD", prices[1].Symbol)
+	assert.Equal(t, 61500.50, prices[1].CurrentPrice)
+}
+
+func TestGetLastTradingDayForCrypto(t *testing.T) {
+	handler := handlers.NewPriceHandler(nil, nil, nil)
+
+	// Saturday and the July 4th holiday are trading days for crypto but not for stocks
+	for _, date := range []string{"2025-07-26", "2025-07-04"} {
+		day, _ := time.Parse(handlers.DateFormat, date)
+		assert.Equal(t, date, handler.GetLastTradingDayForSymbol("BTC-USD", day).Format(handlers.DateFormat))
+		assert.NotEqual(t, date, handler.GetLastTradingDayForSymbol("AAPL", day).Format(handlers.DateFormat))
+	}
+}
+
+func TestCheckCacheCoverageCrypto(t *testing.T) {
+	handler := handlers.NewPriceHandler(nil, nil, nil)
+	cached := func(symbol string) *models.SymbolHistoricalPrice {
+		return &models.SymbolHistoricalPrice{
+			Symbol:           symbol,
+			HistoricalPrices: []models.ClosePrice{{Date: "2025-07-25", Price: 150.00}}, // Friday
+		}
+	}
+
+	// A stock's Friday close covers the weekend, while crypto still needs Saturday and Sunday
+	assert.Equal(t, handlers.CacheCoverageFull, handler.CheckCacheCoverage(cached("AAPL"), "2025-07-20", "2025-07-27"))
+	assert.Equal(t, handlers.CacheCoveragePartial, handler.CheckCacheCoverage(cached("BTC-USD"), "2025-07-20", "2025-07-27"))
+}
@@ -0,0 +1,157 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/transaction-tracker/price_service/api/handlers"
+	"github.com/transaction-tracker/price_service/internal/models"
+	"github.com/transaction-tracker/price_service/internal/provider"
+)
+
+func TestValidateFXParameters(t *testing.T) {
+	tests := []struct {
+		name        string
+		base        string
+		quote       string
+		from        string
+		to          string
+		expectError bool
+		errorMsg    string
+	}{
+		{
+			name:  "Valid pair without range",
+			base:  "TWD",
+			quote: "USD",
+		},
+		{
+			name:  "Valid pair with range",
+			base:  "CAD",
+			quote: "USD",
+			from:  "2025-01-01",
+			to:    "2025-01-31",
+		},
+		{
+			name:        "Invalid base",
+			base:        "US",
+			quote:       "TWD",
+			expectError: true,
+			errorMsg:    "base must be a 3-letter currency code",
+		},
+		{
+			name:        "Invalid quote",
+			base:        "USD",
+			quote:       "",
+			expectError: true,
+			errorMsg:    "quote must be a 3-letter currency code",
+		},
+		{
+			name:        "Same currency",
+			base:        "USD",
+			quote:       "USD",
+			expectError: true,
+			errorMsg:    "base and quote currencies must differ",
+		},
+		{
+			name:        "Incomplete range",
+			base:        "TWD",
+			quote:       "USD",
+			from:        "2025-01-01",
+			expectError: true,
+			errorMsg:    "both 'from' and 'to' parameters are required",
+		},
+		{
+			name:        "Reversed range",
+			base:        "TWD",
+			quote:       "USD",
+			from:        "2025-02-01",
+			to:          "2025-01-01",
+			expectError: true,
+			errorMsg:    "'from' date must be earlier than or equal to 'to' date",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := handlers.ValidateFXParameters(tt.base, tt.quote, tt.from, tt.to)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFilterFXRatesByDateRange(t *testing.T) {
+	data := &models.CurrencyPairRates{
+		Base:  "TWD",
+		Quote: "USD",
+		Rates: []models.FXRate{
+			{Date: "2025-01-10", Rate: 0.0305},
+			{Date: "2025-01-09", Rate: 0.0304},
+			{Date: "2025-01-06", Rate: 0.0303},
+			{Date: "2025-01-03", Rate: 0.0302},
+			{Date: "2025-01-02", Rate: 0.0301},
+		},
+	}
+
+	// The range starts on a weekend, so the preceding Friday is kept for carry-forward
+	result := handlers.FilterFXRatesByDateRange(data, "2025-01-04", "2025-01-09")
+
+	dates := make([]string, 0, len(result.Rates))
+	for _, rate := range result.Rates {
+		dates = append(dates, rate.Date)
+	}
+	assert.Equal(t, []string{"2025-01-09", "2025-01-06", "2025-01-03"}, dates)
+	assert.Equal(t, "TWD", result.Base)
+	assert.Equal(t, "USD", result.Quote)
+
+	assert.Equal(t, data, handlers.FilterFXRatesByDateRange(data, "", ""))
+}
+
+func TestAlphaVantageGetHistoricalFXRates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "FX_DAILY", r.URL.Query().Get("function"))
+		assert.Equal(t, "TWD", r.URL.Query().Get("from_symbol"))
+		assert.Equal(t, "USD", r.URL.Query().Get("to_symbol"))
+		w.Write([]byte(`{
+			"Meta Data": {"1. Information": "Forex Daily Prices (open, high, low, close)"},
+			"Time Series FX (Daily)": {
+				"2025-01-02": {"1. open": "0.0300", "2. high": "0.0302", "3. low": "0.0299", "4. close": "0.0301"},
+				"2025-01-03": {"1. open": "0.0301", "2. high": "0.0303", "3. low": "0.0300", "4. close": "0.0302"}
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	alphaVantage := provider.NewAlphaVantageProvider("test-key")
+	alphaVantage.BaseURL = server.URL
+
+	rates, err := alphaVantage.GetHistoricalFXRates(context.Background(), "TWD", "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, "TWD", rates.Base)
+	assert.Equal(t, "USD", rates.Quote)
+	assert.Equal(t, []models.FXRate{
+		{Date: "2025-01-03", Rate: 0.0302},
+		{Date: "2025-01-02", Rate: 0.0301},
+	}, rates.Rates)
+}
+
+func TestAlphaVantageGetHistoricalFXRatesEmptySeries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Note": "Thank you for using Alpha Vantage!"}`))
+	}))
+	defer server.Close()
+
+	alphaVantage := provider.NewAlphaVantageProvider("test-key")
+	alphaVantage.BaseURL = server.URL
+
+	_, err := alphaVantage.GetHistoricalFXRates(context.Background(), "TWD", "USD")
+	assert.Error(t, err)
+}
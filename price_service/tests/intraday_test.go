@@ -0,0 +1,140 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/transaction-tracker/price_service/api/handlers"
+	"github.com/transaction-tracker/price_service/internal/models"
+	"github.com/transaction-tracker/price_service/internal/provider"
+)
+
+func TestAlphaVantageIntradayPrices(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "TIME_SERIES_INTRADAY", r.URL.Query().Get("function"))
+		assert.Equal(t, "5min", r.URL.Query().Get("interval"))
+		assert.Equal(t, "false", r.URL.Query().Get("extended_hours"))
+		w.Write([]byte(`{
+			"Meta Data": {"1. Information": "Intraday (5min) open, high, low, close prices and volume", "2. Symbol": "AAPL", "6. Time Zone": "US/Eastern"},
+			"Time Series (5min)": {
+				"2025-07-23 09:30:00": {"1. open": "212.0", "4. close": "212.50", "5. volume": "1000"},
+				"2025-07-23 15:55:00": {"1. open": "214.0", "4. close": "214.15", "5. volume": "1000"},
+				"2025-07-22 15:55:00": {"1. open": "211.0", "4. close": "211.25", "5. volume": "1000"}
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	alphaVantage := provider.NewAlphaVantageProvider("test-key")
+	alphaVantage.BaseURL = server.URL
+
+	intraday, err := alphaVantage.GetIntradayPrices(context.Background(), "AAPL", models.Interval5Min)
+	require.NoError(t, err)
+	assert.Equal(t, models.ResolutionIntraday, intraday.Resolution)
+	assert.Equal(t, models.Interval5Min, intraday.Interval)
+
+	// New York is four hours behind UTC in July
+	assert.Equal(t, []models.IntradayPrice{
+		{Time: time.Date(2025, 7, 23, 19, 55, 0, 0, time.UTC), Price: 214.15},
+		{Time: time.Date(2025, 7, 23, 13, 30, 0, 0, time.UTC), Price: 212.50},
+		{Time: time.Date(2025, 7, 22, 19, 55, 0, 0, time.UTC), Price: 211.25},
+	}, intraday.IntradayPrices)
+
+	_, err = alphaVantage.GetIntradayPrices(context.Background(), "AAPL", models.Interval("30min"))
+	assert.Error(t, err)
+}
+
+func TestCoinbaseIntradayPrices(t *testing.T) {
+	hour := time.Now().UTC().Truncate(time.Hour)
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "3600", r.URL.Query().Get("granularity"))
+		start, err := time.Parse(time.RFC3339, r.URL.Query().Get("start"))
+		require.NoError(t, err)
+		candles := [][]float64{}
+		// Only the most recent page has candles
+		if hour.Sub(start) <= 300*time.Hour {
+			candles = [][]float64{
+				{float64(hour.Unix()), 117000, 118500, 117500, 118000.25, 10},
+				{float64(hour.Add(-time.Hour).Unix()), 116000, 117600, 116500, 117500.5, 10},
+			}
+		}
+		json.NewEncoder(w).Encode(candles)
+	}))
+	defer server.Close()
+
+	intraday, err := provider.NewCoinbaseProvider(server.URL).GetIntradayPrices(context.Background(), "btc-usd", models.Interval60Min)
+	require.NoError(t, err)
+	assert.Equal(t, "BTC-USD", intraday.Symbol)
+	assert.Equal(t, []models.IntradayPrice{
+		{Time: hour, Price: 118000.25},
+		{Time: hour.Add(-time.Hour), Price: 117500.5},
+	}, intraday.IntradayPrices)
+	// 30 days of hourly candles take three pages of 300
+	assert.Equal(t, 3, requests)
+}
+
+func TestIntradayCacheTTL(t *testing.T) {
+	newYork := mustCalendar(t, "NYSE").Location
+
+	// While the market trades, a new bar is due every interval
+	assert.Equal(t, 5*time.Minute, handlers.IntradayCacheTTL("AAPL", models.Interval5Min, time.Date(2025, 7, 23, 10, 0, 0, 0, newYork)))
+
+	// After Friday's close the bars hold until Monday's open
+	friday := time.Date(2025, 7, 25, 17, 0, 0, 0, newYork)
+	assert.Equal(t, time.Date(2025, 7, 28, 9, 30, 0, 0, newYork).Sub(friday), handlers.IntradayCacheTTL("AAPL", models.Interval5Min, friday))
+
+	// Crypto trades through the weekend
+	saturday := time.Date(2025, 7, 26, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Hour, handlers.IntradayCacheTTL("BTC-USD", models.Interval60Min, saturday))
+}
+
+func TestFilterIntradayPrices(t *testing.T) {
+	handler := handlers.NewPriceHandler(nil, nil, nil)
+	data := &models.SymbolIntradayPrice{
+		Symbol:     "2330.TW",
+		Resolution: models.ResolutionIntraday,
+		Interval:   models.Interval60Min,
+		IntradayPrices: []models.IntradayPrice{
+			{Time: time.Date(2025, 7, 23, 4, 0, 0, 0, time.UTC), Price: 1110},
+			{Time: time.Date(2025, 7, 23, 1, 0, 0, 0, time.UTC), Price: 1105},
+			{Time: time.Date(2025, 7, 22, 5, 0, 0, 0, time.UTC), Price: 1100},
+		},
+	}
+
+	// Bars are grouped by the date in Taipei
+	filtered := handler.FilterIntradayPrices(data, "2025-07-23", "", "")
+	assert.Len(t, filtered.IntradayPrices, 2)
+	assert.Equal(t, models.Interval60Min, filtered.Interval)
+
+	filtered = handler.FilterIntradayPrices(data, "", "2025-07-21", "2025-07-22")
+	assert.Equal(t, []models.IntradayPrice{{Time: time.Date(2025, 7, 22, 5, 0, 0, 0, time.UTC), Price: 1100}}, filtered.IntradayPrices)
+
+	assert.Len(t, handler.FilterIntradayPrices(data, "", "", "").IntradayPrices, 3)
+}
+
+func TestIntradayQueryRequiresInterval(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewPriceHandler(nil, nil, nil)
+	router := gin.New()
+	router.GET("/api/v1/price/historical", handler.GetHistoricalPrices)
+
+	for _, query := range []string{
+		"symbol=AAPL&resolution=intraday",
+		"symbol=AAPL&resolution=intraday&interval=30min",
+		"symbol=AAPL&resolution=intraday&interval=5min&from=2025-07-01",
+	} {
+		req, _ := http.NewRequest("GET", "/api/v1/price/historical?"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
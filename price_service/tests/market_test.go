@@ -0,0 +1,197 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/transaction-tracker/price_service/api/handlers"
+	"github.com/transaction-tracker/price_service/internal/calendar"
+	"github.com/transaction-tracker/price_service/internal/models"
+)
+
+func mustCalendar(t *testing.T, code string) *calendar.Calendar {
+	t.Helper()
+	marketCalendar, ok := calendar.Get(code)
+	require.True(t, ok, code)
+	return marketCalendar
+}
+
+func holidayDates(holidays []calendar.Holiday) []string {
+	dates := make([]string, len(holidays))
+	for i, holiday := range holidays {
+		dates[i] = holiday.Date
+	}
+	return dates
+}
+
+func day(t *testing.T, date string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(handlers.DateFormat, date)
+	require.NoError(t, err)
+	return parsed
+}
+
+func TestNYSECalendarHolidays(t *testing.T) {
+	nyse := mustCalendar(t, "NYSE")
+
+	assert.Equal(t, []string{
+		"2025-01-01", "2025-01-09", "2025-01-20", "2025-02-17", "2025-04-18", "2025-05-26",
+		"2025-06-19", "2025-07-04", "2025-09-01", "2025-11-27", "2025-12-25",
+	}, holidayDates(nyse.Holidays(day(t, "2025-01-01"), day(t, "2025-12-31"))))
+
+	// Good Friday, and holidays on weekends made up on the nearest weekday
+	for date, name := range map[string]string{
+		"2024-03-29": "Good Friday",
+		"2026-04-03": "Good Friday",
+		"2026-07-03": "Independence Day",                     // July 4th is a Saturday
+		"2027-06-18": "Juneteenth National Independence Day", // June 19th is a Saturday
+		"2027-12-24": "Christmas Day",                        // Christmas is a Saturday
+		"2023-01-02": "New Year's Day",                       // New Year's Day is a Sunday
+	} {
+		holiday, ok := nyse.Holiday(day(t, date))
+		assert.True(t, ok, date)
+		assert.Equal(t, name, holiday, date)
+	}
+
+	// New Year's Day on a Saturday is not made up on the Friday before
+	assert.True(t, nyse.IsTradingDay(day(t, "2021-12-31")))
+	// Juneteenth only became a market holiday in 2022
+	assert.True(t, nyse.IsTradingDay(day(t, "2021-06-18")))
+}
+
+func TestNYSECalendarEarlyCloses(t *testing.T) {
+	nyse := mustCalendar(t, "NYSE")
+
+	earlyCloses := nyse.EarlyCloses(day(t, "2025-01-01"), day(t, "2025-12-31"))
+	require.Len(t, earlyCloses, 3)
+	assert.Equal(t, "2025-07-03", earlyCloses[0].Date)
+	assert.Equal(t, "2025-11-28", earlyCloses[1].Date)
+	assert.Equal(t, "2025-12-24", earlyCloses[2].Date)
+
+	session, ok := nyse.SessionOn(day(t, "2025-11-28"))
+	require.True(t, ok)
+	assert.Equal(t, 13*time.Hour, session.Close)
+
+	// July 3rd is itself the holiday when July 4th is a Saturday, so there is no early close
+	assert.Empty(t, nyse.EarlyCloses(day(t, "2026-07-01"), day(t, "2026-07-31")))
+}
+
+func TestOtherExchangeCalendars(t *testing.T) {
+	// Taiwan: Lunar New Year is listed, National Day follows its rule
+	twse := mustCalendar(t, "TWSE")
+	assert.False(t, twse.IsTradingDay(day(t, "2025-01-29")))
+	assert.False(t, twse.IsTradingDay(day(t, "2025-10-10")))
+	assert.True(t, twse.IsTradingDay(day(t, "2025-07-04")), "US holidays do not apply")
+	assert.Equal(t, "2025-01-22", twse.LastTradingDay(day(t, "2025-02-02")).Format(handlers.DateFormat))
+	assert.Equal(t, "2026-02-11", twse.LastTradingDay(day(t, "2026-02-22")).Format(handlers.DateFormat))
+	assert.Equal(t, "2027-02-11", twse.NextTradingDay(day(t, "2027-02-01")).Format(handlers.DateFormat))
+
+	// Listed closures run through 2027; later years only know the holidays rules describe
+	assert.True(t, twse.Covers(2027))
+	assert.False(t, twse.Covers(2028))
+	assert.True(t, mustCalendar(t, "NYSE").Covers(2040))
+
+	// Canada: Victoria Day, and Boxing Day moved past Christmas made up on Monday
+	tsx := mustCalendar(t, "TSX")
+	assert.False(t, tsx.IsTradingDay(day(t, "2025-05-19")))
+	assert.Equal(t, []string{"2027-12-27", "2027-12-28"}, holidayDates(tsx.Holidays(day(t, "2027-12-20"), day(t, "2027-12-31"))))
+
+	// United Kingdom: Easter Monday and bank holidays, with early closes on the eves
+	lse := mustCalendar(t, "LSE")
+	assert.Equal(t, []string{"2025-04-18", "2025-04-21", "2025-05-05", "2025-05-26"},
+		holidayDates(lse.Holidays(day(t, "2025-04-01"), day(t, "2025-05-31"))))
+	session, ok := lse.SessionOn(day(t, "2025-12-31"))
+	require.True(t, ok)
+	assert.Equal(t, 12*time.Hour+30*time.Minute, session.Close)
+}
+
+func TestCalendarForSymbol(t *testing.T) {
+	for symbol, code := range map[string]string{
+		"AAPL":     "NYSE",
+		"BRK.B":    "NYSE",
+		"2330.TW":  "TWSE",
+		"6488.TWO": "TWSE",
+		"SHOP.TO":  "TSX",
+		"VOD.L":    "LSE",
+	} {
+		assert.Equal(t, code, calendar.ForSymbol(symbol).Code, symbol)
+	}
+
+	// Victoria Day closes Toronto but not New York
+	handler := handlers.NewPriceHandler(nil, nil, nil)
+	victoriaDay := day(t, "2025-05-19")
+	assert.Equal(t, "2025-05-16", handler.GetLastTradingDayForSymbol("SHOP.TO", victoriaDay).Format(handlers.DateFormat))
+	assert.Equal(t, "2025-05-19", handler.GetLastTradingDayForSymbol("SHOP", victoriaDay).Format(handlers.DateFormat))
+}
+
+func TestCalendarStatus(t *testing.T) {
+	nyse := mustCalendar(t, "NYSE")
+	newYork := nyse.Location
+
+	// During the session
+	status := nyse.Status(time.Date(2025, 7, 23, 10, 0, 0, 0, newYork))
+	assert.True(t, status.Open)
+	assert.Equal(t, time.Date(2025, 7, 23, 16, 0, 0, 0, newYork), status.NextClose)
+	assert.Equal(t, time.Date(2025, 7, 24, 9, 30, 0, 0, newYork), status.NextOpen)
+
+	// After the early close before Independence Day, the market next opens after the holiday weekend
+	now := time.Date(2025, 7, 3, 14, 0, 0, 0, newYork)
+	status = nyse.Status(now)
+	assert.False(t, status.Open)
+	assert.True(t, nyse.HasClosed(now))
+	assert.Equal(t, time.Date(2025, 7, 7, 9, 30, 0, 0, newYork), status.NextOpen)
+
+	// Taipei is already on the next day
+	twse := mustCalendar(t, "TWSE")
+	status = twse.Status(time.Date(2025, 7, 23, 22, 0, 0, 0, newYork))
+	assert.True(t, status.Open)
+}
+
+func TestMarketEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewMarketHandler()
+	router := gin.New()
+	router.GET("/api/v1/market/calendar", handler.GetMarketCalendar)
+	router.GET("/api/v1/market/status", handler.GetMarketStatus)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/api/v1/market/calendar?symbol=2330.TW&from=2025-10-01&to=2025-10-31")
+	require.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Data models.MarketCalendar `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "TWSE", response.Data.Exchange)
+	assert.Equal(t, "Asia/Taipei", response.Data.Timezone)
+	assert.Equal(t, models.MarketSession{Open: "09:00", Close: "13:30"}, response.Data.Session)
+	assert.Equal(t, []models.MarketHoliday{
+		{Date: "2025-10-06", Name: "Mid-Autumn Festival"},
+		{Date: "2025-10-10", Name: "National Day"},
+		{Date: "2025-10-24", Name: "Taiwan Retrocession Day"},
+	}, response.Data.Holidays)
+
+	var statuses struct {
+		Data []models.MarketStatus `json:"data"`
+	}
+	w = get("/api/v1/market/status")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &statuses))
+	assert.Len(t, statuses.Data, len(calendar.Exchanges()))
+
+	assert.Equal(t, http.StatusOK, get("/api/v1/market/status?exchange=lse").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/api/v1/market/status?exchange=HKEX").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/api/v1/market/calendar").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/api/v1/market/calendar?exchange=NYSE&from=2025-01-01").Code)
+}
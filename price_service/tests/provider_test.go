@@ -2,12 +2,77 @@ package tests
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/transaction-tracker/price_service/api/handlers"
 	"github.com/transaction-tracker/price_service/internal/config"
+	"github.com/transaction-tracker/price_service/internal/models"
 	"github.com/transaction-tracker/price_service/internal/provider"
 )
 
+// fakePriceProvider serves fixed prices for the symbols it knows, or fails every call with err
+type fakePriceProvider struct {
+	prices map[string]float64
+	err    error
+	calls  int
+}
+
+func (f *fakePriceProvider) GetCurrentPrices(ctx context.Context, symbols []string) ([]models.SymbolCurrentPrice, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	var prices []models.SymbolCurrentPrice
+	for _, symbol := range symbols {
+		if price, ok := f.prices[symbol]; ok {
+			prices = append(prices, models.SymbolCurrentPrice{Symbol: symbol, CurrentPrice: price})
+		}
+	}
+	return prices, nil
+}
+
+func (f *fakePriceProvider) GetHistoricalPrices(ctx context.Context, symbol string, resolution models.Resolution) (*models.SymbolHistoricalPrice, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	price, ok := f.prices[symbol]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", provider.ErrSymbolNotFound, symbol)
+	}
+	return &models.SymbolHistoricalPrice{
+		Symbol:           symbol,
+		Resolution:       resolution,
+		HistoricalPrices: []models.ClosePrice{{Date: "2025-07-23", Price: price}},
+	}, nil
+}
+
+func (f *fakePriceProvider) GetIntradayPrices(ctx context.Context, symbol string, interval models.Interval) (*models.SymbolIntradayPrice, error) {
+	f.calls++
+	return nil, errors.New("intraday prices are not supported")
+}
+
+// newChainedProviderMap registers primary and backup providers and chains them for NYSE symbols
+func newChainedProviderMap(t *testing.T, primary, backup *fakePriceProvider) *provider.ThirdPartyProviderMap {
+	t.Helper()
+	chain := map[string][]string{"NYSE": {"primary", "backup"}}
+	providerMap, err := provider.NewThirdPartyProviderMap(&config.Config{
+		StockAPI: config.StockAPIConfig{
+			Chains: config.ProviderChainsConfig{Current: chain, Historical: chain},
+		},
+	}, provider.NamedProvider{Name: "primary", Provider: primary}, provider.NamedProvider{Name: "backup", Provider: backup})
+	require.NoError(t, err)
+	return providerMap
+}
+
 func TestNewThirdPartyProviderMap(t *testing.T) {
 	cfg := &config.Config{
 		StockAPI: config.StockAPIConfig{
@@ -62,3 +127,165 @@ func TestNewThirdPartyProviderMapMissingKeys(t *testing.T) {
 		t.Fatalf("NewThirdPartyProviderMap should not fail with missing keys: %v", err)
 	}
 }
+
+func TestParseProviderChains(t *testing.T) {
+	chains, err := config.ParseProviderChains(" Finnhub, alphavantage ; crypto=coinbase;TWSE=twse,finnhub")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"DEFAULT": {"finnhub", "alphavantage"},
+		"CRYPTO":  {"coinbase"},
+		"TWSE":    {"twse", "finnhub"},
+	}, chains)
+
+	chains, err = config.ParseProviderChains("")
+	require.NoError(t, err)
+	assert.Empty(t, chains)
+
+	_, err = config.ParseProviderChains("NYSE=")
+	assert.Error(t, err)
+	_, err = config.ParseProviderChains("=finnhub")
+	assert.Error(t, err)
+}
+
+func TestProviderChainValidation(t *testing.T) {
+	// Chains may only name registered providers
+	_, err := provider.NewThirdPartyProviderMap(&config.Config{
+		StockAPI: config.StockAPIConfig{Chains: config.ProviderChainsConfig{
+			Current: map[string][]string{"DEFAULT": {"finnhub", "polygon"}},
+		}},
+	})
+	assert.ErrorContains(t, err, "unknown provider polygon")
+
+	// ...that serve the chain's capability: Finnhub has no historical prices
+	_, err = provider.NewThirdPartyProviderMap(&config.Config{
+		StockAPI: config.StockAPIConfig{Chains: config.ProviderChainsConfig{
+			Historical: map[string][]string{"NYSE": {"finnhub"}},
+		}},
+	})
+	assert.ErrorContains(t, err, "does not serve historical prices")
+}
+
+func TestProviderChainFailover(t *testing.T) {
+	primary := &fakePriceProvider{err: errors.New("service unavailable")}
+	backup := &fakePriceProvider{prices: map[string]float64{"AAPL": 214.15}}
+	providerMap := newChainedProviderMap(t, primary, backup)
+
+	// The backup serves while the primary fails, and is recorded as the provider
+	for i := 0; i < 3; i++ {
+		historical, err := providerMap.GetHistoricalPrices(context.Background(), "AAPL", models.ResolutionDaily)
+		require.NoError(t, err)
+		assert.Equal(t, "backup", historical.Provider)
+	}
+	assert.Equal(t, 3, primary.calls)
+
+	// Three failures in a row take the primary out of rotation
+	historical, err := providerMap.GetHistoricalPrices(context.Background(), "AAPL", models.ResolutionDaily)
+	require.NoError(t, err)
+	assert.Equal(t, "backup", historical.Provider)
+	assert.Equal(t, 3, primary.calls, "the unhealthy primary is not tried while the backup serves")
+
+	status := providerMap.Status()
+	for _, providerStatus := range status.Providers {
+		if providerStatus.Name == "primary" {
+			assert.False(t, providerStatus.Healthy)
+			assert.Equal(t, 3, providerStatus.ConsecutiveFailures)
+			assert.Equal(t, "service unavailable", providerStatus.LastError)
+			assert.NotNil(t, providerStatus.RetryAfter)
+		}
+	}
+	assert.Contains(t, status.Chains, models.ProviderChain{Capability: "historical", Market: "NYSE", Providers: []string{"primary", "backup"}})
+
+	// When the backup cannot serve, the unhealthy primary is still tried as a last resort
+	_, err = providerMap.GetHistoricalPrices(context.Background(), "MSFT", models.ResolutionDaily)
+	assert.Error(t, err)
+	assert.Equal(t, 4, primary.calls)
+}
+
+func TestProviderChainRateLimited(t *testing.T) {
+	primary := &fakePriceProvider{err: fmt.Errorf("%w: 5 calls per minute", provider.ErrRateLimited)}
+	backup := &fakePriceProvider{prices: map[string]float64{"AAPL": 214.15}}
+	providerMap := newChainedProviderMap(t, primary, backup)
+
+	// A rate limit takes the provider out of rotation at once
+	for i := 0; i < 2; i++ {
+		_, err := providerMap.GetHistoricalPrices(context.Background(), "AAPL", models.ResolutionDaily)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, primary.calls)
+}
+
+func TestProviderChainCurrentPrices(t *testing.T) {
+	primary := &fakePriceProvider{prices: map[string]float64{"AAPL": 214.15}}
+	backup := &fakePriceProvider{prices: map[string]float64{"AAPL": 214.10, "MSFT": 505.25}}
+	providerMap := newChainedProviderMap(t, primary, backup)
+
+	// Symbols the primary has no price for are passed on to the backup, in the order requested
+	prices, err := providerMap.GetCurrentPrices(context.Background(), []string{"MSFT", "AAPL", "NOPE"})
+	require.NoError(t, err)
+	require.Len(t, prices, 2)
+	assert.Equal(t, "MSFT", prices[0].Symbol)
+	assert.Equal(t, "backup", prices[0].Provider)
+	assert.Equal(t, "AAPL", prices[1].Symbol)
+	assert.Equal(t, 214.15, prices[1].CurrentPrice)
+	assert.Equal(t, "primary", prices[1].Provider)
+
+	// Every provider failing fails the request
+	failing := newChainedProviderMap(t, &fakePriceProvider{err: errors.New("timeout")}, &fakePriceProvider{err: errors.New("timeout")})
+	_, err = failing.GetCurrentPrices(context.Background(), []string{"AAPL"})
+	assert.Error(t, err)
+}
+
+func TestAlphaVantageCurrentPrices(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GLOBAL_QUOTE", r.URL.Query().Get("function"))
+		switch r.URL.Query().Get("symbol") {
+		case "IBM":
+			w.Write([]byte(`{"Global Quote": {"01. symbol": "IBM", "05. price": "256.1000", "07. latest trading day": "2025-07-23",
+				"08. previous close": "255.0000", "09. change": "1.1000", "10. change percent": "0.4314%"}}`))
+		case "NOPE":
+			w.Write([]byte(`{"Global Quote": {}}`))
+		default:
+			w.Write([]byte(`{"Information": "We have detected your API key and our standard API rate limit is 25 requests per day."}`))
+		}
+	}))
+	defer server.Close()
+
+	alphaVantage := provider.NewAlphaVantageProvider("test-key")
+	alphaVantage.BaseURL = server.URL
+
+	prices, err := alphaVantage.GetCurrentPrices(context.Background(), []string{"IBM", "NOPE"})
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	assert.Equal(t, "IBM", prices[0].Symbol)
+	assert.Equal(t, 256.1, prices[0].CurrentPrice)
+	assert.Equal(t, 255.0, prices[0].PreviousClose)
+	assert.InDelta(t, 0.4314, prices[0].ChangePercent, 1e-9)
+
+	_, err = alphaVantage.GetCurrentPrices(context.Background(), []string{"MSFT"})
+	assert.ErrorIs(t, err, provider.ErrRateLimited)
+}
+
+func TestProvidersEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	providerMap, err := provider.NewThirdPartyProviderMap(&config.Config{})
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.GET("/api/v1/providers", handlers.NewProvidersHandler(providerMap).GetProviders)
+	req, _ := http.NewRequest("GET", "/api/v1/providers", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data models.ProvidersOverview `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Data.Providers, 6)
+	assert.Equal(t, "alphavantage", response.Data.Providers[0].Name)
+	assert.Equal(t, []string{"current", "historical", "intraday", "fx"}, response.Data.Providers[0].Capabilities)
+	assert.True(t, response.Data.Providers[0].Healthy)
+	assert.Contains(t, response.Data.Chains, models.ProviderChain{Capability: "current", Market: "DEFAULT", Providers: []string{"finnhub", "alphavantage"}})
+	assert.Contains(t, response.Data.Chains, models.ProviderChain{Capability: "intraday", Market: "CRYPTO", Providers: []string{"coinbase"}})
+	assert.Contains(t, response.Data.Chains, models.ProviderChain{Capability: "historical", Market: "TPEX", Providers: []string{"yahoo", "tpex"}})
+}
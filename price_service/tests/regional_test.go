@@ -0,0 +1,245 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/transaction-tracker/price_service/internal/config"
+	"github.com/transaction-tracker/price_service/internal/models"
+	"github.com/transaction-tracker/price_service/internal/provider"
+)
+
+// readFixture loads a recorded provider response from testdata
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	return body
+}
+
+// monthServer serves a recorded month of daily trading for the first request, the current month,
+// and months without trading after it
+func monthServer(t *testing.T, path, fixture, noData string) *httptest.Server {
+	t.Helper()
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, path, r.URL.Path)
+		requests++
+		if requests == 1 {
+			w.Write(readFixture(t, fixture))
+			return
+		}
+		w.Write([]byte(noData))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestTWSEProviderDailyQuotes(t *testing.T) {
+	const path = "/rwd/zh/afterTrading/STOCK_DAY"
+	noData := string(readFixture(t, "twse-stock-day-no-data.json"))
+
+	server := monthServer(t, path, "twse-stock-day-2330.json", noData)
+	prices, err := provider.NewTWSEProvider(server.URL).GetCurrentPrices(context.Background(), []string{"2330.tw"})
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	assert.Equal(t, "2330.TW", prices[0].Symbol)
+	assert.Equal(t, 1075.0, prices[0].CurrentPrice)
+	assert.Equal(t, 1070.0, prices[0].PreviousClose)
+	assert.Equal(t, 5.0, prices[0].Change)
+	assert.Equal(t, "TWD", prices[0].Currency)
+	// The close of 8 July at 13:30 in Taipei
+	assert.Equal(t, time.Date(2025, 7, 8, 5, 30, 0, 0, time.UTC), prices[0].Timestamp.UTC())
+
+	// History pages back a month at a time until a month without trading
+	server = monthServer(t, path, "twse-stock-day-2330.json", noData)
+	historical, err := provider.NewTWSEProvider(server.URL).GetHistoricalPrices(context.Background(), "2330.TW", models.ResolutionWeekly)
+	require.NoError(t, err)
+	assert.Equal(t, []models.ClosePrice{
+		{Date: "2025-07-08", Price: 1075},
+		{Date: "2025-07-04", Price: 1080},
+	}, historical.HistoricalPrices)
+
+	// Unknown stocks have no months of trading
+	server = monthServer(t, path, "twse-stock-day-no-data.json", noData)
+	twse := provider.NewTWSEProvider(server.URL)
+	prices, err = twse.GetCurrentPrices(context.Background(), []string{"9999.TW"})
+	require.NoError(t, err)
+	assert.Empty(t, prices)
+	_, err = twse.GetHistoricalPrices(context.Background(), "9999.TW", models.ResolutionDaily)
+	assert.ErrorIs(t, err, provider.ErrSymbolNotFound)
+}
+
+func TestTWSEProviderThrottled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html><body>THE PAGE CANNOT BE ACCESSED!</body></html>"))
+	}))
+	defer server.Close()
+
+	_, err := provider.NewTWSEProvider(server.URL).GetCurrentPrices(context.Background(), []string{"2330.TW"})
+	assert.ErrorIs(t, err, provider.ErrRateLimited)
+}
+
+func TestTPExProviderDailyQuotes(t *testing.T) {
+	const path = "/www/zh-tw/afterTrading/tradingStock"
+	noData := `{"tables": [{"data": [], "totalCount": 0}], "code": "6488", "stat": "ok"}`
+
+	server := monthServer(t, path, "tpex-trading-stock-6488.json", noData)
+	prices, err := provider.NewTPExProvider(server.URL).GetCurrentPrices(context.Background(), []string{"6488.TWO"})
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	assert.Equal(t, "6488.TWO", prices[0].Symbol)
+	assert.Equal(t, 341.0, prices[0].CurrentPrice)
+	assert.Equal(t, 346.5, prices[0].PreviousClose)
+
+	// Days without a trade are left out
+	server = monthServer(t, path, "tpex-trading-stock-6488.json", noData)
+	historical, err := provider.NewTPExProvider(server.URL).GetHistoricalPrices(context.Background(), "6488.TWO", models.ResolutionDaily)
+	require.NoError(t, err)
+	assert.Equal(t, []models.ClosePrice{
+		{Date: "2025-07-07", Price: 341},
+		{Date: "2025-07-03", Price: 346.5},
+		{Date: "2025-07-02", Price: 344},
+		{Date: "2025-07-01", Price: 338.5},
+	}, historical.HistoricalPrices)
+}
+
+func TestYahooProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case r.URL.Path == "/v8/finance/chart/2330.TW" && query.Get("interval") == "1d" && query.Get("range") == "1d":
+			w.Write(readFixture(t, "yahoo-chart-2330-tw-1d.json"))
+		case r.URL.Path == "/v8/finance/chart/SHOP.TO" && query.Get("interval") == "1d" && query.Get("range") == "10y":
+			w.Write(readFixture(t, "yahoo-chart-shop-to-daily.json"))
+		case r.URL.Path == "/v8/finance/chart/SHOP.TO" && query.Get("interval") == "60m":
+			w.Write(readFixture(t, "yahoo-chart-shop-to-60m.json"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write(readFixture(t, "yahoo-chart-not-found.json"))
+		}
+	}))
+	defer server.Close()
+	yahoo := provider.NewYahooProvider(server.URL)
+
+	prices, err := yahoo.GetCurrentPrices(context.Background(), []string{"2330.TW", "NOPE.TW"})
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	assert.Equal(t, "2330.TW", prices[0].Symbol)
+	assert.Equal(t, 1135.0, prices[0].CurrentPrice)
+	assert.Equal(t, 1120.0, prices[0].PreviousClose)
+	assert.Equal(t, "TWD", prices[0].Currency)
+	assert.Equal(t, time.Date(2025, 7, 23, 5, 30, 0, 0, time.UTC), prices[0].Timestamp)
+
+	// Days without a trade are left out, and bars are dated in Toronto
+	historical, err := yahoo.GetHistoricalPrices(context.Background(), "shop.to", models.ResolutionDaily)
+	require.NoError(t, err)
+	assert.Equal(t, []models.ClosePrice{
+		{Date: "2025-07-23", Price: 166.42},
+		{Date: "2025-07-21", Price: 163.1},
+	}, historical.HistoricalPrices)
+
+	intraday, err := yahoo.GetIntradayPrices(context.Background(), "SHOP.TO", models.Interval60Min)
+	require.NoError(t, err)
+	assert.Equal(t, []models.IntradayPrice{
+		{Time: time.Date(2025, 7, 23, 19, 0, 0, 0, time.UTC), Price: 166.1},
+		{Time: time.Date(2025, 7, 23, 18, 0, 0, 0, time.UTC), Price: 165.8},
+	}, intraday.IntradayPrices)
+
+	_, err = yahoo.GetHistoricalPrices(context.Background(), "NOPE.TW", models.ResolutionDaily)
+	assert.ErrorIs(t, err, provider.ErrSymbolNotFound)
+}
+
+func TestYahooProviderRestoresSplitCloses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "split", r.URL.Query().Get("events"))
+		w.Write(readFixture(t, "yahoo-chart-shop-to-split.json"))
+	}))
+	defer server.Close()
+
+	// Closes before the 10:1 split of 29 June 2022 are the prices traded, not the adjusted ones
+	historical, err := provider.NewYahooProvider(server.URL).GetHistoricalPrices(context.Background(), "SHOP.TO", models.ResolutionDaily)
+	require.NoError(t, err)
+	require.Len(t, historical.HistoricalPrices, 3)
+	assert.Equal(t, "2022-06-29", historical.HistoricalPrices[0].Date)
+	assert.InDelta(t, 42.16, historical.HistoricalPrices[0].Price, 1e-9)
+	assert.Equal(t, "2022-06-28", historical.HistoricalPrices[1].Date)
+	assert.InDelta(t, 435.0, historical.HistoricalPrices[1].Price, 1e-9)
+	assert.InDelta(t, 455.1, historical.HistoricalPrices[2].Price, 1e-9)
+}
+
+func TestMarketForSymbol(t *testing.T) {
+	for symbol, market := range map[string]string{
+		"AAPL":     "NYSE",
+		"2330.TW":  "TWSE",
+		"6488.TWO": "TPEX",
+		"SHOP.TO":  "TSX",
+		"VOD.L":    "LSE",
+		"BTC-USD":  "CRYPTO",
+	} {
+		assert.Equal(t, market, provider.MarketForSymbol(symbol), symbol)
+	}
+}
+
+func TestProviderMapRoutesTaiwanSymbols(t *testing.T) {
+	twseThrottled := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rwd/zh/afterTrading/STOCK_DAY":
+			if twseThrottled {
+				w.Write([]byte("<html><body>THE PAGE CANNOT BE ACCESSED!</body></html>"))
+				return
+			}
+			w.Write(readFixture(t, "twse-stock-day-2330.json"))
+		case "/www/zh-tw/afterTrading/tradingStock":
+			w.Write(readFixture(t, "tpex-trading-stock-6488.json"))
+		case "/v8/finance/chart/2330.TW":
+			w.Write(readFixture(t, "yahoo-chart-2330-tw-1d.json"))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// Finnhub and Alpha Vantage are not asked for Taiwan symbols
+	providerMap, err := provider.NewThirdPartyProviderMap(&config.Config{
+		StockAPI: config.StockAPIConfig{
+			AlphaVantage: config.ProviderConfig{BaseURL: server.URL},
+			Finnhub:      config.ProviderConfig{BaseURL: server.URL},
+			TWSE:         config.ProviderConfig{BaseURL: server.URL},
+			TPEx:         config.ProviderConfig{BaseURL: server.URL},
+			Yahoo:        config.ProviderConfig{BaseURL: server.URL},
+		},
+	})
+	require.NoError(t, err)
+
+	prices, err := providerMap.GetCurrentPrices(context.Background(), []string{"2330.TW", "6488.TWO"})
+	require.NoError(t, err)
+	require.Len(t, prices, 2)
+	assert.Equal(t, "twse", prices[0].Provider)
+	assert.Equal(t, 1075.0, prices[0].CurrentPrice)
+	assert.Equal(t, "tpex", prices[1].Provider)
+	assert.Equal(t, 341.0, prices[1].CurrentPrice)
+
+	// A throttled TWSE falls back to Yahoo
+	twseThrottled = true
+	prices, err = providerMap.GetCurrentPrices(context.Background(), []string{"2330.TW"})
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	assert.Equal(t, "yahoo", prices[0].Provider)
+	assert.Equal(t, 1135.0, prices[0].CurrentPrice)
+
+	// History comes from Yahoo, which reaches back further than the exchanges' year of reports
+	twseThrottled = false
+	historical, err := providerMap.GetHistoricalPrices(context.Background(), "2330.TW", models.ResolutionDaily)
+	require.NoError(t, err)
+	assert.Equal(t, "yahoo", historical.Provider)
+}
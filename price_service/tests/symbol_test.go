@@ -0,0 +1,236 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/transaction-tracker/price_service/api/handlers"
+	"github.com/transaction-tracker/price_service/internal/models"
+	"github.com/transaction-tracker/price_service/internal/provider"
+	"github.com/transaction-tracker/price_service/internal/symbols"
+)
+
+// fakeSymbolProvider knows a fixed set of symbols and counts the lookups made
+type fakeSymbolProvider struct {
+	symbols map[string]models.SymbolInfo
+	lookups int
+	err     error
+}
+
+func (p *fakeSymbolProvider) GetSymbolInfo(ctx context.Context, symbol string) (*models.SymbolInfo, error) {
+	p.lookups++
+	if p.err != nil {
+		return nil, p.err
+	}
+	info, ok := p.symbols[symbol]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", provider.ErrSymbolNotFound, symbol)
+	}
+	return &info, nil
+}
+
+func (p *fakeSymbolProvider) SearchSymbols(ctx context.Context, query string) ([]models.SymbolInfo, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	var results []models.SymbolInfo
+	for _, info := range p.symbols {
+		results = append(results, models.SymbolInfo{Symbol: info.Symbol, Name: info.Name})
+	}
+	return results, nil
+}
+
+func newSymbolRouter(t *testing.T, symbolProvider provider.SymbolInfoProvider) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	store, err := symbols.NewStore("")
+	require.NoError(t, err)
+
+	handler := handlers.NewSymbolHandler(store, symbolProvider)
+	router := gin.New()
+	router.GET("/api/v1/symbols/search", handler.SearchSymbols)
+	router.GET("/api/v1/symbols/:symbol", handler.GetSymbol)
+	return router
+}
+
+func getSymbolResponse(t *testing.T, router *gin.Engine, path string, data interface{}) int {
+	t.Helper()
+	req, _ := http.NewRequest("GET", path, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if data != nil && w.Code == http.StatusOK {
+		response := struct {
+			Success bool        `json:"success"`
+			Data    interface{} `json:"data"`
+		}{Data: data}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.True(t, response.Success)
+	}
+	return w.Code
+}
+
+func TestSymbolStoreSeedData(t *testing.T) {
+	store, err := symbols.NewStore("")
+	require.NoError(t, err)
+
+	info, ok := store.Get("aapl")
+	require.True(t, ok)
+	assert.Equal(t, "Apple Inc", info.Name)
+	assert.Equal(t, "equity", info.AssetClass)
+	assert.Equal(t, "US", info.Country)
+
+	info, ok = store.Get("2330.TW")
+	require.True(t, ok)
+	assert.Equal(t, "TWD", info.Currency)
+
+	info, ok = store.Get("BTC-USD")
+	require.True(t, ok)
+	assert.Equal(t, "crypto", info.AssetClass)
+}
+
+func TestSymbolStoreSeedFile(t *testing.T) {
+	seedFile := filepath.Join(t.TempDir(), "symbols.json")
+	require.NoError(t, os.WriteFile(seedFile, []byte(`[
+		{"symbol": "aapl", "name": "Apple Inc (custom)", "asset_class": "Equity", "currency": "usd"},
+		{"symbol": "6758.T", "name": "Sony Group Corporation", "asset_class": "equity", "exchange": "TSE", "currency": "JPY", "country": "JP"}
+	]`), 0o600))
+
+	store, err := symbols.NewStore(seedFile)
+	require.NoError(t, err)
+
+	// The seed file overrides the built-in dataset and is normalised
+	info, ok := store.Get("AAPL")
+	require.True(t, ok)
+	assert.Equal(t, "Apple Inc (custom)", info.Name)
+	assert.Equal(t, "equity", info.AssetClass)
+	assert.Equal(t, "USD", info.Currency)
+
+	_, ok = store.Get("6758.T")
+	assert.True(t, ok)
+
+	_, err = symbols.NewStore(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestSymbolStoreSearch(t *testing.T) {
+	store, err := symbols.NewStore("")
+	require.NoError(t, err)
+
+	// The symbol itself, then symbols starting with the query, then names containing it
+	results := store.Search("goog", 10)
+	require.Len(t, results, 2)
+	assert.Equal(t, "GOOG", results[0].Symbol)
+	assert.Equal(t, "GOOGL", results[1].Symbol)
+
+	results = store.Search("vanguard", 2)
+	require.Len(t, results, 2)
+	assert.Equal(t, "BND", results[0].Symbol)
+
+	assert.Empty(t, store.Search(" ", 10))
+}
+
+func TestGetSymbol(t *testing.T) {
+	symbolProvider := &fakeSymbolProvider{symbols: map[string]models.SymbolInfo{
+		"ASML": {Symbol: "ASML", Name: "ASML Holding NV", AssetClass: "equity", Exchange: "NASDAQ", Currency: "USD", Country: "NL"},
+	}}
+	router := newSymbolRouter(t, symbolProvider)
+
+	// Known locally, without asking the provider
+	var info models.SymbolInfo
+	assert.Equal(t, http.StatusOK, getSymbolResponse(t, router, "/api/v1/symbols/msft", &info))
+	assert.Equal(t, "Microsoft Corporation", info.Name)
+	assert.Equal(t, 0, symbolProvider.lookups)
+
+	// Looked up from the provider once and kept
+	for i := 0; i < 2; i++ {
+		info = models.SymbolInfo{}
+		assert.Equal(t, http.StatusOK, getSymbolResponse(t, router, "/api/v1/symbols/ASML", &info))
+		assert.Equal(t, "NL", info.Country)
+	}
+	assert.Equal(t, 1, symbolProvider.lookups)
+
+	assert.Equal(t, http.StatusNotFound, getSymbolResponse(t, router, "/api/v1/symbols/NOPE", nil))
+	assert.Equal(t, http.StatusBadRequest, getSymbolResponse(t, router, "/api/v1/symbols/$AAPL", nil))
+
+	failing := newSymbolRouter(t, &fakeSymbolProvider{err: fmt.Errorf("provider down")})
+	assert.Equal(t, http.StatusServiceUnavailable, getSymbolResponse(t, failing, "/api/v1/symbols/ASML", nil))
+}
+
+func TestSearchSymbols(t *testing.T) {
+	router := newSymbolRouter(t, &fakeSymbolProvider{symbols: map[string]models.SymbolInfo{
+		"TSLA": {Symbol: "TSLA", Name: "TESLA INC"},
+		"TSLX": {Symbol: "TSLX", Name: "SIXTH STREET SPECIALTY LENDING"},
+	}})
+
+	// Local matches come first with their full data; the provider tops them up
+	var results []models.SymbolInfo
+	assert.Equal(t, http.StatusOK, getSymbolResponse(t, router, "/api/v1/symbols/search?q=TSL", &results))
+	require.Len(t, results, 2)
+	assert.Equal(t, "TSLA", results[0].Symbol)
+	assert.Equal(t, "Tesla Inc", results[0].Name)
+	assert.Equal(t, "TSLX", results[1].Symbol)
+
+	// A failing provider leaves the local matches
+	failing := newSymbolRouter(t, &fakeSymbolProvider{err: fmt.Errorf("provider down")})
+	results = nil
+	assert.Equal(t, http.StatusOK, getSymbolResponse(t, failing, "/api/v1/symbols/search?q=apple&limit=1", &results))
+	require.Len(t, results, 1)
+	assert.Equal(t, "AAPL", results[0].Symbol)
+
+	assert.Equal(t, http.StatusBadRequest, getSymbolResponse(t, router, "/api/v1/symbols/search", nil))
+	assert.Equal(t, http.StatusBadRequest, getSymbolResponse(t, router, "/api/v1/symbols/search?q=a&limit=500", nil))
+}
+
+func TestFinnhubProviderSymbolInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/stock/profile2":
+			if r.URL.Query().Get("symbol") == "ASML" {
+				w.Write([]byte(`{"country":"nl","currency":"USD","exchange":"NASDAQ NMS - GLOBAL MARKET","finnhubIndustry":"Semiconductors","name":"ASML Holding NV","ticker":"ASML"}`))
+				return
+			}
+			w.Write([]byte(`{}`))
+		case "/search":
+			w.Write([]byte(`{"count":2,"result":[
+				{"description":"SCHWAB US DIVIDEND EQUITY ETF","displaySymbol":"SCHD","symbol":"SCHD","type":"ETP"},
+				{"description":"SCHWAB US LARGE-CAP ETF","displaySymbol":"SCHX","symbol":"SCHX","type":"ETP"}
+			]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	finnhub := provider.NewFinnhubProvider("test-finnhub-key", server.URL)
+
+	info, err := finnhub.GetSymbolInfo(context.Background(), "asml")
+	require.NoError(t, err)
+	assert.Equal(t, models.SymbolInfo{
+		Symbol:     "ASML",
+		Name:       "ASML Holding NV",
+		AssetClass: "equity",
+		Exchange:   "NASDAQ NMS - GLOBAL MARKET",
+		Currency:   "USD",
+		Country:    "NL",
+		Sector:     "Semiconductors",
+	}, *info)
+
+	// Funds have no profile and are found through search
+	info, err = finnhub.GetSymbolInfo(context.Background(), "SCHD")
+	require.NoError(t, err)
+	assert.Equal(t, "SCHWAB US DIVIDEND EQUITY ETF", info.Name)
+	assert.Equal(t, "etf", info.AssetClass)
+
+	_, err = finnhub.GetSymbolInfo(context.Background(), "NOPE")
+	assert.ErrorIs(t, err, provider.ErrSymbolNotFound)
+}